@@ -24,6 +24,7 @@ type apiServer struct {
 	cfg          *config.Config
 	log          logger.Logger
 	api          resolvers.ApiResolver
+	keys         *handlers.ApiKeys
 	srv          *http.Server
 	closed       chan interface{}
 	isVersionReq bool
@@ -69,11 +70,14 @@ func (app *apiServer) run() {
 	// make sure to capture terminate signals
 	app.observeSignals()
 
-	// run services
-	svc.Manager().Run()
+	// run services; the API alone serves data indexed by another instance
+	if app.cfg.Server.Mode != config.ModeApi {
+		svc.Manager().Run()
+	}
 
 	// start responding to requests
 	app.log.Infof("welcome to Fantom GraphQL API server")
+	app.log.Infof("running in %s mode", app.cfg.Server.Mode)
 	app.log.Infof("listening for requests on %s", app.cfg.Server.BindAddress)
 
 	// listen the interface
@@ -107,21 +111,53 @@ func (app *apiServer) makeHttpServer() {
 
 // setupHandlers initializes an array of handlers for our HTTP API end-points.
 func (app *apiServer) setupHandlers(mux *http.ServeMux) {
+	// setup server status end-point
+	mux.Handle("/status", handlers.Status(app.cfg, app.log))
+
+	// the indexer alone does not serve the API
+	if app.cfg.Server.Mode == config.ModeIndexer {
+		return
+	}
+
 	// create root resolver
 	app.api = resolvers.New()
 
+	// API keys limit the access to the API end-points, if enabled
+	limit := func(h http.Handler) http.Handler { return h }
+	if app.cfg.ApiKeys.Enabled {
+		app.keys = handlers.NewApiKeys(app.cfg, app.log)
+		limit = app.keys.Handler
+	}
+
 	// setup GraphQL API handler
 	h := http.TimeoutHandler(
-		handlers.Api(app.cfg, app.log, app.api),
+		limit(handlers.Api(app.cfg, app.log, app.api)),
 		time.Second*time.Duration(app.cfg.Server.ResolverTimeout),
 		"Service timeout.",
 	)
 	mux.Handle("/api", h)
 	mux.Handle("/graphql", h)
 
+	// setup Etherscan compatible REST API
+	mux.Handle("/etherscan/api", http.TimeoutHandler(
+		limit(handlers.Etherscan(app.cfg, app.log)),
+		time.Second*time.Duration(app.cfg.Server.ResolverTimeout),
+		"Service timeout.",
+	))
+
 	// setup gas price estimator REST API resolver
 	mux.Handle("/json/gas", handlers.GasPrice(app.log))
 
+	// setup JSON-RPC gateway
+	mux.Handle("/rpc", http.TimeoutHandler(
+		limit(handlers.Rpc(app.cfg, app.log)),
+		time.Second*time.Duration(app.cfg.Server.ResolverTimeout),
+		"Service timeout.",
+	))
+
+	// setup account history export end-points
+	mux.Handle("/export/", limit(handlers.Export(app.cfg, app.log)))
+
 	// handle GraphiQL interface
 	mux.Handle("/graphi", handlers.GraphiHandler(app.cfg.Server.DomainAddress, app.log))
 }
@@ -162,13 +198,21 @@ func (app *apiServer) observeSignals() {
 // terminate modules of the API server.
 func (app *apiServer) terminate() {
 	// close resolvers
-	app.log.Notice("closing resolver")
-	app.api.Close()
+	if app.api != nil {
+		app.log.Notice("closing resolver")
+		app.api.Close()
+	}
+
+	// persist the API usage collected
+	if app.keys != nil {
+		app.log.Notice("closing API keys")
+		app.keys.Close()
+	}
 
 	// terminate observers, scanners and dispatchers, etc.
-	app.log.Notice("closing services")
-	if mgr := svc.Manager(); mgr != nil {
-		mgr.Close()
+	if app.cfg.Server.Mode != config.ModeApi {
+		app.log.Notice("closing services")
+		svc.Manager().Close()
 	}
 
 	// terminate connections to DB, blockchain, etc.
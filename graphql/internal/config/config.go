@@ -50,6 +50,27 @@ type Config struct {
 	// Governance configuration
 	Governance Governance `mapstructure:"governance"`
 
+	// Faucet configuration
+	Faucet Faucet `mapstructure:"faucet"`
+
+	// Alerting configuration
+	Alerting Alerting `mapstructure:"alerting"`
+
+	// Export configuration
+	Export Export `mapstructure:"export"`
+
+	// JSON-RPC gateway configuration
+	Rpc Rpc `mapstructure:"rpc"`
+
+	// API keys and access tiers configuration
+	ApiKeys ApiKeys `mapstructure:"api_keys"`
+
+	// GraphQL query complexity limits configuration
+	Complexity Complexity `mapstructure:"complexity"`
+
+	// Indexer leader election configuration
+	Lease Lease `mapstructure:"lease"`
+
 	// TokenLogoFilePath contains the path to JSON file with the map
 	// of known ERC20 tokens to their logo URLs.
 	// The file will be loaded on configuration loading.
@@ -59,6 +80,11 @@ type Config struct {
 	// mapped to URL addresses of their logos.
 	TokenLogo map[common.Address]string
 
+	// SignaturesFilePath contains the path to a text file with additional
+	// function and event signatures to be imported into the signature database.
+	// The file will be loaded on the first use of the signature database.
+	SignaturesFilePath string `mapstructure:"signatures_file"`
+
 	// ReScanBlocks represents the number of blocks to be re-scanned.
 	RepoCommand RepoCmd `mapstructure:"cmd"`
 }
@@ -71,18 +97,36 @@ type RepoCmd struct {
 
 // Server represents the GraphQL server configuration
 type Server struct {
-	BindAddress     string   `mapstructure:"bind"`
-	DomainAddress   string   `mapstructure:"domain"`
-	Origin          string   `mapstructure:"origin"`
-	Peers           []string `mapstructure:"peers"`
-	CorsOrigin      []string `mapstructure:"cors_origins"`
-	ReadTimeout     int64    `mapstructure:"read_timeout"`
-	WriteTimeout    int64    `mapstructure:"write_timeout"`
-	IdleTimeout     int64    `mapstructure:"idle_timeout"`
-	HeaderTimeout   int64    `mapstructure:"header_timeout"`
-	ResolverTimeout int64    `mapstructure:"resolver_timeout"`
+	BindAddress     string           `mapstructure:"bind"`
+	DomainAddress   string           `mapstructure:"domain"`
+	Origin          string           `mapstructure:"origin"`
+	Peers           []string         `mapstructure:"peers"`
+	PeerSigners     []common.Address `mapstructure:"peer_signers"`
+	SyncAudience    string           `mapstructure:"sync_audience"`
+	CorsOrigin      []string         `mapstructure:"cors_origins"`
+	ReadTimeout     int64            `mapstructure:"read_timeout"`
+	WriteTimeout    int64            `mapstructure:"write_timeout"`
+	IdleTimeout     int64            `mapstructure:"idle_timeout"`
+	HeaderTimeout   int64            `mapstructure:"header_timeout"`
+	ResolverTimeout int64            `mapstructure:"resolver_timeout"`
+	TrustProxy      bool             `mapstructure:"trust_proxy"`
+	ProxyHops       int              `mapstructure:"proxy_hops"`
+	AdminToken      string           `mapstructure:"admin_token"`
+	Mode            string           `mapstructure:"mode"`
 }
 
+// Run modes of the API server.
+const (
+	// ModeAll runs both the blockchain indexer and the API.
+	ModeAll = "all"
+
+	// ModeIndexer runs only the blockchain indexer with a minimal status end-point.
+	ModeIndexer = "indexer"
+
+	// ModeApi runs only the API serving the data indexed by another instance.
+	ModeApi = "api"
+)
+
 // ServerSignature represents the signature used by this server
 // on sending requests to the blockchain, especially signed requests.
 type ServerSignature struct {
@@ -90,6 +134,123 @@ type ServerSignature struct {
 	PrivateKey *ecdsa.PrivateKey `mapstructure:"pkey"`
 }
 
+// Faucet represents the native tokens faucet configuration.
+// Amounts are in FTM units, the rate limits apply to the given period.
+type Faucet struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Amount       float64       `mapstructure:"amount"`
+	DailyBudget  float64       `mapstructure:"daily_budget"`
+	Period       time.Duration `mapstructure:"period"`
+	AddressLimit int64         `mapstructure:"address_limit"`
+	IpLimit      int64         `mapstructure:"ip_limit"`
+}
+
+// Alerting represents the alerting engine configuration.
+// Rules configured here are evaluated together with the rules stored in the database.
+type Alerting struct {
+	Rules []AlertRule `mapstructure:"rules"`
+	Sinks []AlertSink `mapstructure:"sinks"`
+}
+
+// AlertRule represents a declarative alert rule configuration.
+// Empty conditions match anything; all the set conditions must match.
+type AlertRule struct {
+	Name         string         `mapstructure:"name"`
+	Event        string         `mapstructure:"event"`
+	From         common.Address `mapstructure:"from"`
+	To           common.Address `mapstructure:"to"`
+	Address      common.Address `mapstructure:"address"`
+	Token        common.Address `mapstructure:"token"`
+	TokenTrxType string         `mapstructure:"type"`
+	Method       string         `mapstructure:"method"`
+	MinValue     string         `mapstructure:"min_value"`
+	Status       string         `mapstructure:"status"`
+	Sinks        []string       `mapstructure:"sinks"`
+}
+
+// AlertSink represents an alert sink configuration. The type selects the sink
+// implementation (log, file, webhook); other fields are used by the type as needed.
+type AlertSink struct {
+	Name   string `mapstructure:"name"`
+	Type   string `mapstructure:"type"`
+	Path   string `mapstructure:"path"`
+	URL    string `mapstructure:"url"`
+	Secret string `mapstructure:"secret"`
+}
+
+// Export represents the account history export configuration.
+// MaxRows limits the number of rows of a single export; clients may ask for less.
+// Keep the limit in line with the server write timeout, the stream is cut when it expires.
+type Export struct {
+	MaxRows int64 `mapstructure:"max_rows"`
+}
+
+// Rpc represents the JSON-RPC gateway configuration.
+type Rpc struct {
+	Methods   []string `mapstructure:"methods"`
+	RateLimit float64  `mapstructure:"rate_limit"`
+	RateBurst int      `mapstructure:"rate_burst"`
+	MaxBatch  int      `mapstructure:"max_batch"`
+}
+
+// ApiKeys represents the API keys and access tiers configuration.
+// Clients without an API key are served by the anonymous tier.
+type ApiKeys struct {
+	Enabled       bool      `mapstructure:"enabled"`
+	Header        string    `mapstructure:"header"`
+	Param         string    `mapstructure:"param"`
+	AnonymousTier string    `mapstructure:"anonymous_tier"`
+	Tiers         []ApiTier `mapstructure:"tiers"`
+}
+
+// ApiTier represents an API access tier with its rate limits.
+// Zero limits are not enforced.
+type ApiTier struct {
+	Name       string  `mapstructure:"name"`
+	Rate       float64 `mapstructure:"rate"`
+	Burst      int     `mapstructure:"burst"`
+	DailyQuota int64   `mapstructure:"daily_quota"`
+}
+
+// Tier provides the access tier of the given name; nil if not configured.
+func (ak *ApiKeys) Tier(name string) *ApiTier {
+	for i := range ak.Tiers {
+		if ak.Tiers[i].Name == name {
+			return &ak.Tiers[i]
+		}
+	}
+	return nil
+}
+
+// Complexity represents the static cost limits of GraphQL operations.
+// Fields with a selection cost the default cost unless configured otherwise, leaf fields are free.
+// The cost of selections of a list is multiplied by the list size. Zero limits are not enforced.
+type Complexity struct {
+	MaxDepth    int         `mapstructure:"max_depth"`
+	MaxCost     int64       `mapstructure:"max_cost"`
+	DefaultCost int64       `mapstructure:"default_cost"`
+	Costs       []FieldCost `mapstructure:"costs"`
+}
+
+// Lease represents the indexer leader election configuration. If enabled, only the instance
+// holding the indexer lease runs the blockchain indexing services; others stand by
+// and take over once the lease lapses. The holder identifies the instance, host name and PID are used if not set.
+// The stop timeout is the expected time the leader needs to stop the indexing services when stepping down.
+type Lease struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Holder      string        `mapstructure:"holder"`
+	Ttl         time.Duration `mapstructure:"ttl"`
+	Heartbeat   time.Duration `mapstructure:"heartbeat"`
+	StopTimeout time.Duration `mapstructure:"stop_timeout"`
+}
+
+// FieldCost represents the cost of a GraphQL field. The field is identified
+// by its name, or by the name of its type and the name of the field, e.g. "Account.txList".
+type FieldCost struct {
+	Field string `mapstructure:"field"`
+	Cost  int64  `mapstructure:"cost"`
+}
+
 // Log represents the logger configuration
 type Log struct {
 	Level  string `mapstructure:"level"`
@@ -98,7 +259,8 @@ type Log struct {
 
 // OperaNode represents the Opera network node access configuration
 type OperaNode struct {
-	ApiNodeUrl string `mapstructure:"url"`
+	ApiNodeUrl string        `mapstructure:"url"`
+	Timeout    time.Duration `mapstructure:"timeout"`
 }
 
 // PeerNetworking defines configuration for Opera p2p protocol.
@@ -110,8 +272,10 @@ type PeerNetworking struct {
 
 // Database represents the database access configuration.
 type Database struct {
-	Url    string `mapstructure:"url"`
-	DbName string `mapstructure:"db"`
+	Url        string        `mapstructure:"url"`
+	DbName     string        `mapstructure:"db"`
+	EventsSize int64         `mapstructure:"events_size"`
+	Timeout    time.Duration `mapstructure:"timeout"`
 }
 
 // Cache represents the cache sub-system configuration.
@@ -124,6 +288,7 @@ type Cache struct {
 type Compiler struct {
 	CompilerTempPath       string `mapstructure:"temp"`
 	DefaultSolCompilerPath string `mapstructure:"sol"`
+	SolReleasesPath        string `mapstructure:"releases"`
 }
 
 // Repository represents the repository configuration.
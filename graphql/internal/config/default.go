@@ -30,9 +30,16 @@ const (
 	defHeaderTimeout   = 1
 	defResolverTimeout = 30
 
+	// defProxyHops holds default number of trusted reverse proxies in front of the server
+	defProxyHops = 1
+
 	// defServerDomain holds default API server domain address
 	defServerDomain = "localhost:16761"
 
+	// defSyncAudience holds the default audience of contract syncs accepted from API peers;
+	// it must be configured explicitly to accept syncs from trusted peer signers
+	defSyncAudience = ""
+
 	// defLoggingLevel holds default Logging level
 	// See `godoc.org/github.com/op/go-logging` for the full format specification
 	// See `golang.org/pkg/time/` for time format specification
@@ -44,12 +51,22 @@ const (
 	// defLachesisUrl holds default Opera network connection string
 	defLachesisUrl = "~/.lachesis/data/lachesis.ipc"
 
+	// defLachesisTimeout holds default deadline of a single call of the Opera node
+	defLachesisTimeout = 15 * time.Second
+
 	// defMongoUrl holds default MongoDB connection string
 	defMongoUrl = "mongodb://localhost:27017"
 
 	// defMongoDatabase holds the default name of the API persistent database
 	defMongoDatabase = "fantom"
 
+	// defMongoTimeout holds default deadline of a single database operation
+	defMongoTimeout = 30 * time.Second
+
+	// defMongoEventsSize holds the default size of the store events collection in MB;
+	// the collection is used only if the database does not support change streams
+	defMongoEventsSize = 64
+
 	// defCacheEvictionTime holds default time for in-memory eviction periods
 	defCacheEvictionTime = 15 * time.Minute
 
@@ -59,6 +76,10 @@ const (
 	// defSolCompilerPath represents the default SOL compiler path
 	defSolCompilerPath = "/usr/bin/solc"
 
+	// defSolReleasesPath represents the default path of other SOL compiler releases;
+	// only the default compiler is available if not set
+	defSolReleasesPath = ""
+
 	// defApiStateOrigin represents the default origin used for API state syncing
 	defApiStateOrigin = "https://localhost"
 
@@ -82,14 +103,105 @@ const (
 
 	// defBlockScanRescanDepth represents the amount of blocks re-scanned on server start
 	defBlockScanRescanDepth = 200
+
+	// defFaucetAmount represents the default amount of FTM sent by the faucet on a request
+	defFaucetAmount = 1.0
+
+	// defFaucetDailyBudget represents the default max amount of FTM sent by the faucet per day
+	defFaucetDailyBudget = 1000.0
+
+	// defFaucetPeriod represents the default period of the faucet rate limits
+	defFaucetPeriod = 24 * time.Hour
+
+	// defFaucetAddressLimit represents the default max number of faucet requests per address and period
+	defFaucetAddressLimit = 1
+
+	// defFaucetIpLimit represents the default max number of faucet requests per client IP and period
+	defFaucetIpLimit = 5
+
+	// defExportMaxRows represents the default max number of rows of a single account history export
+	defExportMaxRows = 100000
+
+	// defRpcRateLimit represents the default number of JSON-RPC gateway calls per second allowed to a client
+	defRpcRateLimit = 20.0
+
+	// defRpcRateBurst represents the default max burst of JSON-RPC gateway calls of a client
+	defRpcRateBurst = 50
+
+	// defRpcMaxBatch represents the default max number of calls in a JSON-RPC gateway batch request
+	defRpcMaxBatch = 50
+
+	// defApiKeyHeader represents the default HTTP header carrying the API key
+	defApiKeyHeader = "X-Api-Key"
+
+	// defApiKeyParam represents the default query parameter carrying the API key
+	defApiKeyParam = "apikey"
+
+	// defApiAnonymousTier represents the default access tier of clients without an API key
+	defApiAnonymousTier = "anonymous"
+
+	// defComplexityMaxDepth represents the default max depth of a GraphQL operation
+	defComplexityMaxDepth = 15
+
+	// defComplexityMaxCost represents the default max cost of a GraphQL operation
+	defComplexityMaxCost = 10000
+
+	// defComplexityDefaultCost represents the default cost of a GraphQL field with a selection
+	defComplexityDefaultCost = 1
+
+	// defLeaseTtl represents the default time the indexer lease lapses after if not renewed
+	defLeaseTtl = 30 * time.Second
+
+	// defLeaseHeartbeat represents the default period of the indexer lease renewal
+	defLeaseHeartbeat = 10 * time.Second
+
+	// defLeaseStopTimeout represents the default time the indexer leader needs to stop the services
+	defLeaseStopTimeout = 5 * time.Second
 )
 
 // default list of API peers
 var defApiPeers = []string{"https://localhost:16761/api"}
 
+// defApiPeerSigners holds the default list of trusted API peer signers; no peer is trusted by default.
+var defApiPeerSigners = make([]string, 0)
+
 // defCorsAllowOrigins holds CORS default allowed origins.
 var defCorsAllowOrigins = []string{"*"}
 
+// defRpcMethods holds the default allow-list of JSON-RPC gateway methods.
+var defRpcMethods = []string{
+	"web3_clientVersion",
+	"net_version",
+	"eth_chainId",
+	"eth_blockNumber",
+	"eth_gasPrice",
+	"eth_maxPriorityFeePerGas",
+	"eth_feeHistory",
+	"eth_getBalance",
+	"eth_getCode",
+	"eth_getStorageAt",
+	"eth_getTransactionCount",
+	"eth_getBlockByHash",
+	"eth_getBlockByNumber",
+	"eth_getBlockTransactionCountByHash",
+	"eth_getBlockTransactionCountByNumber",
+	"eth_getTransactionByHash",
+	"eth_getTransactionByBlockHashAndIndex",
+	"eth_getTransactionByBlockNumberAndIndex",
+	"eth_getTransactionReceipt",
+	"eth_getLogs",
+	"eth_call",
+	"eth_estimateGas",
+	"eth_sendRawTransaction",
+}
+
+// defApiTiers holds the default list of API access tiers.
+var defApiTiers = []map[string]interface{}{
+	{"name": "anonymous", "rate": 5.0, "burst": 10, "daily_quota": 10000},
+	{"name": "free", "rate": 10.0, "burst": 20, "daily_quota": 100000},
+	{"name": "pro", "rate": 50.0, "burst": 100, "daily_quota": 0},
+}
+
 // default list of API peers
 var defVotingSources = make([]string, 0)
 
@@ -109,13 +221,20 @@ func applyDefaults(cfg *viper.Viper) {
 	cfg.SetDefault(keyLoggingLevel, defLoggingLevel)
 	cfg.SetDefault(keyLoggingFormat, defLoggingFormat)
 	cfg.SetDefault(keyLachesisUrl, defLachesisUrl)
+	cfg.SetDefault(keyLachesisTimeout, defLachesisTimeout)
 	cfg.SetDefault(keyMongoUrl, defMongoUrl)
 	cfg.SetDefault(keyMongoDatabase, defMongoDatabase)
+	cfg.SetDefault(keyMongoEvents, defMongoEventsSize)
+	cfg.SetDefault(keyMongoTimeout, defMongoTimeout)
 	cfg.SetDefault(keySolCompilerPath, defSolCompilerPath)
+	cfg.SetDefault(keySolReleasesPath, defSolReleasesPath)
 	cfg.SetDefault(keyApiPeers, defApiPeers)
+	cfg.SetDefault(keyApiPeerSigners, defApiPeerSigners)
+	cfg.SetDefault(keySyncAudience, defSyncAudience)
 	cfg.SetDefault(keyApiStateOrigin, defApiStateOrigin)
 	cfg.SetDefault(keyErc20TokenMapFilePath, defTokenLogoFilePath)
 	cfg.SetDefault(keyErc20Logos, defERC20Logo)
+	cfg.SetDefault(keySignaturesFilePath, "")
 
 	// in-memory cache
 	cfg.SetDefault(keyCacheEvictionTime, defCacheEvictionTime)
@@ -133,6 +252,14 @@ func applyDefaults(cfg *viper.Viper) {
 
 	// cors
 	cfg.SetDefault(keyCorsAllowOrigins, defCorsAllowOrigins)
+	cfg.SetDefault(keyTrustProxy, false)
+	cfg.SetDefault(keyProxyHops, defProxyHops)
+
+	// administrative API is disabled without a token
+	cfg.SetDefault(keyAdminToken, "")
+
+	// both the indexer and the API run by default
+	cfg.SetDefault(keyServerMode, ModeAll)
 
 	// staking configuration defaults
 	cfg.SetDefault(keyStakingSfcContract, defSfcContract)
@@ -147,4 +274,41 @@ func applyDefaults(cfg *viper.Viper) {
 
 	// P2P defaults
 	cfg.SetDefault(keyP2PBindUDP, "0.0.0.0:19173")
+
+	// faucet is disabled by default
+	cfg.SetDefault(keyFaucetEnabled, false)
+	cfg.SetDefault(keyFaucetAmount, defFaucetAmount)
+	cfg.SetDefault(keyFaucetDailyBudget, defFaucetDailyBudget)
+	cfg.SetDefault(keyFaucetPeriod, defFaucetPeriod)
+	cfg.SetDefault(keyFaucetAddressLimit, defFaucetAddressLimit)
+	cfg.SetDefault(keyFaucetIpLimit, defFaucetIpLimit)
+
+	// account history export
+	cfg.SetDefault(keyExportMaxRows, defExportMaxRows)
+
+	// JSON-RPC gateway
+	cfg.SetDefault(keyRpcMethods, defRpcMethods)
+	cfg.SetDefault(keyRpcRateLimit, defRpcRateLimit)
+	cfg.SetDefault(keyRpcRateBurst, defRpcRateBurst)
+	cfg.SetDefault(keyRpcMaxBatch, defRpcMaxBatch)
+
+	// API keys are not enforced by default
+	cfg.SetDefault(keyApiKeysEnabled, false)
+	cfg.SetDefault(keyApiKeysHeader, defApiKeyHeader)
+	cfg.SetDefault(keyApiKeysParam, defApiKeyParam)
+	cfg.SetDefault(keyApiKeysAnonymousTier, defApiAnonymousTier)
+	cfg.SetDefault(keyApiKeysTiers, defApiTiers)
+
+	// GraphQL query complexity
+	cfg.SetDefault(keyComplexityMaxDepth, defComplexityMaxDepth)
+	cfg.SetDefault(keyComplexityMaxCost, defComplexityMaxCost)
+	cfg.SetDefault(keyComplexityDefaultCost, defComplexityDefaultCost)
+	cfg.SetDefault(keyComplexityCosts, make([]map[string]interface{}, 0))
+
+	// single indexer without leader election by default
+	cfg.SetDefault(keyLeaseEnabled, false)
+	cfg.SetDefault(keyLeaseHolder, "")
+	cfg.SetDefault(keyLeaseTtl, defLeaseTtl)
+	cfg.SetDefault(keyLeaseHeartbeat, defLeaseHeartbeat)
+	cfg.SetDefault(keyLeaseStopTimeout, defLeaseStopTimeout)
 }
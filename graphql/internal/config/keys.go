@@ -17,8 +17,14 @@ const (
 	keyBindAddress      = "server.bind"
 	keyDomainAddress    = "server.domain"
 	keyApiPeers         = "server.peers"
+	keyApiPeerSigners   = "server.peer_signers"
+	keySyncAudience     = "server.sync_audience"
 	keyApiStateOrigin   = "server.origin"
 	keyCorsAllowOrigins = "server.cors_origins"
+	keyTrustProxy       = "server.trust_proxy"
+	keyProxyHops        = "server.proxy_hops"
+	keyAdminToken       = "server.admin_token"
+	keyServerMode       = "server.mode"
 
 	// server time out related keys
 	keyTimeoutRead     = "server.read_timeout"
@@ -36,11 +42,14 @@ const (
 	keyLoggingFormat = "log.format"
 
 	// node connection related options
-	keyLachesisUrl = "lachesis.url"
+	keyLachesisUrl     = "lachesis.url"
+	keyLachesisTimeout = "node.timeout"
 
 	// off-chain database related options
 	keyMongoUrl      = "db.url"
 	keyMongoDatabase = "db.db"
+	keyMongoEvents   = "db.events_size"
+	keyMongoTimeout  = "db.timeout"
 
 	// cache related options
 	keyCacheEvictionTime = "cache.eviction"
@@ -48,11 +57,13 @@ const (
 
 	// contract validation related
 	keySolCompilerPath = "compiler.sol"
+	keySolReleasesPath = "compiler.releases"
 
 	// utility options
 	keyVotingSources         = "voting.sources"
 	keyErc20TokenMapFilePath = "erc20_tokens_file"
 	keyErc20Logos            = "erc20_logos"
+	keySignaturesFilePath    = "signatures_file"
 
 	// PoS staking configuration
 	keyStakingSfcContract       = "staking.sfc"
@@ -66,4 +77,41 @@ const (
 	keyDefiUniswapRouter        = "defi.uniswap.router"
 
 	keyP2PBindUDP = "p2p.bind_udp"
+
+	// faucet related options
+	keyFaucetEnabled      = "faucet.enabled"
+	keyFaucetAmount       = "faucet.amount"
+	keyFaucetDailyBudget  = "faucet.daily_budget"
+	keyFaucetPeriod       = "faucet.period"
+	keyFaucetAddressLimit = "faucet.address_limit"
+	keyFaucetIpLimit      = "faucet.ip_limit"
+
+	// account history export options
+	keyExportMaxRows = "export.max_rows"
+
+	// JSON-RPC gateway options
+	keyRpcMethods   = "rpc.methods"
+	keyRpcRateLimit = "rpc.rate_limit"
+	keyRpcRateBurst = "rpc.rate_burst"
+	keyRpcMaxBatch  = "rpc.max_batch"
+
+	// API keys options
+	keyApiKeysEnabled       = "api_keys.enabled"
+	keyApiKeysHeader        = "api_keys.header"
+	keyApiKeysParam         = "api_keys.param"
+	keyApiKeysAnonymousTier = "api_keys.anonymous_tier"
+	keyApiKeysTiers         = "api_keys.tiers"
+
+	// GraphQL query complexity options
+	keyComplexityMaxDepth    = "complexity.max_depth"
+	keyComplexityMaxCost     = "complexity.max_cost"
+	keyComplexityDefaultCost = "complexity.default_cost"
+	keyComplexityCosts       = "complexity.costs"
+
+	// indexer leader election options
+	keyLeaseEnabled     = "lease.enabled"
+	keyLeaseHolder      = "lease.holder"
+	keyLeaseTtl         = "lease.ttl"
+	keyLeaseHeartbeat   = "lease.heartbeat"
+	keyLeaseStopTimeout = "lease.stop_timeout"
 )
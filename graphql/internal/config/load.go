@@ -41,6 +41,28 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// the run mode flag takes precedence over the configuration file
+	if cliMode != "" {
+		config.Server.Mode = cliMode
+	}
+	if err = validateMode(config.Server.Mode); err != nil {
+		return nil, err
+	}
+
+	// contract syncs from trusted peers are accepted only if addressed to this server
+	if len(config.Server.PeerSigners) > 0 && config.Server.SyncAudience == "" {
+		return nil, fmt.Errorf("contract sync audience must be configured with %d trusted peer signers", len(config.Server.PeerSigners))
+	}
+
+	// identify the instance in the indexer leader election
+	if config.Lease.Holder == "" {
+		config.Lease.Holder = defaultLeaseHolder()
+	}
+	if config.Lease.Enabled && config.Lease.Heartbeat+config.Lease.StopTimeout >= config.Lease.Ttl {
+		return nil, fmt.Errorf("lease heartbeat %s and stop timeout %s must be shorter than the lease ttl %s",
+			config.Lease.Heartbeat, config.Lease.StopTimeout, config.Lease.Ttl)
+	}
+
 	// try to load the logo map file
 	loadErc20LogMap(&config)
 
@@ -48,10 +70,23 @@ func Load() (*Config, error) {
 	return &config, nil
 }
 
+// cliMode holds the run mode requested by the CLI flag, if any.
+var cliMode string
+
 // attachCliFlags connects CLI flags to certain configuration options.
 func attachCliFlags(cfg *Config) {
 	flag.Uint64Var(&cfg.RepoCommand.BlockScanReScan, keyConfigCmdBlockScanReScan, defBlockScanRescanDepth, "How many blocks are re-scanned on the server start.")
 	flag.StringVar(&cfg.RepoCommand.RestoreStake, keyConfigCmdRestoreStake, "", "Owner of the stake to be restored.")
+	flag.StringVar(&cliMode, "mode", "", "Run mode of the server; indexer, api, or all.")
+}
+
+// validateMode checks the given run mode is known.
+func validateMode(mode string) error {
+	switch mode {
+	case ModeAll, ModeIndexer, ModeApi:
+		return nil
+	}
+	return fmt.Errorf("unknown run mode %s; expected %s, %s, or %s", mode, ModeIndexer, ModeApi, ModeAll)
 }
 
 // readConfigFile reads the config file and provides instance
@@ -279,3 +314,12 @@ func reader() *viper.Viper {
 
 	return cfg
 }
+
+// defaultLeaseHolder provides the default identifier of the instance in the indexer leader election.
+func defaultLeaseHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
@@ -0,0 +1,293 @@
+// Package complexity implements static cost analysis of GraphQL operations.
+package complexity
+
+import (
+	"fantom-api-graphql/internal/config"
+	"fmt"
+	"github.com/graph-gophers/graphql-go/types"
+	"math"
+	"strings"
+)
+
+// countArgument represents the name of the argument controlling the size of a list.
+const countArgument = "count"
+
+// Analyzer calculates the depth and the cost of GraphQL operations before they are executed.
+// The cost of a field is its own cost plus the cost of its selections multiplied
+// by the size of the list requested by the count argument.
+type Analyzer struct {
+	schema      *types.Schema
+	maxDepth    int
+	maxCost     int64
+	defaultCost int64
+	costs       map[string]int64
+}
+
+// Result represents the outcome of an operation analysis.
+type Result struct {
+	Depth int
+	Cost  int64
+}
+
+// LimitError represents an operation rejected for exceeding the depth, or the cost limit.
+type LimitError struct {
+	Result
+	MaxDepth int
+	MaxCost  int64
+}
+
+// Error implements the error interface for the limit error.
+func (le *LimitError) Error() string {
+	if le.MaxDepth > 0 && le.Depth > le.MaxDepth {
+		return fmt.Sprintf("query depth %d exceeds the max depth %d", le.Depth, le.MaxDepth)
+	}
+	return fmt.Sprintf("query cost %d exceeds the max cost %d", le.Cost, le.MaxCost)
+}
+
+// walker keeps the state of a single operation analysis.
+type walker struct {
+	*Analyzer
+	doc      *document
+	vars     map[string]interface{}
+	defaults map[string]interface{}
+	visiting map[string]bool
+}
+
+// New creates a new analyzer of operations of the given schema.
+func New(schema *types.Schema, cfg *config.Complexity) *Analyzer {
+	an := Analyzer{
+		schema:      schema,
+		maxDepth:    cfg.MaxDepth,
+		maxCost:     cfg.MaxCost,
+		defaultCost: cfg.DefaultCost,
+		costs:       make(map[string]int64, len(cfg.Costs)),
+	}
+	for _, fc := range cfg.Costs {
+		an.costs[fc.Field] = fc.Cost
+	}
+	return &an
+}
+
+// Analyze calculates the depth and the cost of the given operation of the query document
+// and checks them against the configured limits. A LimitError is returned if the operation
+// exceeds a limit; other errors signal the operation could not be analyzed.
+func (an *Analyzer) Analyze(query string, operationName string, vars map[string]interface{}) (Result, error) {
+	doc, err := parse(query)
+	if err != nil {
+		return Result{}, err
+	}
+
+	op, err := doc.operation(operationName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	root, ok := an.schema.EntryPoints[op.kind]
+	if !ok {
+		return Result{}, fmt.Errorf("%s operations not supported", op.kind)
+	}
+
+	w := walker{Analyzer: an, doc: doc, vars: vars, defaults: op.defaults, visiting: make(map[string]bool)}
+	cost, depth, err := w.selectionSet(op.selections, root.TypeName(), 0)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Depth: depth, Cost: cost}
+	if (an.maxDepth > 0 && depth > an.maxDepth) || (an.maxCost > 0 && cost > an.maxCost) {
+		return res, &LimitError{Result: res, MaxDepth: an.maxDepth, MaxCost: an.maxCost}
+	}
+	return res, nil
+}
+
+// operation provides the operation of the given name; the name may be empty
+// if the document contains a single operation.
+func (doc *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operation name required")
+		}
+		return doc.operations[0], nil
+	}
+
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("operation %s not found", name)
+}
+
+// selectionSet calculates the cost and the depth of the selections on the given type.
+func (w *walker) selectionSet(list []selection, typeName string, depth int) (int64, int, error) {
+	var cost int64
+	maxDepth := depth
+
+	for i := range list {
+		c, d, err := w.selection(&list[i], typeName, depth)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		cost = saturatedAdd(cost, c)
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	return cost, maxDepth, nil
+}
+
+// selection calculates the cost and the depth of a single selection on the given type.
+// Selections of all the fragments are counted in, even if they target different types.
+func (w *walker) selection(sel *selection, typeName string, depth int) (int64, int, error) {
+	switch {
+	case sel.spread != "":
+		f, ok := w.doc.fragments[sel.spread]
+		if !ok {
+			return 0, 0, fmt.Errorf("fragment %s not found", sel.spread)
+		}
+		if w.visiting[f.name] {
+			return 0, 0, fmt.Errorf("fragment %s spreads itself", f.name)
+		}
+
+		w.visiting[f.name] = true
+		defer delete(w.visiting, f.name)
+		return w.selectionSet(f.selections, f.on, depth)
+
+	case sel.inline:
+		if sel.typeCond != "" {
+			typeName = sel.typeCond
+		}
+		return w.selectionSet(sel.selections, typeName, depth)
+	}
+
+	// introspection is not limited
+	if strings.HasPrefix(sel.name, "__") {
+		return 0, depth, nil
+	}
+
+	def := w.field(typeName, sel.name)
+
+	cost, ok := w.costs[typeName+"."+sel.name]
+	if !ok {
+		cost, ok = w.costs[sel.name]
+	}
+	if !ok && len(sel.selections) > 0 {
+		cost = w.defaultCost
+	}
+	if len(sel.selections) == 0 {
+		return cost, depth + 1, nil
+	}
+
+	var childType string
+	if def != nil {
+		childType = namedType(def.Type)
+	}
+
+	sub, d, err := w.selectionSet(sel.selections, childType, depth+1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return saturatedAdd(cost, saturatedMul(w.listSize(sel, def), sub)), d, nil
+}
+
+// field provides the definition of the field of the given type; nil if not known.
+func (w *walker) field(typeName string, name string) *types.FieldDefinition {
+	switch t := w.schema.Types[typeName].(type) {
+	case *types.ObjectTypeDefinition:
+		return t.Fields.Get(name)
+	case *types.InterfaceTypeDefinition:
+		return t.Fields.Get(name)
+	}
+	return nil
+}
+
+// listSize provides the size of the list requested by the count argument of the field;
+// the schema default of the argument is used if the argument is not given.
+func (w *walker) listSize(sel *selection, def *types.FieldDefinition) int64 {
+	if v, ok := sel.args[countArgument]; ok {
+		if n, ok := w.int(v); ok {
+			return abs(n)
+		}
+		return 1
+	}
+
+	if def != nil {
+		if arg := def.Arguments.Get(countArgument); arg != nil && arg.Default != nil {
+			if n, ok := arg.Default.Deserialize(nil).(int32); ok {
+				return abs(int64(n))
+			}
+		}
+	}
+	return 1
+}
+
+// int resolves the given argument value into an integer; variables are resolved
+// from the request variables, or their defaults.
+func (w *walker) int(v interface{}) (int64, bool) {
+	if name, ok := v.(variable); ok {
+		var found bool
+		if v, found = w.vars[string(name)]; !found {
+			v = w.defaults[string(name)]
+		}
+	}
+
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int32:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case float64:
+		// JSON decoded variables are floats
+		if n > math.MaxInt64 || n < math.MinInt64 {
+			return math.MaxInt64, true
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// namedType provides the name of the named type wrapped by list and non-null types.
+func namedType(t types.Type) string {
+	for {
+		switch w := t.(type) {
+		case *types.List:
+			t = w.OfType
+		case *types.NonNull:
+			t = w.OfType
+		case types.NamedType:
+			return w.TypeName()
+		default:
+			return ""
+		}
+	}
+}
+
+// abs provides the absolute value of the given number; the direction of a list does not change its cost.
+func abs(n int64) int64 {
+	if n < 0 {
+		if n == math.MinInt64 {
+			return math.MaxInt64
+		}
+		return -n
+	}
+	return n
+}
+
+// saturatedAdd adds non-negative numbers capping the result at the max int64 value.
+func saturatedAdd(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// saturatedMul multiplies non-negative numbers capping the result at the max int64 value.
+func saturatedMul(a, b int64) int64 {
+	if a != 0 && b > math.MaxInt64/a {
+		return math.MaxInt64
+	}
+	return a * b
+}
@@ -0,0 +1,181 @@
+package complexity
+
+import (
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/onsi/gomega"
+	"math"
+	"testing"
+)
+
+// testSchema is a minimal schema of a block list with nested transactions.
+const testSchema = `
+schema {
+    query: Query
+    subscription: Subscription
+}
+
+type Query {
+    block(number: Long): Block
+    blocks(count: Int = 25): [Block!]!
+    node: Node
+}
+
+type Subscription {
+    onBlock: Block!
+}
+
+interface Node {
+    id: ID!
+    children(count: Int!): [Block!]!
+}
+
+type Block implements Node {
+    id: ID!
+    number: Long!
+    children(count: Int!): [Block!]!
+    txList(count: Int!): [Transaction!]!
+}
+
+type Transaction {
+    hash: String!
+    block: Block!
+}
+
+scalar Long
+`
+
+// newTestAnalyzer creates an analyzer of the test schema with the given limits.
+func newTestAnalyzer(maxDepth int, maxCost int64) *Analyzer {
+	schema := graphql.MustParseSchema(testSchema, nil)
+	return New(schema.ASTSchema(), &config.Complexity{
+		MaxDepth:    maxDepth,
+		MaxCost:     maxCost,
+		DefaultCost: 1,
+		Costs: []config.FieldCost{
+			{Field: "blocks", Cost: 2},
+			{Field: "Block.txList", Cost: 5},
+		},
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		op    string
+		vars  map[string]interface{}
+		want  Result
+	}{
+		{name: "leaf only", query: "{ __typename }", want: Result{Depth: 0, Cost: 0}},
+		{name: "single object", query: "{ block { number } }", want: Result{Depth: 2, Cost: 1}},
+		{name: "schema default count", query: "{ blocks { number } }", want: Result{Depth: 2, Cost: 2}},
+		{name: "list of objects", query: "{ blocks(count: 10) { txList(count: 5) { hash } } }", want: Result{Depth: 3, Cost: 2 + 10*5}},
+		{name: "negative count", query: "{ blocks(count: -10) { txList(count: 5) { hash } } }", want: Result{Depth: 3, Cost: 2 + 10*5}},
+		{name: "nested lists", query: "{ blocks(count: 10) { txList(count: 5) { block { number } } } }", want: Result{Depth: 4, Cost: 2 + 10*(5+5*1)}},
+		{
+			name:  "count variable",
+			query: "query Q($n: Int = 3) { blocks(count: $n) { txList(count: 5) { hash } } }",
+			vars:  map[string]interface{}{"n": float64(20)},
+			want:  Result{Depth: 3, Cost: 2 + 20*5},
+		},
+		{
+			name:  "count variable default",
+			query: "query Q($n: Int = 3) { blocks(count: $n) { txList(count: 5) { hash } } }",
+			want:  Result{Depth: 3, Cost: 2 + 3*5},
+		},
+		{
+			name:  "selected operation",
+			query: "query A { block { number } } query B { blocks(count: 4) { txList(count: 1) { hash } } }",
+			op:    "B",
+			want:  Result{Depth: 3, Cost: 2 + 4*5},
+		},
+		{
+			name:  "fragments",
+			query: "{ blocks(count: 2) { ...F ... on Block { children(count: 3) { number } } } } fragment F on Block { txList(count: 2) { hash } }",
+			want:  Result{Depth: 3, Cost: 2 + 2*(5+1)},
+		},
+		{name: "interface field", query: "{ node { children(count: 4) { txList(count: 1) { hash } } } }", want: Result{Depth: 4, Cost: 1 + 1*(1+4*5)}},
+		{name: "subscription", query: "subscription { onBlock { txList(count: 3) { hash } } }", want: Result{Depth: 3, Cost: 1 + 1*5}},
+		{name: "introspection", query: "{ __schema { types { name } } }", want: Result{Depth: 0, Cost: 0}},
+		{name: "unknown field", query: "{ missing { number } }", want: Result{Depth: 2, Cost: 1}},
+		{
+			name:  "saturated cost",
+			query: "{ blocks(count: 9223372036854775807) { children(count: 9223372036854775807) { txList(count: 9223372036854775807) { hash } } } }",
+			want:  Result{Depth: 4, Cost: math.MaxInt64},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			res, err := newTestAnalyzer(0, 0).Analyze(tt.query, tt.op, tt.vars)
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(res).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		op    string
+	}{
+		{name: "syntax error", query: "{ block { number }"},
+		{name: "operation name required", query: "query A { block { number } } query B { block { number } }"},
+		{name: "operation not found", query: "query A { block { number } }", op: "B"},
+		{name: "mutation not supported", query: "mutation { block { number } }"},
+		{name: "fragment not found", query: "{ block { ...F } }"},
+		{name: "fragment cycle", query: "{ block { ...F } } fragment F on Block { children(count: 2) { ...F } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			_, err := newTestAnalyzer(0, 0).Analyze(tt.query, tt.op, nil)
+			g.Expect(err).ToNot(gomega.BeNil())
+
+			var le *LimitError
+			g.Expect(errors.As(err, &le)).To(gomega.BeFalse())
+		})
+	}
+}
+
+func TestAnalyzeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		maxCost  int64
+		query    string
+		limited  bool
+		message  string
+	}{
+		{name: "within limits", maxDepth: 3, maxCost: 52, query: "{ blocks(count: 10) { txList(count: 5) { hash } } }"},
+		{name: "too deep", maxDepth: 2, maxCost: 100, query: "{ blocks(count: 10) { txList(count: 5) { hash } } }", limited: true, message: "query depth 3 exceeds the max depth 2"},
+		{name: "too costly", maxDepth: 3, maxCost: 51, query: "{ blocks(count: 10) { txList(count: 5) { hash } } }", limited: true, message: "query cost 52 exceeds the max cost 51"},
+		{name: "no limits", query: "{ blocks(count: 1000) { txList(count: 1000) { block { number } } } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			res, err := newTestAnalyzer(tt.maxDepth, tt.maxCost).Analyze(tt.query, "", nil)
+			if !tt.limited {
+				g.Expect(err).To(gomega.BeNil())
+				return
+			}
+
+			var le *LimitError
+			g.Expect(errors.As(err, &le)).To(gomega.BeTrue())
+			g.Expect(le.Result).To(gomega.Equal(res))
+			g.Expect(le.MaxDepth).To(gomega.Equal(tt.maxDepth))
+			g.Expect(le.MaxCost).To(gomega.Equal(tt.maxCost))
+			g.Expect(le.Error()).To(gomega.Equal(tt.message))
+		})
+	}
+}
@@ -0,0 +1,497 @@
+// Package complexity implements static cost analysis of GraphQL operations.
+package complexity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// token kinds of the GraphQL lexer
+const (
+	tokEOF = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+// byteOrderMark represents the Unicode BOM ignored by the lexer.
+const byteOrderMark = "\ufeff"
+
+// token represents a lexical token of a GraphQL document.
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// document represents a parsed GraphQL executable document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation represents an operation definition of a document.
+type operation struct {
+	kind       string
+	name       string
+	defaults   map[string]interface{}
+	selections []selection
+}
+
+// fragment represents a named fragment definition of a document.
+type fragment struct {
+	name       string
+	on         string
+	selections []selection
+}
+
+// selection represents a field, a fragment spread, or an inline fragment of a selection set.
+type selection struct {
+	// field details
+	name       string
+	args       map[string]interface{}
+	selections []selection
+
+	// fragment spread name, or inline fragment type condition
+	spread   string
+	inline   bool
+	typeCond string
+}
+
+// variable represents a reference to an operation variable in an argument value.
+type variable string
+
+// parser implements a recursive descent parser of GraphQL executable documents.
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// parse parses the given GraphQL query document.
+func parse(src string) (doc *document, err error) {
+	p := parser{src: src}
+
+	// syntax errors are raised by panics inside the parser to keep it simple
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(syntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, se
+		}
+	}()
+
+	p.next()
+	doc = &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokEOF {
+		if p.tok.kind == tokName && p.tok.text == "fragment" {
+			f := p.fragmentDefinition()
+			doc.fragments[f.name] = f
+			continue
+		}
+		doc.operations = append(doc.operations, p.operationDefinition())
+	}
+
+	if len(doc.operations) == 0 {
+		p.fail("no operation found")
+	}
+	return doc, nil
+}
+
+// syntaxError represents a GraphQL document syntax error.
+type syntaxError struct {
+	msg string
+	pos int
+}
+
+// Error implements the error interface for the syntax error.
+func (se syntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d: %s", se.pos, se.msg)
+}
+
+// fail raises a syntax error at the current token.
+func (p *parser) fail(format string, args ...interface{}) {
+	panic(syntaxError{msg: fmt.Sprintf(format, args...), pos: p.tok.pos})
+}
+
+// operationDefinition parses an operation; the shorthand query form is supported.
+func (p *parser) operationDefinition() *operation {
+	op := operation{kind: "query", defaults: make(map[string]interface{})}
+	if p.peek(tokPunct, "{") {
+		op.selections = p.selectionSet()
+		return &op
+	}
+
+	op.kind = p.expect(tokName, "").text
+	if op.kind != "query" && op.kind != "mutation" && op.kind != "subscription" {
+		p.fail("unexpected %q", op.kind)
+	}
+	if p.tok.kind == tokName {
+		op.name = p.advance().text
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect(tokPunct, "$")
+			name := p.expect(tokName, "").text
+			p.expect(tokPunct, ":")
+			p.typeRef()
+			if p.skip("=") {
+				op.defaults[name] = p.value()
+			}
+			p.directives()
+		}
+	}
+	p.directives()
+	op.selections = p.selectionSet()
+	return &op
+}
+
+// fragmentDefinition parses a named fragment.
+func (p *parser) fragmentDefinition() *fragment {
+	p.expect(tokName, "fragment")
+	f := fragment{name: p.expect(tokName, "").text}
+	p.expect(tokName, "on")
+	f.on = p.expect(tokName, "").text
+	p.directives()
+	f.selections = p.selectionSet()
+	return &f
+}
+
+// selectionSet parses a selection set enclosed in braces.
+func (p *parser) selectionSet() []selection {
+	p.expect(tokPunct, "{")
+
+	list := make([]selection, 0)
+	for !p.skip("}") {
+		list = append(list, p.selection())
+	}
+	return list
+}
+
+// selection parses a single field, fragment spread, or inline fragment.
+func (p *parser) selection() selection {
+	if p.skip("...") {
+		// inline fragment with an optional type condition
+		if p.peek(tokName, "on") {
+			p.advance()
+			sel := selection{inline: true, typeCond: p.expect(tokName, "").text}
+			p.directives()
+			sel.selections = p.selectionSet()
+			return sel
+		}
+		if p.tok.kind != tokName {
+			p.directives()
+			return selection{inline: true, selections: p.selectionSet()}
+		}
+
+		sel := selection{spread: p.advance().text}
+		p.directives()
+		return sel
+	}
+
+	// field with an optional alias
+	sel := selection{name: p.expect(tokName, "").text}
+	if p.skip(":") {
+		sel.name = p.expect(tokName, "").text
+	}
+	sel.args = p.arguments()
+	p.directives()
+	if p.peek(tokPunct, "{") {
+		sel.selections = p.selectionSet()
+	}
+	return sel
+}
+
+// arguments parses an optional list of arguments.
+func (p *parser) arguments() map[string]interface{} {
+	args := make(map[string]interface{})
+	if !p.skip("(") {
+		return args
+	}
+
+	for !p.skip(")") {
+		name := p.expect(tokName, "").text
+		p.expect(tokPunct, ":")
+		args[name] = p.value()
+	}
+	return args
+}
+
+// directives parses and drops an optional list of directives.
+func (p *parser) directives() {
+	for p.skip("@") {
+		p.expect(tokName, "")
+		p.arguments()
+	}
+}
+
+// typeRef parses and drops a variable type reference.
+func (p *parser) typeRef() {
+	if p.skip("[") {
+		p.typeRef()
+		p.expect(tokPunct, "]")
+	} else {
+		p.expect(tokName, "")
+	}
+	p.skip("!")
+}
+
+// value parses an argument value into its Go representation.
+func (p *parser) value() interface{} {
+	switch t := p.advance(); t.kind {
+	case tokInt:
+		v, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			p.fail("invalid int %s", t.text)
+		}
+		return v
+	case tokFloat:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			p.fail("invalid float %s", t.text)
+		}
+		return v
+	case tokString:
+		return t.text
+	case tokName:
+		switch t.text {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return t.text
+	case tokPunct:
+		switch t.text {
+		case "$":
+			return variable(p.expect(tokName, "").text)
+		case "[":
+			list := make([]interface{}, 0)
+			for !p.skip("]") {
+				list = append(list, p.value())
+			}
+			return list
+		case "{":
+			obj := make(map[string]interface{})
+			for !p.skip("}") {
+				name := p.expect(tokName, "").text
+				p.expect(tokPunct, ":")
+				obj[name] = p.value()
+			}
+			return obj
+		}
+	}
+	p.fail("unexpected value")
+	return nil
+}
+
+// peek checks if the current token is of the given kind and text.
+func (p *parser) peek(kind int, text string) bool {
+	return p.tok.kind == kind && (text == "" || p.tok.text == text)
+}
+
+// skip consumes the current token if it's the given punctuator.
+func (p *parser) skip(punct string) bool {
+	if !p.peek(tokPunct, punct) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+// expect consumes the current token of the given kind and text; any text matches if empty.
+func (p *parser) expect(kind int, text string) token {
+	if !p.peek(kind, text) {
+		if p.tok.kind == tokEOF {
+			p.fail("unexpected end of document")
+		}
+		p.fail("unexpected %q", p.tok.text)
+	}
+	return p.advance()
+}
+
+// advance consumes the current token and provides it.
+func (p *parser) advance() token {
+	t := p.tok
+	p.next()
+	return t
+}
+
+// next reads the next significant token of the source.
+func (p *parser) next() {
+	// skip ignored tokens; white space, line terminators, commas, comments and BOM
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if strings.HasPrefix(p.src[p.pos:], byteOrderMark) {
+			p.pos += len(byteOrderMark)
+			continue
+		}
+		break
+	}
+
+	p.tok = token{kind: tokEOF, pos: p.pos}
+	if p.pos >= len(p.src) {
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.tok = token{kind: tokPunct, text: "...", pos: p.pos}
+		p.pos += 3
+	case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+		p.tok = token{kind: tokPunct, text: string(c), pos: p.pos}
+		p.pos++
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		start := p.pos
+		for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, text: p.src[start:p.pos], pos: start}
+	case c == '-' || (c >= '0' && c <= '9'):
+		p.number()
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		p.blockString()
+	case c == '"':
+		p.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		panic(syntaxError{msg: fmt.Sprintf("unexpected character %q", r), pos: p.pos})
+	}
+}
+
+// number reads an int, or a float token.
+func (p *parser) number() {
+	start, kind := p.pos, tokInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	p.digits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokFloat
+		p.pos++
+		p.digits()
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		p.digits()
+	}
+	p.tok = token{kind: kind, text: p.src[start:p.pos], pos: start}
+}
+
+// digits reads a non-empty sequence of decimal digits.
+func (p *parser) digits() {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		panic(syntaxError{msg: "digit expected", pos: p.pos})
+	}
+}
+
+// string reads a quoted string token resolving its escape sequences.
+func (p *parser) string() {
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			p.tok = token{kind: tokString, text: sb.String(), pos: start}
+			return
+		case c == '\n' || c == '\r':
+			panic(syntaxError{msg: "unterminated string", pos: start})
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.escape(&sb)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	panic(syntaxError{msg: "unterminated string", pos: start})
+}
+
+// escape reads an escape sequence of a quoted string.
+func (p *parser) escape(sb *strings.Builder) {
+	e := p.src[p.pos+1]
+	p.pos += 2
+
+	switch e {
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'u':
+		if p.pos+4 > len(p.src) {
+			panic(syntaxError{msg: "invalid unicode escape", pos: p.pos})
+		}
+		r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+		if err != nil {
+			panic(syntaxError{msg: "invalid unicode escape", pos: p.pos})
+		}
+		sb.WriteRune(rune(r))
+		p.pos += 4
+	default:
+		sb.WriteByte(e)
+	}
+}
+
+// blockString reads a triple quoted block string token.
+func (p *parser) blockString() {
+	start := p.pos
+	p.pos += 3
+
+	end := strings.Index(p.src[p.pos:], `"""`)
+	for end >= 0 && end > 0 && p.src[p.pos+end-1] == '\\' {
+		next := strings.Index(p.src[p.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += 3 + next
+	}
+	if end < 0 {
+		panic(syntaxError{msg: "unterminated block string", pos: start})
+	}
+
+	text := strings.ReplaceAll(p.src[p.pos:p.pos+end], `\"""`, `"""`)
+	p.pos += end + 3
+	p.tok = token{kind: tokString, text: text, pos: start}
+}
+
+// isNameChar checks if the given character can be a part of a GraphQL name.
+func isNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,112 @@
+package complexity
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		check func(g *gomega.WithT, doc *document)
+	}{
+		{
+			name:  "shorthand query",
+			query: "{ block { number } }",
+			check: func(g *gomega.WithT, doc *document) {
+				g.Expect(doc.operations).To(gomega.HaveLen(1))
+				g.Expect(doc.operations[0].kind).To(gomega.Equal("query"))
+				g.Expect(doc.operations[0].selections).To(gomega.HaveLen(1))
+				g.Expect(doc.operations[0].selections[0].name).To(gomega.Equal("block"))
+				g.Expect(doc.operations[0].selections[0].selections[0].name).To(gomega.Equal("number"))
+			},
+		},
+		{
+			name:  "named operation with variables",
+			query: "query Blocks($count: Int = 25, $cursor: Cursor, $list: [Int!]! @skip(if: true)) { blocks(count: $count, cursor: $cursor) { totalCount } }",
+			check: func(g *gomega.WithT, doc *document) {
+				op := doc.operations[0]
+				g.Expect(op.name).To(gomega.Equal("Blocks"))
+				g.Expect(op.defaults).To(gomega.Equal(map[string]interface{}{"count": int64(25)}))
+				g.Expect(op.selections[0].args).To(gomega.Equal(map[string]interface{}{"count": variable("count"), "cursor": variable("cursor")}))
+			},
+		},
+		{
+			name:  "alias and argument values",
+			query: `{ latest: block(number: -1, hash: "0xA\n", ok: true, no: false, none: null, ratio: 1.5e3, kind: BLOCK, list: [1, 2], obj: {a: 1}) { number } }`,
+			check: func(g *gomega.WithT, doc *document) {
+				sel := doc.operations[0].selections[0]
+				g.Expect(sel.name).To(gomega.Equal("block"))
+				g.Expect(sel.args).To(gomega.Equal(map[string]interface{}{
+					"number": int64(-1),
+					"hash":   "0xA\n",
+					"ok":     true,
+					"no":     false,
+					"none":   nil,
+					"ratio":  1500.0,
+					"kind":   "BLOCK",
+					"list":   []interface{}{int64(1), int64(2)},
+					"obj":    map[string]interface{}{"a": int64(1)},
+				}))
+			},
+		},
+		{
+			name:  "block string",
+			query: "{ search(text: \"\"\"a \\\"\"\" b\"\"\") { id } }",
+			check: func(g *gomega.WithT, doc *document) {
+				g.Expect(doc.operations[0].selections[0].args["text"]).To(gomega.Equal(`a """ b`))
+			},
+		},
+		{
+			name:  "fragments",
+			query: "query Q { block { ...BlockFields ... on Block { hash } ... @include(if: true) { number } } } fragment BlockFields on Block @deprecated { number }",
+			check: func(g *gomega.WithT, doc *document) {
+				g.Expect(doc.fragments).To(gomega.HaveKey("BlockFields"))
+				g.Expect(doc.fragments["BlockFields"].on).To(gomega.Equal("Block"))
+
+				list := doc.operations[0].selections[0].selections
+				g.Expect(list).To(gomega.HaveLen(3))
+				g.Expect(list[0].spread).To(gomega.Equal("BlockFields"))
+				g.Expect(list[1].inline).To(gomega.BeTrue())
+				g.Expect(list[1].typeCond).To(gomega.Equal("Block"))
+				g.Expect(list[2].inline).To(gomega.BeTrue())
+				g.Expect(list[2].typeCond).To(gomega.BeEmpty())
+			},
+		},
+		{
+			name:  "ignored tokens",
+			query: byteOrderMark + "# comment\r\n{ block ,, { number } }\t",
+			check: func(g *gomega.WithT, doc *document) {
+				g.Expect(doc.operations[0].selections[0].selections).To(gomega.HaveLen(1))
+			},
+		},
+		{name: "empty document", query: ""},
+		{name: "fragment only", query: "fragment F on Block { number }"},
+		{name: "unknown operation kind", query: "select { block }"},
+		{name: "unterminated selection", query: "{ block { number }"},
+		{name: "unterminated string", query: `{ block(hash: "0x) { number } }`},
+		{name: "unterminated block string", query: `{ block(hash: """0x) { number } }`},
+		{name: "invalid number", query: "{ block(number: 1.) { number } }"},
+		{name: "invalid int", query: "{ block(number: 99999999999999999999) { number } }"},
+		{name: "invalid unicode escape", query: `{ block(hash: "\u00zz") { number } }`},
+		{name: "unexpected character", query: "{ block % }"},
+		{name: "missing value", query: "{ block(number: ) { number } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			doc, err := parse(tt.query)
+			if tt.check == nil {
+				g.Expect(err).ToNot(gomega.BeNil())
+				g.Expect(err).To(gomega.BeAssignableToTypeOf(syntaxError{}))
+				return
+			}
+
+			g.Expect(err).To(gomega.BeNil())
+			tt.check(g, doc)
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package resolvers
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -27,9 +28,9 @@ func NewAccount(acc *types.Account) *Account {
 }
 
 // Account resolves blockchain account by address.
-func (rs *rootResolver) Account(args struct{ Address common.Address }) (*Account, error) {
+func (rs *rootResolver) Account(ctx context.Context, args struct{ Address common.Address }) (*Account, error) {
 	// simply pull the block by hash
-	acc, err := repository.R().Account(&args.Address)
+	acc, err := repository.R().Account(ctx, &args.Address)
 	if err != nil {
 		log.Errorf("could not get the specified account")
 		return nil, err
@@ -43,10 +44,10 @@ func (rs *rootResolver) AccountsActive() (hexutil.Uint64, error) {
 }
 
 // Balance resolves total balance of the account.
-func (acc *Account) Balance() (hexutil.Big, error) {
+func (acc *Account) Balance(ctx context.Context) (hexutil.Big, error) {
 	// get the balance
 	val, err, _ := acc.cg.Do("balance", func() (interface{}, error) {
-		return repository.R().AccountBalance(&acc.Address)
+		return repository.R().AccountBalance(ctx, &acc.Address)
 	})
 
 	// can not get the balance?
@@ -57,9 +58,9 @@ func (acc *Account) Balance() (hexutil.Big, error) {
 }
 
 // TotalValue resolves account total value including delegated amount and pending rewards.
-func (acc *Account) TotalValue() (hexutil.Big, error) {
+func (acc *Account) TotalValue(ctx context.Context) (hexutil.Big, error) {
 	// get the balance
-	balance, err := acc.Balance()
+	balance, err := acc.Balance(ctx)
 	if err != nil {
 		return hexutil.Big{}, err
 	}
@@ -70,9 +71,9 @@ func (acc *Account) TotalValue() (hexutil.Big, error) {
 }
 
 // TxCount resolves the number of transaction sent by the account, also known as nonce.
-func (acc *Account) TxCount() (hexutil.Uint64, error) {
+func (acc *Account) TxCount(ctx context.Context) (hexutil.Uint64, error) {
 	// get the sender by address
-	bal, err := repository.R().AccountNonce(&acc.Address)
+	bal, err := repository.R().AccountNonce(ctx, &acc.Address)
 	if err != nil {
 		return hexutil.Uint64(0), err
 	}
@@ -81,17 +82,25 @@ func (acc *Account) TxCount() (hexutil.Uint64, error) {
 }
 
 // TxList resolves list of transaction associated with the account.
-func (acc *Account) TxList(args struct {
+func (acc *Account) TxList(ctx context.Context, args struct {
 	Recipient *common.Address
 	Cursor    *Cursor
 	Count     int32
+	ListRange
+	TxListFilter
 }) (*TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
 	args.Count = listLimitCount(args.Count, accMaxTransactionsPerRequest)
 
+	// validate the advanced filter
+	tf, err := args.trxFilter()
+	if err != nil {
+		return nil, err
+	}
+
 	// get the transaction hash list from repository
-	bl, err := repository.R().AccountTransactions(&acc.Address, args.Recipient, (*string)(args.Cursor), args.Count)
+	bl, err := repository.R().AccountTransactions(ctx, &acc.Address, args.Recipient, args.rangeFilter(), tf, (*string)(args.Cursor), args.Count)
 	if err != nil {
 		return nil, err
 	}
@@ -100,11 +109,13 @@ func (acc *Account) TxList(args struct {
 }
 
 // Erc20TxList resolves list of ERC20 transactions associated with the account.
-func (acc *Account) Erc20TxList(args struct {
+func (acc *Account) Erc20TxList(ctx context.Context, args struct {
 	Cursor *Cursor
 	Count  int32
 	Token  *common.Address
 	TxType *[]string
+	ListRange
+	TokenTxListFilter
 }) (*ERC20TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -112,11 +123,14 @@ func (acc *Account) Erc20TxList(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC20Token,
 		args.Token,
 		nil,
 		&acc.Address,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		args.tokenTrxFilter(),
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -128,12 +142,13 @@ func (acc *Account) Erc20TxList(args struct {
 }
 
 // Erc721TxList resolves list of ERC721 transactions associated with the account.
-func (acc *Account) Erc721TxList(args struct {
+func (acc *Account) Erc721TxList(ctx context.Context, args struct {
 	Cursor  *Cursor
 	Count   int32
 	Token   *common.Address
 	TokenId *hexutil.Big
 	TxType  *[]string
+	ListRange
 }) (*ERC721TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -141,11 +156,14 @@ func (acc *Account) Erc721TxList(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC721Contract,
 		args.Token,
 		(*big.Int)(args.TokenId),
 		&acc.Address,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		nil,
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -157,12 +175,13 @@ func (acc *Account) Erc721TxList(args struct {
 }
 
 // Erc1155TxList resolves list of ERC1155 transactions associated with the account.
-func (acc *Account) Erc1155TxList(args struct {
+func (acc *Account) Erc1155TxList(ctx context.Context, args struct {
 	Cursor  *Cursor
 	Count   int32
 	Token   *common.Address
 	TokenId *hexutil.Big
 	TxType  *[]string
+	ListRange
 }) (*ERC1155TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -170,11 +189,14 @@ func (acc *Account) Erc1155TxList(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC1155Contract,
 		args.Token,
 		(*big.Int)(args.TokenId),
 		&acc.Address,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		nil,
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -187,14 +209,14 @@ func (acc *Account) Erc1155TxList(args struct {
 
 // Contract resolves the account smart contract detail,
 // if the account is a smart contract address.
-func (acc *Account) Contract() (*Contract, error) {
+func (acc *Account) Contract(ctx context.Context) (*Contract, error) {
 	// is this actually a contract account?
 	if acc.ContractTx == nil {
 		return nil, nil
 	}
 
 	// get new contract
-	con, err := repository.R().Contract(&acc.Address)
+	con, err := loadersOf(ctx).contract(acc.Address)
 	if err != nil {
 		return nil, err
 	}
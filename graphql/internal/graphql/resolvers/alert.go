@@ -0,0 +1,119 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// Alert represents resolvable match of an alert rule on the indexed activity.
+type Alert struct {
+	types.Alert
+}
+
+// AlertList represents resolvable list of alert edges structure.
+type AlertList struct {
+	types.AlertList
+}
+
+// AlertListEdge represents a single edge of an alert list structure.
+type AlertListEdge struct {
+	Alert *Alert
+}
+
+// NewAlert creates a new instance of resolvable alert.
+func NewAlert(a *types.Alert) *Alert {
+	return &Alert{Alert: *a}
+}
+
+// NewAlertList builds new resolvable list of alerts.
+func NewAlertList(al *types.AlertList) *AlertList {
+	return &AlertList{AlertList: *al}
+}
+
+// Alerts resolves list of alerts raised by the alert rules encapsulated in a listable structure.
+func (rs *rootResolver) Alerts(ctx context.Context, args *struct {
+	Cursor *Cursor
+	Count  int32
+}) (*AlertList, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	// limit query size; the count can be either positive or negative
+	// this controls the loading direction
+	args.Count = listLimitCount(args.Count, listMaxEdgesPerRequest)
+
+	al, err := repository.R().Alerts((*string)(args.Cursor), args.Count)
+	if err != nil {
+		log.Errorf("can not get alerts list; %s", err.Error())
+		return nil, err
+	}
+	return NewAlertList(al), nil
+}
+
+// TransactionHash resolves the hash of the transaction which raised the alert.
+func (a *Alert) TransactionHash() common.Hash {
+	return a.Alert.Transaction
+}
+
+// Transaction resolves the transaction which raised the alert, if available.
+func (a *Alert) Transaction(ctx context.Context) (*Transaction, error) {
+	trx, err := repository.R().Transaction(ctx, &a.Alert.Transaction)
+	if err != nil || trx == nil {
+		return nil, nil
+	}
+	return NewTransaction(trx), nil
+}
+
+// BlockNumber resolves the number of the block the alert was raised in.
+func (a *Alert) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(a.Alert.BlockNumber)
+}
+
+// TimeStamp resolves the UNIX time stamp of the block the alert was raised in.
+func (a *Alert) TimeStamp() hexutil.Uint64 {
+	return hexutil.Uint64(a.Alert.TimeStamp.Unix())
+}
+
+// Created resolves the UNIX time stamp of the alert creation.
+func (a *Alert) Created() hexutil.Uint64 {
+	return hexutil.Uint64(a.Alert.Created.Unix())
+}
+
+// TotalCount resolves the total number of alerts in the list.
+func (al *AlertList) TotalCount() hexutil.Big {
+	val := (*hexutil.Big)(new(big.Int).SetUint64(al.Total))
+	return *val
+}
+
+// PageInfo resolves the current page information for the alert list.
+func (al *AlertList) PageInfo() (*ListPageInfo, error) {
+	// do we have any items?
+	if al.Collection == nil || len(al.Collection) == 0 {
+		return NewListPageInfo(nil, nil, false, false)
+	}
+
+	// get the first and last elements
+	first := Cursor(al.Collection[0].ID)
+	last := Cursor(al.Collection[len(al.Collection)-1].ID)
+	return NewListPageInfo(&first, &last, !al.IsEnd, !al.IsStart)
+}
+
+// Edges resolves list of edges for the linked alert list.
+func (al *AlertList) Edges() []*AlertListEdge {
+	edges := make([]*AlertListEdge, len(al.Collection))
+	for i, a := range al.Collection {
+		edges[i] = &AlertListEdge{Alert: NewAlert(a)}
+	}
+	return edges
+}
+
+// Cursor resolves the alert cursor in the edges list.
+func (ale *AlertListEdge) Cursor() Cursor {
+	return Cursor(ale.Alert.ID)
+}
@@ -0,0 +1,245 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"strings"
+)
+
+const (
+	// apiKeyUsageMaxDays represents the max number of days of API key usage loaded at once.
+	apiKeyUsageMaxDays = 366
+
+	// apiKeyMaxNameLength represents the max length of an API key holder name.
+	apiKeyMaxNameLength = 64
+)
+
+// ApiKey represents resolvable API key granting its holder the limits of an access tier.
+type ApiKey struct {
+	types.ApiKey
+
+	// key is the API key itself; available only in response to the key creation
+	key *string
+}
+
+// ApiKeyUsage represents resolvable usage of an API key on a single day.
+type ApiKeyUsage struct {
+	types.ApiKeyUsage
+}
+
+// ApiTier represents resolvable API access tier.
+type ApiTier struct {
+	config.ApiTier
+}
+
+// NewApiKey creates a new instance of resolvable API key.
+func NewApiKey(key *types.ApiKey) *ApiKey {
+	return &ApiKey{ApiKey: *key}
+}
+
+// ApiKeys resolves the list of all the API keys.
+func (rs *rootResolver) ApiKeys(ctx context.Context) ([]*ApiKey, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	kl, err := repository.R().ApiKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*ApiKey, len(kl))
+	for i, k := range kl {
+		list[i] = NewApiKey(k)
+	}
+	return list, nil
+}
+
+// ApiKey resolves the API key of the given ID.
+func (rs *rootResolver) ApiKey(ctx context.Context, args *struct{ Id string }) (*ApiKey, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	k, err := repository.R().ApiKey(args.Id)
+	if err != nil || k == nil {
+		return nil, err
+	}
+	return NewApiKey(k), nil
+}
+
+// ApiTiers resolves the list of the configured API access tiers.
+func (rs *rootResolver) ApiTiers(ctx context.Context) ([]*ApiTier, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	list := make([]*ApiTier, len(cfg.ApiKeys.Tiers))
+	for i, t := range cfg.ApiKeys.Tiers {
+		list[i] = &ApiTier{ApiTier: t}
+	}
+	return list, nil
+}
+
+// AnonymousApiUsage resolves the daily usage of the API by clients without an API key.
+func (rs *rootResolver) AnonymousApiUsage(ctx context.Context, args *struct{ Days int32 }) ([]*ApiKeyUsage, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+	return apiKeyUsage("", args.Days)
+}
+
+// CreateApiKey resolves generation of a new API key of the given holder name and access tier.
+func (rs *rootResolver) CreateApiKey(ctx context.Context, args *struct {
+	Name string
+	Tier string
+}) (*ApiKey, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	name, err := apiKeyName(args.Name)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ApiKeys.Tier(args.Tier) == nil {
+		return nil, fmt.Errorf("unknown API tier %s", args.Tier)
+	}
+
+	id, err := uuid()
+	if err != nil {
+		return nil, err
+	}
+
+	k, key, err := repository.R().CreateApiKey(id, name, args.Tier)
+	if err != nil {
+		log.Warningf("can not create API key; %s", err.Error())
+		return nil, err
+	}
+	return &ApiKey{ApiKey: *k, key: &key}, nil
+}
+
+// UpdateApiKey resolves update of the holder name, the access tier, or the state of an existing API key.
+func (rs *rootResolver) UpdateApiKey(ctx context.Context, args *struct {
+	Id      string
+	Name    *string
+	Tier    *string
+	Enabled *bool
+}) (*ApiKey, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	k, err := repository.R().ApiKey(args.Id)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return nil, fmt.Errorf("API key %s not found", args.Id)
+	}
+
+	if args.Name != nil {
+		if k.Name, err = apiKeyName(*args.Name); err != nil {
+			return nil, err
+		}
+	}
+	if args.Tier != nil {
+		if cfg.ApiKeys.Tier(*args.Tier) == nil {
+			return nil, fmt.Errorf("unknown API tier %s", *args.Tier)
+		}
+		k.Tier = *args.Tier
+	}
+	if args.Enabled != nil {
+		k.Enabled = *args.Enabled
+	}
+
+	if err := repository.R().StoreApiKey(k); err != nil {
+		log.Warningf("can not update API key %s; %s", k.ID, err.Error())
+		return nil, err
+	}
+	return NewApiKey(k), nil
+}
+
+// DeleteApiKey resolves removal of the API key of the given ID together with its usage records.
+func (rs *rootResolver) DeleteApiKey(ctx context.Context, args *struct{ Id string }) (bool, error) {
+	if !isAdmin(ctx) {
+		return false, errAdminRequired
+	}
+
+	if err := repository.R().DeleteApiKey(args.Id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// apiKeyName validates and sanitizes the name of an API key holder.
+func apiKeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > apiKeyMaxNameLength {
+		return "", fmt.Errorf("API key name must be 1 to %d characters long", apiKeyMaxNameLength)
+	}
+	return name, nil
+}
+
+// apiKeyUsage loads the daily usage of the API key of the given ID.
+func apiKeyUsage(id string, days int32) ([]*ApiKeyUsage, error) {
+	if days <= 0 || days > apiKeyUsageMaxDays {
+		days = apiKeyUsageMaxDays
+	}
+
+	ul, err := repository.R().ApiKeyUsage(id, days)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*ApiKeyUsage, len(ul))
+	for i, u := range ul {
+		list[i] = &ApiKeyUsage{ApiKeyUsage: *u}
+	}
+	return list, nil
+}
+
+// Id resolves the ID of the API key.
+func (k *ApiKey) Id() string {
+	return k.ID
+}
+
+// Key resolves the API key itself; available only in response to the key creation.
+func (k *ApiKey) Key() *string {
+	return k.key
+}
+
+// Created resolves the UNIX time stamp of the API key creation.
+func (k *ApiKey) Created() hexutil.Uint64 {
+	return hexutil.Uint64(k.ApiKey.Created.Unix())
+}
+
+// Usage resolves the daily usage of the API key over the given number of the most recent days.
+func (k *ApiKey) Usage(args struct{ Days int32 }) ([]*ApiKeyUsage, error) {
+	return apiKeyUsage(k.ID, args.Days)
+}
+
+// Requests resolves the number of requests served on the day.
+func (u *ApiKeyUsage) Requests() hexutil.Uint64 {
+	return hexutil.Uint64(u.ApiKeyUsage.Requests)
+}
+
+// Rejected resolves the number of requests rejected on the day.
+func (u *ApiKeyUsage) Rejected() hexutil.Uint64 {
+	return hexutil.Uint64(u.ApiKeyUsage.Rejected)
+}
+
+// Burst resolves the max number of requests allowed at once.
+func (t *ApiTier) Burst() int32 {
+	return int32(t.ApiTier.Burst)
+}
+
+// DailyQuota resolves the max number of requests allowed per UTC day.
+func (t *ApiTier) DailyQuota() hexutil.Uint64 {
+	return hexutil.Uint64(t.ApiTier.DailyQuota)
+}
@@ -2,6 +2,7 @@
 package resolvers
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -28,7 +29,7 @@ func NewBlockList(blocks *types.BlockList, totalCount *hexutil.Big) *BlockList {
 }
 
 // Blocks resolves list of blockchain blocks encapsulated in a listable structure.
-func (rs *rootResolver) Blocks(args *struct {
+func (rs *rootResolver) Blocks(ctx context.Context, args *struct {
 	Cursor *Cursor
 	Count  int32
 }) (*BlockList, error) {
@@ -43,7 +44,7 @@ func (rs *rootResolver) Blocks(args *struct {
 	}
 
 	// get the first block so we know the total
-	bh, err := repository.R().BlockHeight()
+	bh, err := repository.R().BlockHeight(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +54,7 @@ func (rs *rootResolver) Blocks(args *struct {
 	args.Count = listLimitCount(args.Count, listMaxEdgesPerRequest)
 
 	// get the block list from repository
-	bl, err := repository.R().Blocks(num, args.Count)
+	bl, err := repository.R().Blocks(ctx, num, args.Count)
 	if err != nil {
 		log.Errorf("can not get blocks list; %s", err.Error())
 		return nil, err
@@ -0,0 +1,57 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+)
+
+// clientIPKey represents the context key of the client IP address.
+type clientIPKey struct{}
+
+// clientTokenKey represents the context key of the client bearer token.
+type clientTokenKey struct{}
+
+// errAdminRequired signals an administrative API call made without valid admin credentials.
+var errAdminRequired = fmt.Errorf("administrative access required")
+
+// WithClientIP attaches the IP address of the API client to the given request context.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// WithClientToken attaches the bearer token of the API client to the given request context.
+func WithClientToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, clientTokenKey{}, token)
+}
+
+// clientIP provides the IP address of the API client attached to the request context;
+// empty if not available.
+func clientIP(ctx context.Context) string {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	if !ok {
+		return ""
+	}
+	return ip
+}
+
+// clientToken provides the bearer token of the API client attached to the request context;
+// empty if not available.
+func clientToken(ctx context.Context) string {
+	token, ok := ctx.Value(clientTokenKey{}).(string)
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+// isAdmin checks if the API client presented the configured admin token.
+// Administrative access is disabled if no admin token is configured.
+func isAdmin(ctx context.Context) bool {
+	token := clientToken(ctx)
+	if cfg.Server.AdminToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Server.AdminToken)) == 1
+}
@@ -2,6 +2,7 @@
 package resolvers
 
 import (
+	"context"
 	"crypto/sha256"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
@@ -70,6 +71,21 @@ type ContractValidationInput struct {
 
 	// SourceCode represents the Solidity source code to be validated.
 	SourceCode string `json:"sourceCode"`
+
+	// CodeFormat represents an optional format of the source code,
+	// i.e. solidity-single-file, or solidity-standard-json-input.
+	CodeFormat *string `json:"codeFormat,omitempty"`
+
+	// CompilerVersion represents an optional compiler release used
+	// to build the contract, i.e. v0.8.17+commit.8df45f5f
+	CompilerVersion *string `json:"compilerVersion,omitempty"`
+
+	// EvmVersion represents an optional target EVM version of the compilation.
+	EvmVersion *string `json:"evmVersion,omitempty"`
+
+	// ConstructorArguments represents optional hex encoded ABI constructor
+	// arguments of the contract deployment.
+	ConstructorArguments *string `json:"constructorArguments,omitempty"`
 }
 
 // NewContract builds new resolvable smart contract structure.
@@ -78,8 +94,8 @@ func NewContract(con *types.Contract) *Contract {
 }
 
 // DeployedBy resolves the deployment transaction of the contract.
-func (con *Contract) DeployedBy() (*Transaction, error) {
-	tr, err := repository.R().Transaction(&con.TransactionHash)
+func (con *Contract) DeployedBy(ctx context.Context) (*Transaction, error) {
+	tr, err := repository.R().Transaction(ctx, &con.TransactionHash)
 	return NewTransaction(tr), err
 }
 
@@ -175,36 +191,67 @@ func updateContractFromInput(con *ContractValidationInput, sc *types.Contract) {
 	}
 }
 
+// compilationFromInput builds the compiler settings of the given validation input.
+func compilationFromInput(con *ContractValidationInput) *types.ContractCompilation {
+	var cc types.ContractCompilation
+	if con.CodeFormat != nil {
+		cc.CodeFormat = *con.CodeFormat
+	}
+	if con.CompilerVersion != nil {
+		cc.Compiler = *con.CompilerVersion
+	}
+	if con.EvmVersion != nil {
+		cc.EvmVersion = *con.EvmVersion
+	}
+	if con.ConstructorArguments != nil {
+		cc.ConstructorArguments = *con.ConstructorArguments
+	}
+	return &cc
+}
+
 // ValidateContract resolves smart contract source code vs. deployed byte code and marks
 // the contract as validated if the match is found. Peer API points are ringed on success
 // to notify them about the change.
-func (rs *rootResolver) ValidateContract(args *struct{ Contract ContractValidationInput }) (*Contract, error) {
+func (rs *rootResolver) ValidateContract(ctx context.Context, args *struct{ Contract ContractValidationInput }) (*Contract, error) {
+	sc, err := rs.validateContract(ctx, &args.Contract)
+	if err != nil {
+		return nil, err
+	}
+	return NewContract(sc), nil
+}
+
+// validateContract validates the contract source code of the given input against the deployed byte code
+// and initiates the contract syncing to peer API points on success.
+func (rs *rootResolver) validateContract(ctx context.Context, in *ContractValidationInput) (*types.Contract, error) {
 	// validate the input
-	if err := isValidationValid(&args.Contract); err != nil {
+	if err := isValidationValid(in); err != nil {
 		log.Errorf("can not validate contract, validation request is not valid; %s", err.Error())
 		return nil, err
 	}
 
 	// get a contract to be validated if any
-	sc, err := repository.R().Contract(&args.Contract.Address)
+	sc, err := repository.R().Contract(ctx, &in.Address)
 	if err != nil {
-		log.Errorf("contract [%s] not found", args.Contract.Address.String())
+		log.Errorf("contract [%s] not found", in.Address.String())
 		return nil, err
 	}
+	if sc == nil {
+		return nil, fmt.Errorf("contract %s not found", in.Address.String())
+	}
 
 	// if we already have this source code, no need to do any updates
-	hash := sourceHash(args.Contract.SourceCode)
+	hash := sourceHash(in.SourceCode)
 	if sc.SourceCodeHash != nil && hash.String() == sc.SourceCodeHash.String() {
 		log.Debugf("contract [%s] source code is already known", sc.Address.String())
-		return NewContract(sc), nil
+		return sc, nil
 	}
 
 	// copy relevant information from input into the contract struct
 	sc.SourceCodeHash = &hash
-	updateContractFromInput(&args.Contract, sc)
+	updateContractFromInput(in, sc)
 
 	// do the validation
-	if err := repository.R().ValidateContract(sc); err != nil {
+	if err := repository.R().ValidateContract(ctx, sc, compilationFromInput(in)); err != nil {
 		log.Errorf("contract validation failed; %s", err.Error())
 		return nil, err
 	}
@@ -214,5 +261,5 @@ func (rs *rootResolver) ValidateContract(args *struct{ Contract ContractValidati
 	go rs.syncContract(*sc)
 
 	// return the final updated contract
-	return NewContract(sc), nil
+	return sc, nil
 }
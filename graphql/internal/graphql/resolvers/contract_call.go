@@ -0,0 +1,61 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ContractCallResult represents a resolvable result of a read-only contract call.
+type ContractCallResult struct {
+	types.ContractCallResult
+}
+
+// ContractCallOutput represents a resolvable typed output value of a read-only contract call.
+type ContractCallOutput struct {
+	types.ContractCallOutput
+}
+
+// CallContract resolves a read-only call of a contract function using the stored contract ABI.
+func (rs *rootResolver) CallContract(ctx context.Context, args *struct {
+	Address common.Address
+	Method  string
+	Args    *[]string
+	Block   *hexutil.Uint64
+}) (*ContractCallResult, error) {
+	// no arguments provided
+	in := make([]string, 0)
+	if args.Args != nil {
+		in = *args.Args
+	}
+
+	res, err := repository.R().CallContract(ctx, &args.Address, args.Method, in, args.Block)
+	if err != nil {
+		log.Debugf("can not call %s on %s; %s", args.Method, args.Address.String(), err.Error())
+		return nil, err
+	}
+	return &ContractCallResult{ContractCallResult: *res}, nil
+}
+
+// Outputs resolves the list of typed output values of the call.
+func (cr *ContractCallResult) Outputs() []*ContractCallOutput {
+	list := make([]*ContractCallOutput, len(cr.ContractCallResult.Outputs))
+	for i, out := range cr.ContractCallResult.Outputs {
+		list[i] = &ContractCallOutput{ContractCallOutput: *out}
+	}
+	return list
+}
+
+// Value resolves the output value as a JSON value keeping the structure of arrays and tuples.
+func (co *ContractCallOutput) Value() JSON {
+	return JSON{Value: co.ContractCallOutput.Value}
+}
+
+// ViewFunctions resolves the list of read-only functions of the contract
+// declared by the contract ABI.
+func (con *Contract) ViewFunctions(ctx context.Context) []*types.ContractFunction {
+	return repository.R().ContractViewFunctions(ctx, &con.Address)
+}
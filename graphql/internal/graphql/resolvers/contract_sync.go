@@ -5,8 +5,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
@@ -14,46 +21,204 @@ import (
 const (
 	// contractSyncMutationQuery represents the mutation GraphQL query used
 	// to synchronize contract validation with API peers.
-	contractSyncMutationQuery = "mutation($sc:ContractValidationInput!) { validateContract(contract: $sc) { validated } }"
+	contractSyncMutationQuery = "mutation($sc:ContractSyncInput!) { syncContract(contract: $sc) { validated } }"
 
 	// contractSyncCallTimeout represents a time out value used for contract
 	// syncing GraphQL calls.
 	contractSyncCallTimeout = 60 * time.Second
+
+	// contractSyncRetryInterval represents the interval of checking
+	// for failed contract syncs to be retried.
+	contractSyncRetryInterval = 1 * time.Minute
+
+	// contractSyncRetryDelay represents the initial delay of a failed contract sync retry.
+	// The delay doubles with each failed attempt.
+	contractSyncRetryDelay = 1 * time.Minute
+
+	// contractSyncMaxRetryDelay represents the max delay between contract sync retries.
+	contractSyncMaxRetryDelay = 6 * time.Hour
+
+	// contractSyncMaxAttempts represents the max number of attempts to sync a contract
+	// to a peer before the sync is abandoned.
+	contractSyncMaxAttempts = 12
+
+	// contractSyncMaxAge represents the max age of a contract sync signature accepted;
+	// the same tolerance applies to signatures issued ahead of the local time.
+	contractSyncMaxAge = 5 * time.Minute
 )
 
-// getContractSyncInput prepares input structure used for contract syncing
-// across peer API points.
-func contractSyncInput(con *types.Contract) ContractValidationInput {
-	// prep the validation input to be synced
-	var cInput = ContractValidationInput{
-		Address:      con.Address,
-		Name:         &con.Name,
-		SourceCode:   con.SourceCode,
-		OptimizeRuns: con.OptimizeRuns,
-		Optimized:    con.IsOptimized,
+// ContractSyncInput represents an input structure used to synchronize
+// a validated contract with API peers. The sync is signed by the sending peer.
+type ContractSyncInput struct {
+	// Address represents the deployment address of the validated contract.
+	Address common.Address `json:"address"`
+
+	// Name represents the name of the contract.
+	Name string `json:"name"`
+
+	// Version represents the version of the contract.
+	Version string `json:"version"`
+
+	// SupportContact represents the contract support contact.
+	SupportContact string `json:"supportContact"`
+
+	// License represents the contract open source license.
+	License string `json:"license"`
+
+	// Compiler represents the identifier of the compiler used to validate the contract.
+	Compiler string `json:"compiler"`
+
+	// Optimized signals that the contract byte code was optimized during compilation.
+	Optimized bool `json:"optimized"`
+
+	// OptimizeRuns represents number of optimization runs used during the contract compilation.
+	OptimizeRuns int32 `json:"optimizeRuns"`
+
+	// SourceCode represents the validated Solidity source code.
+	SourceCode string `json:"sourceCode"`
+
+	// Abi represents the ABI definition of the validated contract.
+	Abi string `json:"abi"`
+
+	// Validated represents the unix timestamp of the contract validation.
+	Validated hexutil.Uint64 `json:"validated"`
+
+	// Issued represents the unix timestamp of the sync signature.
+	Issued hexutil.Uint64 `json:"issued"`
+
+	// Audience represents the host of the API peer the sync is addressed to; the peer
+	// accepts only syncs addressed to its configured sync audience.
+	Audience string `json:"audience"`
+
+	// Signer represents the address of the peer signing the sync.
+	Signer common.Address `json:"signer"`
+
+	// Signature represents the signature of the sync made by the signer.
+	Signature hexutil.Bytes `json:"signature,omitempty"`
+}
+
+// ContractSyncStatus represents resolvable state of a contract validation sync to an API peer.
+type ContractSyncStatus struct {
+	types.ContractSync
+}
+
+// hash calculates the hash of the sync input signed by the sending peer.
+func (in ContractSyncInput) hash() ([]byte, error) {
+	in.Signature = nil
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(data), nil
+}
+
+// sign signs the sync input with the private key of this API server.
+func (in *ContractSyncInput) sign() error {
+	if cfg.Signature.PrivateKey == nil {
+		return fmt.Errorf("server signature key not available")
+	}
+
+	in.Signer = crypto.PubkeyToAddress(cfg.Signature.PrivateKey.PublicKey)
+	hash, err := in.hash()
+	if err != nil {
+		return err
+	}
+
+	in.Signature, err = crypto.Sign(hash, cfg.Signature.PrivateKey)
+	return err
+}
+
+// verify checks the sync input is signed by the declared signer, the signer is a trusted API peer,
+// and the sync is a recent one addressed to this API server, so a captured sync can not be replayed.
+func (in *ContractSyncInput) verify() error {
+	if !isTrustedPeerSigner(&in.Signer) {
+		return fmt.Errorf("contract sync signer %s is not trusted", in.Signer.String())
+	}
+
+	if cfg.Server.SyncAudience == "" || !strings.EqualFold(in.Audience, cfg.Server.SyncAudience) {
+		return fmt.Errorf("contract sync addressed to %s", in.Audience)
+	}
+
+	issued := time.Unix(int64(in.Issued), 0)
+	if time.Since(issued) > contractSyncMaxAge || time.Until(issued) > contractSyncMaxAge {
+		return fmt.Errorf("contract sync issued at %s is stale", issued.UTC().Format(time.RFC3339))
+	}
+
+	hash, err := in.hash()
+	if err != nil {
+		return err
+	}
+
+	pk, err := crypto.SigToPub(hash, in.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid contract sync signature; %s", err.Error())
+	}
+
+	if crypto.PubkeyToAddress(*pk) != in.Signer {
+		return fmt.Errorf("contract sync signature does not match signer %s", in.Signer.String())
+	}
+	return nil
+}
+
+// isTrustedPeerSigner checks if the given address is on the allow-list of API peer signers.
+func isTrustedPeerSigner(addr *common.Address) bool {
+	for _, ps := range cfg.Server.PeerSigners {
+		if ps == *addr {
+			return true
+		}
 	}
+	return false
+}
 
-	// transfer compiler version info, if any
-	if 0 < len(con.Version) {
-		cInput.Version = &con.Version
+// contractSyncAudience provides the audience of a contract sync sent to the given peer;
+// the domain address of the peer.
+func contractSyncAudience(peer string) string {
+	u, err := url.Parse(peer)
+	if err != nil || u.Host == "" {
+		return peer
 	}
+	return u.Host
+}
 
-	// transfer support contact info, if any
-	if 0 < len(con.SupportContact) {
-		cInput.SupportContact = &con.SupportContact
+// contractSyncInput prepares an input structure used for contract syncing
+// across peer API points. The input is signed for each peer separately, see signFor().
+func contractSyncInput(con *types.Contract) *ContractSyncInput {
+	// prep the sync input
+	var cInput = ContractSyncInput{
+		Address:        con.Address,
+		Name:           con.Name,
+		Version:        con.Version,
+		SupportContact: con.SupportContact,
+		License:        con.License,
+		Compiler:       con.Compiler,
+		Optimized:      con.IsOptimized,
+		OptimizeRuns:   con.OptimizeRuns,
+		SourceCode:     con.SourceCode,
+		Abi:            con.Abi,
+		Validated:      hexutil.Uint64(time.Now().UTC().Unix()),
 	}
 
-	// transfer contact open source license, if any
-	if 0 < len(con.License) {
-		cInput.License = &con.License
+	// transfer the validation time stamp, if any
+	if con.Validated != nil {
+		cInput.Validated = *con.Validated
 	}
+	return &cInput
+}
 
-	return cInput
+// signFor addresses the sync input to the given peer and signs it.
+func (in *ContractSyncInput) signFor(peer string) error {
+	in.Audience = contractSyncAudience(peer)
+	in.Issued = hexutil.Uint64(time.Now().UTC().Unix())
+	return in.sign()
 }
 
-// constructMutation creates the GraphQL mutation query string
-// for the contract provided.
-func constructMutationPayload(con *types.Contract) (bytes.Buffer, error) {
+// constructMutationPayload creates the GraphQL mutation query payload
+// of the given contract sync input signed for the given peer.
+func constructMutationPayload(in *ContractSyncInput, peer string) ([]byte, error) {
+	if err := in.signFor(peer); err != nil {
+		return nil, err
+	}
+
 	// prepare the payload
 	payload := struct {
 		Query     string                 `json:"query"`
@@ -61,18 +226,100 @@ func constructMutationPayload(con *types.Contract) (bytes.Buffer, error) {
 	}{
 		Query: contractSyncMutationQuery,
 		Variables: map[string]interface{}{
-			"sc": contractSyncInput(con),
+			"sc": in,
 		},
 	}
 
-	// encode the mutation into the output buffer
-	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(payload)
+	return json.Marshal(payload)
+}
+
+// SyncContract stores a validated contract received from a trusted API peer.
+// The contract source code is not re-compiled, the signature of the peer is verified instead.
+func (rs *rootResolver) SyncContract(ctx context.Context, args *struct{ Contract ContractSyncInput }) (*Contract, error) {
+	in := &args.Contract
+	if err := in.verify(); err != nil {
+		log.Errorf("contract %s sync rejected; %s", in.Address.String(), err.Error())
+		return nil, err
+	}
+
+	// get the contract to be updated
+	sc, err := repository.R().Contract(ctx, &in.Address)
 	if err != nil {
-		return buf, err
+		log.Errorf("contract [%s] not available; %s", in.Address.String(), err.Error())
+		return nil, err
+	}
+	if sc == nil {
+		return nil, fmt.Errorf("contract %s not found", in.Address.String())
 	}
 
-	return buf, nil
+	// if we already have this source code, no need to do any updates
+	hash := sourceHash(in.SourceCode)
+	if sc.SourceCodeHash != nil && hash.String() == sc.SourceCodeHash.String() {
+		log.Debugf("contract [%s] source code is already known", sc.Address.String())
+		return NewContract(sc), nil
+	}
+
+	// never roll the contract back to an older validation
+	if sc.Validated != nil && in.Validated <= *sc.Validated {
+		log.Errorf("contract %s sync rejected; validation is not newer than the known one", in.Address.String())
+		return nil, fmt.Errorf("contract %s validation is not newer than the known one", in.Address.String())
+	}
+
+	// copy the validated contract details
+	sc.SourceCodeHash = &hash
+	sc.SourceCode = in.SourceCode
+	sc.Name = in.Name
+	sc.Version = in.Version
+	sc.SupportContact = in.SupportContact
+	sc.License = in.License
+	sc.Compiler = in.Compiler
+	sc.IsOptimized = in.Optimized
+	sc.OptimizeRuns = in.OptimizeRuns
+	sc.Abi = in.Abi
+	sc.Validated = &in.Validated
+
+	if err := repository.R().StoreContract(sc); err != nil {
+		log.Errorf("can not store synced contract %s; %s", sc.Address.String(), err.Error())
+		return nil, err
+	}
+
+	log.Noticef("contract %s synced from %s", sc.Address.String(), in.Signer.String())
+	return NewContract(sc), nil
+}
+
+// ContractSyncStatus resolves the list of contract validation syncs of the given contract.
+// If the contract is not specified, the syncs not finished yet are provided.
+func (rs *rootResolver) ContractSyncStatus(args struct{ Address *common.Address }) ([]*ContractSyncStatus, error) {
+	list, err := repository.R().ContractSyncs(args.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*ContractSyncStatus, len(list))
+	for i, cs := range list {
+		out[i] = &ContractSyncStatus{ContractSync: *cs}
+	}
+	return out, nil
+}
+
+// Address resolves the address of the synced contract.
+func (cs *ContractSyncStatus) Address() common.Address {
+	return common.HexToAddress(cs.Contract)
+}
+
+// LastAttempt resolves the unix time stamp of the last sync attempt.
+func (cs *ContractSyncStatus) LastAttempt() hexutil.Uint64 {
+	return hexutil.Uint64(cs.ContractSync.LastAttempt.Unix())
+}
+
+// NextAttempt resolves the unix time stamp of the next sync attempt, if any is scheduled.
+func (cs *ContractSyncStatus) NextAttempt() *hexutil.Uint64 {
+	if cs.Status != types.ContractSyncStatusPending {
+		return nil
+	}
+
+	ts := hexutil.Uint64(cs.ContractSync.NextAttempt.Unix())
+	return &ts
 }
 
 // SyncContract synchronizes contract across all the peers in the API network.
@@ -83,8 +330,8 @@ func (rs *rootResolver) syncContract(con types.Contract) {
 		return
 	}
 
-	// construct the payload
-	payload, err := constructMutationPayload(&con)
+	// the sync input is kept unsigned; each attempt signs it for the peer
+	payload, err := json.Marshal(contractSyncInput(&con))
 	if err != nil {
 		log.Errorf("can not construct the sync payload; %s", err.Error())
 		return
@@ -99,7 +346,15 @@ func (rs *rootResolver) syncContract(con types.Contract) {
 		wg.Add(1)
 
 		// run the sync
-		go syncContractToPeer(&payload, peer, cfg.Server.DomainAddress, &wg)
+		go func(peer string) {
+			defer wg.Done()
+			syncContractAttempt(&types.ContractSync{
+				ID:       types.ContractSyncID(peer, &con.Address),
+				Peer:     peer,
+				Contract: con.Address.String(),
+				Payload:  payload,
+			})
+		}(peer)
 	}
 
 	// wait for all the sync to finish
@@ -110,29 +365,90 @@ func (rs *rootResolver) syncContract(con types.Contract) {
 	log.Debugf("validation syncing finished")
 }
 
+// retryContractSync retries pending contract syncs which failed before.
+func (rs *rootResolver) retryContractSync() {
+	list, err := repository.R().ContractSyncsDue()
+	if err != nil {
+		log.Errorf("can not load contract syncs to retry; %s", err.Error())
+		return
+	}
+
+	for _, cs := range list {
+		log.Debugf("retrying contract %s sync to %s", cs.Contract, cs.Peer)
+		syncContractAttempt(cs)
+	}
+}
+
+// syncContractAttempt makes an attempt to sync the contract to the peer
+// and stores the result so failed syncs can be retried later.
+func syncContractAttempt(cs *types.ContractSync) {
+	cs.Attempts++
+	cs.LastAttempt = time.Now().UTC()
+
+	err := syncContractPayload(cs)
+	switch {
+	case err == nil:
+		cs.Status = types.ContractSyncStatusSynced
+		cs.LastError = ""
+	case cs.Attempts >= contractSyncMaxAttempts:
+		log.Errorf("contract %s sync to %s abandoned after %d attempts", cs.Contract, cs.Peer, cs.Attempts)
+		cs.Status = types.ContractSyncStatusFailed
+		cs.LastError = err.Error()
+	default:
+		cs.Status = types.ContractSyncStatusPending
+		cs.LastError = err.Error()
+		cs.NextAttempt = cs.LastAttempt.Add(contractSyncBackoff(cs.Attempts))
+	}
+
+	if err := repository.R().StoreContractSync(cs); err != nil {
+		log.Errorf("can not store contract %s sync to %s; %s", cs.Contract, cs.Peer, err.Error())
+	}
+}
+
+// syncContractPayload signs the contract sync input for the peer of the given sync and sends it.
+func syncContractPayload(cs *types.ContractSync) error {
+	var in ContractSyncInput
+	if err := json.Unmarshal(cs.Payload, &in); err != nil {
+		return err
+	}
+	if in.Address.String() != cs.Contract {
+		return fmt.Errorf("invalid contract %s sync payload", cs.Contract)
+	}
+
+	payload, err := constructMutationPayload(&in, cs.Peer)
+	if err != nil {
+		return err
+	}
+	return syncContractToPeer(payload, cs.Peer, cfg.Server.DomainAddress)
+}
+
+// contractSyncBackoff calculates the delay of the next retry after the given number of failed attempts.
+func contractSyncBackoff(attempts int32) time.Duration {
+	delay := contractSyncRetryDelay
+	for i := int32(1); i < attempts && delay < contractSyncMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > contractSyncMaxRetryDelay {
+		return contractSyncMaxRetryDelay
+	}
+	return delay
+}
+
 // syncContractToPeer performs the syncing call for the contract validation.
-func syncContractToPeer(payload *bytes.Buffer, peer string, origin string, wg *sync.WaitGroup) {
+func syncContractToPeer(payload []byte, peer string, origin string) error {
 	// log action
 	log.Debugf("syncing contract validation to %s from %s", peer, origin)
 
-	// make a context with predefined timeout, we don't use the cancel func callback
+	// make a context with predefined timeout
 	ctx, cancel := context.WithTimeout(context.Background(), contractSyncCallTimeout)
-
-	// don't forget to sign off after we are done
-	defer func() {
-		// log finish
-		cancel()
-		log.Noticef("syncing %s finished", peer)
-
-		// signal to wait group we are done
-		wg.Done()
-	}()
+	defer cancel()
 
 	// create the request
-	req, err := http.NewRequestWithContext(ctx, "POST", peer, payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", peer, bytes.NewReader(payload))
 	if err != nil {
 		log.Errorf("can not create new POST request for %s peer", peer)
-		return
+		return err
 	}
 
 	// set headers so we can pass the payload correctly
@@ -146,15 +462,38 @@ func syncContractToPeer(payload *bytes.Buffer, peer string, origin string, wg *s
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Errorf("can not finalize syncing request for %s peer; %s", peer, err.Error())
-		return
+		return err
 	}
 
+	// make sure to close the body
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Errorf("can not close syncing response of %s peer; %s", peer, err.Error())
+		}
+	}()
+
 	// log error code response
 	if 200 != resp.StatusCode {
 		log.Errorf("syncing request to %s has been rejected with code %d", peer, resp.StatusCode)
-		return
+		return fmt.Errorf("rejected with code %d", resp.StatusCode)
+	}
+
+	// GraphQL errors are reported with the success code
+	var res struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		log.Errorf("can not decode syncing response of %s peer; %s", peer, err.Error())
+		return err
+	}
+	if len(res.Errors) > 0 {
+		log.Errorf("syncing request to %s failed; %s", peer, res.Errors[0].Message)
+		return fmt.Errorf("%s", res.Errors[0].Message)
 	}
 
 	// success
-	log.Debugf("syncing request to %s finished with success", peer)
+	log.Noticef("syncing request to %s finished with success", peer)
+	return nil
 }
@@ -0,0 +1,145 @@
+package resolvers
+
+import (
+	"fantom-api-graphql/internal/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+// setContractSyncTestConfig sets a configuration of a peer signing contract syncs for itself.
+func setContractSyncTestConfig(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("can not generate key; %s", err.Error())
+	}
+
+	cfg = &config.Config{
+		Signature: config.ServerSignature{PrivateKey: pk},
+		Server: config.Server{
+			DomainAddress: "localhost:16761",
+			SyncAudience:  "xapi.example.com",
+			PeerSigners:   []common.Address{crypto.PubkeyToAddress(pk.PublicKey)},
+		},
+	}
+}
+
+func TestContractSyncVerify(t *testing.T) {
+	setContractSyncTestConfig(t)
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("can not generate key; %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(in *ContractSyncInput)
+		resign     bool
+		noAudience bool
+		valid      bool
+	}{
+		{name: "valid", valid: true},
+		{name: "audience not configured", noAudience: true},
+		{
+			name:   "other audience",
+			modify: func(in *ContractSyncInput) { in.Audience = "xapi.other.com" },
+			resign: true,
+		},
+		{
+			name:   "stale",
+			modify: func(in *ContractSyncInput) { in.Issued -= hexutil.Uint64(contractSyncMaxAge/time.Second + 1) },
+			resign: true,
+		},
+		{
+			name:   "issued ahead",
+			modify: func(in *ContractSyncInput) { in.Issued += hexutil.Uint64(contractSyncMaxAge/time.Second + 60) },
+			resign: true,
+		},
+		{
+			name:   "tampered source",
+			modify: func(in *ContractSyncInput) { in.SourceCode = "contract B {}" },
+		},
+		{
+			name:   "tampered issue time",
+			modify: func(in *ContractSyncInput) { in.Issued-- },
+		},
+		{
+			name: "untrusted signer",
+			modify: func(in *ContractSyncInput) {
+				in.Signer = crypto.PubkeyToAddress(other.PublicKey)
+				hash, _ := in.hash()
+				in.Signature, _ = crypto.Sign(hash, other)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			in := ContractSyncInput{
+				Address:    common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9"),
+				SourceCode: "contract A {}",
+				Validated:  hexutil.Uint64(time.Now().Unix()),
+			}
+			g.Expect(in.signFor("https://xapi.example.com/api")).To(gomega.BeNil())
+			g.Expect(in.Audience).To(gomega.Equal("xapi.example.com"))
+
+			if tt.modify != nil {
+				tt.modify(&in)
+			}
+			if tt.resign {
+				g.Expect(in.sign()).To(gomega.BeNil())
+			}
+
+			if tt.noAudience {
+				cfg.Server.SyncAudience = ""
+				defer func() { cfg.Server.SyncAudience = in.Audience }()
+			}
+
+			if tt.valid {
+				g.Expect(in.verify()).To(gomega.BeNil())
+			} else {
+				g.Expect(in.verify()).ToNot(gomega.BeNil())
+			}
+		})
+	}
+}
+
+func TestContractSyncAudience(t *testing.T) {
+	tests := []struct {
+		peer string
+		want string
+	}{
+		{peer: "https://xapi.example.com/api", want: "xapi.example.com"},
+		{peer: "http://localhost:16761/api", want: "localhost:16761"},
+		{peer: "localhost:16761", want: "localhost:16761"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.peer, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(contractSyncAudience(tt.peer)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestContractSyncBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: contractSyncRetryDelay},
+		{attempts: 2, want: 2 * contractSyncRetryDelay},
+		{attempts: 5, want: 16 * contractSyncRetryDelay},
+		{attempts: 12, want: contractSyncMaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		g := gomega.NewGomegaWithT(t)
+		g.Expect(contractSyncBackoff(tt.attempts)).To(gomega.Equal(tt.want))
+	}
+}
@@ -0,0 +1,85 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+	"time"
+)
+
+// contractVerificationInterval represents the interval of checking
+// for contract verification jobs waiting in the queue.
+const contractVerificationInterval = 10 * time.Second
+
+// processContractVerifications processes contract verification jobs waiting in the queue
+// until the queue is empty.
+func (rs *rootResolver) processContractVerifications() {
+	for {
+		cv, err := repository.R().NextContractVerification()
+		if err != nil {
+			log.Errorf("can not load contract verification job; %s", err.Error())
+			return
+		}
+		if cv == nil {
+			return
+		}
+
+		rs.processContractVerification(cv)
+	}
+}
+
+// processContractVerification validates the contract of the given verification job
+// and stores the outcome of the job.
+func (rs *rootResolver) processContractVerification(cv *types.ContractVerification) {
+	log.Debugf("processing contract %s verification %s", cv.Contract, cv.GUID)
+
+	_, err := rs.validateContract(context.Background(), contractVerificationInput(cv))
+	if err != nil {
+		cv.Status = types.ContractVerificationStatusFail
+		cv.Error = err.Error()
+	} else {
+		cv.Status = types.ContractVerificationStatusPass
+		cv.Error = ""
+	}
+	cv.Finished = time.Now().UTC()
+
+	if err := repository.R().StoreContractVerification(cv); err != nil {
+		log.Errorf("can not store contract %s verification %s; %s", cv.Contract, cv.GUID, err.Error())
+	}
+}
+
+// contractVerificationInput builds the contract validation input of the given verification job.
+func contractVerificationInput(cv *types.ContractVerification) *ContractValidationInput {
+	in := ContractValidationInput{
+		Address:      common.HexToAddress(cv.Contract),
+		Optimized:    cv.Optimized,
+		OptimizeRuns: cv.OptimizeRuns,
+		SourceCode:   cv.SourceCode,
+	}
+
+	// the name may be qualified by the source file path, i.e. contracts/Token.sol:Token
+	if cv.Name != "" {
+		name := cv.Name[strings.LastIndex(cv.Name, ":")+1:]
+		in.Name = &name
+	}
+
+	if cv.License != "" {
+		in.License = &cv.License
+	}
+	if cv.CodeFormat != "" {
+		in.CodeFormat = &cv.CodeFormat
+	}
+	if cv.Compiler != "" {
+		in.CompilerVersion = &cv.Compiler
+	}
+	if cv.EvmVersion != "" {
+		in.EvmVersion = &cv.EvmVersion
+	}
+	if cv.ConstructorArguments != "" {
+		in.ConstructorArguments = &cv.ConstructorArguments
+	}
+	return &in
+}
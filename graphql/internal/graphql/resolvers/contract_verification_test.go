@@ -0,0 +1,56 @@
+package resolvers
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestContractVerificationInput(t *testing.T) {
+	tests := []struct {
+		name string
+		cv   types.ContractVerification
+		want types.ContractCompilation
+	}{
+		{
+			name: "single file",
+			cv:   types.ContractVerification{Name: "Token", CodeFormat: "solidity-single-file", SourceCode: "contract Token {}"},
+			want: types.ContractCompilation{CodeFormat: "solidity-single-file"},
+		},
+		{
+			name: "standard json input",
+			cv: types.ContractVerification{
+				Name:                 "contracts/Token.sol:Token",
+				CodeFormat:           "solidity-standard-json-input",
+				SourceCode:           `{"language":"Solidity"}`,
+				Compiler:             "v0.8.17+commit.8df45f5f",
+				EvmVersion:           "london",
+				ConstructorArguments: "0000000000000000000000000000000000000000000000000000000000000001",
+			},
+			want: types.ContractCompilation{
+				CodeFormat:           "solidity-standard-json-input",
+				Compiler:             "v0.8.17+commit.8df45f5f",
+				EvmVersion:           "london",
+				ConstructorArguments: "0000000000000000000000000000000000000000000000000000000000000001",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			tt.cv.Contract = "0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9"
+			tt.cv.Optimized, tt.cv.OptimizeRuns = true, 1000
+
+			in := contractVerificationInput(&tt.cv)
+			g.Expect(in.Address).To(gomega.Equal(common.HexToAddress(tt.cv.Contract)))
+			g.Expect(*in.Name).To(gomega.Equal("Token"))
+			g.Expect(in.SourceCode).To(gomega.Equal(tt.cv.SourceCode))
+			g.Expect(in.Optimized).To(gomega.BeTrue())
+			g.Expect(in.OptimizeRuns).To(gomega.Equal(int32(1000)))
+			g.Expect(*compilationFromInput(in)).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package resolvers
 
 import (
-	"fantom-api-graphql/internal/repository"
+	"context"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -22,9 +22,9 @@ func (trx *ERC20Transaction) TrxHash() common.Hash {
 }
 
 // Transaction resolves an instance of the transaction executing the ERC20 call.
-func (trx *ERC20Transaction) Transaction() (*Transaction, error) {
+func (trx *ERC20Transaction) Transaction(ctx context.Context) (*Transaction, error) {
 	// get the transaction from repo
-	tx, err := repository.R().Transaction(&trx.TokenTransaction.Transaction)
+	tx, err := loadersOf(ctx).transaction(trx.TokenTransaction.Transaction)
 	if err != nil {
 		return nil, err
 	}
@@ -32,8 +32,12 @@ func (trx *ERC20Transaction) Transaction() (*Transaction, error) {
 }
 
 // Token resolves instance of the ERC20 token involved.
-func (trx *ERC20Transaction) Token() *ERC20Token {
-	return NewErc20Token(&trx.TokenAddress)
+func (trx *ERC20Transaction) Token(ctx context.Context) *ERC20Token {
+	token, err := loadersOf(ctx).erc20Token(trx.TokenAddress)
+	if err != nil {
+		return nil
+	}
+	return &ERC20Token{*token}
 }
 
 // TrxType resolves the type of the ERC20 transaction.
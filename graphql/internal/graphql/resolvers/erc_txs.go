@@ -1,6 +1,7 @@
 package resolvers
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -9,12 +10,13 @@ import (
 )
 
 // Erc20Transactions resolves list of ERC20 transactions.
-func (rs *rootResolver) Erc20Transactions(args struct {
+func (rs *rootResolver) Erc20Transactions(ctx context.Context, args struct {
 	Cursor  *Cursor
 	Count   int32
 	Token   *common.Address
 	Account *common.Address
 	TxType  *[]string
+	ListRange
 }) (*ERC20TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -22,11 +24,14 @@ func (rs *rootResolver) Erc20Transactions(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC20Token,
 		args.Token,
 		nil,
 		args.Account,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		nil,
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -38,13 +43,14 @@ func (rs *rootResolver) Erc20Transactions(args struct {
 }
 
 // Erc721Transactions resolves list of ERC721 transactions.
-func (rs *rootResolver) Erc721Transactions(args struct {
+func (rs *rootResolver) Erc721Transactions(ctx context.Context, args struct {
 	Cursor  *Cursor
 	Count   int32
 	Token   *common.Address
 	TokenId *hexutil.Big
 	Account *common.Address
 	TxType  *[]string
+	ListRange
 }) (*ERC721TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -52,11 +58,14 @@ func (rs *rootResolver) Erc721Transactions(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC721Contract,
 		args.Token,
 		(*big.Int)(args.TokenId),
 		args.Account,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		nil,
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -68,13 +77,14 @@ func (rs *rootResolver) Erc721Transactions(args struct {
 }
 
 // Erc1155Transactions resolves list of ERC1155 transactions.
-func (rs *rootResolver) Erc1155Transactions(args struct {
+func (rs *rootResolver) Erc1155Transactions(ctx context.Context, args struct {
 	Cursor  *Cursor
 	Count   int32
 	Token   *common.Address
 	TokenId *hexutil.Big
 	Account *common.Address
 	TxType  *[]string
+	ListRange
 }) (*ERC1155TransactionList, error) {
 	// limit query size; the count can be either positive or negative
 	// this controls the loading direction
@@ -82,11 +92,14 @@ func (rs *rootResolver) Erc1155Transactions(args struct {
 
 	// get the transaction hash list from repository
 	tl, err := repository.R().TokenTransactions(
+		ctx,
 		types.AccountTypeERC1155Contract,
 		args.Token,
 		(*big.Int)(args.TokenId),
 		args.Account,
 		ercTrxTypesFromNames(args.TxType),
+		args.rangeFilter(),
+		nil,
 		(*string)(args.Cursor),
 		args.Count,
 	)
@@ -0,0 +1,80 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// faucetRequestsMaxCount represents the max number of faucet requests loaded at once.
+const faucetRequestsMaxCount = 100
+
+// FaucetRequest represents resolvable faucet transfer of native tokens.
+type FaucetRequest struct {
+	types.FaucetRequest
+}
+
+// NewFaucetRequest creates a new instance of resolvable faucet request.
+func NewFaucetRequest(fr *types.FaucetRequest) *FaucetRequest {
+	return &FaucetRequest{FaucetRequest: *fr}
+}
+
+// RequestFunds resolves a request for native tokens sent by the faucet to the given address.
+func (rs *rootResolver) RequestFunds(ctx context.Context, args *struct{ Address common.Address }) (*FaucetRequest, error) {
+	ip := clientIP(ctx)
+	if ip == "" {
+		return nil, fmt.Errorf("client address not available")
+	}
+
+	fr, err := repository.R().RequestFunds(ctx, &args.Address, ip)
+	if err != nil {
+		log.Warningf("faucet request for %s from %s refused; %s", args.Address.String(), ip, err.Error())
+		return nil, err
+	}
+	return NewFaucetRequest(fr), nil
+}
+
+// FaucetRequests resolves the list of the most recent faucet requests,
+// optionally limited to the given recipient address.
+func (rs *rootResolver) FaucetRequests(ctx context.Context, args *struct {
+	Address *common.Address
+	Count   int32
+}) ([]*FaucetRequest, error) {
+	if args.Count <= 0 || args.Count > faucetRequestsMaxCount {
+		args.Count = faucetRequestsMaxCount
+	}
+
+	fl, err := repository.R().FaucetRequests(ctx, args.Address, args.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*FaucetRequest, len(fl))
+	for i, fr := range fl {
+		list[i] = NewFaucetRequest(fr)
+	}
+	return list, nil
+}
+
+// Hash resolves the hash of the faucet transfer transaction.
+func (fr *FaucetRequest) Hash() common.Hash {
+	return fr.FaucetRequest.Transaction
+}
+
+// TimeStamp resolves the UNIX time stamp of the faucet request.
+func (fr *FaucetRequest) TimeStamp() hexutil.Uint64 {
+	return hexutil.Uint64(fr.FaucetRequest.TimeStamp.Unix())
+}
+
+// Transaction resolves the faucet transfer transaction; nil if not available yet.
+func (fr *FaucetRequest) Transaction(ctx context.Context) (*Transaction, error) {
+	trx, err := repository.R().Transaction(ctx, &fr.FaucetRequest.Transaction)
+	if err != nil || trx == nil {
+		return nil, nil
+	}
+	return NewTransaction(trx), nil
+}
@@ -0,0 +1,83 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// storeFeedCapacity represents the capacity of the shared store events queue.
+const storeFeedCapacity = 500
+
+// followStore feeds the subscriptions from the shared store.
+// It's used if the API runs without the indexer; the indexer of another instance
+// fills the store and the repository picks the new records up.
+func (rs *rootResolver) followStore() {
+	defer rs.wgFeed.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan *types.StoreEvent, storeFeedCapacity)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		repository.R().FollowStore(ctx, events)
+	}()
+
+	log.Notice("subscriptions follow the shared store")
+
+	var lastBlock hexutil.Uint64
+	for {
+		select {
+		case <-rs.sigFeedStop:
+			cancel()
+			<-done
+			return
+		case ev := <-events:
+			switch {
+			case ev.Block != nil:
+				rs.feedBlock(ctx, ev.Block.Number, &lastBlock)
+			case ev.Transaction != nil:
+				rs.feedTransaction(ctx, ev.Transaction, &lastBlock)
+			case ev.TokenTransaction != nil:
+				rs.onTokenTrxEvents <- ev.TokenTransaction
+			case ev.Submitted != nil:
+				rs.onTrxStatusEvents <- ev.Submitted
+			}
+		}
+	}
+}
+
+// feedBlock broadcasts the new block of the given number, if not seen yet.
+// The store does not keep the block details, the full block is loaded.
+func (rs *rootResolver) feedBlock(ctx context.Context, num hexutil.Uint64, lastBlock *hexutil.Uint64) {
+	if num <= *lastBlock {
+		return
+	}
+	*lastBlock = num
+
+	blk, err := repository.R().BlockByNumber(ctx, &num)
+	if err != nil {
+		log.Errorf("can not load new block #%d; %s", uint64(num), err.Error())
+		return
+	}
+	rs.onBlockEvents <- blk
+}
+
+// feedTransaction broadcasts the new transaction stored, and its block if not seen yet;
+// the transaction may arrive before its block is announced.
+// The store does not keep all the details, the full transaction is loaded.
+func (rs *rootResolver) feedTransaction(ctx context.Context, stored *types.Transaction, lastBlock *hexutil.Uint64) {
+	if stored.BlockNumber != nil {
+		rs.feedBlock(ctx, *stored.BlockNumber, lastBlock)
+	}
+
+	trx, err := repository.R().Transaction(ctx, &stored.Hash)
+	if err != nil {
+		log.Errorf("can not load new transaction %s; %s", stored.Hash.String(), err.Error())
+		return
+	}
+	rs.onTrxEvents <- trx
+}
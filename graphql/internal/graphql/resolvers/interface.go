@@ -14,10 +14,10 @@ type ApiResolver interface {
 	Version() string
 
 	// Account resolves blockchain account by address.
-	Account(struct{ Address common.Address }) (*Account, error)
+	Account(context.Context, struct{ Address common.Address }) (*Account, error)
 
 	// Contracts resolves list of blockchain smart contracts encapsulated in a listable structure.
-	Contracts(*struct {
+	Contracts(context.Context, *struct {
 		ValidatedOnly bool
 		Cursor        *Cursor
 		Count         int32
@@ -26,44 +26,111 @@ type ApiResolver interface {
 	// ValidateContract resolves smart contract source code vs. deployed byte code and marks
 	// the contract as validated if the match is found. Peer API points are ringed on success
 	// to notify them about the change.
-	ValidateContract(*struct{ Contract ContractValidationInput }) (*Contract, error)
+	ValidateContract(context.Context, *struct{ Contract ContractValidationInput }) (*Contract, error)
+
+	// CallContract resolves a read-only call of a contract function using the stored contract ABI.
+	CallContract(context.Context, *struct {
+		Address common.Address
+		Method  string
+		Args    *[]string
+		Block   *hexutil.Uint64
+	}) (*ContractCallResult, error)
+
+	// SimulateTransaction resolves simulated execution of a transaction on the given block state
+	// with optional state overrides applied.
+	SimulateTransaction(context.Context, *struct {
+		From           *common.Address
+		To             *common.Address
+		Value          *hexutil.Big
+		Data           *hexutil.Bytes
+		Block          *hexutil.Uint64
+		StateOverrides *[]StateOverrideInput
+	}) (*SimulationResult, error)
+
+	// SyncContract stores a validated contract received from a trusted API peer
+	// after verifying the peer signature.
+	SyncContract(context.Context, *struct{ Contract ContractSyncInput }) (*Contract, error)
+
+	// ContractSyncStatus resolves the list of contract validation syncs of the given contract.
+	ContractSyncStatus(struct{ Address *common.Address }) ([]*ContractSyncStatus, error)
 
 	// Block resolves blockchain block by number or by hash. If neither is provided, the most recent block is given.
-	Block(*struct {
+	Block(context.Context, *struct {
 		Number *hexutil.Uint64
 		Hash   *common.Hash
 	}) (*Block, error)
 
 	// Blocks resolves list of blockchain blocks encapsulated in a listable structure.
-	Blocks(*struct {
+	Blocks(context.Context, *struct {
 		Cursor *Cursor
 		Count  int32
 	}) (*BlockList, error)
 
 	// Transaction resolves blockchain transaction by hash.
-	Transaction(*struct{ Hash common.Hash }) (*Transaction, error)
+	Transaction(context.Context, *struct{ Hash common.Hash }) (*Transaction, error)
 
 	// Transactions resolves list of blockchain transactions encapsulated in a listable structure.
-	Transactions(*struct {
+	Transactions(context.Context, *struct {
 		Cursor *Cursor
 		Count  int32
+		ListRange
 	}) (*TransactionList, error)
 
+	// BlockByTime resolves the block collated closest to the given UNIX time stamp.
+	// Search resolves the given search term into a list of matching blocks, transactions,
+	// accounts, tokens and verified contracts.
+	Search(context.Context, struct {
+		Term  string
+		Count int32
+	}) ([]*SearchResult, error)
+
+	BlockByTime(context.Context, *struct {
+		Timestamp hexutil.Uint64
+		Closest   string
+	}) (*Block, error)
+
 	// OnBlock resolves subscription to new blocks' event broadcast.
 	OnBlock(ctx context.Context) <-chan *Block
 
-	// OnTransaction resolves subscription to new transactions' event broadcast.
-	OnTransaction(ctx context.Context) <-chan *Transaction
+	// OnTransaction resolves subscription to new transactions' event broadcast,
+	// optionally filtered by the address of the sender, or the recipient.
+	OnTransaction(ctx context.Context, args *struct {
+		Address   *common.Address
+		Direction string
+	}) <-chan *Transaction
+
+	// OnTokenTransfer resolves subscription to new token transfers' event broadcast.
+	OnTokenTransfer(ctx context.Context, args *struct {
+		Token     *common.Address
+		Account   *common.Address
+		TokenType *string
+	}) <-chan *TokenTransaction
+
+	// OnLogs resolves subscription to new log records' event broadcast
+	// filtered by the emitting contract and the topics.
+	OnLogs(ctx context.Context, args *struct {
+		Address *common.Address
+		Topics  *[]*[]common.Hash
+	}) <-chan *Log
+
+	// OnContractDeployed resolves subscription to new contract deployments' event broadcast.
+	OnContractDeployed(ctx context.Context) <-chan *Transaction
+
+	// OnTransactionStatus resolves subscription to status changes of a transaction submitted through the API.
+	OnTransactionStatus(context.Context, *struct{ Hash common.Hash }) (<-chan *SubmittedTransaction, error)
+
+	// SubmittedTransaction resolves the lifecycle state of a transaction submitted through the API.
+	SubmittedTransaction(*struct{ Hash common.Hash }) (*SubmittedTransaction, error)
 
 	// Price resolves price details of the Opera blockchain token for the given target symbols.
 	Price(*struct{ To string }) (types.Price, error)
 
 	// GasPrice resolves the current amount of WEI for single Gas.
-	GasPrice() (hexutil.Uint64, error)
+	GasPrice(context.Context) (hexutil.Uint64, error)
 
 	// EstimateGas resolves the estimated amount of Gas required to perform
 	// transaction described by the input params.
-	EstimateGas(struct {
+	EstimateGas(context.Context, struct {
 		From  *common.Address
 		To    *common.Address
 		Value *hexutil.Big
@@ -71,7 +138,81 @@ type ApiResolver interface {
 	}) (*hexutil.Uint64, error)
 
 	// SendTransaction sends raw signed and RLP encoded transaction to the blockchain.
-	SendTransaction(*struct{ Tx hexutil.Bytes }) (*Transaction, error)
+	SendTransaction(context.Context, *struct{ Tx hexutil.Bytes }) (*Transaction, error)
+
+	// Signatures resolves the list of candidate text signatures
+	// of the given function selector, or event topic.
+	Signatures(struct{ Hash hexutil.Bytes }) []*types.Signature
+
+	// RegisterSignature registers a new function, or event text signature
+	// after verifying the text hashes to the given selector, or topic.
+	RegisterSignature(context.Context, struct {
+		Hash      hexutil.Bytes
+		Signature string
+	}) (*types.Signature, error)
+
+	// RequestFunds resolves a request for native tokens sent by the faucet to the given address.
+	RequestFunds(context.Context, *struct{ Address common.Address }) (*FaucetRequest, error)
+
+	// FaucetRequests resolves the list of the most recent faucet requests,
+	// optionally limited to the given recipient address.
+	FaucetRequests(context.Context, *struct {
+		Address *common.Address
+		Count   int32
+	}) ([]*FaucetRequest, error)
+
+	// Webhooks resolves the list of all registered webhooks.
+	Webhooks(context.Context) ([]*Webhook, error)
+
+	// Webhook resolves the registered webhook of the given ID.
+	Webhook(context.Context, *struct{ Id string }) (*Webhook, error)
+
+	// CreateWebhook resolves registration of a new webhook.
+	CreateWebhook(context.Context, *struct{ Hook WebhookInput }) (*Webhook, error)
+
+	// UpdateWebhook resolves update of an existing webhook.
+	UpdateWebhook(context.Context, *struct {
+		Id   string
+		Hook WebhookInput
+	}) (*Webhook, error)
+
+	// DeleteWebhook resolves removal of the webhook of the given ID together with its delivery log.
+	DeleteWebhook(context.Context, *struct{ Id string }) (bool, error)
+
+	// ApiKeys resolves the list of all the API keys.
+	ApiKeys(context.Context) ([]*ApiKey, error)
+
+	// ApiKey resolves the API key of the given ID.
+	ApiKey(context.Context, *struct{ Id string }) (*ApiKey, error)
+
+	// ApiTiers resolves the list of the configured API access tiers.
+	ApiTiers(context.Context) ([]*ApiTier, error)
+
+	// AnonymousApiUsage resolves the daily usage of the API by clients without an API key.
+	AnonymousApiUsage(context.Context, *struct{ Days int32 }) ([]*ApiKeyUsage, error)
+
+	// CreateApiKey resolves generation of a new API key.
+	CreateApiKey(context.Context, *struct {
+		Name string
+		Tier string
+	}) (*ApiKey, error)
+
+	// UpdateApiKey resolves update of an existing API key.
+	UpdateApiKey(context.Context, *struct {
+		Id      string
+		Name    *string
+		Tier    *string
+		Enabled *bool
+	}) (*ApiKey, error)
+
+	// DeleteApiKey resolves removal of the API key of the given ID together with its usage records.
+	DeleteApiKey(context.Context, *struct{ Id string }) (bool, error)
+
+	// Alerts resolves list of alerts raised by the alert rules encapsulated in a listable structure.
+	Alerts(context.Context, *struct {
+		Cursor *Cursor
+		Count  int32
+	}) (*AlertList, error)
 
 	// Erc20Token resolves an instance of ERC20 token if available.
 	Erc20Token(*struct{ Token common.Address }) *ERC20Token
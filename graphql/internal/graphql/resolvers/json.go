@@ -0,0 +1,27 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"encoding/json"
+)
+
+// JSON represents an arbitrary JSON value; a scalar, a list, or an object.
+type JSON struct {
+	Value interface{}
+}
+
+// ImplementsGraphQLType notifies the GraphQL that this type resolves JSON scalar.
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL unmarshal incoming JSON value into a local variable.
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	j.Value = input
+	return nil
+}
+
+// MarshalJSON encodes the JSON value for transport.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
@@ -0,0 +1,26 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ListRange represents the block and time range arguments of list queries.
+// It's embedded into the arguments of the list resolvers.
+type ListRange struct {
+	FromBlock *hexutil.Uint64
+	ToBlock   *hexutil.Uint64
+	FromTime  *hexutil.Uint64
+	ToTime    *hexutil.Uint64
+}
+
+// rangeFilter provides the range filter of the list range arguments.
+func (lr *ListRange) rangeFilter() *types.RangeFilter {
+	return &types.RangeFilter{
+		FromBlock: (*uint64)(lr.FromBlock),
+		ToBlock:   (*uint64)(lr.ToBlock),
+		FromTime:  (*uint64)(lr.FromTime),
+		ToTime:    (*uint64)(lr.ToTime),
+	}
+}
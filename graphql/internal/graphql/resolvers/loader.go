@@ -0,0 +1,364 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"sync"
+	"time"
+)
+
+const (
+	// loaderWait represents the time a batch loader collects keys before it loads them.
+	loaderWait = 2 * time.Millisecond
+
+	// LoaderMaxBatch represents the max number of keys loaded by a batch loader at once.
+	LoaderMaxBatch = 100
+)
+
+// loadersKey represents the context key of the request batch loaders.
+type loadersKey struct{}
+
+// loaders represents the set of batch loaders of a single API request.
+// Entities loaded by the loaders are kept for the lifetime of the request,
+// lookups are cancelled with the request context.
+type loaders struct {
+	ctx          context.Context
+	accounts     *batchLoader
+	blocks       *batchLoader
+	transactions *batchLoader
+	contracts    *batchLoader
+	erc20Tokens  *batchLoader
+}
+
+// batchLoader coalesces lookups of entities made within a short time window
+// into a single batch lookup of the underlying repository.
+type batchLoader struct {
+	ctx   context.Context
+	fetch func(context.Context, []interface{}) (map[interface{}]interface{}, error)
+
+	mu      sync.Mutex
+	results map[interface{}]*loaderResult
+	batch   *loaderBatch
+}
+
+// loaderResult represents the result of a key lookup; done is closed once the result is available.
+type loaderResult struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// loaderBatch represents a set of keys waiting to be loaded.
+type loaderBatch struct {
+	keys    []interface{}
+	results []*loaderResult
+	sent    bool
+}
+
+// WithLoaders attaches a new set of batch loaders to the given request context.
+// Resolvers fall back to direct repository lookups if the context has no loaders.
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		ctx:          ctx,
+		accounts:     newBatchLoader(ctx, fetchAccounts),
+		blocks:       newBatchLoader(ctx, fetchBlocks),
+		transactions: newBatchLoader(ctx, fetchTransactions),
+		contracts:    newBatchLoader(ctx, fetchContracts),
+		erc20Tokens:  newBatchLoader(ctx, fetchErc20Tokens),
+	})
+}
+
+// loadersOf provides the batch loaders attached to the request context.
+// If the context has no loaders, the loaders provided look the entities up directly.
+func loadersOf(ctx context.Context) *loaders {
+	l, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		return &loaders{ctx: ctx}
+	}
+	return l
+}
+
+// newBatchLoader creates a new batch loader using the given batch lookup bound to the given context.
+func newBatchLoader(ctx context.Context, fetch func(context.Context, []interface{}) (map[interface{}]interface{}, error)) *batchLoader {
+	return &batchLoader{ctx: ctx, fetch: fetch, results: make(map[interface{}]*loaderResult)}
+}
+
+// load provides the entity of the given key; nil if the entity does not exist.
+func (bl *batchLoader) load(key interface{}) (interface{}, error) {
+	res := bl.enqueue(key)
+	<-res.done
+	return res.value, res.err
+}
+
+// loadMany provides the entities of the given keys loaded together.
+func (bl *batchLoader) loadMany(keys []interface{}) ([]interface{}, error) {
+	list := make([]*loaderResult, len(keys))
+	for i, k := range keys {
+		list[i] = bl.enqueue(k)
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, res := range list {
+		<-res.done
+		if res.err != nil {
+			return nil, res.err
+		}
+		values[i] = res.value
+	}
+	return values, nil
+}
+
+// enqueue adds the key to the pending batch, unless the key has been requested already.
+func (bl *batchLoader) enqueue(key interface{}) *loaderResult {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	if res, ok := bl.results[key]; ok {
+		return res
+	}
+
+	res := &loaderResult{done: make(chan struct{})}
+	bl.results[key] = res
+
+	if bl.batch == nil {
+		b := &loaderBatch{}
+		bl.batch = b
+		time.AfterFunc(loaderWait, func() { bl.dispatch(b) })
+	}
+
+	bl.batch.keys = append(bl.batch.keys, key)
+	bl.batch.results = append(bl.batch.results, res)
+
+	// full batch does not wait for the time window to pass
+	if len(bl.batch.keys) >= LoaderMaxBatch {
+		b := bl.batch
+		b.sent, bl.batch = true, nil
+		go bl.run(b)
+	}
+	return res
+}
+
+// dispatch loads the given batch once its time window passed, unless it has been loaded already.
+func (bl *batchLoader) dispatch(b *loaderBatch) {
+	bl.mu.Lock()
+	if b.sent {
+		bl.mu.Unlock()
+		return
+	}
+	b.sent, bl.batch = true, nil
+	bl.mu.Unlock()
+
+	bl.run(b)
+}
+
+// run loads the keys of the batch and publishes the results.
+func (bl *batchLoader) run(b *loaderBatch) {
+	values, err := bl.fetch(bl.ctx, b.keys)
+	for i, res := range b.results {
+		res.value, res.err = values[b.keys[i]], err
+		close(res.done)
+	}
+}
+
+// account provides the account of the given address.
+func (l *loaders) account(addr common.Address) (*types.Account, error) {
+	if l.accounts == nil {
+		return repository.R().Account(l.ctx, &addr)
+	}
+
+	acc, err := l.accounts.load(addr)
+	if err != nil || acc == nil {
+		return nil, err
+	}
+	return acc.(*types.Account), nil
+}
+
+// block provides the block of the given number.
+func (l *loaders) block(num hexutil.Uint64) (*types.Block, error) {
+	if l.blocks == nil {
+		return repository.R().BlockByNumber(l.ctx, &num)
+	}
+
+	blk, err := l.blocks.load(num)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, repository.ErrBlockNotFound
+	}
+	return blk.(*types.Block), nil
+}
+
+// transaction provides the transaction of the given hash.
+func (l *loaders) transaction(hash common.Hash) (*types.Transaction, error) {
+	if l.transactions == nil {
+		return repository.R().Transaction(l.ctx, &hash)
+	}
+
+	trx, err := l.transactions.load(hash)
+	if err != nil {
+		return nil, err
+	}
+	if trx == nil {
+		return nil, repository.ErrTransactionNotFound
+	}
+	return trx.(*types.Transaction), nil
+}
+
+// transactionList provides the transactions of the given hashes.
+func (l *loaders) transactionList(hashes []*common.Hash) ([]*types.Transaction, error) {
+	list := make([]*types.Transaction, len(hashes))
+	if l.transactions == nil {
+		for i, hash := range hashes {
+			trx, err := repository.R().Transaction(l.ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = trx
+		}
+		return list, nil
+	}
+
+	keys := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		keys[i] = *hash
+	}
+
+	values, err := l.transactions.loadMany(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, trx := range values {
+		if trx == nil {
+			return nil, repository.ErrTransactionNotFound
+		}
+		list[i] = trx.(*types.Transaction)
+	}
+	return list, nil
+}
+
+// contract provides the smart contract of the given address; nil if not known.
+func (l *loaders) contract(addr common.Address) (*types.Contract, error) {
+	if l.contracts == nil {
+		return repository.R().Contract(l.ctx, &addr)
+	}
+
+	sc, err := l.contracts.load(addr)
+	if err != nil || sc == nil {
+		return nil, err
+	}
+	return sc.(*types.Contract), nil
+}
+
+// erc20Token provides the ERC20 token of the given address.
+func (l *loaders) erc20Token(addr common.Address) (*types.Erc20Token, error) {
+	if l.erc20Tokens == nil {
+		return repository.R().Erc20Token(&addr)
+	}
+
+	token, err := l.erc20Tokens.load(addr)
+	if err != nil || token == nil {
+		return nil, err
+	}
+	return token.(*types.Erc20Token), nil
+}
+
+// fetchAccounts loads a batch of accounts.
+func fetchAccounts(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+	addr := make([]common.Address, len(keys))
+	for i, k := range keys {
+		addr[i] = k.(common.Address)
+	}
+
+	list, err := repository.R().AccountsByAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}]interface{}, len(list))
+	for k, v := range list {
+		res[k] = v
+	}
+	return res, nil
+}
+
+// fetchBlocks loads a batch of blocks.
+func fetchBlocks(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+	nums := make([]hexutil.Uint64, len(keys))
+	for i, k := range keys {
+		nums[i] = k.(hexutil.Uint64)
+	}
+
+	list, err := repository.R().BlocksByNumber(ctx, nums)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}]interface{}, len(list))
+	for k, v := range list {
+		res[k] = v
+	}
+	return res, nil
+}
+
+// fetchTransactions loads a batch of transactions.
+func fetchTransactions(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+	hashes := make([]common.Hash, len(keys))
+	for i, k := range keys {
+		hashes[i] = k.(common.Hash)
+	}
+
+	list, err := repository.R().TransactionsByHash(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}]interface{}, len(list))
+	for k, v := range list {
+		res[k] = v
+	}
+	return res, nil
+}
+
+// fetchContracts loads a batch of smart contracts.
+func fetchContracts(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+	addr := make([]common.Address, len(keys))
+	for i, k := range keys {
+		addr[i] = k.(common.Address)
+	}
+
+	list, err := repository.R().ContractsByAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}]interface{}, len(list))
+	for k, v := range list {
+		res[k] = v
+	}
+	return res, nil
+}
+
+// fetchErc20Tokens loads a batch of ERC20 tokens.
+func fetchErc20Tokens(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+	addr := make([]common.Address, len(keys))
+	for i, k := range keys {
+		addr[i] = k.(common.Address)
+	}
+
+	list, err := repository.R().Erc20Tokens(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[interface{}]interface{}, len(list))
+	for k, v := range list {
+		res[k] = v
+	}
+	return res, nil
+}
@@ -0,0 +1,191 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"sync"
+	"testing"
+)
+
+// loaderRepository is a repository substitute recording the lookups made.
+type loaderRepository struct {
+	repository.Repository
+	mu      sync.Mutex
+	batches [][]hexutil.Uint64
+	single  []hexutil.Uint64
+	trx     map[common.Hash]*types.Transaction
+}
+
+// BlocksByNumber provides blocks of the given numbers; even numbers only exist.
+func (lr *loaderRepository) BlocksByNumber(_ context.Context, nums []hexutil.Uint64) (map[hexutil.Uint64]*types.Block, error) {
+	lr.mu.Lock()
+	lr.batches = append(lr.batches, nums)
+	lr.mu.Unlock()
+
+	res := make(map[hexutil.Uint64]*types.Block, len(nums))
+	for _, n := range nums {
+		if n%2 == 0 {
+			res[n] = &types.Block{Number: n}
+		}
+	}
+	return res, nil
+}
+
+// BlockByNumber provides the block of the given number.
+func (lr *loaderRepository) BlockByNumber(_ context.Context, num *hexutil.Uint64) (*types.Block, error) {
+	lr.mu.Lock()
+	lr.single = append(lr.single, *num)
+	lr.mu.Unlock()
+	return &types.Block{Number: *num}, nil
+}
+
+// TransactionsByHash provides the known transactions of the given hashes.
+func (lr *loaderRepository) TransactionsByHash(_ context.Context, hashes []common.Hash) (map[common.Hash]*types.Transaction, error) {
+	res := make(map[common.Hash]*types.Transaction, len(hashes))
+	for _, h := range hashes {
+		if trx, ok := lr.trx[h]; ok {
+			res[h] = trx
+		}
+	}
+	return res, nil
+}
+
+// batchSizes provides the sizes of the batch lookups made.
+func (lr *loaderRepository) batchSizes() []int {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	sizes := make([]int, len(lr.batches))
+	for i, b := range lr.batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
+
+func TestBatchLoaderCoalesce(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  int
+		dupes int
+		want  []int
+	}{
+		{name: "single key", keys: 1, want: []int{1}},
+		{name: "duplicate keys", keys: 10, dupes: 10, want: []int{10}},
+		{name: "full batch", keys: LoaderMaxBatch, want: []int{LoaderMaxBatch}},
+		{name: "over full batch", keys: LoaderMaxBatch + 5, want: []int{LoaderMaxBatch, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			lr := &loaderRepository{}
+			repository.Set(lr)
+			l := loadersOf(WithLoaders(context.Background()))
+
+			keys := make([]interface{}, tt.keys+tt.dupes)
+			for i := range keys {
+				keys[i] = hexutil.Uint64(i % tt.keys)
+			}
+
+			values, err := l.blocks.loadMany(keys)
+			g.Expect(err).To(gomega.BeNil())
+			for i, v := range values {
+				if keys[i].(hexutil.Uint64)%2 == 1 {
+					g.Expect(v).To(gomega.BeNil())
+					continue
+				}
+				g.Expect(v.(*types.Block).Number).To(gomega.Equal(keys[i]))
+			}
+
+			g.Expect(lr.batchSizes()).To(gomega.ConsistOf(tt.want))
+
+			// loaded blocks are kept for the request
+			_, err = l.block(0)
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(lr.batchSizes()).To(gomega.HaveLen(len(tt.want)))
+		})
+	}
+}
+
+func TestBatchLoaderError(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	fail := errors.New("database not available")
+	var calls int
+	bl := newBatchLoader(context.Background(), func(_ context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		calls++
+		return nil, fail
+	})
+
+	values, err := bl.loadMany([]interface{}{1, 2, 3})
+	g.Expect(err).To(gomega.Equal(fail))
+	g.Expect(values).To(gomega.BeNil())
+
+	// the failure is kept for the request, it's not retried
+	_, err = bl.load(2)
+	g.Expect(err).To(gomega.Equal(fail))
+	g.Expect(calls).To(gomega.Equal(1))
+}
+
+func TestLoadersTransactionList(t *testing.T) {
+	a, b, c := common.HexToHash("0xa"), common.HexToHash("0xb"), common.HexToHash("0xc")
+	lr := &loaderRepository{trx: map[common.Hash]*types.Transaction{
+		a: {Hash: a},
+		b: {Hash: b},
+	}}
+	repository.Set(lr)
+
+	tests := []struct {
+		name   string
+		hashes []*common.Hash
+		want   []common.Hash
+		err    error
+	}{
+		{name: "ordered as requested", hashes: []*common.Hash{&b, &a, &b}, want: []common.Hash{b, a, b}},
+		{name: "missing transaction", hashes: []*common.Hash{&a, &c}, err: repository.ErrTransactionNotFound},
+		{name: "empty list", hashes: []*common.Hash{}, want: []common.Hash{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			list, err := loadersOf(WithLoaders(context.Background())).transactionList(tt.hashes)
+			if tt.err != nil {
+				g.Expect(err).To(gomega.Equal(tt.err))
+				return
+			}
+
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(list).To(gomega.HaveLen(len(tt.want)))
+			for i, trx := range list {
+				g.Expect(trx.Hash).To(gomega.Equal(tt.want[i]))
+			}
+		})
+	}
+}
+
+func TestLoadersWithoutContext(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	lr := &loaderRepository{}
+	repository.Set(lr)
+
+	// missing blocks are reported as not found
+	_, err := loadersOf(WithLoaders(context.Background())).block(1)
+	g.Expect(err).To(gomega.Equal(repository.ErrBlockNotFound))
+	lr.batches = nil
+
+	// contexts without loaders look the entities up directly
+	blk, err := loadersOf(context.Background()).block(3)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(blk.Number).To(gomega.Equal(hexutil.Uint64(3)))
+	g.Expect(lr.single).To(gomega.Equal([]hexutil.Uint64{3}))
+	g.Expect(lr.batches).To(gomega.BeEmpty())
+}
@@ -0,0 +1,51 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etc "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Log represents resolvable log record emitted by a contract.
+type Log struct {
+	etc.Log
+}
+
+// NewLog builds new resolvable log record structure.
+func NewLog(lg *etc.Log) *Log {
+	return &Log{Log: *lg}
+}
+
+// Index resolves the index of the log record in the block.
+func (lg *Log) Index() hexutil.Uint64 {
+	return hexutil.Uint64(lg.Log.Index)
+}
+
+// Data resolves the non-indexed data of the log record.
+func (lg *Log) Data() hexutil.Bytes {
+	return lg.Log.Data
+}
+
+// EventSignature resolves the text signature of the emitted event, if known.
+func (lg *Log) EventSignature(ctx context.Context) *string {
+	return repository.R().LogEventSignature(ctx, &lg.Log)
+}
+
+// Decoded resolves the log record decoded using a known ABI, or a known event signature.
+func (lg *Log) Decoded(ctx context.Context) *types.DecodedEvent {
+	return repository.R().DecodeLog(ctx, &lg.Log)
+}
+
+// TransactionHash resolves the hash of the transaction which emitted the log record.
+func (lg *Log) TransactionHash() common.Hash {
+	return lg.Log.TxHash
+}
+
+// BlockNumber resolves the number of the block the log record was emitted in.
+func (lg *Log) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(lg.Log.BlockNumber)
+}
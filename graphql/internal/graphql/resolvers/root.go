@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"golang.org/x/sync/singleflight"
 	"sync"
+	"time"
 )
 
 const (
@@ -30,6 +31,10 @@ type rootResolver struct {
 	cg      singleflight.Group
 	sigStop chan bool
 
+	// shared store feed terminator; the feed runs only without a local indexer
+	wgFeed      sync.WaitGroup
+	sigFeedStop chan bool
+
 	// blocks subscriptions management
 	subscribeOnBlock   chan *subscriptOnBlock
 	unsubscribeOnBlock chan string
@@ -41,6 +46,27 @@ type rootResolver struct {
 	unsubscribeOnTrx chan string
 	trxSubscribers   map[string]*subscriptOnTrx
 	onTrxEvents      chan *types.Transaction
+
+	// log records and contract deployments subscriptions management;
+	// both are fed by the transaction events
+	subscribeOnLogs       chan *subscriptOnLogs
+	unsubscribeOnLogs     chan string
+	logsSubscribers       map[string]*subscriptOnLogs
+	subscribeOnContract   chan *subscriptOnContract
+	unsubscribeOnContract chan string
+	contractSubscribers   map[string]*subscriptOnContract
+
+	// token transfers subscriptions management
+	subscribeOnTokenTrx   chan *subscriptOnTokenTrx
+	unsubscribeOnTokenTrx chan string
+	tokenTrxSubscribers   map[string]*subscriptOnTokenTrx
+	onTokenTrxEvents      chan *types.TokenTransaction
+
+	// submitted transaction status subscriptions management
+	subscribeOnTrxStatus   chan *subscriptOnTrxStatus
+	unsubscribeOnTrxStatus chan string
+	trxStatusSubscribers   map[string]*subscriptOnTrxStatus
+	onTrxStatusEvents      chan *types.SubmittedTransaction
 }
 
 // log represents the logger to be used by the repository.
@@ -73,8 +99,9 @@ func New() ApiResolver {
 
 	// create new resolver
 	rs := rootResolver{
-		// create terminator
-		sigStop: make(chan bool, 1),
+		// create terminators
+		sigStop:     make(chan bool, 1),
+		sigFeedStop: make(chan bool, 1),
 
 		// block events subscription basics
 		subscribeOnBlock:   make(chan *subscriptOnBlock, subscriptionQueueCapacity),
@@ -87,13 +114,41 @@ func New() ApiResolver {
 		unsubscribeOnTrx: make(chan string, subscriptionQueueCapacity),
 		trxSubscribers:   make(map[string]*subscriptOnTrx, subscriptionInitialCapacity),
 		onTrxEvents:      make(chan *types.Transaction, onBlockChannelCapacity),
+
+		// log records and contract deployments subscription basics
+		subscribeOnLogs:       make(chan *subscriptOnLogs, subscriptionQueueCapacity),
+		unsubscribeOnLogs:     make(chan string, subscriptionQueueCapacity),
+		logsSubscribers:       make(map[string]*subscriptOnLogs, subscriptionInitialCapacity),
+		subscribeOnContract:   make(chan *subscriptOnContract, subscriptionQueueCapacity),
+		unsubscribeOnContract: make(chan string, subscriptionQueueCapacity),
+		contractSubscribers:   make(map[string]*subscriptOnContract, subscriptionInitialCapacity),
+
+		// token transfer events subscription basics
+		subscribeOnTokenTrx:   make(chan *subscriptOnTokenTrx, subscriptionQueueCapacity),
+		unsubscribeOnTokenTrx: make(chan string, subscriptionQueueCapacity),
+		tokenTrxSubscribers:   make(map[string]*subscriptOnTokenTrx, subscriptionInitialCapacity),
+		onTokenTrxEvents:      make(chan *types.TokenTransaction, onTokenTrxChannelCapacity),
+
+		// submitted transaction status events subscription basics
+		subscribeOnTrxStatus:   make(chan *subscriptOnTrxStatus, subscriptionQueueCapacity),
+		unsubscribeOnTrxStatus: make(chan string, subscriptionQueueCapacity),
+		trxStatusSubscribers:   make(map[string]*subscriptOnTrxStatus, subscriptionInitialCapacity),
+		onTrxStatusEvents:      make(chan *types.SubmittedTransaction, onTrxStatusChannelCapacity),
 	}
 
 	// pass subscription data source channels to the service manager
-	// to get them filled with relevant data
-	sm := svc.Manager()
-	sm.SetBlockChannel(rs.onBlockEvents)
-	sm.SetTrxChannel(rs.onTrxEvents)
+	// to get them filled with relevant data; without the indexer, or if the indexer
+	// may be standing by for another instance, the subscriptions are fed from the shared store
+	if cfg.Server.Mode == config.ModeApi || cfg.Lease.Enabled {
+		rs.wgFeed.Add(1)
+		go rs.followStore()
+	} else {
+		sm := svc.Manager()
+		sm.SetBlockChannel(rs.onBlockEvents)
+		sm.SetTrxChannel(rs.onTrxEvents)
+		sm.SetTokenTrxChannel(rs.onTokenTrxEvents)
+		sm.SetTrxStatusChannel(rs.onTrxStatusEvents)
+	}
 
 	// handle broadcast and subscriptions in a separate routine
 	rs.wg.Add(1)
@@ -107,6 +162,10 @@ func (rs *rootResolver) Close() {
 	// log
 	log.Notice("GraphQL resolver is closing")
 
+	// the feed has to stop first, it needs the broadcast to consume its events
+	rs.sigFeedStop <- true
+	rs.wgFeed.Wait()
+
 	// send the signal
 	rs.sigStop <- true
 	rs.wg.Wait()
@@ -124,29 +183,75 @@ func (rs *rootResolver) run() {
 	// log action
 	log.Notice("GraphQL resolver started")
 
+	// failed contract syncs are retried periodically
+	syncTick := time.NewTicker(contractSyncRetryInterval)
+	defer syncTick.Stop()
+
+	// queued contract verifications are processed periodically
+	verifyTick := time.NewTicker(contractVerificationInterval)
+	defer verifyTick.Stop()
+
 	// main loop waits for data on any channel and act upon it
 	for {
 		select {
 		case <-rs.sigStop:
 			return
 
+		case <-syncTick.C:
+			go rs.retryContractSync()
+
+		case <-verifyTick.C:
+			go rs.processContractVerifications()
+
 		case id := <-rs.unsubscribeOnBlock:
 			delete(rs.blockSubscribers, id)
 
 		case id := <-rs.unsubscribeOnTrx:
 			delete(rs.trxSubscribers, id)
 
+		case id := <-rs.unsubscribeOnLogs:
+			delete(rs.logsSubscribers, id)
+
+		case id := <-rs.unsubscribeOnContract:
+			delete(rs.contractSubscribers, id)
+
+		case id := <-rs.unsubscribeOnTokenTrx:
+			delete(rs.tokenTrxSubscribers, id)
+
+		case id := <-rs.unsubscribeOnTrxStatus:
+			delete(rs.trxStatusSubscribers, id)
+
 		case sub := <-rs.subscribeOnBlock:
 			rs.addBlockSubscriber(sub)
 
 		case sub := <-rs.subscribeOnTrx:
 			rs.addTrxSubscriber(sub)
 
+		case sub := <-rs.subscribeOnLogs:
+			rs.addLogsSubscriber(sub)
+
+		case sub := <-rs.subscribeOnContract:
+			rs.addContractSubscriber(sub)
+
+		case sub := <-rs.subscribeOnTokenTrx:
+			rs.addTokenTrxSubscriber(sub)
+
+		case sub := <-rs.subscribeOnTrxStatus:
+			rs.addTrxStatusSubscriber(sub)
+
 		case evt := <-rs.onBlockEvents:
 			rs.dispatchOnBlock(evt)
 
 		case evt := <-rs.onTrxEvents:
 			rs.dispatchOnTransaction(evt)
+			rs.dispatchOnLogs(evt)
+			rs.dispatchOnContractDeployed(evt)
+
+		case evt := <-rs.onTokenTrxEvents:
+			rs.dispatchOnTokenTransfer(evt)
+
+		case evt := <-rs.onTrxStatusEvents:
+			rs.dispatchOnTrxStatus(evt)
 		}
 	}
 }
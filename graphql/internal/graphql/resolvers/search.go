@@ -0,0 +1,182 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// searchMaxResults is the max number of results the search provides.
+	searchMaxResults = 50
+
+	// searchMinTermLength is the minimal length of a term used to match names and symbols.
+	searchMinTermLength = 2
+)
+
+var (
+	// searchHexNumber matches hex encoded block numbers.
+	searchHexNumber = regexp.MustCompile(`^0x[0-9a-fA-F]{1,16}$`)
+
+	// searchHashTerm matches hex encoded block and transaction hashes.
+	searchHashTerm = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+)
+
+// SearchResult represents a union of entities the search term may resolve into.
+type SearchResult struct {
+	block    *Block
+	trx      *Transaction
+	account  *Account
+	erc20    *ERC20Token
+	erc721   *ERC721Contract
+	erc1155  *ERC1155Contract
+	contract *Contract
+}
+
+// Search resolves the given search term into a list of matching entities.
+// Block numbers, block and transaction hashes and addresses are recognized directly,
+// other terms are matched against names and symbols of tokens and verified contracts.
+func (rs *rootResolver) Search(ctx context.Context, args struct {
+	Term  string
+	Count int32
+}) ([]*SearchResult, error) {
+	term := strings.TrimSpace(args.Term)
+	if term == "" {
+		return nil, fmt.Errorf("empty search term")
+	}
+
+	// block number?
+	if num, ok := searchBlockNumber(term); ok {
+		return searchBlock(ctx, num), nil
+	}
+
+	// block or transaction hash?
+	if searchHashTerm.MatchString(term) {
+		return searchHash(ctx, common.HexToHash(term)), nil
+	}
+
+	// address?
+	if common.IsHexAddress(term) && strings.HasPrefix(term, "0x") {
+		return searchAddress(ctx, common.HexToAddress(term)), nil
+	}
+
+	// names and symbols of tokens and contracts
+	if len(term) < searchMinTermLength {
+		return []*SearchResult{}, nil
+	}
+	if args.Count <= 0 || args.Count > searchMaxResults {
+		args.Count = searchMaxResults
+	}
+	return searchContracts(term, args.Count)
+}
+
+// searchBlockNumber tries to recognize a decimal, or a short hex encoded block number.
+func searchBlockNumber(term string) (hexutil.Uint64, bool) {
+	if searchHexNumber.MatchString(term) {
+		num, err := hexutil.DecodeUint64(term)
+		return hexutil.Uint64(num), err == nil
+	}
+	num, err := strconv.ParseUint(term, 10, 64)
+	return hexutil.Uint64(num), err == nil
+}
+
+// searchBlock provides the block of the given number, if any.
+func searchBlock(ctx context.Context, num hexutil.Uint64) []*SearchResult {
+	blk, err := repository.R().BlockByNumber(ctx, &num)
+	if err != nil || blk == nil {
+		return []*SearchResult{}
+	}
+	return []*SearchResult{{block: NewBlock(blk)}}
+}
+
+// searchHash provides the transaction and/or the block of the given hash.
+func searchHash(ctx context.Context, hash common.Hash) []*SearchResult {
+	list := make([]*SearchResult, 0)
+	if trx, err := repository.R().Transaction(ctx, &hash); err == nil && trx != nil {
+		list = append(list, &SearchResult{trx: NewTransaction(trx)})
+	}
+	if blk, err := repository.R().BlockByHash(ctx, &hash); err == nil && blk != nil {
+		list = append(list, &SearchResult{block: NewBlock(blk)})
+	}
+	return list
+}
+
+// searchAddress provides the account of the given address.
+func searchAddress(ctx context.Context, addr common.Address) []*SearchResult {
+	acc, err := repository.R().Account(ctx, &addr)
+	if err != nil || acc == nil {
+		return []*SearchResult{}
+	}
+	return []*SearchResult{{account: NewAccount(acc)}}
+}
+
+// searchContracts provides tokens and contracts with names, or symbols matching the term.
+func searchContracts(term string, count int32) ([]*SearchResult, error) {
+	cl, err := repository.R().SearchContracts(term, count)
+	if err != nil {
+		log.Errorf("contracts search failed; %s", err.Error())
+		return nil, err
+	}
+
+	list := make([]*SearchResult, 0, len(cl))
+	for _, sc := range cl {
+		var sr SearchResult
+		switch sc.Type {
+		case types.AccountTypeERC20Token:
+			sr.erc20 = NewErc20Token(&sc.Address)
+		case types.AccountTypeERC721Contract:
+			sr.erc721 = NewErc721Contract(&sc.Address)
+		case types.AccountTypeERC1155Contract:
+			sr.erc1155 = NewErc1155Contract(&sc.Address)
+		}
+
+		// fallback to the generic contract if the token is not available
+		if sr.erc20 == nil && sr.erc721 == nil && sr.erc1155 == nil {
+			sr.contract = NewContract(sc)
+		}
+		list = append(list, &sr)
+	}
+	return list, nil
+}
+
+// ToBlock resolves the search result as a block.
+func (sr *SearchResult) ToBlock() (*Block, bool) {
+	return sr.block, sr.block != nil
+}
+
+// ToTransaction resolves the search result as a transaction.
+func (sr *SearchResult) ToTransaction() (*Transaction, bool) {
+	return sr.trx, sr.trx != nil
+}
+
+// ToAccount resolves the search result as an account.
+func (sr *SearchResult) ToAccount() (*Account, bool) {
+	return sr.account, sr.account != nil
+}
+
+// ToERC20Token resolves the search result as an ERC20 token.
+func (sr *SearchResult) ToERC20Token() (*ERC20Token, bool) {
+	return sr.erc20, sr.erc20 != nil
+}
+
+// ToERC721Contract resolves the search result as an ERC721 contract.
+func (sr *SearchResult) ToERC721Contract() (*ERC721Contract, bool) {
+	return sr.erc721, sr.erc721 != nil
+}
+
+// ToERC1155Contract resolves the search result as an ERC1155 contract.
+func (sr *SearchResult) ToERC1155Contract() (*ERC1155Contract, bool) {
+	return sr.erc1155, sr.erc1155 != nil
+}
+
+// ToContract resolves the search result as a generic contract.
+func (sr *SearchResult) ToContract() (*Contract, bool) {
+	return sr.contract, sr.contract != nil
+}
@@ -0,0 +1,46 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Signatures resolves the list of candidate text signatures
+// of the given function selector, or event topic.
+func (rs *rootResolver) Signatures(args struct{ Hash hexutil.Bytes }) []*types.Signature {
+	var list []*types.Signature
+	switch len(args.Hash) {
+	case 4:
+		list = repository.R().FunctionSignatures(args.Hash)
+	case common.HashLength:
+		list = repository.R().EventSignatures(common.BytesToHash(args.Hash))
+	}
+
+	// make sure to return an empty list instead of nil
+	if list == nil {
+		return make([]*types.Signature, 0)
+	}
+	return list
+}
+
+// RegisterSignature registers a new function, or event text signature.
+// Selectors can be collided by brute force, only administrators may label them.
+func (rs *rootResolver) RegisterSignature(ctx context.Context, args struct {
+	Hash      hexutil.Bytes
+	Signature string
+}) (*types.Signature, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	sig, err := repository.R().RegisterSignature(ctx, args.Hash, args.Signature)
+	if err != nil {
+		log.Warningf("can not register signature %s; %s", args.Signature, err.Error())
+		return nil, err
+	}
+	return sig, nil
+}
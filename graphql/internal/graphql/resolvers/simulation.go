@@ -0,0 +1,105 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// StorageSlotInput represents a storage slot value override of a simulated transaction.
+type StorageSlotInput struct {
+	Slot  common.Hash
+	Value common.Hash
+}
+
+// StateOverrideInput represents an account state override of a simulated transaction.
+type StateOverrideInput struct {
+	Address   common.Address
+	Balance   *hexutil.Big
+	Nonce     *hexutil.Uint64
+	Code      *hexutil.Bytes
+	State     *[]StorageSlotInput
+	StateDiff *[]StorageSlotInput
+}
+
+// SimulationResult represents resolvable outcome of a simulated transaction.
+type SimulationResult struct {
+	types.SimulationResult
+}
+
+// SimulateTransaction resolves simulated execution of a transaction on the given block state
+// with optional state overrides applied.
+func (rs *rootResolver) SimulateTransaction(ctx context.Context, args *struct {
+	From           *common.Address
+	To             *common.Address
+	Value          *hexutil.Big
+	Data           *hexutil.Bytes
+	Block          *hexutil.Uint64
+	StateOverrides *[]StateOverrideInput
+}) (*SimulationResult, error) {
+	var data hexutil.Bytes
+	if args.Data != nil {
+		data = *args.Data
+	}
+
+	res, err := repository.R().SimulateTransaction(ctx, args.From, args.To, args.Value, data, args.Block, stateOverrides(args.StateOverrides))
+	if err != nil {
+		return nil, err
+	}
+	return &SimulationResult{SimulationResult: *res}, nil
+}
+
+// stateOverrides converts the list of state override inputs into the list expected by the repository.
+func stateOverrides(in *[]StateOverrideInput) []types.StateOverride {
+	if in == nil {
+		return nil
+	}
+
+	list := make([]types.StateOverride, len(*in))
+	for i, so := range *in {
+		list[i] = types.StateOverride{
+			Address:   so.Address,
+			Balance:   so.Balance,
+			Nonce:     so.Nonce,
+			Code:      so.Code,
+			State:     storageSlots(so.State),
+			StateDiff: storageSlots(so.StateDiff),
+		}
+	}
+	return list
+}
+
+// storageSlots converts the list of storage slot inputs into a slot to value map.
+func storageSlots(in *[]StorageSlotInput) map[common.Hash]common.Hash {
+	if in == nil {
+		return nil
+	}
+
+	slots := make(map[common.Hash]common.Hash, len(*in))
+	for _, s := range *in {
+		slots[s.Slot] = s.Value
+	}
+	return slots
+}
+
+// Logs resolves the list of log records emitted by the simulated transaction.
+func (sr *SimulationResult) Logs() []*Log {
+	list := make([]*Log, len(sr.SimulationResult.Logs))
+	for i := range sr.SimulationResult.Logs {
+		list[i] = NewLog(&sr.SimulationResult.Logs[i])
+	}
+	return list
+}
+
+// TokenTransactions resolves the list of generic token transactions
+// the simulated transaction would execute.
+func (sr *SimulationResult) TokenTransactions() []*TokenTransaction {
+	list := make([]*TokenTransaction, len(sr.SimulationResult.TokenTransactions))
+	for i, tx := range sr.SimulationResult.TokenTransactions {
+		list[i] = NewTokenTransaction(tx)
+	}
+	return list
+}
@@ -0,0 +1,84 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"time"
+)
+
+// onContractChannelCapacity is the number of new contract events held in memory for being broadcast to subscriber.
+const onContractChannelCapacity = 100
+
+// subscriptOnContract represents reference to a subscriber to onContractDeployed events broadcast.
+type subscriptOnContract struct {
+	stop   <-chan struct{}
+	events chan<- *Transaction
+}
+
+// OnContractDeployed resolves subscription to new contract deployments event broadcast.
+func (rs *rootResolver) OnContractDeployed(ctx context.Context) <-chan *Transaction {
+	// make the stream
+	c := make(chan *Transaction, onContractChannelCapacity)
+
+	// subscribe to event dispatch
+	rs.subscribeOnContract <- &subscriptOnContract{
+		stop:   ctx.Done(),
+		events: c,
+	}
+	return c
+}
+
+// addContractSubscriber adds a new subscription to onContractDeployed events.
+func (rs *rootResolver) addContractSubscriber(sub *subscriptOnContract) {
+	id, err := uuid()
+	if err == nil {
+		// add the subscriber to the map
+		rs.contractSubscribers[id] = sub
+	} else {
+		// log critical issue
+		log.Critical("can not generate UUID for new onContractDeployed subscriber")
+		log.Critical(err)
+	}
+}
+
+// dispatchOnContractDeployed dispatches onContractDeployed event to registered subscribers
+// if the given transaction deployed a contract.
+func (rs *rootResolver) dispatchOnContractDeployed(trx *types.Transaction) {
+	if trx.ContractAddress == nil || len(rs.contractSubscribers) == 0 {
+		return
+	}
+
+	// prep the transaction
+	transaction := NewTransaction(trx)
+
+	// broadcast the event in separate go routines so we don't block here
+	for id, sub := range rs.contractSubscribers {
+		go rs.notifyOnContractDeployed(transaction, sub, id)
+	}
+}
+
+// notifyOnContractDeployed broadcasts onContractDeployed event to given subscriber.
+func (rs *rootResolver) notifyOnContractDeployed(trx *Transaction, sub *subscriptOnContract, id string) {
+	// check if the context isn't already closed in which case we just unsub and leave
+	select {
+	case <-sub.stop:
+		rs.unsubscribeOnContract <- id
+		return
+	default:
+	}
+
+	// broadcast
+	select {
+	case <-sub.stop:
+		// just unsub on broken context
+		rs.unsubscribeOnContract <- id
+
+	case sub.events <- trx:
+		// push the transaction to subscriber
+
+	case <-time.After(time.Second):
+		// timeout reached without response? just remove the subscriber
+		rs.unsubscribeOnContract <- id
+	}
+}
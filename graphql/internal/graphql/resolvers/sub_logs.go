@@ -0,0 +1,139 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"time"
+)
+
+// onLogsChannelCapacity is the number of new log events held in memory for being broadcast to subscriber.
+const onLogsChannelCapacity = 500
+
+// subscriptOnLogs represents reference to a subscriber to onLogs events broadcast.
+type subscriptOnLogs struct {
+	stop    <-chan struct{}
+	events  chan<- *Log
+	address *common.Address
+	topics  [][]common.Hash
+}
+
+// OnLogs resolves subscription to new log records event broadcast filtered by the emitting contract
+// and the topics. Topics are matched by position; an empty position matches any topic, multiple topics
+// on the same position match any of them.
+func (rs *rootResolver) OnLogs(ctx context.Context, args *struct {
+	Address *common.Address
+	Topics  *[]*[]common.Hash
+}) <-chan *Log {
+	// make the stream
+	c := make(chan *Log, onLogsChannelCapacity)
+
+	// collect topics filter
+	topics := make([][]common.Hash, 0)
+	if args.Topics != nil {
+		for _, pos := range *args.Topics {
+			if pos == nil {
+				topics = append(topics, nil)
+				continue
+			}
+			topics = append(topics, *pos)
+		}
+	}
+
+	// subscribe to event dispatch
+	rs.subscribeOnLogs <- &subscriptOnLogs{
+		stop:    ctx.Done(),
+		events:  c,
+		address: args.Address,
+		topics:  topics,
+	}
+	return c
+}
+
+// matches checks if the given log record passes the subscriber filter.
+func (sub *subscriptOnLogs) matches(lg *etc.Log) bool {
+	if sub.address != nil && lg.Address != *sub.address {
+		return false
+	}
+
+	// the log must have at least as many topics as filtered
+	if len(sub.topics) > len(lg.Topics) {
+		return false
+	}
+
+	for i, alt := range sub.topics {
+		if len(alt) == 0 {
+			continue
+		}
+
+		var found bool
+		for _, t := range alt {
+			if t == lg.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// addLogsSubscriber adds a new subscription to onLogs events.
+func (rs *rootResolver) addLogsSubscriber(sub *subscriptOnLogs) {
+	id, err := uuid()
+	if err == nil {
+		// add the subscriber to the map
+		rs.logsSubscribers[id] = sub
+	} else {
+		// log critical issue
+		log.Critical("can not generate UUID for new onLogs subscriber")
+		log.Critical(err)
+	}
+}
+
+// dispatchOnLogs dispatches onLogs events of the given transaction to registered subscribers.
+func (rs *rootResolver) dispatchOnLogs(trx *types.Transaction) {
+	for i := range trx.Logs {
+		var lg *Log
+
+		// broadcast the event in separate go routines so we don't block here
+		for id, sub := range rs.logsSubscribers {
+			if !sub.matches(&trx.Logs[i]) {
+				continue
+			}
+			if lg == nil {
+				lg = NewLog(&trx.Logs[i])
+			}
+			go rs.notifyOnLogs(lg, sub, id)
+		}
+	}
+}
+
+// notifyOnLogs broadcasts onLogs event to given subscriber.
+func (rs *rootResolver) notifyOnLogs(lg *Log, sub *subscriptOnLogs, id string) {
+	// check if the context isn't already closed in which case we just unsub and leave
+	select {
+	case <-sub.stop:
+		rs.unsubscribeOnLogs <- id
+		return
+	default:
+	}
+
+	// broadcast
+	select {
+	case <-sub.stop:
+		// just unsub on broken context
+		rs.unsubscribeOnLogs <- id
+
+	case sub.events <- lg:
+		// push the log to subscriber
+
+	case <-time.After(time.Second):
+		// timeout reached without response? just remove the subscriber
+		rs.unsubscribeOnLogs <- id
+	}
+}
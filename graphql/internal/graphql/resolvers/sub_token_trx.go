@@ -0,0 +1,112 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
+
+// onTokenTrxChannelCapacity is the number of new token transaction events held in memory for being broadcast to subscriber.
+const onTokenTrxChannelCapacity = 500
+
+// subscriptOnTokenTrx represents reference to a subscriber to onTokenTransfer events broadcast.
+type subscriptOnTokenTrx struct {
+	stop      <-chan struct{}
+	events    chan<- *TokenTransaction
+	token     *common.Address
+	account   *common.Address
+	tokenType *string
+}
+
+// OnTokenTransfer resolves subscription to new token transfers event broadcast,
+// optionally filtered by the token contract, the sender or recipient account, and the token type.
+func (rs *rootResolver) OnTokenTransfer(ctx context.Context, args *struct {
+	Token     *common.Address
+	Account   *common.Address
+	TokenType *string
+}) <-chan *TokenTransaction {
+	// make the stream
+	c := make(chan *TokenTransaction, onTokenTrxChannelCapacity)
+
+	// subscribe to event dispatch
+	rs.subscribeOnTokenTrx <- &subscriptOnTokenTrx{
+		stop:      ctx.Done(),
+		events:    c,
+		token:     args.Token,
+		account:   args.Account,
+		tokenType: args.TokenType,
+	}
+	return c
+}
+
+// matches checks if the given token transaction passes the subscriber filter.
+func (sub *subscriptOnTokenTrx) matches(tt *types.TokenTransaction) bool {
+	// approvals are not transfers
+	if tt.Type == types.TokenTrxTypeApproval || tt.Type == types.TokenTrxTypeApprovalForAll {
+		return false
+	}
+
+	if sub.token != nil && tt.TokenAddress != *sub.token {
+		return false
+	}
+	if sub.account != nil && tt.Sender != *sub.account && tt.Recipient != *sub.account {
+		return false
+	}
+	return sub.tokenType == nil || tt.TokenType == *sub.tokenType
+}
+
+// addTokenTrxSubscriber adds a new subscription to onTokenTransfer events.
+func (rs *rootResolver) addTokenTrxSubscriber(sub *subscriptOnTokenTrx) {
+	id, err := uuid()
+	if err == nil {
+		// add the subscriber to the map
+		rs.tokenTrxSubscribers[id] = sub
+	} else {
+		// log critical issue
+		log.Critical("can not generate UUID for new onTokenTransfer subscriber")
+		log.Critical(err)
+	}
+}
+
+// dispatchOnTokenTransfer dispatches onTokenTransfer event to registered subscribers.
+func (rs *rootResolver) dispatchOnTokenTransfer(tt *types.TokenTransaction) {
+	var trx *TokenTransaction
+
+	// broadcast the event in separate go routines so we don't block here
+	for id, sub := range rs.tokenTrxSubscribers {
+		if !sub.matches(tt) {
+			continue
+		}
+		if trx == nil {
+			trx = NewTokenTransaction(tt)
+		}
+		go rs.notifyOnTokenTransfer(trx, sub, id)
+	}
+}
+
+// notifyOnTokenTransfer broadcasts onTokenTransfer event to given subscriber.
+func (rs *rootResolver) notifyOnTokenTransfer(trx *TokenTransaction, sub *subscriptOnTokenTrx, id string) {
+	// check if the context isn't already closed in which case we just unsub and leave
+	select {
+	case <-sub.stop:
+		rs.unsubscribeOnTokenTrx <- id
+		return
+	default:
+	}
+
+	// broadcast
+	select {
+	case <-sub.stop:
+		// just unsub on broken context
+		rs.unsubscribeOnTokenTrx <- id
+
+	case sub.events <- trx:
+		// push the token transaction to subscriber
+
+	case <-time.After(time.Second):
+		// timeout reached without response? just remove the subscriber
+		rs.unsubscribeOnTokenTrx <- id
+	}
+}
@@ -4,32 +4,77 @@ package resolvers
 import (
 	"context"
 	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
 	"time"
 )
 
 // onTrxChannelCapacity is the number of new transaction events held in memory for being broadcast to subscriber.
 const onTrxChannelCapacity = 500
 
+const (
+	// trxDirectionAny represents transactions both sent and received by the filtered address.
+	trxDirectionAny = "ANY"
+
+	// trxDirectionIncoming represents transactions received by the filtered address.
+	trxDirectionIncoming = "INCOMING"
+
+	// trxDirectionOutgoing represents transactions sent by the filtered address.
+	trxDirectionOutgoing = "OUTGOING"
+
+	// trxDirectionSelf represents transactions sent by the filtered address to itself.
+	trxDirectionSelf = "SELF"
+)
+
 // subscriptOnTrx represents reference to a subscriber to onTransaction events broadcast.
 type subscriptOnTrx struct {
-	stop   <-chan struct{}
-	events chan<- *Transaction
+	stop      <-chan struct{}
+	events    chan<- *Transaction
+	address   *common.Address
+	direction string
 }
 
-// OnBlock resolves subscription to new blocks event broadcast.
-func (rs *rootResolver) OnTransaction(ctx context.Context) <-chan *Transaction {
+// OnTransaction resolves subscription to new transactions event broadcast,
+// optionally filtered by the address of the sender, or the recipient.
+func (rs *rootResolver) OnTransaction(ctx context.Context, args *struct {
+	Address   *common.Address
+	Direction string
+}) <-chan *Transaction {
 	// make the stream
 	c := make(chan *Transaction, onTrxChannelCapacity)
 
 	// subscribe to event dispatch
 	rs.subscribeOnTrx <- &subscriptOnTrx{
-		stop:   ctx.Done(),
-		events: c,
+		stop:      ctx.Done(),
+		events:    c,
+		address:   args.Address,
+		direction: args.Direction,
 	}
 
 	return c
 }
 
+// matches checks if the given transaction passes the subscriber filter.
+func (sub *subscriptOnTrx) matches(trx *types.Transaction) bool {
+	if sub.address == nil {
+		return true
+	}
+
+	outgoing := trx.From == *sub.address
+	incoming := (trx.To != nil && *trx.To == *sub.address) ||
+		(trx.ContractAddress != nil && *trx.ContractAddress == *sub.address)
+
+	switch sub.direction {
+	case trxDirectionIncoming:
+		return incoming
+	case trxDirectionOutgoing:
+		return outgoing
+	case trxDirectionSelf:
+		return incoming && outgoing
+	default:
+		return incoming || outgoing
+	}
+}
+
 // addTrxSubscriber adds a new subscription to onTransaction events.
 func (rs *rootResolver) addTrxSubscriber(sub *subscriptOnTrx) {
 	id, err := uuid()
@@ -50,7 +95,9 @@ func (rs *rootResolver) dispatchOnTransaction(trx *types.Transaction) {
 
 	// broadcast the event in separate go routines so we don't block here
 	for id, sub := range rs.trxSubscribers {
-		go rs.notifyOnTransaction(transaction, sub, id)
+		if sub.matches(trx) {
+			go rs.notifyOnTransaction(transaction, sub, id)
+		}
 	}
 }
 
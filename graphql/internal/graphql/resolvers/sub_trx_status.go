@@ -0,0 +1,129 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"sync"
+	"time"
+)
+
+// onTrxStatusChannelCapacity is the number of status change events held in memory for being broadcast to subscriber.
+const onTrxStatusChannelCapacity = 10
+
+// subscriptOnTrxStatus represents reference to a subscriber to onTransactionStatus events broadcast.
+type subscriptOnTrxStatus struct {
+	hash   common.Hash
+	stop   <-chan struct{}
+	events chan *SubmittedTransaction
+
+	// the stream is closed once the final state is delivered
+	mu   sync.Mutex
+	done bool
+}
+
+// OnTransactionStatus resolves subscription to status changes of a transaction submitted through the API.
+// The current state is sent immediately, the stream is closed once the transaction reaches a final state.
+func (rs *rootResolver) OnTransactionStatus(ctx context.Context, args *struct{ Hash common.Hash }) (<-chan *SubmittedTransaction, error) {
+	st, err := repository.R().SubmittedTransaction(&args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, fmt.Errorf("transaction %s was not submitted through this API", args.Hash.String())
+	}
+
+	// make the stream
+	sub := &subscriptOnTrxStatus{
+		hash:   args.Hash,
+		stop:   ctx.Done(),
+		events: make(chan *SubmittedTransaction, onTrxStatusChannelCapacity),
+	}
+
+	// push the current state; no need to subscribe if it's final already
+	sub.notify(NewSubmittedTransaction(st))
+	if st.IsFinal() {
+		return sub.events, nil
+	}
+
+	// subscribe to event dispatch
+	rs.subscribeOnTrxStatus <- sub
+	return sub.events, nil
+}
+
+// addTrxStatusSubscriber adds a new subscription to onTransactionStatus events.
+func (rs *rootResolver) addTrxStatusSubscriber(sub *subscriptOnTrxStatus) {
+	id, err := uuid()
+	if err != nil {
+		log.Critical("can not generate UUID for new onTransactionStatus subscriber")
+		log.Critical(err)
+		return
+	}
+
+	// add the subscriber to the map
+	rs.trxStatusSubscribers[id] = sub
+
+	// the status may have changed before the subscription was registered
+	go rs.recheckTrxStatus(sub, id)
+}
+
+// recheckTrxStatus loads the current state of the subscribed transaction
+// and delivers it to the subscriber, if it's final.
+func (rs *rootResolver) recheckTrxStatus(sub *subscriptOnTrxStatus, id string) {
+	st, err := repository.R().SubmittedTransaction(&sub.hash)
+	if err != nil || st == nil || !st.IsFinal() {
+		return
+	}
+	rs.notifyOnTrxStatus(NewSubmittedTransaction(st), sub, id)
+}
+
+// dispatchOnTrxStatus dispatches onTransactionStatus event to registered subscribers of the transaction.
+func (rs *rootResolver) dispatchOnTrxStatus(st *types.SubmittedTransaction) {
+	trx := NewSubmittedTransaction(st)
+
+	// broadcast the event in separate go routines so we don't block here
+	for id, sub := range rs.trxStatusSubscribers {
+		if sub.hash == st.Hash {
+			go rs.notifyOnTrxStatus(trx, sub, id)
+		}
+	}
+}
+
+// notifyOnTrxStatus broadcasts onTransactionStatus event to given subscriber.
+func (rs *rootResolver) notifyOnTrxStatus(st *SubmittedTransaction, sub *subscriptOnTrxStatus, id string) {
+	if !sub.notify(st) || st.IsFinal() {
+		rs.unsubscribeOnTrxStatus <- id
+	}
+}
+
+// notify pushes the given state to the subscriber stream and closes the stream
+// if the state is final. It returns false if the subscriber is gone.
+func (sub *subscriptOnTrxStatus) notify(st *SubmittedTransaction) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.done {
+		return false
+	}
+
+	select {
+	case <-sub.stop:
+		sub.done = true
+		return false
+
+	case sub.events <- st:
+		if st.IsFinal() {
+			sub.done = true
+			close(sub.events)
+		}
+		return true
+
+	case <-time.After(time.Second):
+		// timeout reached without response? just remove the subscriber
+		sub.done = true
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SubmittedTransaction represents resolvable lifecycle state of a transaction submitted through the API.
+type SubmittedTransaction struct {
+	types.SubmittedTransaction
+}
+
+// NewSubmittedTransaction creates a new instance of resolvable submitted transaction.
+func NewSubmittedTransaction(st *types.SubmittedTransaction) *SubmittedTransaction {
+	return &SubmittedTransaction{SubmittedTransaction: *st}
+}
+
+// SubmittedTransaction resolves the lifecycle state of a transaction submitted through the API.
+func (rs *rootResolver) SubmittedTransaction(args *struct{ Hash common.Hash }) (*SubmittedTransaction, error) {
+	st, err := repository.R().SubmittedTransaction(&args.Hash)
+	if err != nil || st == nil {
+		return nil, err
+	}
+	return NewSubmittedTransaction(st), nil
+}
+
+// Nonce resolves the nonce of the submitted transaction.
+func (st *SubmittedTransaction) Nonce() hexutil.Uint64 {
+	return hexutil.Uint64(st.SubmittedTransaction.Nonce)
+}
+
+// BlockNumber resolves the number of the block the transaction was mined in, if mined.
+func (st *SubmittedTransaction) BlockNumber() *hexutil.Uint64 {
+	if st.SubmittedTransaction.BlockNumber == nil {
+		return nil
+	}
+	return (*hexutil.Uint64)(st.SubmittedTransaction.BlockNumber)
+}
+
+// Submitted resolves the UNIX time stamp of the transaction submission.
+func (st *SubmittedTransaction) Submitted() hexutil.Uint64 {
+	return hexutil.Uint64(st.SubmittedTransaction.Submitted.Unix())
+}
+
+// Updated resolves the UNIX time stamp of the last status change.
+func (st *SubmittedTransaction) Updated() hexutil.Uint64 {
+	return hexutil.Uint64(st.SubmittedTransaction.Updated.Unix())
+}
+
+// Transaction resolves the submitted transaction, if available.
+func (st *SubmittedTransaction) Transaction(ctx context.Context) (*Transaction, error) {
+	return submittedTrxDetail(ctx, &st.Hash)
+}
+
+// Replacement resolves the transaction which replaced the submitted transaction, if known.
+func (st *SubmittedTransaction) Replacement(ctx context.Context) (*Transaction, error) {
+	if st.ReplacedBy == nil {
+		return nil, nil
+	}
+	return submittedTrxDetail(ctx, st.ReplacedBy)
+}
+
+// submittedTrxDetail loads the transaction of the given hash; nil if not available.
+func submittedTrxDetail(ctx context.Context, hash *common.Hash) (*Transaction, error) {
+	trx, err := repository.R().Transaction(ctx, hash)
+	if err != nil || trx == nil {
+		return nil, nil
+	}
+	return NewTransaction(trx), nil
+}
@@ -2,6 +2,7 @@
 package resolvers
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
 	"fmt"
@@ -25,7 +26,7 @@ func NewTransaction(trx *types.Transaction) *Transaction {
 }
 
 // Transaction resolves blockchain transaction by transaction hash.
-func (rs *rootResolver) Transaction(args *struct{ Hash common.Hash }) (tx *Transaction, err error) {
+func (rs *rootResolver) Transaction(ctx context.Context, args *struct{ Hash common.Hash }) (tx *Transaction, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Criticalf("transaction loader crashed on %s", args.Hash.String())
@@ -35,7 +36,7 @@ func (rs *rootResolver) Transaction(args *struct{ Hash common.Hash }) (tx *Trans
 	}()
 
 	// get the transaction from repository
-	trx, err := repository.R().Transaction(&args.Hash)
+	trx, err := repository.R().Transaction(ctx, &args.Hash)
 	if err != nil {
 		log.Warningf("can not get transaction %s", args.Hash)
 		return nil, err
@@ -50,9 +51,9 @@ func (rs *rootResolver) Transaction(args *struct{ Hash common.Hash }) (tx *Trans
 }
 
 // SendTransaction sends raw signed and RLP encoded transaction to the blockchain.
-func (rs *rootResolver) SendTransaction(args *struct{ Tx hexutil.Bytes }) (*Transaction, error) {
+func (rs *rootResolver) SendTransaction(ctx context.Context, args *struct{ Tx hexutil.Bytes }) (*Transaction, error) {
 	// get the transaction from repository
-	trx, err := repository.R().SendTransaction(args.Tx)
+	trx, err := repository.R().SendTransaction(ctx, args.Tx)
 	if err != nil {
 		log.Warningf("can not send transaction; %s", err.Error())
 		return nil, err
@@ -62,9 +63,9 @@ func (rs *rootResolver) SendTransaction(args *struct{ Tx hexutil.Bytes }) (*Tran
 }
 
 // Sender resolves sender's account of the transaction.
-func (trx *Transaction) Sender() (*Account, error) {
+func (trx *Transaction) Sender(ctx context.Context) (*Account, error) {
 	// get the sender by address
-	acc, err := repository.R().Account(&trx.From)
+	acc, err := loadersOf(ctx).account(trx.From)
 	if err != nil {
 		return nil, err
 	}
@@ -73,14 +74,14 @@ func (trx *Transaction) Sender() (*Account, error) {
 }
 
 // Recipient resolves recipient's account of the transaction.
-func (trx *Transaction) Recipient() (*Account, error) {
+func (trx *Transaction) Recipient(ctx context.Context) (*Account, error) {
 	// no recipient available
 	if trx.To == nil {
 		return nil, nil
 	}
 
 	// get the recipient by address
-	acc, err := repository.R().Account(trx.To)
+	acc, err := loadersOf(ctx).account(*trx.To)
 	if err != nil {
 		return nil, err
 	}
@@ -89,14 +90,14 @@ func (trx *Transaction) Recipient() (*Account, error) {
 }
 
 // Block resolves block the transaction is bundled in, nil if it's pending and not added to a block yet.
-func (trx *Transaction) Block() (*Block, error) {
+func (trx *Transaction) Block(ctx context.Context) (*Block, error) {
 	// no recipient available
 	if trx.BlockNumber == nil {
 		return nil, nil
 	}
 
 	// get the sender by address
-	blk, err := repository.R().BlockByNumber(trx.BlockNumber)
+	blk, err := loadersOf(ctx).block(*trx.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +105,26 @@ func (trx *Transaction) Block() (*Block, error) {
 	return NewBlock(blk), nil
 }
 
+// DecodedInput resolves the transaction input data decoded by the ABI
+// of the recipient contract, if available.
+func (trx *Transaction) DecodedInput(ctx context.Context) (*types.DecodedCall, error) {
+	return repository.R().DecodeTransactionInput(ctx, &trx.Transaction)
+}
+
+// MethodName resolves the name of the contract function called by the transaction, if known.
+func (trx *Transaction) MethodName(ctx context.Context) (*string, error) {
+	return repository.R().TransactionMethodName(ctx, &trx.Transaction)
+}
+
+// Logs resolves the list of log records emitted in the scope of the transaction call.
+func (trx *Transaction) Logs() []*Log {
+	list := make([]*Log, len(trx.Transaction.Logs))
+	for i := range trx.Transaction.Logs {
+		list[i] = NewLog(&trx.Transaction.Logs[i])
+	}
+	return list
+}
+
 // tokenTransactions loads list of all token transaction related to this transaction call.
 func (trx *Transaction) tokenTransactions() ([]*types.TokenTransaction, error) {
 	// call for it only once
@@ -0,0 +1,89 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+)
+
+const (
+	// trxStatusSuccess represents successfully executed transactions.
+	trxStatusSuccess = "SUCCESS"
+
+	// trxStatusFailed represents reverted transactions.
+	trxStatusFailed = "FAILED"
+)
+
+// TxListFilter represents the advanced filter arguments of the account transaction list.
+// It's embedded into the arguments of the transaction list resolver.
+type TxListFilter struct {
+	Direction     string
+	Status        *string
+	CreationsOnly bool
+	Method        *string
+	MinValue      *hexutil.Big
+	MaxValue      *hexutil.Big
+}
+
+// TokenTxListFilter represents the advanced filter arguments of the token transaction list.
+// It's embedded into the arguments of the ERC20 transaction list resolver.
+type TokenTxListFilter struct {
+	Tokens    *[]common.Address
+	MinAmount *hexutil.Big
+	MaxAmount *hexutil.Big
+}
+
+// trxFilter provides the transaction filter of the advanced filter arguments.
+func (tf *TxListFilter) trxFilter() (*types.TransactionFilter, error) {
+	fi := types.TransactionFilter{
+		CreationsOnly: tf.CreationsOnly,
+		MinValue:      (*big.Int)(tf.MinValue),
+		MaxValue:      (*big.Int)(tf.MaxValue),
+	}
+
+	// direction relative to the account
+	switch tf.Direction {
+	case trxDirectionIncoming:
+		fi.Direction = types.TrxDirectionIncoming
+	case trxDirectionOutgoing:
+		fi.Direction = types.TrxDirectionOutgoing
+	case trxDirectionSelf:
+		fi.Direction = types.TrxDirectionSelf
+	}
+
+	// execution status
+	if tf.Status != nil {
+		var st uint64
+		if *tf.Status == trxStatusSuccess {
+			st = 1
+		}
+		fi.Status = &st
+	}
+
+	// the method is expected to be a 4-byte selector, e.g. 0xa9059cbb
+	if tf.Method != nil {
+		sel, err := hexutil.Decode(*tf.Method)
+		if err != nil || len(sel) != 4 {
+			return nil, fmt.Errorf("invalid method selector %s, 4 bytes expected", *tf.Method)
+		}
+		m := strings.ToLower(*tf.Method)
+		fi.Method = &m
+	}
+	return &fi, nil
+}
+
+// tokenTrxFilter provides the token transaction filter of the advanced filter arguments.
+func (tf *TokenTxListFilter) tokenTrxFilter() *types.TokenTransactionFilter {
+	fi := types.TokenTransactionFilter{
+		MinAmount: (*big.Int)(tf.MinAmount),
+		MaxAmount: (*big.Int)(tf.MaxAmount),
+	}
+	if tf.Tokens != nil {
+		fi.Tokens = *tf.Tokens
+	}
+	return &fi
+}
@@ -2,6 +2,7 @@
 package resolvers
 
 import (
+	"context"
 	"crypto/rand"
 	"fantom-api-graphql/internal/repository"
 	"fantom-api-graphql/internal/types"
@@ -25,9 +26,9 @@ func (rs *rootResolver) Price(args *struct{ To string }) (types.Price, error) {
 }
 
 // GasPrice resolves the current amount of WEI for single Gas.
-func (rs *rootResolver) GasPrice() (hexutil.Uint64, error) {
+func (rs *rootResolver) GasPrice(ctx context.Context) (hexutil.Uint64, error) {
 	// get the actual value
-	price, err := repository.R().GasPrice()
+	price, err := repository.R().GasPrice(ctx)
 	if err != nil {
 		return hexutil.Uint64(0), err
 	}
@@ -45,13 +46,13 @@ func (rs *rootResolver) GasPrice() (hexutil.Uint64, error) {
 
 // EstimateGas resolves the estimated amount of Gas required to perform
 // transaction described by the input params.
-func (rs *rootResolver) EstimateGas(args struct {
+func (rs *rootResolver) EstimateGas(ctx context.Context, args struct {
 	From  *common.Address
 	To    *common.Address
 	Value *hexutil.Big
 	Data  *string
 }) (*hexutil.Uint64, error) {
-	return repository.R().GasEstimate(&args)
+	return repository.R().GasEstimate(ctx, &args)
 }
 
 // uuid generates new random subscription UUID
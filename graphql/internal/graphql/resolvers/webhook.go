@@ -0,0 +1,276 @@
+// Package resolvers implements GraphQL resolvers to incoming API requests.
+package resolvers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"time"
+)
+
+const (
+	// webhookDeliveriesMaxCount represents the max number of webhook deliveries loaded at once.
+	webhookDeliveriesMaxCount = 100
+
+	// webhookSecretLength represents the number of random bytes of a generated webhook secret.
+	webhookSecretLength = 32
+)
+
+// WebhookInput represents the webhook registration details.
+type WebhookInput struct {
+	Url       string
+	Secret    *string
+	Addresses *[]common.Address
+	Tokens    *[]common.Address
+	Events    []string
+	Enabled   *bool
+}
+
+// Webhook represents resolvable registered receiver of address activity notifications.
+type Webhook struct {
+	types.Webhook
+
+	// showSecret controls if the webhook secret can be resolved;
+	// the secret is available only in response to the webhook creation
+	showSecret bool
+}
+
+// WebhookDelivery represents resolvable single notification delivery to a webhook.
+type WebhookDelivery struct {
+	types.WebhookDelivery
+}
+
+// NewWebhook creates a new instance of resolvable webhook.
+func NewWebhook(wh *types.Webhook) *Webhook {
+	return &Webhook{Webhook: *wh}
+}
+
+// NewWebhookDelivery creates a new instance of resolvable webhook delivery.
+func NewWebhookDelivery(wd *types.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{WebhookDelivery: *wd}
+}
+
+// Webhooks resolves the list of all registered webhooks.
+func (rs *rootResolver) Webhooks(ctx context.Context) ([]*Webhook, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	wl, err := repository.R().Webhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Webhook, len(wl))
+	for i, wh := range wl {
+		list[i] = NewWebhook(wh)
+	}
+	return list, nil
+}
+
+// Webhook resolves the registered webhook of the given ID.
+func (rs *rootResolver) Webhook(ctx context.Context, args *struct{ Id string }) (*Webhook, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	wh, err := repository.R().Webhook(args.Id)
+	if err != nil || wh == nil {
+		return nil, err
+	}
+	return NewWebhook(wh), nil
+}
+
+// CreateWebhook resolves registration of a new webhook. A random secret is generated
+// if the input does not provide one.
+func (rs *rootResolver) CreateWebhook(ctx context.Context, args *struct{ Hook WebhookInput }) (*Webhook, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	id, err := uuid()
+	if err != nil {
+		return nil, err
+	}
+
+	wh := types.Webhook{ID: id, Enabled: true, Created: time.Now().UTC()}
+	if err := args.Hook.apply(&wh); err != nil {
+		return nil, err
+	}
+
+	if err := repository.R().StoreWebhook(&wh); err != nil {
+		log.Warningf("can not create webhook; %s", err.Error())
+		return nil, err
+	}
+	return &Webhook{Webhook: wh, showSecret: true}, nil
+}
+
+// UpdateWebhook resolves update of an existing webhook. The secret is kept
+// if the input does not provide a new one.
+func (rs *rootResolver) UpdateWebhook(ctx context.Context, args *struct {
+	Id   string
+	Hook WebhookInput
+}) (*Webhook, error) {
+	if !isAdmin(ctx) {
+		return nil, errAdminRequired
+	}
+
+	wh, err := repository.R().Webhook(args.Id)
+	if err != nil {
+		return nil, err
+	}
+	if wh == nil {
+		return nil, fmt.Errorf("webhook %s not found", args.Id)
+	}
+
+	if err := args.Hook.apply(wh); err != nil {
+		return nil, err
+	}
+
+	if err := repository.R().StoreWebhook(wh); err != nil {
+		log.Warningf("can not update webhook %s; %s", wh.ID, err.Error())
+		return nil, err
+	}
+	return NewWebhook(wh), nil
+}
+
+// DeleteWebhook resolves removal of the webhook of the given ID together with its delivery log.
+func (rs *rootResolver) DeleteWebhook(ctx context.Context, args *struct{ Id string }) (bool, error) {
+	if !isAdmin(ctx) {
+		return false, errAdminRequired
+	}
+
+	if err := repository.R().DeleteWebhook(args.Id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// apply copies the webhook input into the given webhook.
+func (in *WebhookInput) apply(wh *types.Webhook) error {
+	if !types.IsValidWebhookURL(in.Url) {
+		return fmt.Errorf("invalid webhook URL %s, absolute http(s) URL expected", in.Url)
+	}
+
+	wh.URL = in.Url
+	wh.Events = in.Events
+
+	if in.Addresses != nil {
+		wh.Addresses = *in.Addresses
+	}
+	if in.Tokens != nil {
+		wh.Tokens = *in.Tokens
+	}
+	if in.Enabled != nil {
+		wh.Enabled = *in.Enabled
+	}
+
+	if in.Secret != nil && *in.Secret != "" {
+		wh.Secret = *in.Secret
+	}
+	if wh.Secret == "" {
+		secret := make([]byte, webhookSecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		wh.Secret = hex.EncodeToString(secret)
+	}
+	return nil
+}
+
+// Id resolves the ID of the webhook.
+func (wh *Webhook) Id() string {
+	return wh.ID
+}
+
+// Url resolves the URL the webhook deliveries are posted to.
+func (wh *Webhook) Url() string {
+	return wh.URL
+}
+
+// Secret resolves the secret used to sign the webhook deliveries.
+// It's available only in response to the webhook creation.
+func (wh *Webhook) Secret() *string {
+	if !wh.showSecret {
+		return nil
+	}
+	return &wh.Webhook.Secret
+}
+
+// Created resolves the UNIX time stamp of the webhook registration.
+func (wh *Webhook) Created() hexutil.Uint64 {
+	return hexutil.Uint64(wh.Webhook.Created.Unix())
+}
+
+// Deliveries resolves the list of the most recent deliveries of the webhook.
+func (wh *Webhook) Deliveries(args struct{ Count int32 }) ([]*WebhookDelivery, error) {
+	if args.Count <= 0 || args.Count > webhookDeliveriesMaxCount {
+		args.Count = webhookDeliveriesMaxCount
+	}
+
+	dl, err := repository.R().WebhookDeliveries(wh.ID, args.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*WebhookDelivery, len(dl))
+	for i, wd := range dl {
+		list[i] = NewWebhookDelivery(wd)
+	}
+	return list, nil
+}
+
+// Id resolves the ID of the webhook delivery.
+func (wd *WebhookDelivery) Id() string {
+	return wd.ID
+}
+
+// Payload resolves the JSON payload of the webhook delivery.
+func (wd *WebhookDelivery) Payload() string {
+	return string(wd.WebhookDelivery.Payload)
+}
+
+// LastError resolves the error of the last failed delivery attempt, if any.
+func (wd *WebhookDelivery) LastError() *string {
+	if wd.WebhookDelivery.LastError == "" {
+		return nil
+	}
+	return &wd.WebhookDelivery.LastError
+}
+
+// ResponseCode resolves the HTTP status code of the last delivery attempt, if any.
+func (wd *WebhookDelivery) ResponseCode() *int32 {
+	if wd.WebhookDelivery.ResponseCode == 0 {
+		return nil
+	}
+	return &wd.WebhookDelivery.ResponseCode
+}
+
+// Created resolves the UNIX time stamp of the delivery creation.
+func (wd *WebhookDelivery) Created() hexutil.Uint64 {
+	return hexutil.Uint64(wd.WebhookDelivery.Created.Unix())
+}
+
+// LastAttempt resolves the UNIX time stamp of the last delivery attempt, if any.
+func (wd *WebhookDelivery) LastAttempt() *hexutil.Uint64 {
+	if wd.WebhookDelivery.LastAttempt.IsZero() {
+		return nil
+	}
+	ts := hexutil.Uint64(wd.WebhookDelivery.LastAttempt.Unix())
+	return &ts
+}
+
+// NextAttempt resolves the UNIX time stamp of the next scheduled delivery attempt,
+// if the delivery is pending.
+func (wd *WebhookDelivery) NextAttempt() *hexutil.Uint64 {
+	if wd.Status != types.WebhookDeliveryPending {
+		return nil
+	}
+	ts := hexutil.Uint64(wd.WebhookDelivery.NextAttempt.Unix())
+	return &ts
+}
@@ -202,6 +202,32 @@ type ListPageInfo {
     # HasNext specifies if there is another edge before the first one.
     hasPrevious: Boolean!
 }
+# TransactionDirection represents the direction of a transaction
+# relative to a filtered address.
+enum TransactionDirection {
+    # ANY represents transactions both sent and received by the address.
+    ANY
+
+    # INCOMING represents transactions received by the address,
+    # including contract deployments of the address.
+    INCOMING
+
+    # OUTGOING represents transactions sent by the address.
+    OUTGOING
+
+    # SELF represents transactions sent by the address to itself.
+    SELF
+}
+
+# TransactionStatus represents the execution status of a transaction.
+enum TransactionStatus {
+    # SUCCESS represents successfully executed transactions.
+    SUCCESS
+
+    # FAILED represents reverted transactions.
+    FAILED
+}
+
 # Transaction is an Opera block chain transaction.
 type Transaction {
     # Hash is the unique hash of this transaction.
@@ -252,6 +278,17 @@ type Transaction {
     # is a contract address.
     inputData: Bytes!
 
+    # decodedInput represents the input data decoded using the verified ABI
+    # of the recipient contract, or ABI of a well known token standard (ERC20/ERC721/ERC1155).
+    # Constructor arguments are provided for contract deployments.
+    # Null if the input data can not be decoded.
+    decodedInput: DecodedCall
+
+    # methodName is the name of the contract function called by this transaction,
+    # identified by the verified ABI of the recipient contract, or by the signature database.
+    # Null if the function can not be identified, or if this is a contract creation.
+    methodName: String
+
     # BlockHash is the hash of the block this transaction was assigned to.
     # Null if the transaction is pending.
     blockHash: Bytes32
@@ -270,6 +307,9 @@ type Transaction {
     # field will be null.
     status: Long
 
+    # logs is the list of log records emitted in the scope of this transaction call.
+    logs: [Log!]!
+
     # tokenTransactions represents a list of generic token transactions executed in the scope
     # of the transaction call; token type and transaction type is provided.
     tokenTransactions: [TokenTransaction!]!
@@ -363,6 +403,15 @@ type Block {
     txList: [Transaction!]!
 }
 
+# BlockClosest represents the side of a time stamp to look for the closest block on.
+enum BlockClosest {
+    # BEFORE is the latest block collated before, or at the time stamp.
+    BEFORE
+
+    # AFTER is the earliest block collated after the time stamp.
+    AFTER
+}
+
 # ERC721Contract represents a generic ERC721 non-fungible tokens (NFT) contract.
 type ERC721Contract {
     # address of the token is used as the token's unique identifier.
@@ -487,6 +536,12 @@ type Contract {
 
     "Timestamp is the unix timestamp at which this smart contract was deployed."
     timestamp: Long!
+
+    """
+    ViewFunctions is the list of read-only functions declared by the contract ABI
+    with their input and output parameters. Empty if the ABI is not available.
+    """
+    viewFunctions: [ContractFunction!]!
 }
 
 # ContractValidationInput represents a set of data sent from client
@@ -521,6 +576,104 @@ input ContractValidationInput {
 
     "Smart contract source code."
     sourceCode: String!
+
+    """
+    CodeFormat specifies the format of the source code; solidity-single-file,
+    or solidity-standard-json-input. Single file source code is assumed if not specified.
+    """
+    codeFormat: String
+
+    """
+    CompilerVersion specifies the compiler release used to build the contract,
+    i.e. v0.8.17+commit.8df45f5f. The default compiler is used if not specified.
+    """
+    compilerVersion: String
+
+    "EvmVersion specifies the target EVM version of a single file source code compilation."
+    evmVersion: String
+
+    "ConstructorArguments specifies hex encoded ABI constructor arguments of the contract deployment."
+    constructorArguments: String
+}
+
+# ContractSyncInput represents a validated contract synced between API peers.
+# The sync is signed by the sending peer and accepted only from trusted signers.
+input ContractSyncInput {
+    "Address of the validated contract."
+    address: Address!
+
+    "Smart contract name."
+    name: String!
+
+    "Smart contract version identifier."
+    version: String!
+
+    "Smart contract author contact."
+    supportContact: String!
+
+    "Open source license of the contract."
+    license: String!
+
+    "Identifier of the compiler used to validate the contract."
+    compiler: String!
+
+    "Optimized specifies if the compiler was set to optimize the byte code."
+    optimized: Boolean!
+
+    "OptimizeRuns specifies number of optimization runs used during compilation."
+    optimizeRuns: Int!
+
+    "Validated smart contract source code."
+    sourceCode: String!
+
+    "ABI definition of the validated contract."
+    abi: String!
+
+    "Validated is the unix timestamp of the contract validation."
+    validated: Long!
+
+    "Issued is the unix timestamp of the sync signature; stale syncs are rejected."
+    issued: Long!
+
+    "Audience is the host of the API peer the sync is addressed to, matching its configured sync audience."
+    audience: String!
+
+    "Signer is the address of the API peer signing the sync."
+    signer: Address!
+
+    "Signature of the sync content made by the signer."
+    signature: Bytes!
+}
+
+# ContractSyncState represents the state of a contract validation sync to an API peer.
+enum ContractSyncState {
+    PENDING
+    SYNCED
+    FAILED
+}
+
+# ContractSyncStatus represents the status of a contract validation sync to an API peer.
+type ContractSyncStatus {
+    "Address of the synced contract."
+    address: Address!
+
+    "Peer is the URL of the API peer the contract is synced to."
+    peer: String!
+
+    "Status is the current state of the sync."
+    status: ContractSyncState!
+
+    "Attempts is the number of sync attempts made."
+    attempts: Int!
+
+    "LastError is the error of the last failed attempt; empty if none."
+    lastError: String!
+
+    "LastAttempt is the unix timestamp of the last sync attempt."
+    lastAttempt: Long!
+
+    "NextAttempt is the unix timestamp of the next scheduled attempt. Null if no attempt is scheduled."
+    nextAttempt: Long
 }
 
 # ContractList is a list of smart contract edges provided by sequential access request.
@@ -582,6 +735,9 @@ scalar Cursor
 # Time represents date and time including time zone information in RFC3339 format.
 scalar Time
 
+# JSON is an arbitrary JSON value; a scalar, a list, or an object.
+scalar JSON
+
 # ERC1155TransactionList is a list of ERC1155 transaction edges provided by sequential access request.
 type ERC1155TransactionList {
     # Edges contains provided edges of the sequential list.
@@ -686,6 +842,18 @@ type ERC721Transaction {
     # of the ERC721 transaction processing.
     timeStamp: Long!
 }
+# TokenType represents the type of a supported token contract.
+enum TokenType {
+    # ERC20 represents fungible tokens.
+    ERC20
+
+    # ERC721 represents non-fungible tokens.
+    ERC721
+
+    # ERC1155 represents multi-tokens.
+    ERC1155
+}
+
 # TokenTransaction represents a generic token transaction
 # of a supported type of token.
 type TokenTransaction {
@@ -753,16 +921,48 @@ type Account {
     txCount: Long!
 
     # txList represents list of transactions of the account in form of TransactionList.
-    txList(recipient: Address, cursor:Cursor, count:Int!): TransactionList!
+    # The list can be limited to a range of blocks and/or UNIX time stamps, bounds are inclusive.
+    # The list can be further filtered by the direction relative to the account, the execution status,
+    # contract creations only, calls to a 4-byte method selector (e.g. 0xa9059cbb)
+    # and the minimum and/or maximum value in WEI; values are compared with 1 gWei precision.
+    txList(
+        recipient: Address,
+        cursor:Cursor,
+        count:Int!,
+        fromBlock: Long,
+        toBlock: Long,
+        fromTime: Long,
+        toTime: Long,
+        direction: TransactionDirection = ANY,
+        status: TransactionStatus,
+        creationsOnly: Boolean = false,
+        method: String,
+        minValue: BigInt,
+        maxValue: BigInt
+    ): TransactionList!
 
     # erc20TxList represents list of ERC20 transactions of the account.
-    erc20TxList(cursor:Cursor, count:Int = 25, token: Address, txType: [TokenTransactionType!]): ERC20TransactionList!
+    # The list can be limited to any of the given tokens and to the minimum and/or maximum
+    # raw amount of tokens transferred; amounts are compared with 1e-9 precision of the raw amount.
+    erc20TxList(
+        cursor:Cursor,
+        count:Int = 25,
+        token: Address,
+        txType: [TokenTransactionType!],
+        fromBlock: Long,
+        toBlock: Long,
+        fromTime: Long,
+        toTime: Long,
+        tokens: [Address!],
+        minAmount: BigInt,
+        maxAmount: BigInt
+    ): ERC20TransactionList!
 
     # erc721TxList represents list of ERC721 transactions of the account.
-    erc721TxList(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, txType: [TokenTransactionType!]): ERC721TransactionList!
+    erc721TxList(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, txType: [TokenTransactionType!], fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long): ERC721TransactionList!
 
     # erc1155TxList represents list of ERC1155 transactions of the account.
-    erc1155TxList(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, txType: [TokenTransactionType!]): ERC1155TransactionList!
+    erc1155TxList(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, txType: [TokenTransactionType!], fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long): ERC1155TransactionList!
 
     # Details about smart contract, if the account is a smart contract.
     contract: Contract
@@ -806,6 +1006,18 @@ type Query {
     # negative <count> starts the list from bottom.
     blocks(cursor:Cursor, count:Int!):BlockList!
 
+    # Get the block collated closest to the given UNIX time stamp.
+    # The latest block collated before, or at the time stamp is given by default,
+    # use closest AFTER to get the earliest block collated after the time stamp.
+    blockByTime(timestamp: Long!, closest: BlockClosest = BEFORE): Block
+
+    # Search resolves the given term into a list of matching entities.
+    # Block numbers (decimal, or hex), block and transaction hashes and addresses
+    # are recognized directly. Other terms are prefix matched against names and symbols
+    # of ERC20, ERC721 and ERC1155 tokens and names of verified contracts;
+    # exact matches go first, verified and more active contracts are preferred.
+    search(term: String!, count: Int = 10): [SearchResult!]!
+
     # Get transaction information for given transaction hash.
     transaction(hash:Bytes32!):Transaction
 
@@ -814,16 +1026,19 @@ type Query {
     # if negative, return edges before the cursor.
     # For undefined cursor, positive <count> starts the list from top,
     # negative <count> starts the list from bottom.
-    transactions(cursor:Cursor, count:Int!):TransactionList!
+    # The list can be limited to a range of blocks (fromBlock, toBlock)
+    # and/or a range of UNIX time stamps (fromTime, toTime); all bounds are inclusive.
+    transactions(cursor:Cursor, count:Int!, fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long):TransactionList!
 
     # Get filtered list of ERC20 Transactions.
-    erc20Transactions(cursor:Cursor, count:Int = 25, token: Address, account: Address, txType: [TokenTransactionType!]): ERC20TransactionList!
+    # The block and time range bounds are inclusive.
+    erc20Transactions(cursor:Cursor, count:Int = 25, token: Address, account: Address, txType: [TokenTransactionType!], fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long): ERC20TransactionList!
 
     # Get filtered list of ERC721 Transactions.
-    erc721Transactions(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, account: Address, txType: [TokenTransactionType!]): ERC721TransactionList!
+    erc721Transactions(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, account: Address, txType: [TokenTransactionType!], fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long): ERC721TransactionList!
 
     # Get filtered list of ERC1155 Transactions.
-    erc1155Transactions(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, account: Address, txType: [TokenTransactionType!]): ERC1155TransactionList!
+    erc1155Transactions(cursor:Cursor, count:Int = 25, token: Address, tokenId: BigInt, account: Address, txType: [TokenTransactionType!], fromBlock: Long, toBlock: Long, fromTime: Long, toTime: Long): ERC1155TransactionList!
 
     # Returns the current price per gas in WEI units.
     gasPrice: Long!
@@ -896,6 +1111,69 @@ type Query {
 
     # networkNodesAggregated provides an aggregated list of network nodes on the Opera network.
     networkNodesAggregated(level: NetworkNodeGroupLevel = COUNTRY): NetworkNodeGroupList!
+
+    # contractSyncStatus provides the status of contract validation syncs to API peers
+    # for the given contract. If the contract is not specified, syncs not finished yet are provided.
+    contractSyncStatus(address: Address): [ContractSyncStatus!]!
+
+    # callContract executes a read-only call of the given contract function on the state
+    # of the given block, or the latest block, and provides the decoded result.
+    # The call is encoded using the stored contract ABI, or ABI of a well known token standard.
+    # The method is identified by its name, or by its full signature (i.e. balanceOf(address))
+    # if the name is overloaded. Arguments of numeric types are accepted as decimal, or hex numbers,
+    # arguments of array and tuple types are expected to be JSON encoded.
+    callContract(address: Address!, method: String!, args: [String!], block: Long): ContractCallResult!
+
+    # simulateTransaction executes the given transaction on the state of the given block,
+    # or the latest block, without sending it to the block chain. Optional state overrides
+    # are applied to the accounts before the transaction is executed.
+    simulateTransaction(from: Address, to: Address, value: BigInt, data: Bytes, block: Long, stateOverrides: [StateOverrideInput!]): SimulationResult!
+
+    # signatures provides the list of candidate text signatures known
+    # for the given 4 bytes function selector, or 32 bytes event topic.
+    signatures(hash: Bytes!): [Signature!]!
+
+    # faucetRequests provides the list of the most recent native tokens transfers sent by the faucet,
+    # optionally limited to the given recipient address. At most 100 requests are provided.
+    faucetRequests(address: Address, count: Int = 25): [FaucetRequest!]!
+
+    # submittedTransaction provides the lifecycle state of a transaction submitted
+    # through the API; null if the transaction was not submitted through this API.
+    # Transactions are tracked until they are mined, replaced, or dropped.
+    submittedTransaction(hash: Bytes32!): SubmittedTransaction
+
+    # webhooks provides the list of all registered webhooks.
+    # Requires administrative access.
+    webhooks: [Webhook!]!
+
+    # webhook provides the registered webhook of the given ID; null if not found.
+    # Requires administrative access.
+    webhook(id: String!): Webhook
+
+    # apiKeys provides the list of all the API keys.
+    # Requires administrative access.
+    apiKeys: [ApiKey!]!
+
+    # apiKey provides the API key of the given ID; null if not found.
+    # Requires administrative access.
+    apiKey(id: String!): ApiKey
+
+    # apiTiers provides the list of the configured API access tiers.
+    # Requires administrative access.
+    apiTiers: [ApiTier!]!
+
+    # anonymousApiUsage provides the daily usage of the API by clients without an API key
+    # over the given number of the most recent days, the most recent day first.
+    # Requires administrative access.
+    anonymousApiUsage(days: Int = 30): [ApiKeyUsage!]!
+
+    # Get list of Alerts raised by the alert rules with at most <count> edges.
+    # If <count> is positive, return edges after the cursor,
+    # if negative, return edges before the cursor.
+    # For undefined cursor, positive <count> starts the list from top,
+    # negative <count> starts the list from bottom.
+    # Requires administrative access.
+    alerts(cursor: Cursor, count: Int!): AlertList!
 }
 
 # Mutation endpoints for modifying the data
@@ -910,6 +1188,48 @@ type Mutation {
     # Returns updated contract information. If the contract can not be validated,
     # it raises a GraphQL error.
     validateContract(contract: ContractValidationInput!): Contract!
+
+    # Sync a validated contract from a trusted API peer. The sync must be signed
+    # by a peer signer on the allow-list of this API server. The contract source code
+    # is not re-compiled. If the sync is rejected, it raises a GraphQL error.
+    syncContract(contract: ContractSyncInput!): Contract!
+
+    # registerSignature registers a new text signature of a contract function,
+    # or an event, in the signature database. The hash is either the 4 bytes function selector,
+    # or the 32 bytes event topic. The signature text must hash to the given value,
+    # otherwise a GraphQL error is raised. Requires administrative access.
+    registerSignature(hash: Bytes!, signature: String!): Signature!
+
+    # requestFunds sends the configured amount of native tokens from the server account
+    # to the given address. Requests are rate limited per address and per client,
+    # and the total amount sent per day is capped by the faucet budget.
+    requestFunds(address: Address!): FaucetRequest!
+
+    # createWebhook registers a new webhook receiving address activity notifications.
+    # The generated secret is provided only in the response of this call.
+    # Requires administrative access.
+    createWebhook(hook: WebhookInput!): Webhook!
+
+    # updateWebhook updates the registration details of an existing webhook.
+    # Requires administrative access.
+    updateWebhook(id: String!, hook: WebhookInput!): Webhook!
+
+    # deleteWebhook removes the webhook of the given ID together with its delivery log.
+    # Requires administrative access.
+    deleteWebhook(id: String!): Boolean!
+
+    # createApiKey generates a new API key of the given holder name and access tier.
+    # The key is provided only in the response of this call.
+    # Requires administrative access.
+    createApiKey(name: String!, tier: String!): ApiKey!
+
+    # updateApiKey updates the holder name, the access tier, or the state of an existing API key.
+    # Requires administrative access.
+    updateApiKey(id: String!, name: String, tier: String, enabled: Boolean): ApiKey!
+
+    # deleteApiKey removes the API key of the given ID together with its usage records.
+    # Requires administrative access.
+    deleteApiKey(id: String!): Boolean!
 }
 
 # Subscriptions to live events broadcasting
@@ -918,7 +1238,574 @@ type Subscription {
     onBlock: Block!
 
     # Subscribe to receive information about new transactions in the blockchain.
-    onTransaction: Transaction!
+    # If the address is provided, only transactions sent and/or received by the address
+    # are broadcast, based on the direction.
+    onTransaction(address: Address, direction: TransactionDirection = ANY): Transaction!
+
+    # Subscribe to receive information about new ERC20/ERC721/ERC1155 token transfers,
+    # including mints and burns. Transfers can be filtered by the token contract,
+    # the account on either side of the transfer, and the token type (ERC20/ERC721/ERC1155).
+    onTokenTransfer(token: Address, account: Address, tokenType: TokenType): TokenTransaction!
+
+    # Subscribe to receive new log records emitted by contracts. Log records can be filtered
+    # by the emitting contract and the topics. Topics are matched by position; null, or empty list
+    # on a position matches any topic, multiple topics on the same position match any of them.
+    onLogs(address: Address, topics: [[Bytes32!]]): Log!
+
+    # Subscribe to receive transactions deploying new contracts.
+    # The address of the new contract is available in the contractAddress field.
+    onContractDeployed: Transaction!
+
+    # Subscribe to receive status changes of a transaction submitted through the API.
+    # The current state is sent immediately, the stream ends once the transaction
+    # is mined, replaced, or dropped.
+    onTransactionStatus(hash: Bytes32!): SubmittedTransaction!
+}
+
+# DecodedCall represents a contract call input data decoded using a known ABI.
+type DecodedCall {
+    # method is the name of the called contract function;
+    # "constructor" is used for contract deployments.
+    method: String!
+
+    # signature is the canonical signature of the called function,
+    # i.e. transfer(address,uint256)
+    signature: String!
+
+    # args is the list of decoded call arguments.
+    args: [DecodedArgument!]!
+}
+
+# DecodedArgument represents a single argument decoded using a known ABI.
+type DecodedArgument {
+    # name is the name of the argument as declared by the ABI.
+    # Empty if the argument is not named.
+    name: String!
+
+    # type is the canonical Solidity type of the argument, i.e. uint256
+    type: String!
+
+    # value is the human-readable value of the argument. Numbers are provided
+    # as decimal strings, addresses and byte sequences as hex strings,
+    # arrays and tuples as JSON.
+    value: String!
+}
+
+# DecodedEvent represents a contract event log record decoded using a known ABI,
+# or a known event signature.
+type DecodedEvent {
+    # name is the name of the emitted event.
+    name: String!
+
+    # signature is the canonical signature of the emitted event,
+    # i.e. Transfer(address,address,uint256)
+    signature: String!
+
+    # args is the list of decoded event arguments. Indexed arguments of dynamic types
+    # (strings, byte sequences, arrays and tuples) are provided as the hash of the value.
+    # Arguments decoded by the signature database are not named.
+    args: [DecodedArgument!]!
+}
+
+# Log represents a log record emitted by a contract in the scope of a transaction call.
+type Log {
+    # index is the index of the log record in the block.
+    index: Long!
+
+    # transactionHash is the hash of the transaction which emitted the log record.
+    # Zero hash for log records of simulated transactions.
+    transactionHash: Bytes32!
+
+    # blockNumber is the number of the block the log record was emitted in.
+    blockNumber: Long!
+
+    # address is the address of the contract which emitted the log record.
+    address: Address!
+
+    # topics is the list of indexed topics of the log record.
+    # The first topic identifies the event, unless the event is anonymous.
+    topics: [Bytes32!]!
+
+    # data is the non-indexed data of the log record.
+    data: Bytes!
+
+    # eventSignature is the text signature of the emitted event,
+    # i.e. Transfer(address,address,uint256), identified by the verified ABI
+    # of the emitting contract, or by the signature database.
+    # Null if the event can not be identified.
+    eventSignature: String
+
+    # decoded is the log record decoded using the verified ABI of the emitting contract,
+    # ABI of a well known token standard (ERC20/ERC721/ERC1155), or the signature database.
+    # Null if the log record can not be decoded.
+    decoded: DecodedEvent
+}
+
+# SignatureType represents the type of a text signature.
+enum SignatureType {
+    FUNCTION
+    EVENT
+}
+
+# Signature represents a text signature of a contract function, or an event,
+# mapped to its 4 bytes function selector, or 32 bytes event topic.
+type Signature {
+    # type represents the type of the signature.
+    type: SignatureType!
+
+    # hash is the 4 bytes function selector, or the 32 bytes event topic
+    # calculated from the signature text.
+    hash: Bytes!
+
+    # text is the canonical text of the signature, i.e. transfer(address,uint256)
+    text: String!
+}
+
+# AbiParameter represents an input, or output parameter of a contract function
+# as declared by the contract ABI.
+type AbiParameter {
+    # name is the name of the parameter; empty if not named.
+    name: String!
+
+    # type is the canonical Solidity type of the parameter, i.e. uint256, or (address,uint256)[]
+    type: String!
+
+    # components is the list of tuple components if the parameter is a tuple,
+    # or an array of tuples; empty otherwise.
+    components: [AbiParameter!]!
+}
+
+# ContractFunction represents a contract function as declared by the contract ABI.
+type ContractFunction {
+    # name is the name of the function.
+    name: String!
+
+    # signature is the canonical signature of the function, i.e. balanceOf(address)
+    signature: String!
+
+    # selector is the 4 bytes selector of the function.
+    selector: Bytes!
+
+    # stateMutability is the state mutability of the function, i.e. view, or pure.
+    stateMutability: String!
+
+    # inputs is the list of input parameters of the function.
+    inputs: [AbiParameter!]!
+
+    # outputs is the list of output parameters of the function.
+    outputs: [AbiParameter!]!
+}
+
+# ContractCallResult represents the decoded result of a read-only contract call.
+type ContractCallResult {
+    # method is the name of the called contract function.
+    method: String!
+
+    # signature is the canonical signature of the called function.
+    signature: String!
+
+    # outputs is the list of typed output values of the call.
+    outputs: [ContractCallOutput!]!
+}
+
+# ContractCallOutput represents a single typed output value of a read-only contract call.
+type ContractCallOutput {
+    # name is the name of the output parameter; empty if not named.
+    name: String!
+
+    # type is the canonical Solidity type of the output parameter, i.e. uint256, or (address,uint256)[]
+    type: String!
+
+    # value is the output value of the Solidity type. Numbers are decimal strings to keep
+    # their full precision, bytes, addresses and hashes are hex strings, booleans are booleans,
+    # arrays are lists and tuples are objects keyed by the component names.
+    value: JSON!
+}
+
+# StorageSlotInput represents a value of a contract storage slot
+# used to override the state of a simulated transaction.
+input StorageSlotInput {
+    # slot is the key of the storage slot.
+    slot: Bytes32!
+
+    # value is the value of the storage slot.
+    value: Bytes32!
+}
+
+# StateOverrideInput represents temporary changes of an account state
+# applied before a transaction is simulated.
+input StateOverrideInput {
+    # address is the address of the account overridden.
+    address: Address!
+
+    # balance is the fake balance of the account in WEI.
+    balance: BigInt
+
+    # nonce is the fake nonce of the account.
+    nonce: Long
+
+    # code is the fake byte code of the account.
+    code: Bytes
+
+    # state replaces the whole storage of the account with the given slots.
+    state: [StorageSlotInput!]
+
+    # stateDiff overrides individual slots of the account storage.
+    stateDiff: [StorageSlotInput!]
+}
+
+# SimulationResult represents the outcome of a simulated transaction.
+type SimulationResult {
+    # success signals the transaction would finish without being reverted.
+    success: Boolean!
+
+    # error is the execution error of a failed transaction (i.e. execution reverted, out of gas).
+    # Null if the transaction succeeded.
+    error: String
+
+    # revertReason is the decoded reason of a reverted transaction, if provided by the contract.
+    # Both Error(string) and Panic(uint256) errors are recognized.
+    revertReason: String
+
+    # gasUsed is the amount of gas used by the transaction. The value is estimated
+    # if the connected node does not support the call tracer.
+    gasUsed: Long!
+
+    # returnData is the raw output of the call, or the revert data of a reverted call.
+    returnData: Bytes!
+
+    # logsAvailable signals the emitted logs were collected. Logs are available only
+    # if the connected node supports the call tracer (debug_traceCall).
+    logsAvailable: Boolean!
+
+    # logs is the list of log records the transaction would emit.
+    logs: [Log!]!
+
+    # tokenTransactions is the list of ERC20/ERC721/ERC1155 token transactions
+    # the transaction would execute, decoded from the emitted logs.
+    tokenTransactions: [TokenTransaction!]!
+}
+
+# FaucetRequest represents a transfer of native tokens sent by the faucet.
+type FaucetRequest {
+    # hash is the hash of the faucet transfer transaction.
+    hash: Bytes32!
+
+    # recipient is the address the native tokens were sent to.
+    recipient: Address!
+
+    # amount is the amount of native tokens sent in WEI.
+    amount: BigInt!
+
+    # timeStamp is the UNIX time stamp of the faucet request.
+    timeStamp: Long!
+
+    # transaction is the faucet transfer transaction; null if not available yet.
+    transaction: Transaction
+}
+
+# SubmittedTransactionStatus represents the lifecycle state
+# of a transaction submitted through the API.
+enum SubmittedTransactionStatus {
+    # PENDING represents a transaction waiting to be mined.
+    PENDING
+
+    # MINED represents a transaction included in a block.
+    MINED
+
+    # REPLACED represents a transaction replaced by another transaction
+    # of the same sender and nonce.
+    REPLACED
+
+    # DROPPED represents a transaction not mined in time.
+    DROPPED
+}
+
+# SubmittedTransaction represents the lifecycle state of a transaction
+# submitted through the API.
+type SubmittedTransaction {
+    # hash is the hash of the submitted transaction.
+    hash: Bytes32!
+
+    # from is the address of the transaction sender.
+    from: Address!
+
+    # nonce is the nonce of the submitted transaction.
+    nonce: Long!
+
+    # status is the current lifecycle state of the submitted transaction.
+    status: SubmittedTransactionStatus!
+
+    # blockNumber is the number of the block the transaction was mined in.
+    # Null if the transaction has not been mined.
+    blockNumber: Long
+
+    # replacedBy is the hash of the transaction which replaced the submitted transaction.
+    # Null if the transaction has not been replaced, or if the replacement is not known.
+    replacedBy: Bytes32
+
+    # submitted is the UNIX time stamp of the transaction submission.
+    submitted: Long!
+
+    # updated is the UNIX time stamp of the last status change.
+    updated: Long!
+
+    # transaction is the submitted transaction; null if not available.
+    transaction: Transaction
+
+    # replacement is the transaction which replaced the submitted transaction; null if not available.
+    replacement: Transaction
+}
+
+# WebhookEvent represents the kind of address activity delivered to a webhook.
+enum WebhookEvent {
+    # TRANSACTION represents a transaction sent by, sent to,
+    # or deploying a contract at a watched address.
+    TRANSACTION
+
+    # TOKEN_TRANSFER represents a transfer, mint, or burn of a watched token,
+    # or a token transfer from, or to a watched address.
+    TOKEN_TRANSFER
+}
+
+# WebhookDeliveryStatus represents the state of a webhook delivery.
+enum WebhookDeliveryStatus {
+    # PENDING represents a delivery waiting for a (re)try.
+    PENDING
+
+    # DELIVERED represents a delivery accepted by the receiver.
+    DELIVERED
+
+    # FAILED represents a delivery abandoned after too many failed attempts.
+    FAILED
+}
+
+# WebhookInput represents the registration details of a webhook.
+input WebhookInput {
+    # url is the HTTP(S) URL the deliveries are posted to.
+    url: String!
+
+    # secret is the key used to sign the deliveries. A random secret
+    # is generated on creation, and kept on update, if not provided.
+    secret: String
+
+    # addresses is the list of watched account addresses.
+    addresses: [Address!]
+
+    # tokens is the list of watched token contracts; used by TOKEN_TRANSFER events only.
+    tokens: [Address!]
+
+    # events is the list of activity kinds delivered to the webhook.
+    events: [WebhookEvent!]!
+
+    # enabled controls if the deliveries are made. New webhooks are enabled by default.
+    enabled: Boolean
+}
+
+# Webhook represents a registered receiver of address activity notifications.
+# Deliveries are posted as JSON documents signed by HMAC-SHA256 of the body
+# keyed by the webhook secret. The hex encoded signature is sent in the
+# X-Webhook-Signature header prefixed with "sha256=". Failed deliveries are
+# retried with exponential backoff.
+type Webhook {
+    # id is the unique identifier of the webhook.
+    id: String!
+
+    # url is the HTTP(S) URL the deliveries are posted to.
+    url: String!
+
+    # secret is the key used to sign the deliveries.
+    # It's available only in response to the webhook creation.
+    secret: String
+
+    # addresses is the list of watched account addresses.
+    addresses: [Address!]!
+
+    # tokens is the list of watched token contracts.
+    tokens: [Address!]!
+
+    # events is the list of activity kinds delivered to the webhook.
+    events: [WebhookEvent!]!
+
+    # enabled signals if the deliveries are made.
+    enabled: Boolean!
+
+    # created is the UNIX time stamp of the webhook registration.
+    created: Long!
+
+    # deliveries is the list of the most recent deliveries of the webhook,
+    # newest first. At most 100 deliveries are provided.
+    deliveries(count: Int = 25): [WebhookDelivery!]!
+}
+
+# WebhookDelivery represents a single notification delivery to a webhook.
+type WebhookDelivery {
+    # id is the unique identifier of the delivery.
+    id: String!
+
+    # event is the kind of the delivered activity.
+    event: WebhookEvent!
+
+    # payload is the JSON document posted to the webhook.
+    payload: String!
+
+    # status is the current state of the delivery.
+    status: WebhookDeliveryStatus!
+
+    # attempts is the number of delivery attempts made.
+    attempts: Int!
+
+    # responseCode is the HTTP status code of the last attempt;
+    # null if no response has been received.
+    responseCode: Int
+
+    # lastError is the error of the last failed attempt; null if none.
+    lastError: String
+
+    # created is the UNIX time stamp of the delivery creation.
+    created: Long!
+
+    # lastAttempt is the UNIX time stamp of the last attempt; null if not attempted yet.
+    lastAttempt: Long
+
+    # nextAttempt is the UNIX time stamp of the next scheduled attempt;
+    # null if the delivery is not pending.
+    nextAttempt: Long
+}
+
+# AlertEvent represents the kind of indexed activity an alert rule is evaluated against.
+enum AlertEvent {
+    # TRANSACTION represents a processed transaction.
+    TRANSACTION
+
+    # TOKEN_TRANSFER represents a processed token transaction.
+    TOKEN_TRANSFER
+}
+
+# Alert represents a match of a declarative alert rule on the indexed activity.
+# Rules are configured by the API server operator.
+type Alert {
+    # id is the unique identifier of the alert.
+    id: String!
+
+    # rule is the identifier of the matched rule.
+    rule: String!
+
+    # ruleName is the human-readable name of the matched rule.
+    ruleName: String!
+
+    # event is the kind of the activity which raised the alert.
+    event: AlertEvent!
+
+    # transactionHash is the hash of the transaction which raised the alert.
+    transactionHash: Bytes32!
+
+    # transaction is the transaction which raised the alert; null if not available.
+    transaction: Transaction
+
+    # blockNumber is the number of the block the alert was raised in.
+    blockNumber: Long!
+
+    # from is the sender of the transaction, or the token transaction.
+    from: Address!
+
+    # to is the recipient of the transaction, or the token transaction;
+    # null for contract deployments.
+    to: Address
+
+    # token is the token contract of a token transaction; null for transactions.
+    token: Address
+
+    # value is the native value of the transaction, or the amount of the token transaction.
+    value: BigInt!
+
+    # message is the human-readable description of the alert.
+    message: String!
+
+    # timeStamp is the UNIX time stamp of the block the alert was raised in.
+    timeStamp: Long!
+
+    # created is the UNIX time stamp of the alert creation.
+    created: Long!
+}
+
+# AlertList is a list of alert edges provided by sequential access request.
+type AlertList {
+    # Edges contains provided edges of the sequential list.
+    edges: [AlertListEdge!]!
+
+    # TotalCount is the maximum number of alerts available for sequential access.
+    totalCount: BigInt!
+
+    # PageInfo is an information about the current page of alert edges.
+    pageInfo: ListPageInfo!
+}
+
+# AlertListEdge is a single edge in a sequential list of alerts.
+type AlertListEdge {
+    cursor: Cursor!
+    alert: Alert!
+}
+
+# SearchResult represents an entity the search term resolved into.
+union SearchResult = Block | Transaction | Account | ERC20Token | ERC721Contract | ERC1155Contract | Contract
+
+# ApiKey represents an API key granting its holder the limits of an access tier.
+type ApiKey {
+    # id is the unique identifier of the key used to manage it.
+    id: String!
+
+    # key is the API key to be presented by the client. It's available only
+    # in response to the key creation, the key can not be recovered later.
+    key: String
+
+    # prefix represents the first characters of the key helping to recognize it.
+    prefix: String!
+
+    # name represents the name of the key holder.
+    name: String!
+
+    # tier represents the name of the access tier of the key.
+    tier: String!
+
+    # enabled signals the key can be used to access the API.
+    enabled: Boolean!
+
+    # created represents the UNIX time stamp of the key creation.
+    created: Long!
+
+    # usage provides the daily usage of the key over the given number
+    # of the most recent days, the most recent day first.
+    usage(days: Int = 30): [ApiKeyUsage!]!
+}
+
+# ApiKeyUsage represents the usage of an API key on a single day.
+type ApiKeyUsage {
+    # day represents the UTC day of the usage in format YYYY-MM-DD.
+    day: String!
+
+    # requests represents the number of requests served.
+    requests: Long!
+
+    # rejected represents the number of requests rejected by the rate limits,
+    # or the daily quota.
+    rejected: Long!
+}
+
+# ApiTier represents an API access tier with its rate limits.
+# Zero limits are not enforced.
+type ApiTier {
+    # name represents the name of the tier.
+    name: String!
+
+    # rate represents the number of requests per second allowed.
+    rate: Float!
+
+    # burst represents the max number of requests allowed at once.
+    burst: Int!
+
+    # dailyQuota represents the max number of requests allowed per UTC day.
+    dailyQuota: Long!
 }
 
 `
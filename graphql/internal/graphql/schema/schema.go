@@ -2,9 +2,20 @@
 // to validate requests and build responses on the API interface.
 package gqlschema
 
+import "regexp"
+
 //go:generate sh ./tools/make_bundle.sh
 
+// mutationEntryPoint matches the mutation entry point of the root schema definition.
+var mutationEntryPoint = regexp.MustCompile(`(?m)^\s*mutation\s*:\s*Mutation\s*$\n?`)
+
 // Schema provides textual representation of the GraphQL schema content.
 func Schema() string {
 	return schema
 }
+
+// ReadOnly provides textual representation of the GraphQL schema content without the mutation
+// entry point. The Mutation type stays defined, but no mutation operation can be executed.
+func ReadOnly() string {
+	return mutationEntryPoint.ReplaceAllString(schema, "")
+}
@@ -3,6 +3,7 @@
 package gqlschema
 
 import (
+	"github.com/graph-gophers/graphql-go"
 	"github.com/onsi/gomega"
 	"testing"
 )
@@ -100,3 +101,15 @@ func TestBundleContent(t *testing.T) {
 		g.Expect(s).To(gomega.MatchRegexp(c.re))
 	}
 }
+
+func TestReadOnly(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ro := ReadOnly()
+	g.Expect(ro).To(gomega.MatchRegexp("(?m)^schema\\s+{\\s+query\\s*:\\s*Query\\s+subscription\\s*:\\s*Subscription\\s*}"))
+	g.Expect(ro).To(gomega.MatchRegexp("(?m)^type\\s+Mutation\\s+{"))
+
+	// the read only schema is still valid
+	_, err := graphql.ParseSchema(ro, nil)
+	g.Expect(err).To(gomega.BeNil())
+}
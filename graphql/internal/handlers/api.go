@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/graphql/complexity"
 	"fantom-api-graphql/internal/graphql/resolvers"
 	gqlSchema "fantom-api-graphql/internal/graphql/schema"
 	"fantom-api-graphql/internal/logger"
@@ -19,16 +20,36 @@ func Api(cfg *config.Config, log logger.Logger, rs resolvers.ApiResolver) http.H
 	corsHandler := cors.New(corsOptions(cfg))
 	corsHandler.Log = log
 
-	// we don't want to write a method for each type field if it could be matched directly
-	opts := []graphql.SchemaOpt{graphql.UseFieldResolvers()}
+	// we don't want to write a method for each type field if it could be matched directly;
+	// list items are resolved in parallel so batch loaders can collect a full batch of keys
+	opts := []graphql.SchemaOpt{graphql.UseFieldResolvers(), graphql.MaxParallelism(resolvers.LoaderMaxBatch)}
 
-	// create new parsed GraphQL schema
-	schema := graphql.MustParseSchema(gqlSchema.Schema(), rs, opts...)
+	// create new parsed GraphQL schema; the API mode serves data indexed by another instance
+	// and does not change any state, mutations are not offered at all
+	sdl := gqlSchema.Schema()
+	if cfg.Server.Mode == config.ModeApi {
+		sdl = gqlSchema.ReadOnly()
+	}
+	schema := graphql.MustParseSchema(sdl, rs, opts...)
+
+	// operations over the limits are rejected before they are executed, both over HTTP and websocket;
+	// batch loaders live for a single HTTP request, subscriptions resolve without them
+	analyzer := complexity.New(schema.ASTSchema(), &cfg.Complexity)
+	limited := &ComplexityHandler{
+		handler:   &LoaderHandler{handler: &relay.Handler{Schema: schema}},
+		analyzer:  analyzer,
+		log:       log,
+		proxyHops: trustedProxyHops(cfg),
+	}
+	ws := &subscriptionService{schema: schema, analyzer: analyzer, log: log}
 
 	// return the constructed API handler chain
 	return &LoggingHandler{
-		logger:  log,
-		handler: corsHandler.Handler(graphqlws.NewHandlerFunc(schema, &relay.Handler{Schema: schema})),
+		logger: log,
+		handler: &ClientHandler{
+			handler:   corsHandler.Handler(graphqlws.NewHandlerFunc(ws, limited, graphqlws.WithContextGenerator(graphqlws.ContextGeneratorFunc(apiClientContext)))),
+			proxyHops: trustedProxyHops(cfg),
+		},
 	}
 }
 
@@ -37,7 +58,7 @@ func corsOptions(cfg *config.Config) cors.Options {
 	return cors.Options{
 		AllowedOrigins: cfg.Server.CorsOrigin,
 		AllowedMethods: []string{"HEAD", "GET", "POST"},
-		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Requested-With"},
+		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization"},
 		MaxAge:         300,
 	}
 }
@@ -0,0 +1,298 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// apiKeyCacheTTL represents the time an API key is kept in memory before it's re-loaded.
+	apiKeyCacheTTL = time.Minute
+
+	// apiUsageFlushInterval represents the interval of persisting the collected API usage.
+	apiUsageFlushInterval = 30 * time.Second
+
+	// apiAnonymousUsageKey represents the usage record of the anonymous clients.
+	apiAnonymousUsageKey = ""
+)
+
+// ApiKeys meters requests of API clients against the limits of their access tiers.
+// Clients are identified by their API key, or by their IP address if no key is presented.
+type ApiKeys struct {
+	cfg *config.ApiKeys
+	log logger.Logger
+
+	// proxyHops is the number of trusted reverse proxies identifying anonymous clients
+	proxyHops int
+
+	// adminToken identifies administrative requests which are not limited
+	adminToken string
+
+	// rate limiters of the access tiers
+	limiters map[string]*clientRateLimiter
+
+	mu    sync.Mutex
+	keys  map[string]*apiKeyEntry
+	usage map[string]*types.ApiKeyUsage
+
+	sigStop chan bool
+	wg      sync.WaitGroup
+}
+
+// apiKeyEntry represents an API key kept in memory; the key is nil if it's not known.
+type apiKeyEntry struct {
+	key    *types.ApiKey
+	loaded time.Time
+}
+
+// apiClient represents an API client metered against the limits of its access tier.
+type apiClient struct {
+	tier *config.ApiTier
+
+	// id identifies the client for the rate limit and the daily quota
+	id string
+
+	// usage identifies the usage record the requests of the client are collected to
+	usage string
+}
+
+// apiClientKey is the context key of the metered client of a request.
+type apiClientKey struct{}
+
+// apiMeteredClient represents the metered client of a request; websocket connections
+// keep it to meter the operations sent over the connection.
+type apiMeteredClient struct {
+	keys   *ApiKeys
+	client *apiClient
+}
+
+// NewApiKeys creates the API keys metering and starts persisting the usage of the API.
+func NewApiKeys(cfg *config.Config, log logger.Logger) *ApiKeys {
+	ak := ApiKeys{
+		cfg:        &cfg.ApiKeys,
+		log:        log,
+		proxyHops:  trustedProxyHops(cfg),
+		adminToken: cfg.Server.AdminToken,
+		limiters:   make(map[string]*clientRateLimiter, len(cfg.ApiKeys.Tiers)),
+		keys:       make(map[string]*apiKeyEntry),
+		usage:      make(map[string]*types.ApiKeyUsage),
+		sigStop:    make(chan bool, 1),
+	}
+
+	for _, t := range cfg.ApiKeys.Tiers {
+		ak.limiters[t.Name] = newClientRateLimiter(t.Rate, t.Burst)
+	}
+
+	ak.wg.Add(1)
+	go ak.run()
+	return &ak
+}
+
+// Close terminates the usage persisting; the usage collected so far is persisted.
+func (ak *ApiKeys) Close() {
+	ak.sigStop <- true
+	ak.wg.Wait()
+}
+
+// Handler wraps the given handler so only requests within the limits of the client access tier are passed to it.
+func (ak *ApiKeys) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ak.isAdmin(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the key may be sent by a header, or a parameter
+		key := r.Header.Get(ak.cfg.Header)
+		if key == "" && ak.cfg.Param != "" {
+			// only url-encoded form bodies are parsed, the GraphQL body is left intact
+			if err := r.ParseForm(); err == nil {
+				key = r.Form.Get(ak.cfg.Param)
+			}
+		}
+
+		var c apiClient
+		if key != "" {
+			k := ak.apiKey(key)
+			if k == nil || !k.Enabled {
+				http.Error(w, "invalid API key", http.StatusUnauthorized)
+				return
+			}
+			c = apiClient{tier: ak.cfg.Tier(k.Tier), id: k.ID, usage: k.ID}
+		} else {
+			c = apiClient{tier: ak.cfg.Tier(ak.cfg.AnonymousTier), id: "ip:" + clientIP(r, ak.proxyHops), usage: apiAnonymousUsageKey}
+		}
+
+		if c.tier == nil {
+			http.Error(w, "API key required", http.StatusUnauthorized)
+			return
+		}
+
+		if status, msg, retry := ak.admit(&c); status != 0 {
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(retry.Seconds()), 10))
+			http.Error(w, msg, status)
+			return
+		}
+
+		// websocket connections meter the operations sent over them by the client
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiClientKey{}, &apiMeteredClient{keys: ak, client: &c})))
+	})
+}
+
+// admit counts a request of the client against the limits of its access tier.
+// If the request is over a limit, the HTTP status, the reason and the time to retry after are provided;
+// the status is zero if the request is admitted.
+func (ak *ApiKeys) admit(c *apiClient) (int, string, time.Duration) {
+	if !ak.limiters[c.tier.Name].allow(c.id, 1) {
+		ak.record(c.usage, false)
+		return http.StatusTooManyRequests, "rate limit exceeded", time.Second
+	}
+
+	if c.tier.DailyQuota <= 0 {
+		ak.record(c.usage, true)
+		return 0, "", 0
+	}
+
+	// the quota is shared by all the API instances, it's counted in the database
+	now := time.Now().UTC()
+	ok, err := repository.R().ConsumeApiQuota(c.id, now.Format(types.ApiKeyUsageDayFormat), c.tier.DailyQuota)
+	if err != nil {
+		return http.StatusServiceUnavailable, "daily quota not available", time.Second
+	}
+	if !ok {
+		ak.record(c.usage, false)
+		return http.StatusTooManyRequests, "daily quota exceeded", now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+	}
+
+	// requests of an API key are already counted in its usage record by the quota
+	if c.usage != c.id {
+		ak.record(c.usage, true)
+	}
+	return 0, "", 0
+}
+
+// apiClientContext passes the metered client of a websocket upgrade request to the connection context.
+func apiClientContext(ctx context.Context, r *http.Request) (context.Context, error) {
+	if mc, ok := r.Context().Value(apiClientKey{}).(*apiMeteredClient); ok {
+		return context.WithValue(ctx, apiClientKey{}, mc), nil
+	}
+	return ctx, nil
+}
+
+// meterOperation counts an operation sent over a websocket connection
+// against the limits of the client of the connection, if metered.
+func meterOperation(ctx context.Context) error {
+	mc, ok := ctx.Value(apiClientKey{}).(*apiMeteredClient)
+	if !ok {
+		return nil
+	}
+
+	if status, msg, _ := mc.keys.admit(mc.client); status != 0 {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// isAdmin checks if the request presents the configured admin token.
+func (ak *ApiKeys) isAdmin(r *http.Request) bool {
+	token := clientToken(r)
+	if ak.adminToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(ak.adminToken)) == 1
+}
+
+// apiKey provides the API key record of the given key; nil if the key is not known.
+func (ak *ApiKeys) apiKey(key string) *types.ApiKey {
+	ak.mu.Lock()
+	e, ok := ak.keys[key]
+	ak.mu.Unlock()
+
+	if ok && time.Since(e.loaded) < apiKeyCacheTTL {
+		return e.key
+	}
+
+	k, err := repository.R().ApiKeyByKey(key)
+	if err != nil {
+		ak.log.Errorf("can not load API key; %s", err.Error())
+		return nil
+	}
+
+	ak.mu.Lock()
+	ak.keys[key] = &apiKeyEntry{key: k, loaded: time.Now()}
+	ak.mu.Unlock()
+	return k
+}
+
+// record adds a served, or a rejected request to the usage collected for persisting.
+func (ak *ApiKeys) record(key string, served bool) {
+	ak.mu.Lock()
+	defer ak.mu.Unlock()
+
+	u, ok := ak.usage[key]
+	if !ok {
+		u = &types.ApiKeyUsage{Key: key, Day: time.Now().UTC().Format(types.ApiKeyUsageDayFormat)}
+		ak.usage[key] = u
+	}
+
+	if served {
+		u.Requests++
+	} else {
+		u.Rejected++
+	}
+}
+
+// run persists the collected usage periodically.
+func (ak *ApiKeys) run() {
+	defer ak.wg.Done()
+
+	tick := time.NewTicker(apiUsageFlushInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ak.sigStop:
+			ak.flush()
+			return
+		case <-tick.C:
+			ak.flush()
+			ak.release()
+		}
+	}
+}
+
+// flush persists the usage collected since the previous flush.
+func (ak *ApiKeys) flush() {
+	ak.mu.Lock()
+	usage := ak.usage
+	ak.usage = make(map[string]*types.ApiKeyUsage, len(usage))
+	ak.mu.Unlock()
+
+	for _, u := range usage {
+		if err := repository.R().AddApiKeyUsage(u); err != nil {
+			ak.log.Errorf("can not persist API usage of %s; %s", u.Key, err.Error())
+		}
+	}
+}
+
+// release drops expired API keys from the memory.
+func (ak *ApiKeys) release() {
+	ak.mu.Lock()
+	defer ak.mu.Unlock()
+
+	for k, e := range ak.keys {
+		if time.Since(e.loaded) > apiKeyCacheTTL {
+			delete(ak.keys, k)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// quotaRepository is a repository substitute counting the API usage in memory.
+type quotaRepository struct {
+	repository.Repository
+	mu    sync.Mutex
+	keys  map[string]*types.ApiKey
+	usage map[string]*types.ApiKeyUsage
+}
+
+// ApiKeyByKey provides the API key of the given key.
+func (qr *quotaRepository) ApiKeyByKey(key string) (*types.ApiKey, error) {
+	return qr.keys[key], nil
+}
+
+// ConsumeApiQuota counts a request against the daily quota.
+func (qr *quotaRepository) ConsumeApiQuota(key string, day string, quota int64) (bool, error) {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	u, ok := qr.usage[key+"#"+day]
+	if !ok {
+		u = &types.ApiKeyUsage{Key: key, Day: day}
+		qr.usage[key+"#"+day] = u
+	}
+	if u.Requests >= quota {
+		return false, nil
+	}
+	u.Requests++
+	return true, nil
+}
+
+// AddApiKeyUsage adds the collected usage.
+func (qr *quotaRepository) AddApiKeyUsage(add *types.ApiKeyUsage) error {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	u, ok := qr.usage[add.Key+"#"+add.Day]
+	if !ok {
+		u = &types.ApiKeyUsage{Key: add.Key, Day: add.Day}
+		qr.usage[add.Key+"#"+add.Day] = u
+	}
+	u.Requests += add.Requests
+	u.Rejected += add.Rejected
+	return nil
+}
+
+// requests provides the number of served and rejected requests of the usage record of today.
+func (qr *quotaRepository) requests(key string, day string) (int64, int64) {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	if u, ok := qr.usage[key+"#"+day]; ok {
+		return u.Requests, u.Rejected
+	}
+	return 0, 0
+}
+
+func TestApiKeysQuota(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	qr := &quotaRepository{
+		keys:  map[string]*types.ApiKey{"secret": {ID: "key1", Tier: "paid", Enabled: true}},
+		usage: make(map[string]*types.ApiKeyUsage),
+	}
+	repository.Set(qr)
+
+	cfg := &config.Config{
+		AppName: "test",
+		Log:     config.Log{Level: "CRITICAL", Format: "%{message}"},
+		ApiKeys: config.ApiKeys{
+			Header:        "X-Api-Key",
+			AnonymousTier: "free",
+			Tiers: []config.ApiTier{
+				{Name: "free", DailyQuota: 2},
+				{Name: "paid", DailyQuota: 3},
+			},
+		},
+	}
+	ak := NewApiKeys(cfg, logger.New(cfg))
+
+	// the next handler runs an operation on a websocket connection of the client
+	var wsErr error
+	h := ak.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := apiClientContext(context.Background(), r)
+		g.Expect(err).To(gomega.BeNil())
+		wsErr = meterOperation(ctx)
+	}))
+
+	call := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if key != "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the API key quota counts both the request and the operation sent over the connection
+	g.Expect(call("secret")).To(gomega.Equal(http.StatusOK))
+	g.Expect(wsErr).To(gomega.BeNil())
+	g.Expect(call("secret")).To(gomega.Equal(http.StatusOK))
+	g.Expect(wsErr).To(gomega.MatchError("daily quota exceeded"))
+	g.Expect(call("secret")).To(gomega.Equal(http.StatusTooManyRequests))
+
+	// anonymous clients are limited by their address
+	g.Expect(call("")).To(gomega.Equal(http.StatusOK))
+	g.Expect(wsErr).To(gomega.BeNil())
+	g.Expect(call("")).To(gomega.Equal(http.StatusTooManyRequests))
+
+	g.Expect(call("unknown")).To(gomega.Equal(http.StatusUnauthorized))
+	ak.Close()
+
+	day := ""
+	for _, u := range qr.usage {
+		day = u.Day
+	}
+
+	// served requests of the API key are counted by the quota only
+	req, rej := qr.requests("key1", day)
+	g.Expect(req).To(gomega.Equal(int64(3)))
+	g.Expect(rej).To(gomega.Equal(int64(2)))
+
+	req, _ = qr.requests("ip:192.0.2.1", day)
+	g.Expect(req).To(gomega.Equal(int64(2)))
+
+	req, rej = qr.requests(apiAnonymousUsageKey, day)
+	g.Expect(req).To(gomega.Equal(int64(2)))
+	g.Expect(rej).To(gomega.Equal(int64(1)))
+}
+
+func TestMeterOperationNotMetered(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx, err := apiClientContext(context.Background(), httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(meterOperation(ctx)).To(gomega.Succeed())
+}
@@ -0,0 +1,69 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/graphql/resolvers"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientHandler attaches the IP address and the credentials of the API client to the request context.
+type ClientHandler struct {
+	handler   http.Handler
+	proxyHops int
+}
+
+// ServeHTTP handles incoming request by attaching the client address and credentials to the request context
+// and passing it to the next handler in the chain.
+func (h *ClientHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := resolvers.WithClientIP(r.Context(), clientIP(r, h.proxyHops))
+	ctx = resolvers.WithClientToken(ctx, clientToken(r))
+	h.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// clientIP provides the IP address of the client of the given request. Headers set by a reverse proxy
+// are used only if the proxy is trusted, they can be forged by the client otherwise. Each of the given
+// number of trusted proxies appends the address it received the request from to the X-Forwarded-For list,
+// so the client address is read that many hops from the right; anything left of it is client provided.
+func clientIP(r *http.Request, proxyHops int) string {
+	if proxyHops > 0 {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			hops := strings.Split(strings.Join(fwd, ","), ",")
+			pos := len(hops) - proxyHops
+			if pos < 0 {
+				pos = 0
+			}
+			if ip := net.ParseIP(strings.TrimSpace(hops[pos])); ip != nil {
+				return ip.String()
+			}
+		} else if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); ip != nil {
+			return ip.String()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// trustedProxyHops provides the number of trusted reverse proxies in front of the server;
+// zero if the proxy headers are not trusted at all.
+func trustedProxyHops(cfg *config.Config) int {
+	if !cfg.Server.TrustProxy {
+		return 0
+	}
+	return cfg.Server.ProxyHops
+}
+
+// clientToken provides the bearer token sent by the client in the Authorization header; empty if none.
+func clientToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/onsi/gomega"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name string
+		hops int
+		xff  []string
+		real string
+		want string
+	}{
+		{name: "untrusted proxy", hops: 0, xff: []string{"10.0.0.1"}, want: "192.0.2.1"},
+		{name: "single proxy", hops: 1, xff: []string{"203.0.113.7"}, want: "203.0.113.7"},
+		{name: "spoofed left-most entry", hops: 1, xff: []string{"1.2.3.4, 203.0.113.7"}, want: "203.0.113.7"},
+		{name: "repeated headers", hops: 1, xff: []string{"1.2.3.4", "203.0.113.7"}, want: "203.0.113.7"},
+		{name: "two proxies", hops: 2, xff: []string{"1.2.3.4, 203.0.113.7, 10.0.0.2"}, want: "203.0.113.7"},
+		{name: "fewer hops than proxies", hops: 2, xff: []string{"203.0.113.7"}, want: "203.0.113.7"},
+		{name: "invalid entry", hops: 1, xff: []string{"unknown"}, want: "192.0.2.1"},
+		{name: "real ip header", hops: 1, real: "203.0.113.9", want: "203.0.113.9"},
+		{name: "no proxy headers", hops: 1, want: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			r := httptest.NewRequest("POST", "/graphql", nil)
+			r.RemoteAddr = "192.0.2.1:43210"
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.real != "" {
+				r.Header.Set("X-Real-Ip", tt.real)
+			}
+
+			g.Expect(clientIP(r, tt.hops)).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fantom-api-graphql/internal/graphql/complexity"
+	"fantom-api-graphql/internal/logger"
+	"io/ioutil"
+	"net/http"
+)
+
+// ComplexityHandler rejects GraphQL operations exceeding the configured depth, or cost
+// before they are executed. Requests the analyzer can not understand are rejected as well,
+// an operation which can not be analyzed can not be proven to be within the limits.
+type ComplexityHandler struct {
+	handler   http.Handler
+	analyzer  *complexity.Analyzer
+	log       logger.Logger
+	proxyHops int
+}
+
+// complexityRequest represents the GraphQL request body.
+type complexityRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP handles incoming request by analyzing the GraphQL operation
+// and passing it to the next handler in the chain if it's within the limits.
+func (h *ComplexityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the GraphQL handler reads the operation from the body regardless of the method
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "can not read request", http.StatusBadRequest)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var req complexityRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.log.Debugf("invalid GraphQL request from %s; %s", clientIP(r, h.proxyHops), err.Error())
+		h.fail(w, http.StatusBadRequest, "invalid GraphQL request", map[string]interface{}{"code": "BAD_REQUEST"})
+		return
+	}
+
+	res, err := h.analyzer.Analyze(req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		var le *complexity.LimitError
+		if errors.As(err, &le) {
+			h.reject(w, r, &req, le)
+			return
+		}
+
+		h.log.Debugf("query %s from %s not analyzed; %s", req.OperationName, clientIP(r, h.proxyHops), err.Error())
+		h.fail(w, http.StatusOK, "operation can not be analyzed; "+err.Error(), map[string]interface{}{"code": "QUERY_NOT_ANALYZED"})
+		return
+	}
+
+	h.log.Debugf("query %s depth %d cost %d", req.OperationName, res.Depth, res.Cost)
+	h.handler.ServeHTTP(w, r)
+}
+
+// reject responds to the GraphQL request exceeding the limits with an error
+// describing the computed depth and cost of the operation.
+func (h *ComplexityHandler) reject(w http.ResponseWriter, r *http.Request, req *complexityRequest, le *complexity.LimitError) {
+	h.log.Warningf("query %s from %s rejected; depth %d of %d, cost %d of %d",
+		req.OperationName, clientIP(r, h.proxyHops), le.Depth, le.MaxDepth, le.Cost, le.MaxCost)
+
+	h.fail(w, http.StatusOK, le.Error(), map[string]interface{}{
+		"code":     "QUERY_TOO_COMPLEX",
+		"depth":    le.Depth,
+		"maxDepth": le.MaxDepth,
+		"cost":     le.Cost,
+		"maxCost":  le.MaxCost,
+	})
+}
+
+// fail responds to the GraphQL request with an error of the given message and extensions.
+func (h *ComplexityHandler) fail(w http.ResponseWriter, status int, msg string, ext map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []interface{}{
+			map[string]interface{}{"message": msg, "extensions": ext},
+		},
+	})
+	if err != nil {
+		h.log.Errorf("can not write response; %s", err.Error())
+	}
+}
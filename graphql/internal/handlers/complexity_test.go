@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/graphql/complexity"
+	"fantom-api-graphql/internal/logger"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// complexityTestSchema is a minimal schema of a block list.
+const complexityTestSchema = `
+schema {
+    query: Query
+}
+
+type Query {
+    blocks(count: Int!): [Block!]!
+}
+
+type Block {
+    number: Int!
+    txList(count: Int!): [Block!]!
+}
+`
+
+// newComplexityTestAnalyzer creates an analyzer of the test schema with the max cost of 10.
+func newComplexityTestAnalyzer() (*graphql.Schema, *complexity.Analyzer) {
+	schema := graphql.MustParseSchema(complexityTestSchema, nil)
+	return schema, complexity.New(schema.ASTSchema(), &config.Complexity{MaxCost: 10, DefaultCost: 1})
+}
+
+func TestComplexityHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		code   string
+		passed bool
+	}{
+		{name: "within limits", method: http.MethodPost, body: `{"query": "{ blocks(count: 5) { txList(count: 1) { number } } }"}`, status: http.StatusOK, passed: true},
+		{name: "within limits by GET", method: http.MethodGet, body: `{"query": "{ blocks(count: 5) { txList(count: 1) { number } } }"}`, status: http.StatusOK, passed: true},
+		{name: "too complex", method: http.MethodPost, body: `{"query": "{ blocks(count: 20) { txList(count: 1) { number } } }"}`, status: http.StatusOK, code: "QUERY_TOO_COMPLEX"},
+		{name: "too complex by GET", method: http.MethodGet, body: `{"query": "{ blocks(count: 20) { txList(count: 1) { number } } }"}`, status: http.StatusOK, code: "QUERY_TOO_COMPLEX"},
+		{name: "syntax error", method: http.MethodPost, body: `{"query": "{ blocks(count: 20) { number }"}`, status: http.StatusOK, code: "QUERY_NOT_ANALYZED"},
+		{name: "unknown operation", method: http.MethodPost, body: `{"query": "query A { blocks(count: 1) { number } }", "operationName": "B"}`, status: http.StatusOK, code: "QUERY_NOT_ANALYZED"},
+		{name: "not JSON", method: http.MethodPost, body: `query { blocks(count: 20) { number } }`, status: http.StatusBadRequest, code: "BAD_REQUEST"},
+		{name: "empty body", method: http.MethodGet, body: ``, status: http.StatusBadRequest, code: "BAD_REQUEST"},
+	}
+
+	cfg := &config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}}
+	_, analyzer := newComplexityTestAnalyzer()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			var passed bool
+			h := &ComplexityHandler{
+				handler:  http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { passed = true }),
+				analyzer: analyzer,
+				log:      logger.New(cfg),
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/graphql", strings.NewReader(tt.body)))
+			g.Expect(rec.Code).To(gomega.Equal(tt.status))
+			g.Expect(passed).To(gomega.Equal(tt.passed))
+			if tt.passed {
+				return
+			}
+
+			var res struct {
+				Errors []struct {
+					Message    string                 `json:"message"`
+					Extensions map[string]interface{} `json:"extensions"`
+				} `json:"errors"`
+			}
+			g.Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(gomega.Succeed())
+			g.Expect(res.Errors).To(gomega.HaveLen(1))
+			g.Expect(res.Errors[0].Extensions["code"]).To(gomega.Equal(tt.code))
+		})
+	}
+}
+
+func TestSubscriptionServiceLimits(t *testing.T) {
+	cfg := &config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}}
+	schema, analyzer := newComplexityTestAnalyzer()
+	svc := &subscriptionService{schema: schema, analyzer: analyzer, log: logger.New(cfg)}
+
+	tests := []struct {
+		name    string
+		query   string
+		limited bool
+	}{
+		{name: "too complex", query: "{ blocks(count: 20) { txList(count: 1) { number } } }", limited: true},
+		{name: "syntax error", query: "{ blocks(count: 5) { number }"},
+		{name: "operation name required", query: "query A { blocks(count: 1) { number } } query B { blocks(count: 1) { number } }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			c, err := svc.Subscribe(context.Background(), tt.query, "", nil)
+			g.Expect(c).To(gomega.BeNil())
+			g.Expect(err).ToNot(gomega.BeNil())
+
+			var le *complexity.LimitError
+			g.Expect(errors.As(err, &le)).To(gomega.Equal(tt.limited))
+		})
+	}
+}
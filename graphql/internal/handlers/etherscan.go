@@ -0,0 +1,214 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/cors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// etherscanMaxResultWindow is the max number of records reachable by the page and offset of a list.
+	etherscanMaxResultWindow = 10000
+
+	// etherscanStatusOK is the status of a successful response.
+	etherscanStatusOK = "1"
+
+	// etherscanStatusNotOK is the status of a failed response, or a response without records.
+	etherscanStatusNotOK = "0"
+)
+
+// etherscanAction represents a handler of an Etherscan compatible API action.
+// It receives the request context and parameters and provides the result of the response.
+type etherscanAction func(ctx context.Context, q url.Values) (interface{}, error)
+
+// etherscanResponse represents the envelope of the Etherscan compatible API responses.
+type etherscanResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Result  interface{} `json:"result"`
+}
+
+// etherscanNoRecords represents an empty list result; the value is the message of the response.
+type etherscanNoRecords string
+
+// Error implements the error interface for the empty list result.
+func (nr etherscanNoRecords) Error() string {
+	return string(nr)
+}
+
+// etherscanNotOk represents a failed action result reported to the client as is, without the error prefix.
+type etherscanNotOk string
+
+// Error implements the error interface for the failed action result.
+func (nok etherscanNotOk) Error() string {
+	return string(nok)
+}
+
+// etherscanActions is the map of the supported actions identified by the module and the action name.
+var etherscanActions = map[string]etherscanAction{
+	"account.balance":            etherscanBalance,
+	"account.balancemulti":       etherscanBalanceMulti,
+	"account.txlist":             etherscanTxList,
+	"account.tokentx":            etherscanTokenTx,
+	"account.tokennfttx":         etherscanTokenNftTx,
+	"account.token1155tx":        etherscanToken1155Tx,
+	"contract.getabi":            etherscanGetAbi,
+	"contract.getsourcecode":     etherscanGetSourceCode,
+	"contract.verifysourcecode":  etherscanVerifySourceCode,
+	"contract.checkverifystatus": etherscanCheckVerifyStatus,
+	"block.getblocknobytime":     etherscanBlockNoByTime,
+	"logs.getLogs":               etherscanGetLogs,
+	"stats.tokensupply":          etherscanTokenSupply,
+}
+
+// etherscanWriteActions is the set of actions changing a state; the API mode does not serve them.
+var etherscanWriteActions = map[string]bool{
+	"contract.verifysourcecode":    true,
+	"proxy.eth_sendRawTransaction": true,
+}
+
+// Etherscan constructs and returns the HTTP handler serving the Etherscan compatible API.
+// The module and the action of the request select the Etherscan compatible action to be served.
+func Etherscan(cfg *config.Config, log logger.Logger) http.Handler {
+	corsHandler := cors.New(corsOptions(cfg))
+	corsHandler.Log = log
+
+	api := corsHandler.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the proxy module follows the JSON-RPC response format
+		module, action := r.Form.Get("module"), r.Form.Get("action")
+		if cfg.Server.Mode == config.ModeApi && etherscanWriteActions[module+"."+action] {
+			etherscanWrite(w, &etherscanResponse{Status: etherscanStatusNotOK, Message: "NOTOK", Result: "Error! Action not available on read only API"}, log)
+			return
+		}
+
+		if module == "proxy" {
+			etherscanProxy(r.Context(), w, r.Form, log)
+			return
+		}
+
+		fn, ok := etherscanActions[module+"."+action]
+		if !ok {
+			etherscanWrite(w, &etherscanResponse{Status: etherscanStatusNotOK, Message: "NOTOK", Result: "Error! Missing Or invalid Action name"}, log)
+			return
+		}
+
+		res, err := fn(r.Context(), r.Form)
+		etherscanWrite(w, etherscanResult(res, err), log)
+	}))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// form values include both the query and the url-encoded body
+		if err := r.ParseForm(); err != nil || r.Form.Get("module") == "" {
+			etherscanWrite(w, &etherscanResponse{Status: etherscanStatusNotOK, Message: "NOTOK", Result: "Error! Missing Or invalid Module name"}, log)
+			return
+		}
+
+		log.Debugf("[%s <- %s] etherscan %s.%s", r.Proto, r.RemoteAddr, r.Form.Get("module"), r.Form.Get("action"))
+		api.ServeHTTP(w, r)
+	})
+}
+
+// etherscanResult builds the response envelope of the given action result.
+func etherscanResult(res interface{}, err error) *etherscanResponse {
+	if err == nil {
+		return &etherscanResponse{Status: etherscanStatusOK, Message: "OK", Result: res}
+	}
+
+	var nr etherscanNoRecords
+	if errors.As(err, &nr) {
+		return &etherscanResponse{Status: etherscanStatusNotOK, Message: nr.Error(), Result: []interface{}{}}
+	}
+
+	var nok etherscanNotOk
+	if errors.As(err, &nok) {
+		return &etherscanResponse{Status: etherscanStatusNotOK, Message: "NOTOK", Result: nok.Error()}
+	}
+	return &etherscanResponse{Status: etherscanStatusNotOK, Message: "NOTOK", Result: "Error! " + err.Error()}
+}
+
+// etherscanWrite writes the given response to the client.
+func etherscanWrite(w http.ResponseWriter, res interface{}, log logger.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf("can not encode etherscan response; %s", err.Error())
+	}
+}
+
+// etherscanAddress parses a mandatory address parameter.
+func etherscanAddress(q url.Values, name string) (*common.Address, error) {
+	v := q.Get(name)
+	if !common.IsHexAddress(v) {
+		return nil, fmt.Errorf("Invalid address format")
+	}
+	addr := common.HexToAddress(v)
+	return &addr, nil
+}
+
+// etherscanOptionalAddress parses an optional address parameter.
+func etherscanOptionalAddress(q url.Values, name string) (*common.Address, error) {
+	if q.Get(name) == "" {
+		return nil, nil
+	}
+	return etherscanAddress(q, name)
+}
+
+// etherscanUint parses an optional unsigned integer parameter.
+func etherscanUint(q url.Values, name string, def uint64) (uint64, error) {
+	v := q.Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s", name)
+	}
+	return val, nil
+}
+
+// etherscanPage parses the page and offset parameters into the number of records skipped and loaded.
+// The whole result window is returned if the page is not specified.
+func etherscanPage(q url.Values, window uint64) (int64, int64, error) {
+	page, err := etherscanUint(q, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := etherscanUint(q, "offset", window)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	if offset == 0 || offset > window {
+		offset = window
+	}
+	if page*offset > window {
+		return 0, 0, fmt.Errorf("Result window is too large, PageNo x Offset size must be less than or equal to %d", window)
+	}
+	return int64((page - 1) * offset), int64(offset), nil
+}
+
+// etherscanSortAsc parses the sort parameter; the ascending order is the default.
+func etherscanSortAsc(q url.Values) bool {
+	return !strings.EqualFold(q.Get("sort"), "desc")
+}
+
+// etherscanHex formats an address the way Etherscan does, in lower case.
+func etherscanHex(addr *common.Address) string {
+	if addr == nil {
+		return ""
+	}
+	return strings.ToLower(addr.String())
+}
@@ -0,0 +1,372 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// etherscanMaxBalanceMulti is the max number of addresses of a single multi balance request.
+const etherscanMaxBalanceMulti = 20
+
+// etherscanTransaction represents a native transaction in the Etherscan compatible format.
+type etherscanTransaction struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  string `json:"transactionIndex"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Value             string `json:"value"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	IsError           string `json:"isError"`
+	TxReceiptStatus   string `json:"txreceipt_status"`
+	Input             string `json:"input"`
+	ContractAddress   string `json:"contractAddress"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	GasUsed           string `json:"gasUsed"`
+	Confirmations     string `json:"confirmations"`
+	MethodId          string `json:"methodId"`
+	FunctionName      string `json:"functionName"`
+}
+
+// etherscanTokenTransaction represents a token transaction in the Etherscan compatible format.
+// Fungible tokens have the value, ERC721 tokens the token ID, ERC1155 tokens both the token ID and the value.
+type etherscanTokenTransaction struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	From              string `json:"from"`
+	ContractAddress   string `json:"contractAddress"`
+	To                string `json:"to"`
+	Value             string `json:"value,omitempty"`
+	TokenID           string `json:"tokenID,omitempty"`
+	TokenValue        string `json:"tokenValue,omitempty"`
+	TokenName         string `json:"tokenName"`
+	TokenSymbol       string `json:"tokenSymbol"`
+	TokenDecimal      string `json:"tokenDecimal,omitempty"`
+	TransactionIndex  string `json:"transactionIndex"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	GasUsed           string `json:"gasUsed"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	Input             string `json:"input"`
+	Confirmations     string `json:"confirmations"`
+}
+
+// etherscanBalance provides the native balance of an account in WEI.
+func etherscanBalance(ctx context.Context, q url.Values) (interface{}, error) {
+	addr, err := etherscanAddress(q, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	bal, err := repository.R().AccountBalance(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return bal.ToInt().String(), nil
+}
+
+// etherscanBalanceMulti provides the native balances of a comma separated list of accounts in WEI.
+func etherscanBalanceMulti(ctx context.Context, q url.Values) (interface{}, error) {
+	list := strings.Split(q.Get("address"), ",")
+	if len(list) > etherscanMaxBalanceMulti {
+		return nil, fmt.Errorf("Maximum of %d addresses are allowed", etherscanMaxBalanceMulti)
+	}
+
+	res := make([]map[string]string, 0, len(list))
+	for _, a := range list {
+		if !common.IsHexAddress(a) {
+			return nil, fmt.Errorf("Invalid address format")
+		}
+
+		addr := common.HexToAddress(a)
+		bal, err := repository.R().AccountBalance(ctx, &addr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, map[string]string{"account": etherscanHex(&addr), "balance": bal.ToInt().String()})
+	}
+	return res, nil
+}
+
+// etherscanBlockRange parses the start and end block parameters of a list.
+func etherscanBlockRange(q url.Values) (*types.RangeFilter, error) {
+	from, err := etherscanUint(q, "startblock", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := types.RangeFilter{FromBlock: &from}
+	if q.Get("endblock") != "" {
+		to, err := etherscanUint(q, "endblock", 0)
+		if err != nil {
+			return nil, err
+		}
+		rf.ToBlock = &to
+	}
+	return &rf, nil
+}
+
+// etherscanTxList provides the list of native transactions of an account.
+func etherscanTxList(ctx context.Context, q url.Values) (interface{}, error) {
+	addr, err := etherscanAddress(q, "address")
+	if err != nil {
+		return nil, err
+	}
+	rf, err := etherscanBlockRange(q)
+	if err != nil {
+		return nil, err
+	}
+	skip, limit, err := etherscanPage(q, etherscanMaxResultWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := repository.R().AccountTransactionsPage(ctx, addr, rf, skip, limit, etherscanSortAsc(q))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, etherscanNoRecords("No transactions found")
+	}
+
+	height := etherscanHeight(ctx)
+	res := make([]*etherscanTransaction, len(list))
+	for i, trx := range list {
+		res[i] = etherscanTransactionOf(ctx, trx, height)
+	}
+	return res, nil
+}
+
+// etherscanTransactionOf converts the transaction into the Etherscan compatible format.
+func etherscanTransactionOf(ctx context.Context, trx *types.Transaction, height uint64) *etherscanTransaction {
+	et := etherscanTransaction{
+		TimeStamp:       strconv.FormatInt(trx.TimeStamp.Unix(), 10),
+		Hash:            trx.Hash.String(),
+		Nonce:           strconv.FormatUint(uint64(trx.Nonce), 10),
+		From:            etherscanHex(&trx.From),
+		To:              etherscanHex(trx.To),
+		Value:           trx.Value.ToInt().String(),
+		Gas:             strconv.FormatUint(uint64(trx.Gas), 10),
+		GasPrice:        trx.GasPrice.ToInt().String(),
+		IsError:         "0",
+		TxReceiptStatus: "1",
+		Input:           trx.InputData.String(),
+		ContractAddress: etherscanHex(trx.ContractAddress),
+	}
+
+	if trx.BlockNumber != nil {
+		et.BlockNumber = strconv.FormatUint(uint64(*trx.BlockNumber), 10)
+		et.Confirmations = etherscanConfirmations(uint64(*trx.BlockNumber), height)
+	}
+	if trx.BlockHash != nil {
+		et.BlockHash = trx.BlockHash.String()
+	}
+	if trx.TrxIndex != nil {
+		et.TransactionIndex = strconv.FormatUint(uint64(*trx.TrxIndex), 10)
+	}
+	if trx.Status != nil && *trx.Status == 0 {
+		et.IsError = "1"
+		et.TxReceiptStatus = "0"
+	}
+	if trx.GasUsed != nil {
+		et.GasUsed = strconv.FormatUint(uint64(*trx.GasUsed), 10)
+	}
+	if trx.CumulativeGasUsed != nil {
+		et.CumulativeGasUsed = strconv.FormatUint(uint64(*trx.CumulativeGasUsed), 10)
+	}
+
+	// the called method
+	if len(trx.InputData) >= 4 {
+		et.MethodId = trx.InputData[:4].String()
+		if name, err := repository.R().TransactionMethodName(ctx, trx); err == nil && name != nil {
+			et.FunctionName = *name
+		}
+	} else {
+		et.MethodId = "0x"
+	}
+	return &et
+}
+
+// etherscanTokenTx provides the list of ERC20 token transactions of an account and/or a token.
+func etherscanTokenTx(ctx context.Context, q url.Values) (interface{}, error) {
+	return etherscanTokenList(ctx, q, types.AccountTypeERC20Token)
+}
+
+// etherscanTokenNftTx provides the list of ERC721 token transactions of an account and/or a token.
+func etherscanTokenNftTx(ctx context.Context, q url.Values) (interface{}, error) {
+	return etherscanTokenList(ctx, q, types.AccountTypeERC721Contract)
+}
+
+// etherscanToken1155Tx provides the list of ERC1155 token transactions of an account and/or a token.
+func etherscanToken1155Tx(ctx context.Context, q url.Values) (interface{}, error) {
+	return etherscanTokenList(ctx, q, types.AccountTypeERC1155Contract)
+}
+
+// etherscanTokenList provides the list of token transactions of the given token type.
+func etherscanTokenList(ctx context.Context, q url.Values, tokenType string) (interface{}, error) {
+	addr, err := etherscanOptionalAddress(q, "address")
+	if err != nil {
+		return nil, err
+	}
+	token, err := etherscanOptionalAddress(q, "contractaddress")
+	if err != nil {
+		return nil, err
+	}
+	if addr == nil && token == nil {
+		return nil, fmt.Errorf("Missing address, or contract address")
+	}
+
+	rf, err := etherscanBlockRange(q)
+	if err != nil {
+		return nil, err
+	}
+	skip, limit, err := etherscanPage(q, etherscanMaxResultWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := repository.R().TokenTransactionsPage(ctx, tokenType, token, addr, rf, skip, limit, etherscanSortAsc(q))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, etherscanNoRecords("No transactions found")
+	}
+
+	height := etherscanHeight(ctx)
+	tokens := make(map[common.Address]*etherscanToken)
+	calls := make(map[common.Hash]*types.Transaction)
+
+	res := make([]*etherscanTokenTransaction, len(list))
+	for i, etx := range list {
+		res[i] = etherscanTokenTransactionOf(etx, etherscanTokenOf(ctx, tokens, etx), etherscanCall(ctx, calls, &etx.Transaction), height)
+	}
+	return res, nil
+}
+
+// etherscanTokenTransactionOf converts the token transaction into the Etherscan compatible format.
+func etherscanTokenTransactionOf(etx *types.TokenTransaction, tok *etherscanToken, trx *types.Transaction, height uint64) *etherscanTokenTransaction {
+	et := etherscanTokenTransaction{
+		BlockNumber:      strconv.FormatUint(etx.BlockNumber, 10),
+		TimeStamp:        strconv.FormatUint(uint64(etx.TimeStamp), 10),
+		Hash:             etx.Transaction.String(),
+		From:             etherscanHex(&etx.Sender),
+		ContractAddress:  etherscanHex(&etx.TokenAddress),
+		To:               etherscanHex(&etx.Recipient),
+		TokenName:        tok.name,
+		TokenSymbol:      tok.symbol,
+		TransactionIndex: strconv.FormatUint(uint64(etx.TrxIndex), 10),
+		Input:            "deprecated",
+		Confirmations:    etherscanConfirmations(etx.BlockNumber, height),
+	}
+
+	switch etx.TokenType {
+	case types.AccountTypeERC20Token:
+		et.Value = etx.Amount.ToInt().String()
+		et.TokenDecimal = strconv.Itoa(tok.decimals)
+	case types.AccountTypeERC721Contract:
+		et.TokenID = etx.TokenId.ToInt().String()
+		et.TokenDecimal = "0"
+	default:
+		et.TokenID = etx.TokenId.ToInt().String()
+		et.TokenValue = etx.Amount.ToInt().String()
+	}
+
+	// details of the parent transaction
+	if trx != nil {
+		et.Nonce = strconv.FormatUint(uint64(trx.Nonce), 10)
+		et.Gas = strconv.FormatUint(uint64(trx.Gas), 10)
+		et.GasPrice = trx.GasPrice.ToInt().String()
+		if trx.BlockHash != nil {
+			et.BlockHash = trx.BlockHash.String()
+		}
+		if trx.GasUsed != nil {
+			et.GasUsed = strconv.FormatUint(uint64(*trx.GasUsed), 10)
+		}
+		if trx.CumulativeGasUsed != nil {
+			et.CumulativeGasUsed = strconv.FormatUint(uint64(*trx.CumulativeGasUsed), 10)
+		}
+	}
+	return &et
+}
+
+// etherscanToken represents the details of a token used to format token transactions.
+type etherscanToken struct {
+	name     string
+	symbol   string
+	decimals int
+}
+
+// etherscanTokenOf provides the details of the token of the given token transaction.
+// The details are loaded once per request and kept in the given map.
+func etherscanTokenOf(ctx context.Context, tokens map[common.Address]*etherscanToken, etx *types.TokenTransaction) *etherscanToken {
+	if tok, ok := tokens[etx.TokenAddress]; ok {
+		return tok
+	}
+
+	tok := new(etherscanToken)
+	switch etx.TokenType {
+	case types.AccountTypeERC20Token:
+		tok.name, _ = repository.R().Erc20Name(&etx.TokenAddress)
+		tok.symbol, _ = repository.R().Erc20Symbol(&etx.TokenAddress)
+		if dec, err := repository.R().Erc20Decimals(&etx.TokenAddress); err == nil {
+			tok.decimals = int(dec)
+		}
+	case types.AccountTypeERC721Contract:
+		tok.name, _ = repository.R().Erc721Name(&etx.TokenAddress)
+		tok.symbol, _ = repository.R().Erc721Symbol(&etx.TokenAddress)
+	default:
+		if sc, err := repository.R().Contract(ctx, &etx.TokenAddress); err == nil && sc != nil {
+			tok.name, tok.symbol = sc.Name, sc.Symbol
+		}
+	}
+
+	tokens[etx.TokenAddress] = tok
+	return tok
+}
+
+// etherscanCall provides the transaction of the given hash.
+// Transactions are loaded once per request and kept in the given map.
+func etherscanCall(ctx context.Context, calls map[common.Hash]*types.Transaction, hash *common.Hash) *types.Transaction {
+	if trx, ok := calls[*hash]; ok {
+		return trx
+	}
+
+	trx, err := repository.R().Transaction(ctx, hash)
+	if err != nil {
+		trx = nil
+	}
+	calls[*hash] = trx
+	return trx
+}
+
+// etherscanHeight provides the current block height; zero if not available.
+func etherscanHeight(ctx context.Context) uint64 {
+	h, err := repository.R().BlockHeight(ctx)
+	if err != nil {
+		return 0
+	}
+	return h.ToInt().Uint64()
+}
+
+// etherscanConfirmations provides the number of confirmations of the given block.
+func etherscanConfirmations(blk uint64, height uint64) string {
+	if height < blk {
+		return "0"
+	}
+	return strconv.FormatUint(height-blk+1, 10)
+}
@@ -0,0 +1,203 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// etherscanMaxLogs is the max number of log records reachable by the page and offset of the logs list.
+const etherscanMaxLogs = 1000
+
+// etherscanLog represents an event log record in the Etherscan compatible format.
+type etherscanLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	TimeStamp        string   `json:"timeStamp"`
+	GasPrice         string   `json:"gasPrice"`
+	GasUsed          string   `json:"gasUsed"`
+	LogIndex         string   `json:"logIndex"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+}
+
+// etherscanBlockNoByTime provides the number of the block collated closest to the given time stamp.
+func etherscanBlockNoByTime(ctx context.Context, q url.Values) (interface{}, error) {
+	ts, err := strconv.ParseUint(q.Get("timestamp"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid timestamp")
+	}
+
+	var after bool
+	switch strings.ToLower(q.Get("closest")) {
+	case "after":
+		after = true
+	case "before", "":
+	default:
+		return nil, fmt.Errorf("Invalid closest parameter, before or after expected")
+	}
+
+	blk, err := repository.R().BlockByTime(ctx, ts, after)
+	if err != nil {
+		return nil, fmt.Errorf("No closest block found")
+	}
+	return strconv.FormatUint(uint64(blk.Number), 10), nil
+}
+
+// etherscanTokenSupply provides the total supply of an ERC20 token.
+func etherscanTokenSupply(ctx context.Context, q url.Values) (interface{}, error) {
+	token, err := etherscanAddress(q, "contractaddress")
+	if err != nil {
+		return nil, err
+	}
+
+	supply, err := repository.R().Erc20TotalSupply(token)
+	if err != nil {
+		return nil, err
+	}
+	return supply.ToInt().String(), nil
+}
+
+// etherscanGetLogs provides event logs matching the given block range, address and topics.
+// Topics at different positions can be combined only by the "and" operator.
+func etherscanGetLogs(ctx context.Context, q url.Values) (interface{}, error) {
+	filter, err := etherscanLogFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	skip, limit, err := etherscanPage(q, etherscanMaxLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := repository.R().NodeCall(ctx, "eth_getLogs", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []etc.Log
+	if err := json.Unmarshal(raw, &logs); err != nil {
+		return nil, err
+	}
+
+	// apply the page
+	if int64(len(logs)) <= skip {
+		return nil, etherscanNoRecords("No records found")
+	}
+	logs = logs[skip:]
+	if int64(len(logs)) > limit {
+		logs = logs[:limit]
+	}
+
+	blocks := make(map[uint64]*types.Block)
+	calls := make(map[common.Hash]*types.Transaction)
+	res := make([]*etherscanLog, len(logs))
+	for i := range logs {
+		res[i] = etherscanLogOf(ctx, &logs[i], blocks, calls)
+	}
+	return res, nil
+}
+
+// etherscanLogFilter builds the node log filter from the request parameters.
+func etherscanLogFilter(q url.Values) (map[string]interface{}, error) {
+	filter := map[string]interface{}{
+		"fromBlock": etherscanBlockTag(q.Get("fromBlock"), "0x0"),
+		"toBlock":   etherscanBlockTag(q.Get("toBlock"), "latest"),
+	}
+
+	if q.Get("address") != "" {
+		addr, err := etherscanAddress(q, "address")
+		if err != nil {
+			return nil, err
+		}
+		filter["address"] = addr
+	}
+
+	// topics by position; nil matches anything
+	topics := make([]interface{}, 4)
+	last := -1
+	for i := range topics {
+		v := q.Get(fmt.Sprintf("topic%d", i))
+		if v == "" {
+			continue
+		}
+		if len(v) != 66 || !strings.HasPrefix(v, "0x") {
+			return nil, fmt.Errorf("Invalid topic%d", i)
+		}
+		topics[i] = common.HexToHash(v)
+		last = i
+	}
+
+	// only the conjunction of topics is supported by the node
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			if op := q.Get(fmt.Sprintf("topic%d_%d_opr", i, j)); op != "" && !strings.EqualFold(op, "and") {
+				return nil, fmt.Errorf("Only the \"and\" topic operator is supported")
+			}
+		}
+	}
+
+	if last >= 0 {
+		filter["topics"] = topics[:last+1]
+	}
+	return filter, nil
+}
+
+// etherscanBlockTag converts a decimal block number, or a block tag into the node block tag.
+func etherscanBlockTag(v string, def string) string {
+	if v == "" {
+		return def
+	}
+	if num, err := strconv.ParseUint(v, 10, 64); err == nil {
+		return hexutil.EncodeUint64(num)
+	}
+	return v
+}
+
+// etherscanLogOf converts the log record into the Etherscan compatible format.
+// Blocks and transactions are loaded once per request and kept in the given maps.
+func etherscanLogOf(ctx context.Context, lg *etc.Log, blocks map[uint64]*types.Block, calls map[common.Hash]*types.Transaction) *etherscanLog {
+	el := etherscanLog{
+		Address:          etherscanHex(&lg.Address),
+		Topics:           make([]string, len(lg.Topics)),
+		Data:             hexutil.Encode(lg.Data),
+		BlockNumber:      hexutil.EncodeUint64(lg.BlockNumber),
+		LogIndex:         hexutil.EncodeUint64(uint64(lg.Index)),
+		TransactionHash:  lg.TxHash.String(),
+		TransactionIndex: hexutil.EncodeUint64(uint64(lg.TxIndex)),
+	}
+	for i, t := range lg.Topics {
+		el.Topics[i] = t.String()
+	}
+
+	// the block time stamp
+	blk, ok := blocks[lg.BlockNumber]
+	if !ok {
+		num := hexutil.Uint64(lg.BlockNumber)
+		blk, _ = repository.R().BlockByNumber(ctx, &num)
+		blocks[lg.BlockNumber] = blk
+	}
+	if blk != nil {
+		el.TimeStamp = blk.TimeStamp.String()
+	}
+
+	// the gas of the transaction
+	if trx := etherscanCall(ctx, calls, &lg.TxHash); trx != nil {
+		el.GasPrice = trx.GasPrice.String()
+		if trx.GasUsed != nil {
+			el.GasUsed = trx.GasUsed.String()
+		}
+	}
+	return &el
+}
@@ -0,0 +1,187 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// etherscanNotVerified is the message of contracts without verified source code.
+	etherscanNotVerified = "Contract source code not verified"
+
+	// etherscanAlreadyVerified is the message of a verification submitted for an already verified contract.
+	etherscanAlreadyVerified = "Contract source code already verified"
+
+	// etherscanVerifyPending is the status of a verification job waiting for processing.
+	etherscanVerifyPending = "Pending in queue"
+
+	// etherscanVerifyPass is the status of a verification job which verified the contract.
+	etherscanVerifyPass = "Pass - Verified"
+
+	// etherscanVerifyFail is the status of a verification job which failed to verify the contract.
+	etherscanVerifyFail = "Fail - Unable to verify"
+)
+
+// etherscanLicenses maps the Etherscan license type codes to the license identifiers.
+var etherscanLicenses = map[string]string{
+	"1":  "None",
+	"2":  "Unlicense",
+	"3":  "MIT",
+	"4":  "GPL-2.0",
+	"5":  "GPL-3.0",
+	"6":  "LGPL-2.1",
+	"7":  "LGPL-3.0",
+	"8":  "BSD-2-Clause",
+	"9":  "BSD-3-Clause",
+	"10": "MPL-2.0",
+	"11": "OSL-3.0",
+	"12": "Apache-2.0",
+	"13": "AGPL-3.0",
+	"14": "BUSL-1.1",
+}
+
+// etherscanSourceCode represents the verified source code of a contract in the Etherscan compatible format.
+type etherscanSourceCode struct {
+	SourceCode           string `json:"SourceCode"`
+	ABI                  string `json:"ABI"`
+	ContractName         string `json:"ContractName"`
+	CompilerVersion      string `json:"CompilerVersion"`
+	OptimizationUsed     string `json:"OptimizationUsed"`
+	Runs                 string `json:"Runs"`
+	ConstructorArguments string `json:"ConstructorArguments"`
+	EVMVersion           string `json:"EVMVersion"`
+	Library              string `json:"Library"`
+	LicenseType          string `json:"LicenseType"`
+	Proxy                string `json:"Proxy"`
+	Implementation       string `json:"Implementation"`
+	SwarmSource          string `json:"SwarmSource"`
+}
+
+// etherscanContract loads the contract of the address parameter; nil if the address is not a known contract.
+func etherscanContract(ctx context.Context, q url.Values) (*types.Contract, error) {
+	addr, err := etherscanAddress(q, "address")
+	if err != nil {
+		return nil, err
+	}
+	return repository.R().Contract(ctx, addr)
+}
+
+// etherscanGetAbi provides the ABI of a verified contract.
+func etherscanGetAbi(ctx context.Context, q url.Values) (interface{}, error) {
+	sc, err := etherscanContract(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil || sc.Validated == nil || sc.Abi == "" {
+		return nil, fmt.Errorf(etherscanNotVerified)
+	}
+	return sc.Abi, nil
+}
+
+// etherscanGetSourceCode provides the verified source code of a contract.
+// Unverified contracts are reported with an empty source code, the way Etherscan does.
+func etherscanGetSourceCode(ctx context.Context, q url.Values) (interface{}, error) {
+	sc, err := etherscanContract(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil || sc.Validated == nil {
+		return []*etherscanSourceCode{{ABI: etherscanNotVerified, Proxy: "0"}}, nil
+	}
+
+	opt := "0"
+	if sc.IsOptimized {
+		opt = "1"
+	}
+	return []*etherscanSourceCode{{
+		SourceCode:       sc.SourceCode,
+		ABI:              sc.Abi,
+		ContractName:     sc.Name,
+		CompilerVersion:  sc.Compiler,
+		OptimizationUsed: opt,
+		Runs:             strconv.Itoa(int(sc.OptimizeRuns)),
+		EVMVersion:       "Default",
+		LicenseType:      sc.License,
+		Proxy:            "0",
+	}}, nil
+}
+
+// etherscanVerifySourceCode queues the submitted source code for verification against the deployed contract.
+// The GUID of the verification job is provided so the client can check the status of the verification.
+func etherscanVerifySourceCode(ctx context.Context, q url.Values) (interface{}, error) {
+	addr, err := etherscanAddress(q, "contractaddress")
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := repository.R().Contract(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil {
+		return nil, etherscanNotOk(fmt.Sprintf("Unable to locate ContractCode at %s", etherscanHex(addr)))
+	}
+	if sc.Validated != nil {
+		return nil, etherscanNotOk(etherscanAlreadyVerified)
+	}
+
+	cv := types.ContractVerification{
+		Contract:   addr.String(),
+		Name:       q.Get("contractname"),
+		CodeFormat: q.Get("codeformat"),
+		SourceCode: q.Get("sourceCode"),
+		Compiler:   q.Get("compilerversion"),
+		Optimized:  q.Get("optimizationUsed") == "1",
+		EvmVersion: q.Get("evmversion"),
+		License:    etherscanLicenses[q.Get("licenseType")],
+	}
+	if cv.SourceCode == "" {
+		return nil, fmt.Errorf("Missing source code")
+	}
+	if cv.CodeFormat == "" {
+		cv.CodeFormat = "solidity-single-file"
+	}
+
+	// the misspelled parameter name is used by the Etherscan API
+	cv.ConstructorArguments = q.Get("constructorArguements")
+	if cv.ConstructorArguments == "" {
+		cv.ConstructorArguments = q.Get("constructorArguments")
+	}
+
+	runs, err := etherscanUint(q, "runs", 200)
+	if err != nil {
+		return nil, err
+	}
+	cv.OptimizeRuns = int32(runs)
+
+	if err := repository.R().SubmitContractVerification(&cv); err != nil {
+		return nil, err
+	}
+	return cv.GUID, nil
+}
+
+// etherscanCheckVerifyStatus provides the status of the contract verification job of the given GUID.
+func etherscanCheckVerifyStatus(ctx context.Context, q url.Values) (interface{}, error) {
+	cv, err := repository.R().ContractVerification(q.Get("guid"))
+	if err != nil {
+		return nil, err
+	}
+	if cv == nil {
+		return nil, etherscanNotOk("Unknown UID")
+	}
+
+	switch cv.Status {
+	case types.ContractVerificationStatusPass:
+		return etherscanVerifyPass, nil
+	case types.ContractVerificationStatusFail:
+		return nil, etherscanNotOk(strings.TrimSuffix(etherscanVerifyFail+" - "+cv.Error, " - "))
+	default:
+		return nil, etherscanNotOk(etherscanVerifyPending)
+	}
+}
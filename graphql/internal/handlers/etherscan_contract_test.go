@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// verifyRepository is a repository substitute keeping contract verification jobs in memory.
+type verifyRepository struct {
+	repository.Repository
+	contract *types.Contract
+	jobs     map[string]*types.ContractVerification
+}
+
+// Contract provides the only known contract.
+func (vr *verifyRepository) Contract(_ context.Context, addr *common.Address) (*types.Contract, error) {
+	if vr.contract == nil || vr.contract.Address != *addr {
+		return nil, nil
+	}
+	return vr.contract, nil
+}
+
+// SubmitContractVerification queues the given job.
+func (vr *verifyRepository) SubmitContractVerification(cv *types.ContractVerification) error {
+	cv.GUID = fmt.Sprintf("guid%d", len(vr.jobs)+1)
+	cv.Status = types.ContractVerificationStatusPending
+	vr.jobs[cv.GUID] = cv
+	return nil
+}
+
+// ContractVerification provides the job of the given GUID.
+func (vr *verifyRepository) ContractVerification(guid string) (*types.ContractVerification, error) {
+	return vr.jobs[guid], nil
+}
+
+// etherscanServe submits the given form to the Etherscan handler and decodes the response.
+func etherscanServe(t *testing.T, h http.Handler, form url.Values) etherscanResponse {
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res etherscanResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("invalid response %s; %s", rec.Body.String(), err.Error())
+	}
+	return res
+}
+
+func TestEtherscanVerifySourceCode(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	addr := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+	vr := &verifyRepository{contract: &types.Contract{Address: addr}, jobs: make(map[string]*types.ContractVerification)}
+	repository.Set(vr)
+
+	cfg := &config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}}
+	h := Etherscan(cfg, logger.New(cfg))
+
+	// submit the job
+	res := etherscanServe(t, h, url.Values{
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"contractaddress":       {addr.String()},
+		"sourceCode":            {"{\"language\":\"Solidity\"}"},
+		"codeformat":            {"solidity-standard-json-input"},
+		"contractname":          {"contracts/Token.sol:Token"},
+		"compilerversion":       {"v0.8.17+commit.8df45f5f"},
+		"evmversion":            {"london"},
+		"optimizationUsed":      {"1"},
+		"runs":                  {"1000"},
+		"constructorArguements": {"0000000000000000000000000000000000000000000000000000000000000001"},
+	})
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusOK))
+	g.Expect(res.Result).To(gomega.Equal("guid1"))
+
+	cv := vr.jobs["guid1"]
+	g.Expect(cv).ToNot(gomega.BeNil())
+	g.Expect(cv.Contract).To(gomega.Equal(addr.String()))
+	g.Expect(cv.CodeFormat).To(gomega.Equal("solidity-standard-json-input"))
+	g.Expect(cv.Compiler).To(gomega.Equal("v0.8.17+commit.8df45f5f"))
+	g.Expect(cv.EvmVersion).To(gomega.Equal("london"))
+	g.Expect(cv.Optimized).To(gomega.BeTrue())
+	g.Expect(cv.OptimizeRuns).To(gomega.Equal(int32(1000)))
+	g.Expect(cv.ConstructorArguments).To(gomega.HaveLen(64))
+
+	// poll the job status
+	poll := url.Values{"module": {"contract"}, "action": {"checkverifystatus"}, "guid": {"guid1"}}
+	res = etherscanServe(t, h, poll)
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusNotOK))
+	g.Expect(res.Result).To(gomega.Equal(etherscanVerifyPending))
+
+	cv.Status, cv.Error = types.ContractVerificationStatusFail, "source code does not match"
+	res = etherscanServe(t, h, poll)
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusNotOK))
+	g.Expect(res.Result).To(gomega.Equal(etherscanVerifyFail + " - source code does not match"))
+
+	cv.Status = types.ContractVerificationStatusPass
+	res = etherscanServe(t, h, poll)
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusOK))
+	g.Expect(res.Result).To(gomega.Equal(etherscanVerifyPass))
+
+	// unknown job
+	res = etherscanServe(t, h, url.Values{"module": {"contract"}, "action": {"checkverifystatus"}, "guid": {"unknown"}})
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusNotOK))
+	g.Expect(res.Result).To(gomega.Equal("Unknown UID"))
+
+	// already verified contract
+	vr.contract.Validated = new(hexutil.Uint64)
+	res = etherscanServe(t, h, url.Values{"module": {"contract"}, "action": {"verifysourcecode"}, "contractaddress": {addr.String()}, "sourceCode": {"contract A {}"}})
+	g.Expect(res.Status).To(gomega.Equal(etherscanStatusNotOK))
+	g.Expect(res.Result).To(gomega.Equal(etherscanAlreadyVerified))
+}
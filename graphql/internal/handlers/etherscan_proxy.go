@@ -0,0 +1,153 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// etherscanProxyResponse represents the JSON-RPC envelope of the proxy module responses.
+type etherscanProxyResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// etherscanProxyAction builds the node call method and parameters from the request parameters.
+type etherscanProxyAction func(q url.Values) (string, []interface{}, error)
+
+// etherscanProxyActions is the map of the supported proxy module actions.
+var etherscanProxyActions = map[string]etherscanProxyAction{
+	"eth_blockNumber": func(q url.Values) (string, []interface{}, error) {
+		return "eth_blockNumber", nil, nil
+	},
+	"eth_getBlockByNumber": func(q url.Values) (string, []interface{}, error) {
+		return "eth_getBlockByNumber", []interface{}{etherscanProxyTag(q), strings.EqualFold(q.Get("boolean"), "true")}, nil
+	},
+	"eth_getUncleByBlockNumberAndIndex": func(q url.Values) (string, []interface{}, error) {
+		return "eth_getUncleByBlockNumberAndIndex", []interface{}{etherscanProxyTag(q), q.Get("index")}, nil
+	},
+	"eth_getBlockTransactionCountByNumber": func(q url.Values) (string, []interface{}, error) {
+		return "eth_getBlockTransactionCountByNumber", []interface{}{etherscanProxyTag(q)}, nil
+	},
+	"eth_getTransactionByHash": func(q url.Values) (string, []interface{}, error) {
+		hash, err := etherscanProxyHash(q, "txhash")
+		return "eth_getTransactionByHash", []interface{}{hash}, err
+	},
+	"eth_getTransactionByBlockNumberAndIndex": func(q url.Values) (string, []interface{}, error) {
+		return "eth_getTransactionByBlockNumberAndIndex", []interface{}{etherscanProxyTag(q), q.Get("index")}, nil
+	},
+	"eth_getTransactionCount": func(q url.Values) (string, []interface{}, error) {
+		addr, err := etherscanAddress(q, "address")
+		return "eth_getTransactionCount", []interface{}{addr, etherscanProxyTag(q)}, err
+	},
+	"eth_getTransactionReceipt": func(q url.Values) (string, []interface{}, error) {
+		hash, err := etherscanProxyHash(q, "txhash")
+		return "eth_getTransactionReceipt", []interface{}{hash}, err
+	},
+	"eth_call": func(q url.Values) (string, []interface{}, error) {
+		to, err := etherscanAddress(q, "to")
+		if err != nil {
+			return "", nil, err
+		}
+		return "eth_call", []interface{}{map[string]interface{}{"to": to, "data": q.Get("data")}, etherscanProxyTag(q)}, nil
+	},
+	"eth_getCode": func(q url.Values) (string, []interface{}, error) {
+		addr, err := etherscanAddress(q, "address")
+		return "eth_getCode", []interface{}{addr, etherscanProxyTag(q)}, err
+	},
+	"eth_getStorageAt": func(q url.Values) (string, []interface{}, error) {
+		addr, err := etherscanAddress(q, "address")
+		return "eth_getStorageAt", []interface{}{addr, q.Get("position"), etherscanProxyTag(q)}, err
+	},
+	"eth_gasPrice": func(q url.Values) (string, []interface{}, error) {
+		return "eth_gasPrice", nil, nil
+	},
+	"eth_estimateGas": func(q url.Values) (string, []interface{}, error) {
+		to, err := etherscanAddress(q, "to")
+		if err != nil {
+			return "", nil, err
+		}
+
+		call := map[string]interface{}{"to": to}
+		for _, k := range []string{"data", "value", "gas", "gasPrice"} {
+			if v := q.Get(k); v != "" {
+				call[k] = v
+			}
+		}
+		return "eth_estimateGas", []interface{}{call}, nil
+	},
+}
+
+// etherscanProxy serves the proxy module calls passing them to the connected node.
+// The response follows the JSON-RPC response format instead of the Etherscan envelope.
+func etherscanProxy(ctx context.Context, w http.ResponseWriter, q url.Values, log logger.Logger) {
+	res, err := etherscanProxyCall(ctx, q)
+	if err != nil {
+		log.Debugf("etherscan proxy %s failed; %s", q.Get("action"), err.Error())
+		etherscanWrite(w, &etherscanProxyResponse{
+			JsonRpc: rpcVersion,
+			Id:      1,
+			Error:   &rpcError{Code: rpcErrServer, Message: err.Error()},
+		}, log)
+		return
+	}
+	etherscanWrite(w, &etherscanProxyResponse{JsonRpc: rpcVersion, Id: 1, Result: res}, log)
+}
+
+// etherscanProxyCall executes the proxy module action and provides its raw result.
+func etherscanProxyCall(ctx context.Context, q url.Values) (json.RawMessage, error) {
+	action := q.Get("action")
+
+	// raw transactions are sent through the repository to be tracked the usual way
+	if action == "eth_sendRawTransaction" {
+		data, err := hexutil.Decode(q.Get("hex"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid raw transaction; %s", err.Error())
+		}
+
+		trx, err := repository.R().SendTransaction(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(trx.Hash)
+	}
+
+	fn, ok := etherscanProxyActions[action]
+	if !ok {
+		return nil, fmt.Errorf("unsupported proxy action %s", action)
+	}
+
+	method, params, err := fn(q)
+	if err != nil {
+		return nil, err
+	}
+	return repository.R().NodeCall(ctx, method, params...)
+}
+
+// etherscanProxyTag provides the block tag parameter; the latest block is the default.
+func etherscanProxyTag(q url.Values) string {
+	if v := q.Get("tag"); v != "" {
+		return v
+	}
+	return "latest"
+}
+
+// etherscanProxyHash parses a mandatory hash parameter.
+func etherscanProxyHash(q url.Values, name string) (*common.Hash, error) {
+	v := q.Get(name)
+	if len(v) != 66 || !strings.HasPrefix(v, "0x") {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	hash := common.HexToHash(v)
+	return &hash, nil
+}
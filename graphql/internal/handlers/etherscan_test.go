@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// mustJson encodes the given value to JSON, or fails the test.
+func mustJson(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("can not encode %v; %s", v, err.Error())
+	}
+	return string(data)
+}
+
+func TestEtherscanResult(t *testing.T) {
+	tests := []struct {
+		name string
+		res  interface{}
+		err  error
+		want string
+	}{
+		{name: "ok", res: "1000", want: `{"status":"1","message":"OK","result":"1000"}`},
+		{name: "no records", err: etherscanNoRecords("No transactions found"), want: `{"status":"0","message":"No transactions found","result":[]}`},
+		{name: "not ok", err: etherscanNotOk("Pending in queue"), want: `{"status":"0","message":"NOTOK","result":"Pending in queue"}`},
+		{name: "error", err: errors.New("Invalid address format"), want: `{"status":"0","message":"NOTOK","result":"Error! Invalid address format"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(mustJson(t, etherscanResult(tt.res, tt.err))).To(gomega.MatchJSON(tt.want))
+		})
+	}
+}
+
+func TestEtherscanPage(t *testing.T) {
+	tests := []struct {
+		query string
+		skip  int64
+		limit int64
+		valid bool
+	}{
+		{query: "", skip: 0, limit: 10000, valid: true},
+		{query: "page=3&offset=100", skip: 200, limit: 100, valid: true},
+		{query: "page=0&offset=0", skip: 0, limit: 10000, valid: true},
+		{query: "page=2&offset=20000", valid: false},
+		{query: "page=101&offset=100", valid: false},
+		{query: "page=x", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			q, err := url.ParseQuery(tt.query)
+			g.Expect(err).To(gomega.BeNil())
+
+			skip, limit, err := etherscanPage(q, etherscanMaxResultWindow)
+			if !tt.valid {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(skip).To(gomega.Equal(tt.skip))
+			g.Expect(limit).To(gomega.Equal(tt.limit))
+		})
+	}
+}
+
+func TestEtherscanTransactionOf(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	blk, idx, status, used := hexutil.Uint64(100), hexutil.Uint(2), hexutil.Uint64(0), hexutil.Uint64(21000)
+	from := common.HexToAddress("0x5AA1E8A1B4EA1E2B5FA4CDB7A7AED9BD84B4A3D9")
+	to := common.HexToAddress("0x21BE370D5312F44CB42CE377BC9B8A0CEF1A4C83")
+	hash := common.HexToHash("0x6e2d1bfb5b3d6a0a0cbc0e1e5d5a9b3f7b0e6fd2ff7a9f0a3ad4c6fd3b8e1a55")
+
+	et := etherscanTransactionOf(context.Background(), &types.Transaction{
+		BlockHash:   &hash,
+		BlockNumber: &blk,
+		TimeStamp:   time.Unix(1666000000, 0),
+		From:        from,
+		To:          &to,
+		Gas:         25000,
+		GasUsed:     &used,
+		GasPrice:    hexutil.Big(*big.NewInt(1e9)),
+		Hash:        hash,
+		Nonce:       7,
+		TrxIndex:    &idx,
+		Value:       hexutil.Big(*big.NewInt(1e18)),
+		InputData:   hexutil.Bytes{},
+		Status:      &status,
+	}, 109)
+
+	g.Expect(mustJson(t, et)).To(gomega.MatchJSON(`{
+		"blockNumber": "100",
+		"timeStamp": "1666000000",
+		"hash": "` + hash.String() + `",
+		"nonce": "7",
+		"blockHash": "` + hash.String() + `",
+		"transactionIndex": "2",
+		"from": "0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9",
+		"to": "0x21be370d5312f44cb42ce377bc9b8a0cef1a4c83",
+		"value": "1000000000000000000",
+		"gas": "25000",
+		"gasPrice": "1000000000",
+		"isError": "1",
+		"txreceipt_status": "0",
+		"input": "0x",
+		"contractAddress": "",
+		"cumulativeGasUsed": "",
+		"gasUsed": "21000",
+		"confirmations": "10",
+		"methodId": "0x",
+		"functionName": ""
+	}`))
+}
+
+func TestEtherscanTokenTransactionOf(t *testing.T) {
+	base := types.TokenTransaction{
+		Transaction:  common.HexToHash("0x6e2d1bfb5b3d6a0a0cbc0e1e5d5a9b3f7b0e6fd2ff7a9f0a3ad4c6fd3b8e1a55"),
+		TrxIndex:     1,
+		TokenAddress: common.HexToAddress("0x21be370d5312f44cb42ce377bc9b8a0cef1a4c83"),
+		Sender:       common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9"),
+		Recipient:    common.HexToAddress("0x04068da6c83afcfa0e13ba15a6696662335d5b75"),
+		Amount:       hexutil.Big(*big.NewInt(5000)),
+		TokenId:      hexutil.Big(*big.NewInt(42)),
+		TimeStamp:    1666000000,
+		BlockNumber:  100,
+	}
+
+	tests := []struct {
+		tokenType string
+		has       []string
+		hasNot    []string
+	}{
+		{tokenType: types.AccountTypeERC20Token, has: []string{"value", "tokenDecimal"}, hasNot: []string{"tokenID", "tokenValue"}},
+		{tokenType: types.AccountTypeERC721Contract, has: []string{"tokenID", "tokenDecimal"}, hasNot: []string{"value", "tokenValue"}},
+		{tokenType: types.AccountTypeERC1155Contract, has: []string{"tokenID", "tokenValue"}, hasNot: []string{"value", "tokenDecimal"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tokenType, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			etx := base
+			etx.TokenType = tt.tokenType
+			et := etherscanTokenTransactionOf(&etx, &etherscanToken{name: "Wrapped Fantom", symbol: "WFTM", decimals: 18}, nil, 100)
+
+			var row map[string]interface{}
+			g.Expect(json.Unmarshal([]byte(mustJson(t, et)), &row)).To(gomega.Succeed())
+			for _, k := range tt.has {
+				g.Expect(row).To(gomega.HaveKey(k))
+			}
+			for _, k := range tt.hasNot {
+				g.Expect(row).ToNot(gomega.HaveKey(k))
+			}
+			g.Expect(row).To(gomega.HaveKeyWithValue("tokenSymbol", "WFTM"))
+			g.Expect(row).To(gomega.HaveKeyWithValue("confirmations", "1"))
+			g.Expect(row).To(gomega.HaveKeyWithValue("input", "deprecated"))
+		})
+	}
+}
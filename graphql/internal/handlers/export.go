@@ -0,0 +1,403 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// exportPathPrefix is the path prefix of the account history export end-points.
+	exportPathPrefix = "/export/account/"
+
+	// exportFlushRows is the number of rows written before the output is flushed to the client.
+	exportFlushRows = 500
+
+	// exportDateFormat is the format of dates accepted as the export range bounds.
+	exportDateFormat = "2006-01-02"
+
+	// exportNativeDecimals is the number of decimals of the native FTM token.
+	exportNativeDecimals = 18
+
+	// exportWriteWindow is the time the client has to receive the output written until the next flush.
+	exportWriteWindow = 30 * time.Second
+)
+
+// exportTransactionColumns is the list of columns of the native transactions export.
+var exportTransactionColumns = []string{"hash", "block", "time", "from", "to", "contract", "value", "fee", "status", "method"}
+
+// exportTokenColumns is the list of columns of the token transactions export.
+var exportTokenColumns = []string{"hash", "time", "token", "symbol", "token_type", "type", "from", "to", "amount", "token_id"}
+
+// exportWriter represents an output format of the account history export.
+type exportWriter interface {
+	header([]string) error
+	row([]string) error
+	flush() error
+}
+
+// csvExportWriter writes the export as comma separated values with a header line.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+// jsonlExportWriter writes the export as JSON Lines, one object per row.
+type jsonlExportWriter struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+// exportDeadliner represents a response writer able to extend the write deadline of the connection.
+// Responses of the HTTP server of Go 1.20 and newer implement it; on older servers
+// the export stream is cut off by the server write timeout.
+type exportDeadliner interface {
+	SetWriteDeadline(time.Time) error
+}
+
+// tokenDetail represents the details of a token used to format exported token transactions.
+type tokenDetail struct {
+	symbol   string
+	decimals int
+}
+
+// Export constructs and returns the HTTP handler streaming account history exports.
+// Supported paths are /export/account/{address}/transactions.{csv|jsonl}
+// and /export/account/{address}/tokens.{csv|jsonl}; the range is limited by optional
+// "from" and "to" query parameters (a date YYYY-MM-DD, or an UNIX time stamp)
+// and the number of rows by the optional "limit" parameter.
+func Export(cfg *config.Config, log logger.Logger) http.Handler {
+	return &LoggingHandler{
+		logger: log,
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			// parse the request
+			addr, kind, format, err := exportPath(r.URL.Path)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			rf, err := exportRange(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			limit, err := exportLimit(r, cfg.Export.MaxRows)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			// prep the output
+			var ew exportWriter
+			if format == "csv" {
+				w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+				ew = &csvExportWriter{w: csv.NewWriter(w)}
+			} else {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				ew = &jsonlExportWriter{enc: json.NewEncoder(w)}
+			}
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.%s\"", strings.ToLower(addr.String()), kind, format))
+			if r.Method == http.MethodHead {
+				return
+			}
+
+			// stream the rows; the write deadline of the server is extended on each flush
+			exportExtendDeadline(w)
+			if kind == "transactions" {
+				err = exportTransactions(r.Context(), w, ew, &addr, rf, limit)
+			} else {
+				err = exportTokenTransactions(r.Context(), w, ew, &addr, rf, limit)
+			}
+			if err != nil {
+				log.Errorf("export of %s %s failed; %s", addr.String(), kind, err.Error())
+			}
+		}),
+	}
+}
+
+// exportPath parses the account address, the kind and the format of the export from the request path.
+func exportPath(path string) (common.Address, string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(path, exportPathPrefix), "/")
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, "", "", fmt.Errorf("unknown export %s", path)
+	}
+
+	dot := strings.LastIndex(parts[1], ".")
+	if dot < 0 {
+		return common.Address{}, "", "", fmt.Errorf("unknown export %s", path)
+	}
+
+	kind, format := parts[1][:dot], parts[1][dot+1:]
+	if (kind != "transactions" && kind != "tokens") || (format != "csv" && format != "jsonl") {
+		return common.Address{}, "", "", fmt.Errorf("unknown export %s", path)
+	}
+	return common.HexToAddress(parts[0]), kind, format, nil
+}
+
+// exportRange parses the time range of the export from the request query.
+func exportRange(r *http.Request) (*types.RangeFilter, error) {
+	var rf types.RangeFilter
+	var err error
+
+	if v := r.URL.Query().Get("from"); v != "" {
+		if rf.FromTime, err = exportTime(v, 0); err != nil {
+			return nil, err
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if rf.ToTime, err = exportTime(v, 24*time.Hour-time.Second); err != nil {
+			return nil, err
+		}
+	}
+	return &rf, nil
+}
+
+// exportTime parses a date, or an UNIX time stamp; the offset is added to dates
+// so the end of the day can be used as the upper bound.
+func exportTime(v string, offset time.Duration) (*uint64, error) {
+	if ts, err := strconv.ParseUint(v, 10, 64); err == nil {
+		return &ts, nil
+	}
+
+	t, err := time.ParseInLocation(exportDateFormat, v, time.UTC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %s, YYYY-MM-DD or UNIX time stamp expected", v)
+	}
+	ts := uint64(t.Add(offset).Unix())
+	return &ts, nil
+}
+
+// exportLimit parses the max number of rows of the export from the request query.
+func exportLimit(r *http.Request, max int64) (int64, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return max, nil
+	}
+
+	limit, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit %s", v)
+	}
+	if limit > max {
+		limit = max
+	}
+	return limit, nil
+}
+
+// exportTransactions streams native transactions of the account into the export writer.
+func exportTransactions(ctx context.Context, w http.ResponseWriter, ew exportWriter, addr *common.Address, rf *types.RangeFilter, limit int64) error {
+	if err := ew.header(exportTransactionColumns); err != nil {
+		return err
+	}
+
+	var rows int
+	err := repository.R().ExportAccountTransactions(ctx, addr, rf, limit, func(trx *types.Transaction) error {
+		if err := ew.row(exportTransactionRow(trx)); err != nil {
+			return err
+		}
+		return exportFlush(w, ew, &rows)
+	})
+	if err != nil {
+		return err
+	}
+	return ew.flush()
+}
+
+// exportTransactionRow formats the given transaction into an export row.
+func exportTransactionRow(trx *types.Transaction) []string {
+	row := make([]string, len(exportTransactionColumns))
+	row[0] = trx.Hash.String()
+	if trx.BlockNumber != nil {
+		row[1] = strconv.FormatUint(uint64(*trx.BlockNumber), 10)
+	}
+	row[2] = trx.TimeStamp.UTC().Format(time.RFC3339)
+	row[3] = trx.From.String()
+	if trx.To != nil {
+		row[4] = trx.To.String()
+	}
+	if trx.ContractAddress != nil {
+		row[5] = trx.ContractAddress.String()
+	}
+	row[6] = exportUnits(trx.Value.ToInt(), exportNativeDecimals)
+	if trx.GasUsed != nil {
+		fee := new(big.Int).Mul(trx.GasPrice.ToInt(), new(big.Int).SetUint64(uint64(*trx.GasUsed)))
+		row[7] = exportUnits(fee, exportNativeDecimals)
+	}
+	if trx.Status != nil {
+		row[8] = strconv.FormatUint(uint64(*trx.Status), 10)
+	}
+	if len(trx.InputData) >= 4 {
+		row[9] = trx.InputData[:4].String()
+	}
+	return row
+}
+
+// exportTokenTransactions streams token transactions of the account into the export writer.
+func exportTokenTransactions(ctx context.Context, w http.ResponseWriter, ew exportWriter, addr *common.Address, rf *types.RangeFilter, limit int64) error {
+	if err := ew.header(exportTokenColumns); err != nil {
+		return err
+	}
+
+	var rows int
+	tokens := make(map[common.Address]*tokenDetail)
+	err := repository.R().ExportTokenTransactions(ctx, addr, rf, limit, func(etx *types.TokenTransaction) error {
+		if err := ew.row(exportTokenRow(etx, exportToken(tokens, etx))); err != nil {
+			return err
+		}
+		return exportFlush(w, ew, &rows)
+	})
+	if err != nil {
+		return err
+	}
+	return ew.flush()
+}
+
+// exportToken provides the details of the token of the given token transaction.
+// The details are loaded once per export and kept in the given map.
+func exportToken(tokens map[common.Address]*tokenDetail, etx *types.TokenTransaction) *tokenDetail {
+	if td, ok := tokens[etx.TokenAddress]; ok {
+		return td
+	}
+
+	td := new(tokenDetail)
+	switch etx.TokenType {
+	case types.AccountTypeERC20Token:
+		if dec, err := repository.R().Erc20Decimals(&etx.TokenAddress); err == nil {
+			td.decimals = int(dec)
+		}
+		td.symbol, _ = repository.R().Erc20Symbol(&etx.TokenAddress)
+	case types.AccountTypeERC721Contract:
+		td.symbol, _ = repository.R().Erc721Symbol(&etx.TokenAddress)
+	}
+
+	tokens[etx.TokenAddress] = td
+	return td
+}
+
+// exportTokenRow formats the given token transaction into an export row.
+func exportTokenRow(etx *types.TokenTransaction, td *tokenDetail) []string {
+	row := make([]string, len(exportTokenColumns))
+	row[0] = etx.Transaction.String()
+	row[1] = time.Unix(int64(etx.TimeStamp), 0).UTC().Format(time.RFC3339)
+	row[2] = etx.TokenAddress.String()
+	row[3] = td.symbol
+	row[4] = etx.TokenType
+	row[5] = types.TokenTrxTypeName(etx.Type)
+	row[6] = etx.Sender.String()
+	row[7] = etx.Recipient.String()
+	row[8] = exportUnits(etx.Amount.ToInt(), td.decimals)
+	if etx.TokenType != types.AccountTypeERC20Token {
+		row[9] = etx.TokenId.ToInt().String()
+	}
+	return row
+}
+
+// exportFlush flushes the output to the client every exportFlushRows rows.
+func exportFlush(w http.ResponseWriter, ew exportWriter, rows *int) error {
+	*rows++
+	if *rows%exportFlushRows != 0 {
+		return nil
+	}
+	if err := ew.flush(); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	exportExtendDeadline(w)
+	return nil
+}
+
+// exportExtendDeadline extends the write deadline of the connection, if the response writer allows it,
+// so long exports are not cut off by the server write timeout while the client keeps receiving.
+func exportExtendDeadline(w http.ResponseWriter) {
+	if d, ok := w.(exportDeadliner); ok {
+		_ = d.SetWriteDeadline(time.Now().Add(exportWriteWindow))
+	}
+}
+
+// exportUnits formats the given raw amount as a decimal number with the given number of decimals.
+func exportUnits(val *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return val.String()
+	}
+
+	div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(val), div, new(big.Int))
+
+	s := whole.String()
+	if frac.Sign() != 0 {
+		fs := strings.TrimRight(fmt.Sprintf("%0*s", decimals, frac.String()), "0")
+		s = s + "." + fs
+	}
+	if val.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// header writes the CSV header line.
+func (cw *csvExportWriter) header(columns []string) error {
+	return cw.w.Write(columns)
+}
+
+// row writes a CSV line.
+func (cw *csvExportWriter) row(values []string) error {
+	safe := make([]string, len(values))
+	for i, v := range values {
+		safe[i] = csvSafeCell(v)
+	}
+	return cw.w.Write(safe)
+}
+
+// csvSafeCell neutralizes a cell value spreadsheet applications would evaluate as a formula
+// by prefixing it with an apostrophe. Token symbols and names are set by token contracts
+// and can not be trusted.
+func csvSafeCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// flush writes buffered CSV lines to the output.
+func (cw *csvExportWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// header keeps the column names used as keys of the JSON objects.
+func (jw *jsonlExportWriter) header(columns []string) error {
+	jw.columns = columns
+	return nil
+}
+
+// row writes a JSON object line.
+func (jw *jsonlExportWriter) row(values []string) error {
+	obj := make(map[string]string, len(values))
+	for i, v := range values {
+		obj[jw.columns[i]] = v
+	}
+	return jw.enc.Encode(obj)
+}
+
+// flush does nothing, the JSON encoder writes directly to the output.
+func (jw *jsonlExportWriter) flush() error {
+	return nil
+}
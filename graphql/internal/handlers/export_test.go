@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// deadlineRecorder represents a response recorder keeping track of the write deadline set.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadline time.Time
+}
+
+// SetWriteDeadline records the write deadline.
+func (dr *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	dr.deadline = t
+	return nil
+}
+
+func TestCsvExportWriterRow(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{name: "plain values", in: []string{"0xabc", "12.5", "FTM"}, want: "0xabc,12.5,FTM\n"},
+		{name: "formula", in: []string{"=HYPERLINK(\"http://x\")"}, want: "\"'=HYPERLINK(\"\"http://x\"\")\"\n"},
+		{name: "plus and minus", in: []string{"+1", "-1"}, want: "'+1,'-1\n"},
+		{name: "at sign", in: []string{"@SUM(A1)"}, want: "'@SUM(A1)\n"},
+		{name: "leading tab", in: []string{"\tx"}, want: "'\tx\n"},
+		{name: "inner formula chars", in: []string{"a=b", "x@y"}, want: "a=b,x@y\n"},
+		{name: "empty", in: []string{"", "x"}, want: ",x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			var buf bytes.Buffer
+			cw := &csvExportWriter{w: csv.NewWriter(&buf)}
+			g.Expect(cw.row(tt.in)).To(gomega.Succeed())
+			g.Expect(cw.flush()).To(gomega.Succeed())
+			g.Expect(buf.String()).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestExportFlush(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+	var buf bytes.Buffer
+	cw := &csvExportWriter{w: csv.NewWriter(&buf)}
+
+	// the deadline is extended only when the output is flushed
+	var rows int
+	for i := 0; i < exportFlushRows-1; i++ {
+		g.Expect(exportFlush(w, cw, &rows)).To(gomega.Succeed())
+	}
+	g.Expect(w.deadline.IsZero()).To(gomega.BeTrue())
+
+	g.Expect(exportFlush(w, cw, &rows)).To(gomega.Succeed())
+	g.Expect(w.Flushed).To(gomega.BeTrue())
+	g.Expect(w.deadline).To(gomega.BeTemporally("~", time.Now().Add(exportWriteWindow), time.Second))
+}
+
+func TestExportExtendDeadline(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the stream outlives the server write timeout only if the deadline is extended
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exportExtendDeadline(w)
+		for i := 0; i < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte("row\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	srv.Config.WriteTimeout = 150 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := ioutil.ReadAll(res.Body)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(body)).To(gomega.Equal("row\nrow\nrow\n"))
+}
@@ -0,0 +1,19 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"fantom-api-graphql/internal/graphql/resolvers"
+	"net/http"
+)
+
+// LoaderHandler attaches request scoped batch loaders to the request context so lookups
+// of the same kind made by resolvers within a single GraphQL execution are coalesced.
+type LoaderHandler struct {
+	handler http.Handler
+}
+
+// ServeHTTP handles incoming request by attaching new batch loaders to the request context
+// and passing it to the next handler in the chain.
+func (h *LoaderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r.WithContext(resolvers.WithLoaders(r.Context())))
+}
@@ -0,0 +1,82 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// rateLimiterIdleTimeout represents the time after which an idle client limiter is released.
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+// clientRateLimiter limits the rate of requests of individual clients using a token bucket per client.
+type clientRateLimiter struct {
+	mu      sync.Mutex
+	limit   rate.Limit
+	burst   int
+	clients map[string]*clientLimiter
+	swept   time.Time
+}
+
+// clientLimiter represents the token bucket of a single client.
+type clientLimiter struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// newClientRateLimiter creates a new client rate limiter allowing the given number of calls
+// per second with the given burst. The rate is not limited if the limit is not positive.
+func newClientRateLimiter(limit float64, burst int) *clientRateLimiter {
+	rl := clientRateLimiter{
+		limit:   rate.Limit(limit),
+		burst:   burst,
+		clients: make(map[string]*clientLimiter),
+		swept:   time.Now(),
+	}
+
+	if limit <= 0 {
+		rl.limit = rate.Inf
+	}
+	if rl.burst < 1 {
+		rl.burst = 1
+	}
+	return &rl
+}
+
+// allow checks if the client can make the given number of calls now
+// and consumes the tokens of the calls if so.
+func (rl *clientRateLimiter) allow(client string, calls int) bool {
+	if rl.limit == rate.Inf {
+		return true
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.sweep(now)
+
+	cl, ok := rl.clients[client]
+	if !ok {
+		cl = &clientLimiter{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.clients[client] = cl
+	}
+
+	cl.seen = now
+	return cl.limiter.AllowN(now, calls)
+}
+
+// sweep releases limiters of clients idle for too long; the caller holds the lock.
+func (rl *clientRateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.swept) < rateLimiterIdleTimeout {
+		return
+	}
+
+	for id, cl := range rl.clients {
+		if now.Sub(cl.seen) > rateLimiterIdleTimeout {
+			delete(rl.clients, id)
+		}
+	}
+	rl.swept = now
+}
@@ -13,7 +13,7 @@ func GasPrice(log logger.Logger) http.Handler {
 	// build the handler function
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// get the gas price estimation
-		val, err := repository.R().GasPriceExtended()
+		val, err := repository.R().GasPriceExtended(r.Context())
 		if err != nil {
 			log.Critical("can not get gas price; %s", err.Error())
 			w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,188 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fmt"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/cors"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// rpcMaxBodySize represents the max size of a JSON-RPC gateway request body.
+	rpcMaxBodySize = 5 * 1024 * 1024
+
+	// rpcVersion represents the JSON-RPC protocol version.
+	rpcVersion = "2.0"
+
+	// JSON-RPC error codes used by the gateway
+	rpcErrParse          = -32700
+	rpcErrInvalidRequest = -32600
+	rpcErrMethodNotFound = -32601
+	rpcErrInvalidParams  = -32602
+	rpcErrServer         = -32000
+	rpcErrLimitExceeded  = -32005
+)
+
+// rpcRequest represents a single JSON-RPC call.
+type rpcRequest struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse represents the response to a single JSON-RPC call.
+type rpcResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError represents the error of a failed JSON-RPC call.
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Rpc constructs and returns the HTTP handler of the JSON-RPC gateway. Calls of the allowed methods
+// are served from the repository cache, or forwarded to the connected node. Batch requests are supported
+// and the rate of calls is limited per client.
+func Rpc(cfg *config.Config, log logger.Logger) http.Handler {
+	allowed := make(map[string]bool, len(cfg.Rpc.Methods))
+	for _, m := range cfg.Rpc.Methods {
+		allowed[m] = true
+	}
+	limiter := newClientRateLimiter(cfg.Rpc.RateLimit, cfg.Rpc.RateBurst)
+
+	corsHandler := cors.New(corsOptions(cfg))
+	corsHandler.Log = log
+
+	return &LoggingHandler{
+		logger: log,
+		handler: corsHandler.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, rpcMaxBodySize))
+			if err != nil {
+				rpcWrite(w, http.StatusRequestEntityTooLarge, rpcFailure(nil, rpcErrInvalidRequest, "request too large"), log)
+				return
+			}
+
+			reqs, batch, err := rpcParse(body)
+			if err != nil {
+				rpcWrite(w, http.StatusOK, rpcFailure(nil, rpcErrParse, err.Error()), log)
+				return
+			}
+			if batch && cfg.Rpc.MaxBatch > 0 && len(reqs) > cfg.Rpc.MaxBatch {
+				rpcWrite(w, http.StatusOK, rpcFailure(nil, rpcErrInvalidRequest, fmt.Sprintf("batch too large, max %d calls allowed", cfg.Rpc.MaxBatch)), log)
+				return
+			}
+
+			// each call of a batch counts against the client rate limit
+			if !limiter.allow(clientIP(r, trustedProxyHops(cfg)), len(reqs)) {
+				rpcWrite(w, http.StatusTooManyRequests, rpcFailure(nil, rpcErrLimitExceeded, "rate limit exceeded"), log)
+				return
+			}
+
+			res := make([]*rpcResponse, len(reqs))
+			for i := range reqs {
+				res[i] = rpcCall(r.Context(), &reqs[i], allowed, log)
+			}
+
+			if batch {
+				rpcWrite(w, http.StatusOK, res, log)
+				return
+			}
+			rpcWrite(w, http.StatusOK, res[0], log)
+		})),
+	}
+}
+
+// rpcParse decodes the request body into the list of calls; a batch is recognized by the JSON array.
+func rpcParse(body []byte) ([]rpcRequest, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var list []rpcRequest
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, true, err
+		}
+		if len(list) == 0 {
+			return nil, true, fmt.Errorf("empty batch")
+		}
+		return list, true, nil
+	}
+
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false, err
+	}
+	return []rpcRequest{req}, false, nil
+}
+
+// rpcCall executes a single JSON-RPC call of the gateway.
+func rpcCall(ctx context.Context, req *rpcRequest, allowed map[string]bool, log logger.Logger) *rpcResponse {
+	if req.JsonRpc != rpcVersion || req.Method == "" {
+		return rpcFailure(req.Id, rpcErrInvalidRequest, "invalid request")
+	}
+	if !allowed[req.Method] {
+		return rpcFailure(req.Id, rpcErrMethodNotFound, fmt.Sprintf("the method %s does not exist/is not available", req.Method))
+	}
+
+	// parameters are passed by position only
+	params := bytes.TrimSpace(req.Params)
+	if len(params) > 0 && (params[0] != '[' || !json.Valid(params)) {
+		return rpcFailure(req.Id, rpcErrInvalidParams, "invalid params")
+	}
+
+	res, err := repository.R().GatewayCall(ctx, req.Method, params)
+	if err != nil {
+		log.Debugf("rpc gateway call %s failed; %s", req.Method, err.Error())
+		return rpcNodeFailure(req.Id, err)
+	}
+	return &rpcResponse{JsonRpc: rpcVersion, Id: req.Id, Result: res}
+}
+
+// rpcFailure builds the response of a failed JSON-RPC call.
+func rpcFailure(id json.RawMessage, code int, msg string) *rpcResponse {
+	return &rpcResponse{JsonRpc: rpcVersion, Id: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+// rpcNodeFailure builds the response of a JSON-RPC call failed by the node;
+// the error code and data of the node are passed to the client.
+func rpcNodeFailure(id json.RawMessage, err error) *rpcResponse {
+	res := rpcFailure(id, rpcErrServer, err.Error())
+
+	var re gethrpc.Error
+	if errors.As(err, &re) {
+		res.Error.Code = re.ErrorCode()
+	}
+
+	var de gethrpc.DataError
+	if errors.As(err, &de) {
+		res.Error.Data = de.ErrorData()
+	}
+	return res
+}
+
+// rpcWrite writes the given JSON-RPC response to the client.
+func rpcWrite(w http.ResponseWriter, status int, res interface{}, log logger.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf("can not encode rpc response; %s", err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// requestTag is the context key of the value identifying the test request.
+type requestTag struct{}
+
+// nodeCallRepository is a repository substitute recording the node calls made and their request tags.
+type nodeCallRepository struct {
+	repository.Repository
+	mu    sync.Mutex
+	calls map[string]interface{}
+}
+
+// record keeps the request tag of the given call.
+func (nr *nodeCallRepository) record(ctx context.Context, method string) {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+	nr.calls[method] = ctx.Value(requestTag{})
+}
+
+// NodeCall records the call and provides an empty list.
+func (nr *nodeCallRepository) NodeCall(ctx context.Context, method string, _ ...interface{}) (json.RawMessage, error) {
+	nr.record(ctx, method)
+	return json.RawMessage(`[]`), nil
+}
+
+// GatewayCall records the call and provides an empty list.
+func (nr *nodeCallRepository) GatewayCall(ctx context.Context, method string, _ json.RawMessage) (json.RawMessage, error) {
+	nr.record(ctx, method)
+	return json.RawMessage(`[]`), nil
+}
+
+func TestNodeCallsFollowRequest(t *testing.T) {
+	nr := &nodeCallRepository{calls: make(map[string]interface{})}
+	repository.Set(nr)
+
+	cfg := &config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}}
+	cfg.Rpc.Methods = []string{"eth_getLogs"}
+	log := logger.New(cfg)
+
+	tests := []struct {
+		name   string
+		h      http.Handler
+		ctype  string
+		body   string
+		method string
+	}{
+		{
+			name:   "rpc gateway",
+			h:      Rpc(cfg, log),
+			ctype:  "application/json",
+			body:   `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x1"}]}`,
+			method: "eth_getLogs",
+		},
+		{
+			name:   "etherscan proxy",
+			h:      Etherscan(cfg, log),
+			ctype:  "application/x-www-form-urlencoded",
+			body:   url.Values{"module": {"proxy"}, "action": {"eth_blockNumber"}}.Encode(),
+			method: "eth_blockNumber",
+		},
+		{
+			name:   "etherscan logs",
+			h:      Etherscan(cfg, log),
+			ctype:  "application/x-www-form-urlencoded",
+			body:   url.Values{"module": {"logs"}, "action": {"getLogs"}, "fromBlock": {"1"}, "toBlock": {"2"}}.Encode(),
+			method: "eth_getLogs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			delete(nr.calls, tt.method)
+
+			req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.ctype)
+			req = req.WithContext(context.WithValue(req.Context(), requestTag{}, tt.name))
+
+			rec := httptest.NewRecorder()
+			tt.h.ServeHTTP(rec, req)
+			g.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+
+			// the node call is made within the request context
+			g.Expect(nr.calls).To(gomega.HaveKeyWithValue(tt.method, tt.name))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"encoding/json"
+	"fantom-api-graphql/cmd/apiserver/build"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"net/http"
+	"time"
+)
+
+// serverStatus represents the state of the server reported by the status end-point.
+type serverStatus struct {
+	Name      string       `json:"name"`
+	Version   string       `json:"version"`
+	Mode      string       `json:"mode"`
+	LastBlock uint64       `json:"lastBlock"`
+	NodeBlock uint64       `json:"nodeBlock"`
+	Lease     *leaseStatus `json:"lease,omitempty"`
+}
+
+// leaseStatus represents the state of the indexer lease reported by the status end-point.
+type leaseStatus struct {
+	Holder    string    `json:"holder"`
+	Acquired  time.Time `json:"acquired"`
+	Heartbeat time.Time `json:"heartbeat"`
+	Expires   time.Time `json:"expires"`
+	Lapsed    bool      `json:"lapsed"`
+	Leader    bool      `json:"leader"`
+}
+
+// Status constructs and return the HTTP handler reporting the state of the server
+// and the progress of the blockchain indexing.
+func Status(cfg *config.Config, log logger.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st := serverStatus{
+			Name:    cfg.AppName,
+			Version: build.Short(cfg),
+			Mode:    cfg.Server.Mode,
+		}
+
+		var err error
+		if st.LastBlock, err = repository.R().LastKnownBlock(); err != nil {
+			log.Errorf("can not get the last known block; %s", err.Error())
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		// the node may be out of reach; the status is still worth reporting
+		if h, err := repository.R().BlockHeight(r.Context()); err == nil {
+			st.NodeBlock = h.ToInt().Uint64()
+		}
+
+		// the indexer lease is reported only if the leader election is used
+		if cfg.Lease.Enabled {
+			st.Lease = indexerLease(cfg, log)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(st); err != nil {
+			log.Errorf("can not encode server status; %s", err.Error())
+		}
+	})
+}
+
+// indexerLease provides the current state of the indexer lease; nil if not available.
+func indexerLease(cfg *config.Config, log logger.Logger) *leaseStatus {
+	lease, err := repository.R().Lease(types.LeaseIndexer)
+	if err != nil {
+		log.Errorf("can not get the indexer lease; %s", err.Error())
+		return nil
+	}
+	if lease == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	return &leaseStatus{
+		Holder:    lease.Holder,
+		Acquired:  lease.Acquired,
+		Heartbeat: lease.Heartbeat,
+		Expires:   lease.Expires,
+		Lapsed:    !lease.Expires.After(now),
+		Leader:    lease.IsHeldBy(cfg.Lease.Holder, now),
+	}
+}
@@ -0,0 +1,45 @@
+// Package handlers holds HTTP/WS handlers chain along with separate middleware implementations.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fantom-api-graphql/internal/graphql/complexity"
+	"fantom-api-graphql/internal/logger"
+	"fmt"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// subscriptionService serves GraphQL operations sent over websocket connections.
+// Operations are metered against the API key limits of the connection client and checked
+// against the complexity limits before they are executed, the same way as operations sent over HTTP.
+type subscriptionService struct {
+	schema   *graphql.Schema
+	analyzer *complexity.Analyzer
+	log      logger.Logger
+}
+
+// Subscribe meters and analyzes the operation started on a websocket connection
+// and executes it if it's within the limits.
+func (s *subscriptionService) Subscribe(ctx context.Context, document string, operationName string, variables map[string]interface{}) (<-chan interface{}, error) {
+	if err := meterOperation(ctx); err != nil {
+		s.log.Debugf("websocket operation %s rejected; %s", operationName, err.Error())
+		return nil, err
+	}
+
+	res, err := s.analyzer.Analyze(document, operationName, variables)
+	if err != nil {
+		var le *complexity.LimitError
+		if errors.As(err, &le) {
+			s.log.Warningf("websocket operation %s rejected; depth %d of %d, cost %d of %d",
+				operationName, le.Depth, le.MaxDepth, le.Cost, le.MaxCost)
+			return nil, le
+		}
+
+		s.log.Debugf("websocket operation %s not analyzed; %s", operationName, err.Error())
+		return nil, fmt.Errorf("operation can not be analyzed; %s", err.Error())
+	}
+
+	s.log.Debugf("websocket operation %s depth %d cost %d", operationName, res.Depth, res.Cost)
+	return s.schema.Subscribe(ctx, document, operationName, variables)
+}
@@ -0,0 +1,230 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/repository/rpc/contracts"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bundledAbiTypes represents the token standards of the ABIs bundled with the API server.
+// ERC20 and ERC721 share method selectors, so the order of the list matters.
+var bundledAbiTypes = []string{
+	types.AccountTypeERC20Token,
+	types.AccountTypeERC721Contract,
+	types.AccountTypeERC1155Contract,
+}
+
+// bundledAbiList represents the parsed ABIs of well known token standards
+// used to decode calls of contracts without a verified ABI.
+var bundledAbiList map[string]*abi.ABI
+
+// onceBundledAbi is the sync object used to make sure the bundled ABIs
+// are parsed only once on the first demand.
+var onceBundledAbi sync.Once
+
+// bundledAbis provides the list of parsed ABIs bundled with the API server.
+// The ABI of the given token type goes first, the others follow in the default order.
+func bundledAbis(tokenType string) []*abi.ABI {
+	onceBundledAbi.Do(func() {
+		src := map[string]string{
+			types.AccountTypeERC20Token:      contracts.ERCTwentyMetaData.ABI,
+			types.AccountTypeERC721Contract:  contracts.ERC721MetaData.ABI,
+			types.AccountTypeERC1155Contract: contracts.ERC1155MetaData.ABI,
+		}
+
+		bundledAbiList = make(map[string]*abi.ABI, len(src))
+		for t, s := range src {
+			if ab := parseAbi(s); ab != nil {
+				bundledAbiList[t] = ab
+			}
+		}
+	})
+
+	list := make([]*abi.ABI, 0, len(bundledAbiTypes))
+	if ab, ok := bundledAbiList[tokenType]; ok {
+		list = append(list, ab)
+	}
+	for _, t := range bundledAbiTypes {
+		if ab, ok := bundledAbiList[t]; ok && t != tokenType {
+			list = append(list, ab)
+		}
+	}
+	return list
+}
+
+// parseAbi parses the given JSON ABI definition; it returns nil if the definition is not valid.
+func parseAbi(def string) *abi.ABI {
+	if len(def) == 0 {
+		return nil
+	}
+
+	ab, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		log.Debugf("invalid ABI definition; %s", err.Error())
+		return nil
+	}
+	return &ab
+}
+
+// contractAbi provides the parsed ABI of the contract stored in the repository, if available.
+func (p *proxy) contractAbi(ctx context.Context, addr *common.Address) *abi.ABI {
+	if sc := p.abiContract(ctx, addr); sc != nil {
+		return parseAbi(sc.Abi)
+	}
+	return nil
+}
+
+// contractAbis provides a list of candidate ABIs for decoding calls of the given contract.
+// The stored contract ABI is used first, bundled ABIs of well known token standards follow;
+// the bundled ABI of the detected token type of the contract is preferred.
+func (p *proxy) contractAbis(ctx context.Context, addr *common.Address) []*abi.ABI {
+	sc := p.abiContract(ctx, addr)
+	if sc == nil {
+		return bundledAbis("")
+	}
+
+	list := make([]*abi.ABI, 0)
+	if ab := parseAbi(sc.Abi); ab != nil {
+		list = append(list, ab)
+	}
+	return append(list, bundledAbis(sc.Type)...)
+}
+
+// abiContract provides the contract stored in the repository; nil is returned for unknown contracts.
+func (p *proxy) abiContract(ctx context.Context, addr *common.Address) *types.Contract {
+	sc, err := p.Contract(ctx, addr)
+	if err != nil {
+		p.log.Errorf("can not load contract %s; %s", addr.String(), err.Error())
+		return nil
+	}
+	return sc
+}
+
+// decodedArguments converts the list of unpacked values into decoded arguments.
+func decodedArguments(args abi.Arguments, values []interface{}) []*types.DecodedArgument {
+	list := make([]*types.DecodedArgument, len(values))
+	for i, v := range values {
+		list[i] = &types.DecodedArgument{
+			Name:  args[i].Name,
+			Type:  args[i].Type.String(),
+			Value: abiValueString(v),
+		}
+	}
+	return list
+}
+
+// abiSignature builds the canonical signature for the given name and the list of arguments.
+func abiSignature(name string, args abi.Arguments) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteString("(")
+	for i, a := range args {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(a.Type.String())
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// abiValueString renders the given unpacked ABI value as a human-readable string.
+// Scalar values are rendered directly, complex values are encoded as JSON.
+func abiValueString(v interface{}) string {
+	switch val := abiValue(v).(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+// abiValue converts the given unpacked ABI value into a JSON friendly representation.
+// Numbers are represented by decimal strings to keep their full precision,
+// byte sequences, addresses and hashes are represented by hex strings.
+func abiValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *big.Int:
+		return val.String()
+	case common.Address:
+		return val.String()
+	case common.Hash:
+		return val.String()
+	case []byte:
+		return hexutil.Encode(val)
+	case string:
+		return val
+	case bool:
+		return val
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return abiValue(rv.Elem().Interface())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Array:
+		// fixed size byte arrays are rendered as hex
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(buf), rv)
+			return hexutil.Encode(buf)
+		}
+		return abiListValue(rv)
+	case reflect.Slice:
+		return abiListValue(rv)
+	case reflect.Struct:
+		out := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			name := rv.Type().Field(i).Tag.Get("json")
+			if name == "" {
+				name = rv.Type().Field(i).Name
+			}
+			out[name] = abiValue(rv.Field(i).Interface())
+		}
+		return out
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// abiListValue converts the given array, or slice, of unpacked ABI values
+// into a JSON friendly list.
+func abiListValue(rv reflect.Value) []interface{} {
+	out := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out[i] = abiValue(rv.Index(i).Interface())
+	}
+	return out
+}
@@ -9,6 +9,7 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"context"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,13 +17,13 @@ import (
 )
 
 // Account returns account at Opera blockchain for an address, nil if not found.
-func (p *proxy) Account(addr *common.Address) (acc *types.Account, err error) {
+func (p *proxy) Account(ctx context.Context, addr *common.Address) (acc *types.Account, err error) {
 	// try to get the account from cache
 	acc = p.cache.PullAccount(addr)
 
 	// we still don't know the account? try to manually construct it if possible
 	if acc == nil {
-		acc, err = p.getAccount(addr)
+		acc, err = p.getAccount(ctx, addr)
 		if err != nil {
 			return nil, err
 		}
@@ -32,8 +33,47 @@ func (p *proxy) Account(addr *common.Address) (acc *types.Account, err error) {
 	return acc, nil
 }
 
+// AccountsByAddress returns accounts at Opera blockchain for the given addresses mapped by the address.
+// Accounts missing in cache are loaded from the database at once.
+func (p *proxy) AccountsByAddress(ctx context.Context, addr []common.Address) (map[common.Address]*types.Account, error) {
+	res := make(map[common.Address]*types.Account, len(addr))
+	miss := make([]common.Address, 0, len(addr))
+	for i := range addr {
+		if acc := p.cache.PullAccount(&addr[i]); acc != nil {
+			res[addr[i]] = acc
+			continue
+		}
+		miss = append(miss, addr[i])
+	}
+
+	if len(miss) == 0 {
+		return res, nil
+	}
+
+	known, err := p.db.AccountsByAddress(ctx, miss)
+	if err != nil {
+		p.log.Errorf("can not get %d accounts; %s", len(miss), err.Error())
+		return nil, err
+	}
+
+	for i := range miss {
+		acc, ok := known[miss[i]]
+		if !ok {
+			// at least we know the account existed; check if it's a smart contract
+			acc = &types.Account{Address: miss[i], Type: types.AccountTypeWallet}
+			acc.ContractTx, _ = p.db.ContractTransaction(ctx, &miss[i])
+		}
+
+		if err = p.cache.PushAccount(acc); err != nil {
+			p.log.Warningf("can not keep account [%s] information in memory; %s", miss[i].Hex(), err.Error())
+		}
+		res[miss[i]] = acc
+	}
+	return res, nil
+}
+
 // getAccount builds the account representation after validating it against Opera node.
-func (p *proxy) getAccount(addr *common.Address) (*types.Account, error) {
+func (p *proxy) getAccount(ctx context.Context, addr *common.Address) (*types.Account, error) {
 	// any address given?
 	if addr == nil {
 		p.log.Error("no address given")
@@ -41,7 +81,7 @@ func (p *proxy) getAccount(addr *common.Address) (*types.Account, error) {
 	}
 
 	// try to get the account from database first
-	acc, err := p.db.Account(addr)
+	acc, err := p.db.Account(ctx, addr)
 	if err != nil {
 		p.log.Errorf("can not get the account %s; %s", addr.String(), err.Error())
 		return nil, err
@@ -56,7 +96,7 @@ func (p *proxy) getAccount(addr *common.Address) (*types.Account, error) {
 		acc = &types.Account{Address: *addr, Type: types.AccountTypeWallet}
 
 		// check if this is a smart contract account; we log the error on the call
-		acc.ContractTx, _ = p.db.ContractTransaction(addr)
+		acc.ContractTx, _ = p.db.ContractTransaction(ctx, addr)
 	}
 
 	// also keep a copy at the in-memory cache
@@ -67,24 +107,30 @@ func (p *proxy) getAccount(addr *common.Address) (*types.Account, error) {
 }
 
 // AccountBalance returns the current balance of an account at Opera blockchain.
-func (p *proxy) AccountBalance(addr *common.Address) (*hexutil.Big, error) {
-	return p.rpc.AccountBalance(addr)
+func (p *proxy) AccountBalance(ctx context.Context, addr *common.Address) (*hexutil.Big, error) {
+	return p.rpc.AccountBalance(ctx, addr)
 }
 
 // AccountNonce returns the current number of sent transactions of an account at Opera blockchain.
-func (p *proxy) AccountNonce(addr *common.Address) (*hexutil.Uint64, error) {
-	return p.rpc.AccountNonce(addr)
+func (p *proxy) AccountNonce(ctx context.Context, addr *common.Address) (*hexutil.Uint64, error) {
+	return p.rpc.AccountNonce(ctx, addr)
 }
 
 // AccountTransactions returns slice of AccountTransaction structure for a given account at Opera blockchain.
-func (p *proxy) AccountTransactions(addr *common.Address, rec *common.Address, cursor *string, count int32) (*types.TransactionList, error) {
+func (p *proxy) AccountTransactions(ctx context.Context, addr *common.Address, rec *common.Address, rf *types.RangeFilter, tf *types.TransactionFilter, cursor *string, count int32) (*types.TransactionList, error) {
 	// do we have an account?
 	if addr == nil {
 		return nil, fmt.Errorf("can not get transaction list for empty account")
 	}
 
 	// go to the database for the list of hashes of transaction searched
-	return p.db.AccountTransactions(addr, rec, cursor, count)
+	return p.db.AccountTransactions(ctx, addr, rec, rf, tf, cursor, count)
+}
+
+// AccountTransactionsPage provides an offset addressed page of transactions of the given account
+// within the given block and time range.
+func (p *proxy) AccountTransactionsPage(ctx context.Context, addr *common.Address, rf *types.RangeFilter, skip int64, limit int64, asc bool) ([]*types.Transaction, error) {
+	return p.db.AccountTransactionsPage(ctx, addr, rf, skip, limit, asc)
 }
 
 // AccountsActive returns total number of accounts known to repository.
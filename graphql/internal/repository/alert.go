@@ -0,0 +1,40 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"time"
+)
+
+// AddAlert stores a new alert, unless an alert of the same ID is already known.
+// It reports if the alert has been added.
+func (p *proxy) AddAlert(alert *types.Alert) (bool, error) {
+	return p.db.AddAlert(alert)
+}
+
+// UpdateAlertPush updates the sinks push state of the given alert.
+func (p *proxy) UpdateAlertPush(alert *types.Alert) error {
+	return p.db.UpdateAlertPush(alert)
+}
+
+// AlertsPushDue provides alerts waiting to be pushed to their sinks scheduled for an attempt by the given time.
+func (p *proxy) AlertsPushDue(ts time.Time) ([]*types.Alert, error) {
+	return p.db.AlertsPushDue(ts)
+}
+
+// AlertRules provides the list of alert rules stored in the persistent storage.
+func (p *proxy) AlertRules() ([]*types.AlertRule, error) {
+	return p.db.AlertRules()
+}
+
+// Alerts provides list of alerts starting at the specified cursor.
+func (p *proxy) Alerts(cursor *string, count int32) (*types.AlertList, error) {
+	return p.db.Alerts(cursor, count)
+}
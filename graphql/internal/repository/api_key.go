@@ -0,0 +1,100 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"time"
+)
+
+const (
+	// apiKeyLength represents the number of random bytes of a generated API key.
+	apiKeyLength = 24
+
+	// apiKeyPrefixLength represents the number of characters of the key kept to help recognize it.
+	apiKeyPrefixLength = 8
+)
+
+// CreateApiKey generates and stores a new API key of the given holder name and access tier.
+// The generated key is provided along with the stored record; it can not be recovered later.
+func (p *proxy) CreateApiKey(id string, name string, tier string) (*types.ApiKey, string, error) {
+	raw := make([]byte, apiKeyLength)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	key := hex.EncodeToString(raw)
+
+	ak := types.ApiKey{
+		ID:      id,
+		Hash:    apiKeyHash(key),
+		Prefix:  key[:apiKeyPrefixLength],
+		Name:    name,
+		Tier:    tier,
+		Enabled: true,
+		Created: time.Now().UTC(),
+	}
+	if err := p.db.StoreApiKey(&ak); err != nil {
+		return nil, "", err
+	}
+	return &ak, key, nil
+}
+
+// StoreApiKey updates the given API key.
+func (p *proxy) StoreApiKey(key *types.ApiKey) error {
+	if key.Hash == "" {
+		return fmt.Errorf("api key %s has no hash", key.ID)
+	}
+	return p.db.StoreApiKey(key)
+}
+
+// DeleteApiKey removes the API key of the given ID together with its usage records.
+func (p *proxy) DeleteApiKey(id string) error {
+	return p.db.DeleteApiKey(id)
+}
+
+// ApiKey provides the API key of the given ID; nil if not found.
+func (p *proxy) ApiKey(id string) (*types.ApiKey, error) {
+	return p.db.ApiKey(id)
+}
+
+// ApiKeyByKey provides the API key record of the given key presented by a client; nil if not found.
+func (p *proxy) ApiKeyByKey(key string) (*types.ApiKey, error) {
+	return p.db.ApiKeyByHash(apiKeyHash(key))
+}
+
+// ApiKeys provides the list of all the API keys.
+func (p *proxy) ApiKeys() ([]*types.ApiKey, error) {
+	return p.db.ApiKeys()
+}
+
+// AddApiKeyUsage adds the given numbers of requests to the daily usage of an API key.
+func (p *proxy) AddApiKeyUsage(u *types.ApiKeyUsage) error {
+	return p.db.AddApiKeyUsage(u)
+}
+
+// ConsumeApiQuota counts a request of the client against its daily quota;
+// false is returned if the quota of the day is exhausted.
+func (p *proxy) ConsumeApiQuota(client string, day string, quota int64) (bool, error) {
+	return p.db.ConsumeApiQuota(client, day, quota)
+}
+
+// ApiKeyUsage provides the daily usage of the API key over the given number of the most recent days.
+func (p *proxy) ApiKeyUsage(id string, days int32) ([]*types.ApiKeyUsage, error) {
+	return p.db.ApiKeyUsage(id, int64(days))
+}
+
+// apiKeyHash calculates the hash of the given API key under which the key is stored.
+func apiKeyHash(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -9,6 +9,7 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"context"
 	"errors"
 	"fantom-api-graphql/internal/repository/cache"
 	"fantom-api-graphql/internal/repository/rpc"
@@ -30,8 +31,8 @@ func (p *proxy) ObservedHeaders() chan *etc.Header {
 }
 
 // BlockHeight returns the current height of the Opera blockchain in blocks.
-func (p *proxy) BlockHeight() (*hexutil.Big, error) {
-	return p.rpc.BlockHeight()
+func (p *proxy) BlockHeight(ctx context.Context) (*hexutil.Big, error) {
+	return p.rpc.BlockHeight(ctx)
 }
 
 // LastKnownBlock returns number of the last block known to the repository.
@@ -49,32 +50,69 @@ func (p *proxy) CacheBlock(blk *types.Block) {
 	p.cache.AddBlock(blk)
 }
 
+// StoreBlock records the block as processed in the shared store,
+// so API replicas without the indexer learn about it.
+func (p *proxy) StoreBlock(blk *types.Block) error {
+	return p.db.AddBlock(blk)
+}
+
 // BlockByNumber returns a block at Opera blockchain represented by a number. Top block is returned if the number
 // is not provided.
 // If the block is not found, ErrBlockNotFound error is returned.
-func (p *proxy) BlockByNumber(num *hexutil.Uint64) (*types.Block, error) {
+func (p *proxy) BlockByNumber(ctx context.Context, num *hexutil.Uint64) (*types.Block, error) {
 	// return the top block if block number is not provided
 	if num == nil {
 		tag := rpc.BlockTypeLatest
-		return p.blockByTag(&tag)
+		return p.blockByTag(ctx, &tag)
+	}
+	return p.getBlock(ctx, num.String(), p.blockByTag)
+}
+
+// BlocksByNumber returns blocks at Opera blockchain of the given numbers mapped by the number.
+// Blocks missing in cache are pulled from the node by a single batch call.
+func (p *proxy) BlocksByNumber(ctx context.Context, nums []hexutil.Uint64) (map[hexutil.Uint64]*types.Block, error) {
+	res := make(map[hexutil.Uint64]*types.Block, len(nums))
+	miss := make([]hexutil.Uint64, 0, len(nums))
+	for _, num := range nums {
+		if blk := p.cache.PullBlock(num.String()); blk != nil {
+			res[num] = blk
+			continue
+		}
+		miss = append(miss, num)
+	}
+
+	if len(miss) == 0 {
+		return res, nil
+	}
+
+	list, err := p.rpc.Blocks(ctx, miss)
+	if err != nil {
+		return nil, err
+	}
+
+	for num, blk := range list {
+		if err := p.cache.PushBlock(num.String(), blk); err != nil {
+			p.log.Errorf("can not cache; %s", err.Error())
+		}
+		res[num] = blk
 	}
-	return p.getBlock(num.String(), p.blockByTag)
+	return res, nil
 }
 
 // BlockByHash returns a block at Opera blockchain represented by a hash. Top block is returned if the hash
 // is not provided.
 // If the block is not found, ErrBlockNotFound error is returned.
-func (p *proxy) BlockByHash(hash *common.Hash) (*types.Block, error) {
+func (p *proxy) BlockByHash(ctx context.Context, hash *common.Hash) (*types.Block, error) {
 	// do we have a hash?
 	if hash == nil {
 		tag := rpc.BlockTypeLatest
-		return p.blockByTag(&tag)
+		return p.blockByTag(ctx, &tag)
 	}
-	return p.getBlock(hash.String(), p.rpc.BlockByHash)
+	return p.getBlock(ctx, hash.String(), p.rpc.BlockByHash)
 }
 
 // getBlock gets a block of given tag from cache, or from a repository pull function.
-func (p *proxy) getBlock(tag string, pull func(*string) (*types.Block, error)) (*types.Block, error) {
+func (p *proxy) getBlock(ctx context.Context, tag string, pull func(context.Context, *string) (*types.Block, error)) (*types.Block, error) {
 	// inform what we do
 	p.log.Debugf("block [%s] requested", tag)
 
@@ -88,7 +126,7 @@ func (p *proxy) getBlock(tag string, pull func(*string) (*types.Block, error)) (
 	}
 
 	// extract the block from the chain
-	blk, err := pull(&tag)
+	blk, err := pull(ctx, &tag)
 	if err != nil {
 		// block simply not found?
 		if err == eth.ErrNoResult {
@@ -113,12 +151,12 @@ func (p *proxy) getBlock(tag string, pull func(*string) (*types.Block, error)) (
 
 // blockByTag returns a block at Opera blockchain represented by given tag.
 // The tag could be an encoded block number, or a predefined string tag for "earliest", "latest" or "pending" block.
-func (p *proxy) blockByTag(tag *string) (*types.Block, error) {
+func (p *proxy) blockByTag(ctx context.Context, tag *string) (*types.Block, error) {
 	// inform what we do
 	p.log.Debugf("loading block [%s]", *tag)
 
 	// extract the block
-	block, err := p.rpc.Block(tag)
+	block, err := p.rpc.Block(ctx, tag)
 	if err != nil {
 		// block simply not found?
 		if err == eth.ErrNoResult {
@@ -134,7 +172,7 @@ func (p *proxy) blockByTag(tag *string) (*types.Block, error) {
 }
 
 // initBlockList finds and returns the first block of the list and initializes the list internals accordingly.
-func (p *proxy) initBlockList(num *uint64, count int32) (*types.Block, *types.BlockList, error) {
+func (p *proxy) initBlockList(ctx context.Context, num *uint64, count int32) (*types.Block, *types.BlockList, error) {
 	// start from the latest block by default
 	var tag = rpc.BlockTypeLatest
 
@@ -149,7 +187,7 @@ func (p *proxy) initBlockList(num *uint64, count int32) (*types.Block, *types.Bl
 	p.log.Debugf("initializing a new blocks list using tag [%s]", tag)
 
 	// get the latest block to start from
-	fb, err := p.blockByTag(&tag)
+	fb, err := p.blockByTag(ctx, &tag)
 	if err != nil {
 		p.log.Critical("the starting block not found in the blockchain")
 		return nil, nil, err
@@ -175,7 +213,7 @@ func (p *proxy) initBlockList(num *uint64, count int32) (*types.Block, *types.Bl
 }
 
 // pullBlocks pulls specified list of blocks from repository and calculates boundary situation.
-func (p *proxy) pullBlocks(num *uint64, count int32, toPull int32, current *types.Block, list *types.BlockList) {
+func (p *proxy) pullBlocks(ctx context.Context, num *uint64, count int32, toPull int32, current *types.Block, list *types.BlockList) {
 	// prep the scan vars
 	var next *types.Block
 	var tag hexutil.Uint64
@@ -203,7 +241,7 @@ func (p *proxy) pullBlocks(num *uint64, count int32, toPull int32, current *type
 		}
 
 		// try to get next block; break the loop on search issue; in that case <next> will be nil
-		next, err = p.BlockByNumber(&tag)
+		next, err = p.BlockByNumber(ctx, &tag)
 		if err != nil {
 			break
 		}
@@ -229,7 +267,7 @@ func checkBlocksListBoundary(count int32, next *types.Block, list *types.BlockLi
 // No-number boundaries are handled as follows:
 // 	- For positive count we start from the most recent block and scan to older blocks.
 // 	- For negative count we start from the first block and scan to newer blocks.
-func (p *proxy) Blocks(num *uint64, count int32) (*types.BlockList, error) {
+func (p *proxy) Blocks(ctx context.Context, num *uint64, count int32) (*types.BlockList, error) {
 	// nothing to load?
 	if count == 0 {
 		return nil, fmt.Errorf("nothing to do, zero blocks requested")
@@ -244,13 +282,13 @@ func (p *proxy) Blocks(num *uint64, count int32) (*types.BlockList, error) {
 	}
 
 	// slow block list
-	return p.makeBlocksList(num, count)
+	return p.makeBlocksList(ctx, num, count)
 }
 
 // makeBlocksList creates a block list for defined blocks range.
-func (p *proxy) makeBlocksList(num *uint64, count int32) (*types.BlockList, error) {
+func (p *proxy) makeBlocksList(ctx context.Context, num *uint64, count int32) (*types.BlockList, error) {
 	// init the list
-	current, list, err := p.initBlockList(num, count)
+	current, list, err := p.initBlockList(ctx, num, count)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +305,7 @@ func (p *proxy) makeBlocksList(num *uint64, count int32) (*types.BlockList, erro
 	}
 
 	// get specified list of block from repository
-	p.pullBlocks(num, count, toPull, current, list)
+	p.pullBlocks(ctx, num, count, toPull, current, list)
 
 	// if we scanned from bottom up, we need to reverse the list so newer blocks are on top
 	if count < 0 {
@@ -0,0 +1,88 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BlockByTime returns the last block collated at, or before, the given UNIX time stamp;
+// or the first block collated at, or after, the time stamp if the after flag is set.
+// The search range is narrowed by the transactions known to the database,
+// the block is then found by binary search over blocks provided by the connected node.
+// If the block is not found, ErrBlockNotFound error is returned.
+func (p *proxy) BlockByTime(ctx context.Context, ts uint64, after bool) (*types.Block, error) {
+	top, err := p.BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the time is after the top block; there is no block after it yet
+	if uint64(top.TimeStamp) <= ts {
+		if after && uint64(top.TimeStamp) < ts {
+			return nil, ErrBlockNotFound
+		}
+		return top, nil
+	}
+
+	// narrow the range by the transactions around the time, if known
+	lo, hi := uint64(0), uint64(top.Number)
+	before, next, err := p.db.TransactionBlocksAround(ts)
+	if err != nil {
+		p.log.Errorf("can not narrow block search by time; %s", err.Error())
+	}
+	if before > lo && before < hi {
+		lo = before
+	}
+	if next > lo && next < hi {
+		hi = next
+	}
+
+	// find the first block collated after the time (or at the time, if looking for a block after)
+	pos, err := p.blockSearch(ctx, lo, hi, func(blk *types.Block) bool {
+		return uint64(blk.TimeStamp) > ts || (after && uint64(blk.TimeStamp) == ts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the block before is the one right below the found position
+	if !after {
+		if pos == 0 {
+			return nil, ErrBlockNotFound
+		}
+		pos--
+	}
+
+	num := hexutil.Uint64(pos)
+	return p.BlockByNumber(ctx, &num)
+}
+
+// blockSearch finds the lowest block number in the <lo, hi> range satisfying the given predicate.
+// The predicate is expected to be monotonic over block numbers and to be satisfied by the hi block.
+func (p *proxy) blockSearch(ctx context.Context, lo uint64, hi uint64, pred func(*types.Block) bool) (uint64, error) {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		num := hexutil.Uint64(mid)
+
+		blk, err := p.BlockByNumber(ctx, &num)
+		if err != nil {
+			return 0, err
+		}
+
+		if pred(blk) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
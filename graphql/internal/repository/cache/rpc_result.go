@@ -0,0 +1,37 @@
+// Package cache implements bridge to fast in-memory object cache.
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// rpcResultCacheKeyPrefix is the prefix used for cache key to store results of JSON-RPC calls.
+const rpcResultCacheKeyPrefix = "rpc_"
+
+// PullRpcResult extracts the result of a JSON-RPC call from the in-memory cache if available.
+func (b *MemBridge) PullRpcResult(method string, params []byte) json.RawMessage {
+	data, err := b.cache.Get(getRpcResultKey(method, params))
+	if err != nil {
+		// cache returns ErrEntryNotFound if the key does not exist
+		return nil
+	}
+	return data
+}
+
+// PushRpcResult stores the result of a JSON-RPC call in the in-memory cache.
+func (b *MemBridge) PushRpcResult(method string, params []byte, res json.RawMessage) error {
+	return b.cache.Set(getRpcResultKey(method, params), res)
+}
+
+// getRpcResultKey builds a cache key for the given JSON-RPC call method and encoded parameters.
+func getRpcResultKey(method string, params []byte) string {
+	var sb strings.Builder
+
+	sb.WriteString(rpcResultCacheKeyPrefix)
+	sb.WriteString(method)
+	sb.WriteString(":")
+	sb.Write(params)
+
+	return sb.String()
+}
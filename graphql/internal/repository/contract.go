@@ -9,20 +9,29 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"bytes"
+	"context"
+	"fantom-api-graphql/internal/solidity"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"strings"
+	"time"
 )
 
+// contractCompileTimeout represents the max time a contract source code compilation may take.
+const contractCompileTimeout = 2 * time.Minute
+
 // Contract extract a smart contract information by account address, if available.
-func (p *proxy) Contract(addr *common.Address) (*types.Contract, error) {
+func (p *proxy) Contract(ctx context.Context, addr *common.Address) (*types.Contract, error) {
 	// try cache first
 	sc := p.cache.PullContract(addr)
 
 	// we still don't know the contract? call the db for that
 	if sc == nil {
 		var err error
-		sc, err = p.db.Contract(addr)
+		sc, err = p.db.Contract(ctx, addr)
 		if err != nil {
 			return nil, err
 		}
@@ -38,17 +47,149 @@ func (p *proxy) Contract(addr *common.Address) (*types.Contract, error) {
 	return sc, nil
 }
 
+// ContractsByAddress extracts smart contracts of the given addresses mapped by the address.
+// Contracts missing in cache are loaded from the database at once.
+func (p *proxy) ContractsByAddress(ctx context.Context, addr []common.Address) (map[common.Address]*types.Contract, error) {
+	res := make(map[common.Address]*types.Contract, len(addr))
+	miss := make([]common.Address, 0, len(addr))
+	for i := range addr {
+		if sc := p.cache.PullContract(&addr[i]); sc != nil {
+			res[addr[i]] = sc
+			continue
+		}
+		miss = append(miss, addr[i])
+	}
+
+	if len(miss) == 0 {
+		return res, nil
+	}
+
+	list, err := p.db.ContractsByAddress(ctx, miss)
+	if err != nil {
+		return nil, err
+	}
+
+	for adr, sc := range list {
+		if err = p.cache.PushContract(sc); err != nil {
+			p.log.Criticalf("can not cache contract %s; %s", adr.String(), err.Error())
+		}
+		res[adr] = sc
+	}
+	return res, nil
+}
+
 // Contracts returns list of smart contracts at Opera blockchain.
-func (p *proxy) Contracts(validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
+func (p *proxy) Contracts(ctx context.Context, validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
 	// go to the database for the list of contracts searched
-	return p.db.Contracts(validatedOnly, cursor, count)
+	return p.db.Contracts(ctx, validatedOnly, cursor, count)
 }
 
 // ValidateContract tries to validate contract byte code using
 // provided source code. If successful, the contract information
 // is updated the repository.
-func (p *proxy) ValidateContract(_ *types.Contract) error {
-	return fmt.Errorf("contract validation not supported")
+func (p *proxy) ValidateContract(ctx context.Context, sc *types.Contract, cc *types.ContractCompilation) error {
+	if cc == nil {
+		cc = new(types.ContractCompilation)
+	}
+
+	// get the deployed byte code to compare the compiled code with
+	code, err := p.rpc.AccountCode(ctx, &sc.Address)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no byte code deployed at %s", sc.Address.String())
+	}
+
+	// build the contracts
+	list, err := p.compileContract(ctx, sc, cc)
+	if err != nil {
+		return err
+	}
+
+	for i := range list {
+		if sc.Name != "" && !strings.HasSuffix(list[i].Name, ":"+sc.Name) {
+			continue
+		}
+		if !list[i].Matches(code) {
+			continue
+		}
+
+		// the constructor arguments, if any, must match the deployment
+		if err := p.verifyConstructorArguments(ctx, sc, cc.ConstructorArguments); err != nil {
+			return err
+		}
+
+		// update the contract details
+		sc.Abi = list[i].Abi
+		sc.Compiler = list[i].Compiler
+		if sc.Compiler == "" {
+			sc.Compiler = cc.Compiler
+		}
+		if sc.Name == "" {
+			sc.Name = list[i].Name[strings.LastIndex(list[i].Name, ":")+1:]
+		}
+
+		now := hexutil.Uint64(time.Now().UTC().Unix())
+		sc.Validated = &now
+
+		p.log.Noticef("contract %s validated as %s", sc.Address.String(), list[i].Name)
+		return p.StoreContract(sc)
+	}
+	return fmt.Errorf("source code does not match the byte code deployed at %s", sc.Address.String())
+}
+
+// compileContract builds the source code of the given contract with the requested compiler settings.
+func (p *proxy) compileContract(ctx context.Context, sc *types.Contract, cc *types.ContractCompilation) ([]solidity.Contract, error) {
+	sol, err := solidity.CompilerPath(p.solCompiler, p.solReleases, cc.Compiler)
+	if err != nil {
+		return nil, err
+	}
+
+	// the standard JSON input carries its own compiler settings
+	var in []byte
+	switch cc.CodeFormat {
+	case "", solidity.CodeFormatSingleFile:
+		in, err = solidity.SingleFileInput(sc.SourceCode, &solidity.Settings{
+			Optimized:    sc.IsOptimized,
+			OptimizeRuns: sc.OptimizeRuns,
+			EvmVersion:   cc.EvmVersion,
+		})
+	case solidity.CodeFormatStandardJson:
+		in, err = solidity.StandardJsonInput(sc.SourceCode)
+	default:
+		return nil, fmt.Errorf("code format %s not supported", cc.CodeFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, contractCompileTimeout)
+	defer cancel()
+
+	return solidity.Compile(ctx, sol, in)
+}
+
+// verifyConstructorArguments checks the given hex encoded constructor arguments
+// against the tail of the contract deployment transaction input.
+func (p *proxy) verifyConstructorArguments(ctx context.Context, sc *types.Contract, args string) error {
+	if args == "" {
+		return nil
+	}
+
+	want, err := hexutil.Decode("0x" + strings.TrimPrefix(args, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid constructor arguments; %s", err.Error())
+	}
+
+	trx, err := p.rpc.Transaction(ctx, &sc.TransactionHash)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(trx.InputData, want) {
+		return fmt.Errorf("constructor arguments do not match the deployment of %s", sc.Address.String())
+	}
+	return nil
 }
 
 // StoreContract adds new contract into the repository.
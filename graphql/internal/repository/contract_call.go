@@ -0,0 +1,361 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fantom-api-graphql/internal/repository/rpc"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CallContract executes a read-only call of the given contract function with the given arguments
+// on the state of the given block, or the latest block. The call is encoded using the stored contract ABI,
+// or ABIs of well known token standards. The function can be identified by its name, or by its full signature
+// if the name is overloaded. Arguments of complex types (arrays and tuples) are expected to be JSON encoded.
+func (p *proxy) CallContract(ctx context.Context, addr *common.Address, method string, args []string, block *hexutil.Uint64) (res *types.ContractCallResult, err error) {
+	// ABI packing of unexpected input should not take the resolver down
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Errorf("call of %s on %s crashed; %v", method, addr.String(), r)
+			res, err = nil, fmt.Errorf("invalid call of %s", method)
+		}
+	}()
+
+	m, err := p.contractMethod(ctx, addr, method, len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the arguments by the expected types
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		val, err := abiInputValue(m.Inputs[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument #%d of %s; %s", i, m.Sig, err.Error())
+		}
+		values[i] = val.Interface()
+	}
+
+	data, err := m.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("can not encode call of %s; %s", m.Sig, err.Error())
+	}
+
+	out, err := p.rpc.Call(ctx, &rpc.CallArgs{To: addr, Data: append(common.CopyBytes(m.ID), data...)}, block)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := m.Outputs.Unpack(out)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode result of %s; %s", m.Sig, err.Error())
+	}
+
+	return &types.ContractCallResult{
+		Method:    m.RawName,
+		Signature: m.Sig,
+		Outputs:   callOutputs(m.Outputs, outputs),
+	}, nil
+}
+
+// callOutputs converts the list of unpacked output values into typed call outputs.
+// Arrays and tuples keep their structure, see abiValue().
+func callOutputs(args abi.Arguments, values []interface{}) []*types.ContractCallOutput {
+	list := make([]*types.ContractCallOutput, len(values))
+	for i, v := range values {
+		list[i] = &types.ContractCallOutput{
+			Name:  args[i].Name,
+			Type:  args[i].Type.String(),
+			Value: abiValue(v),
+		}
+	}
+	return list
+}
+
+// ContractViewFunctions provides the list of read-only functions of the given contract
+// declared by the stored contract ABI. An empty list is provided if the ABI is not available.
+func (p *proxy) ContractViewFunctions(ctx context.Context, addr *common.Address) []*types.ContractFunction {
+	list := make([]*types.ContractFunction, 0)
+
+	ab := p.contractAbi(ctx, addr)
+	if ab == nil {
+		return list
+	}
+
+	for _, m := range ab.Methods {
+		if !m.IsConstant() {
+			continue
+		}
+
+		list = append(list, &types.ContractFunction{
+			Name:            m.RawName,
+			Signature:       m.Sig,
+			Selector:        m.ID,
+			StateMutability: m.StateMutability,
+			Inputs:          abiParameters(m.Inputs),
+			Outputs:         abiParameters(m.Outputs),
+		})
+	}
+
+	// methods of the ABI are not ordered
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Signature < list[j].Signature
+	})
+	return list
+}
+
+// contractMethod finds the given method of the given contract in the list of candidate ABIs.
+// The method is identified by its full signature, or by its name and the number of arguments.
+func (p *proxy) contractMethod(ctx context.Context, addr *common.Address, method string, argc int) (*abi.Method, error) {
+	for _, ab := range p.contractAbis(ctx, addr) {
+		var found *abi.Method
+		for _, m := range ab.Methods {
+			// full signature match is exact
+			if m.Sig == method {
+				if len(m.Inputs) != argc {
+					return nil, fmt.Errorf("method %s expects %d arguments, got %d", method, len(m.Inputs), argc)
+				}
+				mc := m
+				return &mc, nil
+			}
+
+			if m.RawName != method || len(m.Inputs) != argc {
+				continue
+			}
+
+			// we can not decide between overloaded methods with the same number of arguments
+			if found != nil {
+				return nil, fmt.Errorf("method %s is ambiguous, please use the full signature", method)
+			}
+
+			mc := m
+			found = &mc
+		}
+
+		if found != nil {
+			return found, nil
+		}
+	}
+	return nil, fmt.Errorf("method %s with %d arguments not found on contract %s", method, argc, addr.String())
+}
+
+// abiParameters converts the given ABI arguments into a list of parameter descriptions.
+func abiParameters(args abi.Arguments) []*types.AbiParameter {
+	list := make([]*types.AbiParameter, len(args))
+	for i, arg := range args {
+		list[i] = &types.AbiParameter{
+			Name:       arg.Name,
+			Type:       arg.Type.String(),
+			Components: abiTypeComponents(arg.Type),
+		}
+	}
+	return list
+}
+
+// abiTypeComponents provides the list of components of the given tuple type,
+// or the given array of tuples type.
+func abiTypeComponents(t abi.Type) []*types.AbiParameter {
+	switch t.T {
+	case abi.TupleTy:
+		list := make([]*types.AbiParameter, len(t.TupleElems))
+		for i, et := range t.TupleElems {
+			list[i] = &types.AbiParameter{
+				Name:       t.TupleRawNames[i],
+				Type:       et.String(),
+				Components: abiTypeComponents(*et),
+			}
+		}
+		return list
+	case abi.SliceTy, abi.ArrayTy:
+		return abiTypeComponents(*t.Elem)
+	}
+	return make([]*types.AbiParameter, 0)
+}
+
+// abiInputValue converts the given input value into the Go representation
+// of the given ABI type expected by the ABI encoder. Scalar values are expected
+// as strings, arrays and tuples are expected as lists, or as JSON encoded strings.
+// Tuples can also be provided as objects keyed by the component names.
+func abiInputValue(t abi.Type, in interface{}) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return abiInputNumber(t, abiInputScalar(in))
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(abiInputScalar(in))
+		return reflect.ValueOf(b), err
+	case abi.StringTy:
+		return reflect.ValueOf(abiInputScalar(in)), nil
+	case abi.AddressTy:
+		s := abiInputScalar(in)
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %s", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BytesTy:
+		b, err := hexutil.Decode(abiInputScalar(in))
+		return reflect.ValueOf(b), err
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(abiInputScalar(in))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		out := reflect.New(t.GetType()).Elem()
+		reflect.Copy(out, reflect.ValueOf(b))
+		return out, nil
+	case abi.SliceTy, abi.ArrayTy:
+		return abiInputList(t, abiInputJson(in))
+	case abi.TupleTy:
+		return abiInputTuple(t, abiInputJson(in))
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+}
+
+// abiInputNumber converts the given decimal, or hex, number into the Go representation
+// of the given integer ABI type.
+func abiInputNumber(t abi.Type, s string) (reflect.Value, error) {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid number %s", s)
+	}
+
+	// check the range of the value
+	if !abiNumberInRange(t, v) {
+		return reflect.Value{}, fmt.Errorf("number %s out of range of %s", s, t.String())
+	}
+
+	// large numbers are represented by big integers
+	if t.GetType() == reflect.TypeOf(v) {
+		return reflect.ValueOf(v), nil
+	}
+
+	out := reflect.New(t.GetType()).Elem()
+	if t.T == abi.UintTy {
+		out.SetUint(v.Uint64())
+	} else {
+		out.SetInt(v.Int64())
+	}
+	return out, nil
+}
+
+// abiNumberInRange checks if the given number fits the range of the given integer ABI type.
+func abiNumberInRange(t abi.Type, v *big.Int) bool {
+	if t.T == abi.UintTy {
+		return v.Sign() >= 0 && v.BitLen() <= t.Size
+	}
+
+	// signed integers span <-2^(n-1), 2^(n-1)-1>
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return v.Cmp(new(big.Int).Neg(limit)) >= 0 && v.Cmp(limit) < 0
+}
+
+// abiInputList converts the given list of input values into the Go representation
+// of the given ABI array, or slice type.
+func abiInputList(t abi.Type, in interface{}) (reflect.Value, error) {
+	list, ok := in.([]interface{})
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("list expected for %s", t.String())
+	}
+
+	var out reflect.Value
+	if t.T == abi.ArrayTy {
+		if len(list) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d items of %s, got %d", t.Size, t.String(), len(list))
+		}
+		out = reflect.New(t.GetType()).Elem()
+	} else {
+		out = reflect.MakeSlice(t.GetType(), len(list), len(list))
+	}
+
+	for i, item := range list {
+		v, err := abiInputValue(*t.Elem, item)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Index(i).Set(v)
+	}
+	return out, nil
+}
+
+// abiInputTuple converts the given list, or object, of input values into the Go representation
+// of the given ABI tuple type.
+func abiInputTuple(t abi.Type, in interface{}) (reflect.Value, error) {
+	items := make([]interface{}, len(t.TupleElems))
+	switch val := in.(type) {
+	case []interface{}:
+		if len(val) != len(items) {
+			return reflect.Value{}, fmt.Errorf("expected %d components of %s, got %d", len(items), t.String(), len(val))
+		}
+		copy(items, val)
+	case map[string]interface{}:
+		for i, name := range t.TupleRawNames {
+			items[i] = val[name]
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("list, or object expected for %s", t.String())
+	}
+
+	out := reflect.New(t.GetType()).Elem()
+	for i, et := range t.TupleElems {
+		v, err := abiInputValue(*et, items[i])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Field(i).Set(v)
+	}
+	return out, nil
+}
+
+// abiInputJson decodes the given input value if it's a JSON encoded list, or object.
+func abiInputJson(in interface{}) interface{} {
+	s, ok := in.(string)
+	if !ok {
+		return in
+	}
+
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") && !strings.HasPrefix(s, "{") {
+		return in
+	}
+
+	var out interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return in
+	}
+	return out
+}
+
+// abiInputScalar renders the given scalar input value as a string.
+func abiInputScalar(in interface{}) string {
+	switch val := in.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", in)
+}
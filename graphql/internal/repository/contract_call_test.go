@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/onsi/gomega"
+	"math/big"
+	"testing"
+)
+
+// mustAbiType builds the ABI type of the given name, or fails the test.
+func mustAbiType(t *testing.T, name string, components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType(name, "", components)
+	if err != nil {
+		t.Fatalf("invalid ABI type %s; %s", name, err.Error())
+	}
+	return typ
+}
+
+func TestAbiInputNumber(t *testing.T) {
+	tests := []struct {
+		typ   string
+		in    string
+		want  interface{}
+		valid bool
+	}{
+		{typ: "int8", in: "-128", want: int8(-128), valid: true},
+		{typ: "int8", in: "127", want: int8(127), valid: true},
+		{typ: "int8", in: "128", valid: false},
+		{typ: "int8", in: "-129", valid: false},
+		{typ: "uint8", in: "255", want: uint8(255), valid: true},
+		{typ: "uint8", in: "256", valid: false},
+		{typ: "uint8", in: "-1", valid: false},
+		{typ: "uint64", in: "0xffffffffffffffff", want: uint64(0xffffffffffffffff), valid: true},
+		{typ: "int24", in: "-8388608", want: big.NewInt(-8388608), valid: true},
+		{typ: "int24", in: "8388608", valid: false},
+		{typ: "int256", in: "-57896044618658097711785492504343953926634992332820282019728792003956564819968", want: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255)), valid: true},
+		{typ: "int256", in: "57896044618658097711785492504343953926634992332820282019728792003956564819968", valid: false},
+		{typ: "uint256", in: "1e18", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+"/"+tt.in, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			v, err := abiInputNumber(mustAbiType(t, tt.typ, nil), tt.in)
+			if !tt.valid {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(v.Interface()).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestAbiInputValue(t *testing.T) {
+	tuple := []abi.ArgumentMarshaling{{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"}}
+	addr := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+
+	tests := []struct {
+		name  string
+		typ   abi.Type
+		in    interface{}
+		check func(g *gomega.WithT, v interface{})
+	}{
+		{
+			name: "address",
+			typ:  mustAbiType(t, "address", nil),
+			in:   addr.String(),
+			check: func(g *gomega.WithT, v interface{}) {
+				g.Expect(v).To(gomega.Equal(addr))
+			},
+		},
+		{
+			name: "bool",
+			typ:  mustAbiType(t, "bool", nil),
+			in:   "true",
+			check: func(g *gomega.WithT, v interface{}) {
+				g.Expect(v).To(gomega.Equal(true))
+			},
+		},
+		{
+			name: "fixed bytes",
+			typ:  mustAbiType(t, "bytes4", nil),
+			in:   "0xa9059cbb",
+			check: func(g *gomega.WithT, v interface{}) {
+				g.Expect(v).To(gomega.Equal([4]byte{0xa9, 0x05, 0x9c, 0xbb}))
+			},
+		},
+		{
+			name: "slice of JSON numbers",
+			typ:  mustAbiType(t, "uint8[]", nil),
+			in:   "[1, 2, \"0x03\"]",
+			check: func(g *gomega.WithT, v interface{}) {
+				g.Expect(v).To(gomega.Equal([]uint8{1, 2, 3}))
+			},
+		},
+		{
+			name: "tuple as object",
+			typ:  mustAbiType(t, "tuple", tuple),
+			in:   `{"to": "` + addr.String() + `", "amount": "1000"}`,
+			check: func(g *gomega.WithT, v interface{}) {
+				g.Expect(v).To(gomega.HaveField("To", addr))
+				g.Expect(v).To(gomega.HaveField("Amount", big.NewInt(1000)))
+			},
+		},
+		{
+			name: "invalid address",
+			typ:  mustAbiType(t, "address", nil),
+			in:   "0x1234",
+		},
+		{
+			name: "short fixed bytes",
+			typ:  mustAbiType(t, "bytes4", nil),
+			in:   "0xa905",
+		},
+		{
+			name: "wrong array length",
+			typ:  mustAbiType(t, "uint8[2]", nil),
+			in:   "[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			v, err := abiInputValue(tt.typ, tt.in)
+			if tt.check == nil {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+
+			g.Expect(err).To(gomega.BeNil())
+			tt.check(g, v.Interface())
+		})
+	}
+}
+
+func TestCallOutputs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	holder := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+	args := abi.Arguments{
+		{Name: "total", Type: mustAbiType(t, "uint256", nil)},
+		{Name: "active", Type: mustAbiType(t, "bool", nil)},
+		{Name: "shares", Type: mustAbiType(t, "tuple[]", []abi.ArgumentMarshaling{
+			{Name: "holder", Type: "address"},
+			{Name: "amounts", Type: "uint64[]"},
+		})},
+	}
+
+	total, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	shares, err := abiInputValue(args[2].Type, `[{"holder": "`+holder.String()+`", "amounts": [1, 2]}]`)
+	g.Expect(err).To(gomega.BeNil())
+
+	data, err := args.Pack(total, true, shares.Interface())
+	g.Expect(err).To(gomega.BeNil())
+	values, err := args.Unpack(data)
+	g.Expect(err).To(gomega.BeNil())
+
+	// arrays and tuples keep their structure
+	out := callOutputs(args, values)
+	g.Expect(out).To(gomega.HaveLen(3))
+	g.Expect(*out[0]).To(gomega.Equal(types.ContractCallOutput{Name: "total", Type: "uint256", Value: total.String()}))
+	g.Expect(*out[1]).To(gomega.Equal(types.ContractCallOutput{Name: "active", Type: "bool", Value: true}))
+	g.Expect(out[2].Type).To(gomega.Equal("(address,uint64[])[]"))
+	g.Expect(out[2].Value).To(gomega.Equal([]interface{}{
+		map[string]interface{}{"holder": holder.String(), "amounts": []interface{}{"1", "2"}},
+	}))
+}
@@ -0,0 +1,35 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StoreContractSync stores the state of a contract validation sync to an API peer.
+func (p *proxy) StoreContractSync(cs *types.ContractSync) error {
+	return p.db.StoreContractSync(cs)
+}
+
+// ContractSyncsDue provides the list of pending contract validation syncs scheduled for a retry.
+func (p *proxy) ContractSyncsDue() ([]*types.ContractSync, error) {
+	return p.db.ContractSyncsDue()
+}
+
+// ContractSyncs provides the list of contract validation syncs of the given contract.
+// If the contract is not specified, the syncs not finished yet are provided.
+func (p *proxy) ContractSyncs(addr *common.Address) ([]*types.ContractSync, error) {
+	if addr == nil {
+		return p.db.ContractSyncs(nil)
+	}
+
+	sa := addr.String()
+	return p.db.ContractSyncs(&sa)
+}
@@ -0,0 +1,50 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fantom-api-graphql/internal/types"
+	"time"
+)
+
+// contractVerificationGuidLength represents the number of random bytes of a verification job GUID.
+// The GUID is hex encoded into the 50 characters long string the Etherscan tooling expects.
+const contractVerificationGuidLength = 25
+
+// SubmitContractVerification queues the given contract verification job for processing.
+// The job is assigned a new unique GUID.
+func (p *proxy) SubmitContractVerification(cv *types.ContractVerification) error {
+	guid := make([]byte, contractVerificationGuidLength)
+	if _, err := rand.Read(guid); err != nil {
+		return err
+	}
+
+	cv.GUID = hex.EncodeToString(guid)
+	cv.Status = types.ContractVerificationStatusPending
+	cv.Submitted = time.Now().UTC()
+	return p.db.StoreContractVerification(cv)
+}
+
+// StoreContractVerification updates the state of a contract verification job.
+func (p *proxy) StoreContractVerification(cv *types.ContractVerification) error {
+	return p.db.StoreContractVerification(cv)
+}
+
+// ContractVerification provides the contract verification job of the given GUID; nil if not found.
+func (p *proxy) ContractVerification(guid string) (*types.ContractVerification, error) {
+	return p.db.ContractVerification(guid)
+}
+
+// NextContractVerification claims the next contract verification job waiting for processing;
+// nil if no job is waiting.
+func (p *proxy) NextContractVerification() (*types.ContractVerification, error) {
+	return p.db.NextContractVerification()
+}
@@ -10,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"math/big"
+	"strings"
 	"time"
 )
 
@@ -54,14 +56,51 @@ func (db *MongoDbBridge) initAccountsCollection() {
 	db.log.Debugf("accounts collection initialized")
 }
 
+// AccountsActivity loads the transaction counters of the given accounts.
+// Accounts not known to the off-chain database are not included in the result.
+func (db *MongoDbBridge) AccountsActivity(addr []common.Address) (map[common.Address]uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(coAccounts)
+
+	ids := make(bson.A, len(addr))
+	for i, a := range addr {
+		ids[i] = a.String()
+	}
+
+	cursor, err := col.Find(ctx,
+		bson.D{{Key: fiAccountPk, Value: bson.D{{Key: "$in", Value: ids}}}},
+		options.Find().SetProjection(bson.D{{Key: fiAccountPk, Value: 1}, {Key: fiAccountTransactionCounter, Value: 1}}))
+	if err != nil {
+		db.log.Errorf("can not load accounts activity; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	res := make(map[common.Address]uint64, len(addr))
+	for cursor.Next(ctx) {
+		var row AccountRow
+		if err := cursor.Decode(&row); err != nil {
+			db.log.Errorf("can not decode account activity; %s", err.Error())
+			continue
+		}
+		res[common.HexToAddress(row.Address)] = row.Counter
+	}
+	return res, nil
+}
+
 // Account tries to load an account identified by the address given from
 // the off-chain database.
-func (db *MongoDbBridge) Account(addr *common.Address) (*types.Account, error) {
+func (db *MongoDbBridge) Account(ctx context.Context, addr *common.Address) (*types.Account, error) {
 	// get the collection for account transactions
 	col := db.client.Database(db.dbName).Collection(coAccounts)
 
 	// try to find the account
-	sr := col.FindOne(context.Background(), bson.D{{Key: fiAccountPk, Value: addr.String()}}, options.FindOne())
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	sr := col.FindOne(ctx, bson.D{{Key: fiAccountPk, Value: addr.String()}}, options.FindOne())
 
 	// error on lookup?
 	if sr.Err() != nil {
@@ -97,8 +136,54 @@ func (db *MongoDbBridge) Account(addr *common.Address) (*types.Account, error) {
 	}, nil
 }
 
+// AccountsByAddress loads the accounts of the given addresses from the off-chain database.
+// Accounts not known to the off-chain database are not included in the result.
+func (db *MongoDbBridge) AccountsByAddress(ctx context.Context, addr []common.Address) (map[common.Address]*types.Account, error) {
+	col := db.client.Database(db.dbName).Collection(coAccounts)
+
+	ids := make(bson.A, len(addr))
+	for i, a := range addr {
+		ids[i] = a.String()
+	}
+
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	cursor, err := col.Find(ctx, bson.D{{Key: fiAccountPk, Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		db.log.Errorf("can not load accounts; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	res := make(map[common.Address]*types.Account, len(addr))
+	for cursor.Next(ctx) {
+		var row AccountRow
+		if err := cursor.Decode(&row); err != nil {
+			db.log.Errorf("can not decode account; %s", err.Error())
+			return nil, err
+		}
+
+		acc := types.Account{
+			Address:      common.HexToAddress(row.Address),
+			Type:         row.Type,
+			LastActivity: hexutil.Uint64(row.Activity),
+			TrxCounter:   hexutil.Uint64(row.Counter),
+		}
+		if row.Sc != nil {
+			h := common.HexToHash(*row.Sc)
+			acc.ContractTx = &h
+		}
+		res[acc.Address] = &acc
+	}
+	return res, nil
+}
+
 // AddAccount stores an account in the blockchain if not exists.
 func (db *MongoDbBridge) AddAccount(acc *types.Account) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do we have account data?
 	if acc == nil {
 		return fmt.Errorf("can not add empty account")
@@ -115,7 +200,7 @@ func (db *MongoDbBridge) AddAccount(acc *types.Account) error {
 	}
 
 	// do the update based on given PK; we don't need to pull the document updated
-	_, err := col.InsertOne(context.Background(), bson.D{
+	_, err := col.InsertOne(ctx, bson.D{
 		{Key: fiAccountPk, Value: acc.Address.String()},
 		{Key: fiScCreationTx, Value: conTx},
 		{Key: fiAccountType, Value: acc.Type},
@@ -142,11 +227,14 @@ func (db *MongoDbBridge) AddAccount(acc *types.Account) error {
 
 // IsAccountKnown checks if an account document already exists in the database.
 func (db *MongoDbBridge) IsAccountKnown(addr *common.Address) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// get the collection for account transactions
 	col := db.client.Database(db.dbName).Collection(coAccounts)
 
 	// try to find the account in the database (it may already exist)
-	sr := col.FindOne(context.Background(), bson.D{
+	sr := col.FindOne(ctx, bson.D{
 		{Key: fiAccountPk, Value: addr.String()},
 	}, options.FindOne().SetProjection(bson.D{{Key: fiAccountPk, Value: true}}))
 
@@ -170,7 +258,8 @@ func (db *MongoDbBridge) AccountCount() (uint64, error) {
 }
 
 // AccountTransactions loads list of transaction hashes of an account.
-func (db *MongoDbBridge) AccountTransactions(addr *common.Address, rec *common.Address, cursor *string, count int32) (*types.TransactionList, error) {
+// The list may be narrowed by the recipient, the block and time range and the advanced filter.
+func (db *MongoDbBridge) AccountTransactions(ctx context.Context, addr *common.Address, rec *common.Address, rf *types.RangeFilter, tf *types.TransactionFilter, cursor *string, count int32) (*types.TransactionList, error) {
 	// nothing to load?
 	if count == 0 {
 		return nil, fmt.Errorf("nothing to do, zero blocks requested")
@@ -184,19 +273,69 @@ func (db *MongoDbBridge) AccountTransactions(addr *common.Address, rec *common.A
 	// log what we do here
 	db.log.Debugf("loading transactions of %s", addr.String())
 
+	// collect the range and advanced filter conditions
+	cond := append(trxRangeConditions(rf), accountTrxConditions(addr, tf)...)
+
 	// make the filter for [(from = Account) OR (to = Account)]
 	if rec == nil {
-		filter := bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "from", Value: addr.String()}}, bson.D{{Key: "to", Value: addr.String()}}}}}
-		return db.Transactions(cursor, count, &filter)
+		filter := withRangeFilter(bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "from", Value: addr.String()}}, bson.D{{Key: "to", Value: addr.String()}}}}}, cond)
+		return db.Transactions(ctx, cursor, count, &filter)
 	}
 
 	// return list of transactions filtered by the account and recipient
-	filter := bson.D{{Key: "from", Value: addr.String()}, {Key: "to", Value: rec.String()}}
-	return db.Transactions(cursor, count, &filter)
+	filter := withRangeFilter(bson.D{{Key: "from", Value: addr.String()}, {Key: "to", Value: rec.String()}}, cond)
+	return db.Transactions(ctx, cursor, count, &filter)
+}
+
+// accountTrxConditions provides the list of conditions of the advanced account transaction filter.
+// All the conditions are applied over the sender/recipient + ordinal indexes,
+// the method selector has its own sparse index.
+func accountTrxConditions(addr *common.Address, tf *types.TransactionFilter) bson.A {
+	cond := bson.A{}
+	if tf.IsEmpty() {
+		return cond
+	}
+
+	// direction of the transaction relative to the account
+	switch tf.Direction {
+	case types.TrxDirectionIncoming:
+		cond = append(cond, bson.D{{Key: fiTransactionRecipient, Value: addr.String()}})
+	case types.TrxDirectionOutgoing:
+		cond = append(cond, bson.D{{Key: fiTransactionSender, Value: addr.String()}})
+	case types.TrxDirectionSelf:
+		cond = append(cond, bson.D{{Key: fiTransactionSender, Value: addr.String()}, {Key: fiTransactionRecipient, Value: addr.String()}})
+	}
+
+	// execution status
+	if tf.Status != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionStatus, Value: *tf.Status}})
+	}
+
+	// contract creations have no recipient
+	if tf.CreationsOnly {
+		cond = append(cond, bson.D{{Key: fiTransactionRecipient, Value: nil}})
+	}
+
+	// calls to the given 4-byte method
+	if tf.Method != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionSelector, Value: strings.ToLower(*tf.Method)}})
+	}
+
+	// value range; the stored amount is kept in gWei
+	if tf.MinValue != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionAmount, Value: bson.D{{Key: "$gte", Value: new(big.Int).Div(tf.MinValue, types.TransactionDecimalsCorrection).Int64()}}}})
+	}
+	if tf.MaxValue != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionAmount, Value: bson.D{{Key: "$lte", Value: new(big.Int).Div(tf.MaxValue, types.TransactionDecimalsCorrection).Int64()}}}})
+	}
+	return cond
 }
 
 // AccountMarkActivity marks the latest account activity in the repository.
 func (db *MongoDbBridge) AccountMarkActivity(addr *common.Address, ts uint64) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// log what we do
 	db.log.Debugf("account %s activity at %s", addr.String(), time.Unix(int64(ts), 0).String())
 
@@ -204,7 +343,7 @@ func (db *MongoDbBridge) AccountMarkActivity(addr *common.Address, ts uint64) er
 	col := db.client.Database(db.dbName).Collection(coAccounts)
 
 	// update the contract details
-	if _, err := col.UpdateOne(context.Background(),
+	if _, err := col.UpdateOne(ctx,
 		bson.D{{Key: fiAccountPk, Value: addr.String()}},
 		bson.D{
 			{Key: "$set", Value: bson.D{{Key: fiAccountLastActivity, Value: ts}}},
@@ -220,6 +359,9 @@ func (db *MongoDbBridge) AccountMarkActivity(addr *common.Address, ts uint64) er
 
 // Erc20TokensList returns a list of known ERC20 tokens ordered by their activity.
 func (db *MongoDbBridge) Erc20TokensList(count int32) ([]common.Address, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// make sure the count is positive; use default size if not
 	if count <= 0 {
 		count = defaultTokenListLength
@@ -239,17 +381,20 @@ func (db *MongoDbBridge) Erc20TokensList(count int32) ([]common.Address, error)
 	}).SetLimit(int64(count))
 
 	// load the data
-	cursor, err := col.Find(context.Background(), filter, opt)
+	cursor, err := col.Find(ctx, filter, opt)
 	if err != nil {
 		db.log.Errorf("error loading ERC20 tokens list; %s", err.Error())
 		return nil, err
 	}
 
-	return db.loadErcContractsList(cursor)
+	return db.loadErcContractsList(ctx, cursor)
 }
 
 // Erc721ContractsList returns a list of known ERC20 tokens ordered by their activity.
 func (db *MongoDbBridge) Erc721ContractsList(count int32) ([]common.Address, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// make sure the count is positive; use default size if not
 	if count <= 0 {
 		count = defaultTokenListLength
@@ -269,17 +414,20 @@ func (db *MongoDbBridge) Erc721ContractsList(count int32) ([]common.Address, err
 	}).SetLimit(int64(count))
 
 	// load the data
-	cursor, err := col.Find(context.Background(), filter, opt)
+	cursor, err := col.Find(ctx, filter, opt)
 	if err != nil {
 		db.log.Errorf("error loading ERC721 tokens list; %s", err.Error())
 		return nil, err
 	}
 
-	return db.loadErcContractsList(cursor)
+	return db.loadErcContractsList(ctx, cursor)
 }
 
 // Erc1155ContractsList returns a list of known ERC1155 contracts ordered by their activity.
 func (db *MongoDbBridge) Erc1155ContractsList(count int32) ([]common.Address, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// make sure the count is positive; use default size if not
 	if count <= 0 {
 		count = defaultTokenListLength
@@ -299,23 +447,23 @@ func (db *MongoDbBridge) Erc1155ContractsList(count int32) ([]common.Address, er
 	}).SetLimit(int64(count))
 
 	// load the data
-	cursor, err := col.Find(context.Background(), filter, opt)
+	cursor, err := col.Find(ctx, filter, opt)
 	if err != nil {
 		db.log.Errorf("error loading ERC1155 tokens list; %s", err.Error())
 		return nil, err
 	}
 
-	return db.loadErcContractsList(cursor)
+	return db.loadErcContractsList(ctx, cursor)
 }
 
-func (db *MongoDbBridge) loadErcContractsList(cursor *mongo.Cursor) ([]common.Address, error) {
+func (db *MongoDbBridge) loadErcContractsList(ctx context.Context, cursor *mongo.Cursor) ([]common.Address, error) {
 	// close the cursor as we leave
 	defer db.closeCursor(cursor)
 
 	// loop and load
 	list := make([]common.Address, 0)
 	var row AccountRow
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		// try to decode the next row
 		if err := cursor.Decode(&row); err != nil {
 			db.log.Errorf("can not decode ERC contracts list row; %s", err.Error())
@@ -328,3 +476,41 @@ func (db *MongoDbBridge) loadErcContractsList(cursor *mongo.Cursor) ([]common.Ad
 
 	return list, nil
 }
+
+// AccountTransactionsPage loads a page of transactions of the given account within the given range.
+// Unlike the cursor based list, the page is addressed by the number of transactions skipped
+// and the order can be chosen; it serves clients of offset paginated interfaces.
+func (db *MongoDbBridge) AccountTransactionsPage(ctx context.Context, addr *common.Address, rf *types.RangeFilter, skip int64, limit int64, asc bool) ([]*types.Transaction, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(coTransactions)
+	filter := withRangeFilter(bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: fiTransactionSender, Value: addr.String()}},
+		bson.D{{Key: fiTransactionRecipient, Value: addr.String()}},
+	}}}, trxRangeConditions(rf))
+
+	list := make([]*types.Transaction, 0)
+	err := db.iterate(ctx, col, filter, pageFindOptions(fiTransactionOrdinalIndex, skip, limit, asc), func(cursor *mongo.Cursor) error {
+		var trx types.Transaction
+		if err := cursor.Decode(&trx); err != nil {
+			db.log.Errorf("can not decode transaction; %s", err.Error())
+			return err
+		}
+		list = append(list, &trx)
+		return nil
+	})
+	return list, err
+}
+
+// pageFindOptions provides the find options of an offset addressed page ordered by the given field.
+func pageFindOptions(sort string, skip int64, limit int64, asc bool) *options.FindOptions {
+	dir := -1
+	if asc {
+		dir = 1
+	}
+	return options.Find().
+		SetSort(bson.D{{Key: sort, Value: dir}}).
+		SetSkip(skip).
+		SetLimit(limit)
+}
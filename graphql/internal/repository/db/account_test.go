@@ -0,0 +1,115 @@
+package db
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"math/big"
+	"testing"
+)
+
+func TestAccountTrxConditions(t *testing.T) {
+	addr := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+	status := uint64(1)
+	method := "0xA9059CBB"
+
+	tests := []struct {
+		name string
+		tf   *types.TransactionFilter
+		want bson.A
+	}{
+		{name: "nil filter", tf: nil, want: bson.A{}},
+		{name: "any direction", tf: &types.TransactionFilter{}, want: bson.A{}},
+		{
+			name: "incoming",
+			tf:   &types.TransactionFilter{Direction: types.TrxDirectionIncoming},
+			want: bson.A{bson.D{{Key: "to", Value: addr.String()}}},
+		},
+		{
+			name: "outgoing",
+			tf:   &types.TransactionFilter{Direction: types.TrxDirectionOutgoing},
+			want: bson.A{bson.D{{Key: "from", Value: addr.String()}}},
+		},
+		{
+			name: "self",
+			tf:   &types.TransactionFilter{Direction: types.TrxDirectionSelf},
+			want: bson.A{bson.D{{Key: "from", Value: addr.String()}, {Key: "to", Value: addr.String()}}},
+		},
+		{
+			name: "status and creations",
+			tf:   &types.TransactionFilter{Status: &status, CreationsOnly: true},
+			want: bson.A{bson.D{{Key: "stat", Value: status}}, bson.D{{Key: "to", Value: nil}}},
+		},
+		{
+			name: "method is lower cased",
+			tf:   &types.TransactionFilter{Method: &method},
+			want: bson.A{bson.D{{Key: "sel", Value: "0xa9059cbb"}}},
+		},
+		{
+			name: "value range in gWei",
+			tf:   &types.TransactionFilter{MinValue: big.NewInt(1_500_000_000), MaxValue: new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18))},
+			want: bson.A{
+				bson.D{{Key: "amo", Value: bson.D{{Key: "$gte", Value: int64(1)}}}},
+				bson.D{{Key: "amo", Value: bson.D{{Key: "$lte", Value: int64(3_000_000_000)}}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(accountTrxConditions(&addr, tt.tf)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestWithTokenTransactionFilter(t *testing.T) {
+	tokens := []common.Address{
+		common.HexToAddress("0x04068da6c83afcfa0e13ba15a6696662335d5b75"),
+		common.HexToAddress("0x21be370d5312f44cb42ce377bc9b8a0cef1a4c83"),
+	}
+
+	tests := []struct {
+		name string
+		tf   *types.TokenTransactionFilter
+		want bson.D
+	}{
+		{name: "nil filter", tf: nil, want: bson.D{}},
+		{
+			name: "tokens",
+			tf:   &types.TokenTransactionFilter{Tokens: tokens},
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: types.FiTokenTransactionToken, Value: bson.D{{Key: "$in", Value: bson.A{tokens[0].String(), tokens[1].String()}}}}},
+			}}},
+		},
+		{
+			name: "amount range",
+			tf:   &types.TokenTransactionFilter{MinAmount: big.NewInt(2_000_000_000), MaxAmount: big.NewInt(5_000_000_000)},
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: types.FiTokenTransactionValue, Value: bson.D{{Key: "$gte", Value: int64(2)}}}},
+				bson.D{{Key: types.FiTokenTransactionValue, Value: bson.D{{Key: "$lte", Value: int64(5)}}}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(WithTokenTransactionFilter(bson.D{}, tt.tf)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestTransactionCollectionIndexes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the index updater drops indexes missing in the list, all of them must be named
+	names := make([]string, 0)
+	for _, ix := range transactionCollectionIndexes() {
+		g.Expect(ix.Options).ToNot(gomega.BeNil())
+		g.Expect(ix.Options.Name).ToNot(gomega.BeNil())
+		names = append(names, *ix.Options.Name)
+	}
+	g.Expect(names).To(gomega.ConsistOf("orx_-1", "from_1", "to_1", "stamp_1", "from_orx", "to_orx", "sel_orx"))
+}
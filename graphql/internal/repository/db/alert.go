@@ -0,0 +1,282 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	// colAlerts represents the name of the alerts collection.
+	colAlerts = "alerts"
+
+	// colAlertRules represents the name of the alert rules collection.
+	colAlertRules = "alert_rules"
+
+	// fiAlertPk is the name of the primary key field of the alert collection.
+	fiAlertPk = "_id"
+
+	// fiAlertOrdinal is the name of the ordinal index field of the alert collection.
+	fiAlertOrdinal = "orx"
+
+	// fiAlertNextPush is the name of the next push attempt field of the alert collection.
+	// The field is set only on alerts waiting to be pushed to their sinks.
+	fiAlertNextPush = "next"
+
+	// alertsPushDueLimit represents the max number of alerts due for a push loaded at once.
+	alertsPushDueLimit = 500
+)
+
+// alertCollectionIndexes provides a list of indexes expected to exist on the alerts collection.
+func alertCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 2)
+
+	ixOrdinal := "ix_alert_orx"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: fiAlertOrdinal, Value: -1}}, Options: &options.IndexOptions{Name: &ixOrdinal}}
+
+	ixNext := "ix_alert_next"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: fiAlertNextPush, Value: 1}}, Options: options.Index().SetName(ixNext).SetSparse(true)}
+
+	return ix
+}
+
+// AddAlert inserts the given alert into the persistent database. An alert of the same ID
+// already known is left untouched so re-scanned activity does not raise alerts twice.
+// The function reports if the alert has been added.
+func (db *MongoDbBridge) AddAlert(alert *types.Alert) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colAlerts)
+
+	res, err := col.UpdateByID(ctx, alert.ID, bson.D{
+		{Key: "$setOnInsert", Value: alert},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not add alert %s; %s", alert.ID, err.Error())
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+// UpdateAlertPush updates the sinks push state of the given alert in the persistent database.
+func (db *MongoDbBridge) UpdateAlertPush(alert *types.Alert) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colAlerts)
+
+	set := bson.D{
+		{Key: "pending", Value: alert.Pending},
+		{Key: "attempts", Value: alert.PushAttempts},
+	}
+	// alerts not waiting for another attempt drop out of the next push index
+	var update bson.D
+	if alert.NextPush.IsZero() {
+		update = bson.D{
+			{Key: "$set", Value: set},
+			{Key: "$unset", Value: bson.D{{Key: fiAlertNextPush, Value: ""}}},
+		}
+	} else {
+		update = bson.D{{Key: "$set", Value: append(set, bson.E{Key: fiAlertNextPush, Value: alert.NextPush})}}
+	}
+
+	if _, err := col.UpdateByID(ctx, alert.ID, update); err != nil {
+		db.log.Errorf("can not update alert %s push; %s", alert.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// AlertsPushDue loads alerts waiting to be pushed to their sinks scheduled for an attempt by the given time.
+func (db *MongoDbBridge) AlertsPushDue(ts time.Time) ([]*types.Alert, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colAlerts)
+
+	cursor, err := col.Find(ctx,
+		bson.D{{Key: fiAlertNextPush, Value: bson.D{{Key: "$lte", Value: ts}}}},
+		options.Find().SetSort(bson.D{{Key: fiAlertNextPush, Value: 1}}).SetLimit(alertsPushDueLimit))
+	if err != nil {
+		db.log.Errorf("can not load alerts due for push; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.Alert, 0)
+	for cursor.Next(ctx) {
+		var alert types.Alert
+		if err := cursor.Decode(&alert); err != nil {
+			db.log.Errorf("can not decode alert; %s", err.Error())
+			continue
+		}
+		list = append(list, &alert)
+	}
+	return list, nil
+}
+
+// AlertRules loads all the alert rules stored in the persistent database.
+func (db *MongoDbBridge) AlertRules() ([]*types.AlertRule, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colAlertRules)
+
+	cursor, err := col.Find(ctx, bson.D{})
+	if err != nil {
+		db.log.Errorf("can not load alert rules; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.AlertRule, 0)
+	for cursor.Next(ctx) {
+		var ar types.AlertRule
+		if err := cursor.Decode(&ar); err != nil {
+			db.log.Errorf("can not decode alert rule; %s", err.Error())
+			continue
+		}
+		list = append(list, &ar)
+	}
+	return list, nil
+}
+
+// alertListTop finds the ordinal index of the first alert of the list based on the cursor and the direction.
+func (db *MongoDbBridge) alertListTop(col *mongo.Collection, cursor *string, count int32, list *types.AlertList) (err error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	var row struct {
+		Value uint64 `bson:"orx"`
+	}
+
+	// the cursor itself is the starting point; otherwise start at the top, or at the bottom
+	filter := bson.D{}
+	opt := options.FindOne().SetProjection(bson.D{{Key: fiAlertOrdinal, Value: true}})
+	if cursor != nil {
+		filter = bson.D{{Key: fiAlertPk, Value: *cursor}}
+	} else if count > 0 {
+		opt.SetSort(bson.D{{Key: fiAlertOrdinal, Value: -1}})
+	} else {
+		opt.SetSort(bson.D{{Key: fiAlertOrdinal, Value: 1}})
+	}
+
+	if err = col.FindOne(ctx, filter, opt).Decode(&row); err != nil {
+		return err
+	}
+	list.First = row.Value
+	return nil
+}
+
+// alertListFilter creates a filter for alert list loading.
+func alertListFilter(cursor *string, count int32, list *types.AlertList) bson.D {
+	op := "$lte"
+	switch {
+	case cursor == nil && count < 0:
+		op = "$gte"
+	case cursor != nil && count > 0:
+		op = "$lt"
+	case cursor != nil && count < 0:
+		op = "$gt"
+	}
+	return bson.D{{Key: fiAlertOrdinal, Value: bson.D{{Key: op, Value: list.First}}}}
+}
+
+// alertListOptions creates a filter options set for alert list loading.
+func alertListOptions(count int32) *options.FindOptions {
+	// from high (new) to low (old) by default; reversed if loading from bottom
+	sd := -1
+	limit := int64(count)
+	if count < 0 {
+		sd = 1
+		limit = -limit
+	}
+
+	// try to get one more record so we can detect list end
+	return options.Find().SetSort(bson.D{{Key: fiAlertOrdinal, Value: sd}}).SetLimit(limit + 1)
+}
+
+// alertListLoad loads the initialized list of alerts from database.
+func (db *MongoDbBridge) alertListLoad(col *mongo.Collection, cursor *string, count int32, list *types.AlertList) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	ld, err := col.Find(ctx, alertListFilter(cursor, count, list), alertListOptions(count))
+	if err != nil {
+		db.log.Errorf("error loading alerts list; %s", err.Error())
+		return err
+	}
+	defer db.closeCursor(ld)
+
+	// loop and load the list; we may not store the last value
+	var alert *types.Alert
+	for ld.Next(ctx) {
+		if alert != nil {
+			list.Collection = append(list.Collection, alert)
+		}
+
+		var row types.Alert
+		if err = ld.Decode(&row); err != nil {
+			db.log.Errorf("can not decode the alert list row; %s", err.Error())
+			return err
+		}
+		alert = &row
+	}
+
+	// we should have all the items already; we may just need to check if a boundary was reached
+	list.IsEnd = (cursor == nil && count < 0) || (count > 0 && int32(len(list.Collection)) < count)
+	list.IsStart = (cursor == nil && count > 0) || (count < 0 && int32(len(list.Collection)) < -count)
+
+	// add the last item as well if we hit the boundary
+	if ((count < 0 && list.IsStart) || (count > 0 && list.IsEnd)) && alert != nil {
+		list.Collection = append(list.Collection, alert)
+	}
+	return nil
+}
+
+// Alerts pulls list of alerts starting at the specified cursor.
+func (db *MongoDbBridge) Alerts(cursor *string, count int32) (*types.AlertList, error) {
+	// nothing to load?
+	if count == 0 {
+		return nil, fmt.Errorf("nothing to do, zero alerts requested")
+	}
+
+	col := db.client.Database(db.dbName).Collection(colAlerts)
+	total, err := db.CountFiltered(col, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := types.AlertList{
+		Collection: make([]*types.Alert, 0),
+		Total:      total,
+		IsStart:    total == 0,
+		IsEnd:      total == 0,
+	}
+	if total == 0 {
+		return &list, nil
+	}
+
+	if err := db.alertListTop(col, cursor, count, &list); err != nil {
+		db.log.Errorf("can not find the initial alert; %s", err.Error())
+		return nil, err
+	}
+
+	if err := db.alertListLoad(col, cursor, count, &list); err != nil {
+		db.log.Errorf("can not load alerts list from database; %s", err.Error())
+		return nil, err
+	}
+
+	// reverse on negative so new-er alerts will be on top
+	if count < 0 {
+		list.Reverse()
+	}
+	return &list, nil
+}
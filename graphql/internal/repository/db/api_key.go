@@ -0,0 +1,205 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// colApiKeys represents the name of the API keys collection.
+	colApiKeys = "api_keys"
+
+	// colApiUsage represents the name of the API keys daily usage collection.
+	colApiUsage = "api_usage"
+)
+
+// apiKeyCollectionIndexes provides a list of indexes expected to exist on the API keys collection.
+func apiKeyCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 1)
+
+	ixHash := "ix_key_hash"
+	unique := true
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "hash", Value: 1}}, Options: &options.IndexOptions{Name: &ixHash, Unique: &unique}}
+
+	return ix
+}
+
+// apiUsageCollectionIndexes provides a list of indexes expected to exist on the API keys usage collection.
+func apiUsageCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 1)
+
+	ixKeyDay := "ix_usage_key_day"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "key", Value: 1}, {Key: "day", Value: -1}}, Options: &options.IndexOptions{Name: &ixKeyDay}}
+
+	return ix
+}
+
+// StoreApiKey stores, or updates, the given API key in the persistent database.
+func (db *MongoDbBridge) StoreApiKey(key *types.ApiKey) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiKeys)
+
+	_, err := col.UpdateByID(ctx, key.ID, bson.D{
+		{Key: "$set", Value: key},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store api key %s; %s", key.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteApiKey removes the given API key and its usage records from the persistent database.
+func (db *MongoDbBridge) DeleteApiKey(id string) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiKeys)
+	if _, err := col.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}}); err != nil {
+		db.log.Errorf("can not delete api key %s; %s", id, err.Error())
+		return err
+	}
+
+	col = db.client.Database(db.dbName).Collection(colApiUsage)
+	if _, err := col.DeleteMany(ctx, bson.D{{Key: "key", Value: id}}); err != nil {
+		db.log.Errorf("can not delete usage of api key %s; %s", id, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ApiKey loads the API key of the given ID; nil if not found.
+func (db *MongoDbBridge) ApiKey(id string) (*types.ApiKey, error) {
+	return db.apiKey(bson.D{{Key: "_id", Value: id}})
+}
+
+// ApiKeyByHash loads the API key of the given key hash; nil if not found.
+func (db *MongoDbBridge) ApiKeyByHash(hash string) (*types.ApiKey, error) {
+	return db.apiKey(bson.D{{Key: "hash", Value: hash}})
+}
+
+// apiKey loads a single API key matching the given filter; nil if not found.
+func (db *MongoDbBridge) apiKey(filter bson.D) (*types.ApiKey, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiKeys)
+
+	var key types.ApiKey
+	if err := col.FindOne(ctx, filter).Decode(&key); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		db.log.Errorf("can not load api key; %s", err.Error())
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ApiKeys loads all the API keys, oldest first.
+func (db *MongoDbBridge) ApiKeys() ([]*types.ApiKey, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiKeys)
+
+	cursor, err := col.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created", Value: 1}}))
+	if err != nil {
+		db.log.Errorf("can not load api keys; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.ApiKey, 0)
+	for cursor.Next(ctx) {
+		var key types.ApiKey
+		if err := cursor.Decode(&key); err != nil {
+			db.log.Errorf("can not decode api key; %s", err.Error())
+			continue
+		}
+		list = append(list, &key)
+	}
+	return list, nil
+}
+
+// AddApiKeyUsage adds the given numbers of requests to the daily usage record of the API key.
+func (db *MongoDbBridge) AddApiKeyUsage(u *types.ApiKeyUsage) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiUsage)
+
+	_, err := col.UpdateByID(ctx, u.Key+"#"+u.Day, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "key", Value: u.Key}, {Key: "day", Value: u.Day}}},
+		{Key: "$inc", Value: bson.D{{Key: "req", Value: u.Requests}, {Key: "rej", Value: u.Rejected}}},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store usage of api key %s; %s", u.Key, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ConsumeApiQuota counts a request of the client against its daily quota by an atomic increment
+// of the usage record of the day; false is returned if the quota of the day is exhausted.
+func (db *MongoDbBridge) ConsumeApiQuota(key string, day string, quota int64) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiUsage)
+
+	// an exhausted record does not match the filter, the upsert of its ID is refused as a duplicate
+	_, err := col.UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: key + "#" + day},
+		{Key: "req", Value: bson.D{{Key: "$lt", Value: quota}}},
+	}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "key", Value: key}, {Key: "day", Value: day}}},
+		{Key: "$inc", Value: bson.D{{Key: "req", Value: int64(1)}}},
+	}, options.Update().SetUpsert(true))
+
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		db.log.Errorf("can not consume quota of %s; %s", key, err.Error())
+		return false, err
+	}
+	return true, nil
+}
+
+// ApiKeyUsage loads the daily usage records of the API key, the most recent first.
+func (db *MongoDbBridge) ApiKeyUsage(key string, days int64) ([]*types.ApiKeyUsage, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colApiUsage)
+
+	cursor, err := col.Find(ctx, bson.D{{Key: "key", Value: key}}, options.Find().
+		SetSort(bson.D{{Key: "day", Value: -1}}).
+		SetLimit(days))
+	if err != nil {
+		db.log.Errorf("can not load usage of api key %s; %s", key, err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.ApiKeyUsage, 0)
+	for cursor.Next(ctx) {
+		var u types.ApiKeyUsage
+		if err := cursor.Decode(&u); err != nil {
+			db.log.Errorf("can not decode api key usage; %s", err.Error())
+			continue
+		}
+		list = append(list, &u)
+	}
+	return list, nil
+}
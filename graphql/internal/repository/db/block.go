@@ -0,0 +1,91 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	// colBlocks represents the name of the processed blocks collection.
+	// The collection announces new blocks to API instances running without the indexer,
+	// the block details are loaded from the node.
+	colBlocks = "blocks"
+
+	// blockRecordTTL represents the time a processed block record is kept.
+	blockRecordTTL = 24 * time.Hour
+)
+
+// blockRow represents a processed block record.
+type blockRow struct {
+	Number int64     `bson:"_id"`
+	Hash   string    `bson:"hash"`
+	Stamp  int64     `bson:"ts"`
+	Txs    int32     `bson:"txs"`
+	Stored time.Time `bson:"stored"`
+}
+
+// blockCollectionIndexes provides a list of indexes expected to exist on the processed blocks collection.
+func blockCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 1)
+
+	ixStored := "ix_blocks_stored"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "stored", Value: 1}}, Options: options.Index().SetName(ixStored).SetExpireAfterSeconds(int32(blockRecordTTL.Seconds()))}
+
+	return ix
+}
+
+// AddBlock records the given block as processed by the indexer.
+// Blocks already recorded are left untouched, so the block is announced only once.
+func (db *MongoDbBridge) AddBlock(blk *types.Block) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colBlocks)
+
+	row := blockRow{
+		Number: int64(blk.Number),
+		Hash:   blk.Hash.String(),
+		Stamp:  int64(blk.TimeStamp),
+		Txs:    int32(len(blk.Txs)),
+		Stored: time.Now().UTC(),
+	}
+
+	res, err := col.UpdateOne(ctx, bson.D{{Key: "_id", Value: row.Number}}, bson.D{
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "hash", Value: row.Hash},
+			{Key: "ts", Value: row.Stamp},
+			{Key: "txs", Value: row.Txs},
+			{Key: "stored", Value: row.Stored},
+		}},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		db.log.Errorf("can not add block #%d; %s", row.Number, err.Error())
+		return err
+	}
+
+	if res.UpsertedCount > 0 {
+		db.pushStoreEvent(colBlocks, row)
+	}
+	return nil
+}
+
+// decodeBlockRow decodes the processed block record into a basic block.
+func decodeBlockRow(doc bson.Raw) (*types.Block, error) {
+	var row blockRow
+	if err := bson.Unmarshal(doc, &row); err != nil {
+		return nil, err
+	}
+
+	return &types.Block{
+		Number:    hexutil.Uint64(row.Number),
+		Hash:      common.HexToHash(row.Hash),
+		TimeStamp: hexutil.Uint64(row.Stamp),
+	}, nil
+}
@@ -22,6 +22,12 @@ type MongoDbBridge struct {
 	log    logger.Logger
 	dbName string
 
+	// timeout represents the deadline of a single database operation
+	timeout time.Duration
+
+	// changeStreams marks the database deployment supports change streams
+	changeStreams bool
+
 	// sync DB related processes
 	wg  sync.WaitGroup
 	sig []chan bool
@@ -66,9 +72,16 @@ func New(cfg *config.Config, log logger.Logger) (*MongoDbBridge, error) {
 
 	// return the bridge
 	db := &MongoDbBridge{
-		client: con,
-		log:    log,
-		dbName: cfg.Db.DbName,
+		client:  con,
+		log:     log,
+		dbName:  cfg.Db.DbName,
+		timeout: cfg.Db.Timeout,
+	}
+
+	// store events are collected only if change streams are not available
+	db.changeStreams = db.supportsChangeStreams()
+	if !db.changeStreams {
+		db.initStoreEvents(cfg.Db.EventsSize << 20)
 	}
 
 	// check the state
@@ -118,8 +131,8 @@ func (db *MongoDbBridge) Close() {
 
 // getAggregateValue extract single aggregate value for a given collection and aggregation pipeline.
 func (db *MongoDbBridge) getAggregateValue(col *mongo.Collection, pipeline *bson.A) (uint64, error) {
-	// work with context
-	ctx := context.Background()
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
 
 	// use aggregate pipeline to get the result set, should be just one row
 	res, err := col.Aggregate(ctx, *pipeline)
@@ -203,13 +216,16 @@ func (db *MongoDbBridge) collectionNeedInit(name string, counter func() (uint64,
 
 // CountFiltered calculates total number of documents in the given collection for the given filter.
 func (db *MongoDbBridge) CountFiltered(col *mongo.Collection, filter *bson.D) (uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// make sure some filter is used
 	if nil == filter {
 		filter = &bson.D{}
 	}
 
 	// do the counting
-	val, err := col.CountDocuments(context.Background(), *filter, options.Count().SetMaxTime(5*time.Second))
+	val, err := col.CountDocuments(ctx, *filter, options.Count().SetMaxTime(5*time.Second))
 	if err != nil {
 		db.log.Errorf("can not count documents in rewards collection; %s", err.Error())
 		return db.EstimateCount(col)
@@ -219,8 +235,11 @@ func (db *MongoDbBridge) CountFiltered(col *mongo.Collection, filter *bson.D) (u
 
 // EstimateCount calculates an estimated number of documents in the given collection.
 func (db *MongoDbBridge) EstimateCount(col *mongo.Collection) (uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do the counting
-	val, err := col.EstimatedDocumentCount(context.Background())
+	val, err := col.EstimatedDocumentCount(ctx)
 	if err != nil {
 		db.log.Errorf("can not count documents in rewards collection; %s", err.Error())
 		return 0, err
@@ -230,9 +249,9 @@ func (db *MongoDbBridge) EstimateCount(col *mongo.Collection) (uint64, error) {
 
 // listDocumentsCount tries to calculate precise documents count and if it's not counted in limited
 // time, use general estimation to speed up the loader.
-func (db *MongoDbBridge) listDocumentsCount(col *mongo.Collection, filter *bson.D) (int64, error) {
+func (db *MongoDbBridge) listDocumentsCount(ctx context.Context, col *mongo.Collection, filter *bson.D) (int64, error) {
 	// try to count the proper way
-	total, err := col.CountDocuments(context.Background(), filter, options.Count().SetMaxTime(docListCountAggregationTimeout))
+	total, err := col.CountDocuments(ctx, filter, options.Count().SetMaxTime(docListCountAggregationTimeout))
 	if err == nil {
 		return total, nil
 	}
@@ -241,7 +260,7 @@ func (db *MongoDbBridge) listDocumentsCount(col *mongo.Collection, filter *bson.
 	db.log.Errorf("can not count documents properly; %s", err.Error())
 
 	// just estimate the whole collection size
-	total, err = col.EstimatedDocumentCount(context.Background())
+	total, err = col.EstimatedDocumentCount(ctx)
 	if err != nil {
 		db.log.Errorf("can not count documents")
 		return 0, err
@@ -249,6 +268,11 @@ func (db *MongoDbBridge) listDocumentsCount(col *mongo.Collection, filter *bson.
 	return total, nil
 }
 
+// opContext derives the context of a single database operation limited by the configured deadline.
+func (db *MongoDbBridge) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, db.timeout)
+}
+
 // closeCursor closes the given query cursor and reports possible issue if it fails.
 func (db *MongoDbBridge) closeCursor(c *mongo.Cursor) {
 	if err := c.Close(context.Background()); err != nil {
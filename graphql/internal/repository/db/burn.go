@@ -66,10 +66,13 @@ func (db *MongoDbBridge) initBurnsCollection(col *mongo.Collection) {
 
 // BurnByBlock pulls a burn information for the given block number, if available.
 func (db *MongoDbBridge) BurnByBlock(bn hexutil.Uint64) (*types.FtmBurn, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurns)
 
 	// try to find existing burn
-	sr := col.FindOne(context.Background(), bson.D{{Key: "block", Value: bn}})
+	sr := col.FindOne(ctx, bson.D{{Key: "block", Value: bn}})
 	if sr.Err() != nil {
 		// if the burn has not been found, add this as a new one
 		if sr.Err() == mongo.ErrNoDocuments {
@@ -92,6 +95,9 @@ func (db *MongoDbBridge) BurnByBlock(bn hexutil.Uint64) (*types.FtmBurn, error)
 
 // StoreBurn stores the given native FTM burn record.
 func (db *MongoDbBridge) StoreBurn(burn *types.FtmBurn) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	if burn == nil {
 		return nil
 	}
@@ -105,7 +111,7 @@ func (db *MongoDbBridge) StoreBurn(burn *types.FtmBurn) error {
 
 	// insert/update the burn data
 	re, err := col.UpdateOne(
-		context.Background(),
+		ctx,
 		bson.D{{Key: "block", Value: burn.BlockNumber}},
 		bson.D{{Key: "$set", Value: burn}},
 		options.Update().SetUpsert(true))
@@ -122,9 +128,12 @@ func (db *MongoDbBridge) StoreBurn(burn *types.FtmBurn) error {
 
 // burnAddBurnValue adds the given value to the total burned amount.
 func (db *MongoDbBridge) burnAddBurnValue(v int64) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurnsAggregate)
 
-	_, err := col.UpdateByID(context.Background(), burnBaseAggregateDate, bson.D{
+	_, err := col.UpdateByID(ctx, burnBaseAggregateDate, bson.D{
 		{Key: "$inc", Value: bson.D{{Key: "amount", Value: v}}},
 	})
 	if err != nil {
@@ -134,8 +143,11 @@ func (db *MongoDbBridge) burnAddBurnValue(v int64) {
 
 // burnAddTotalAggregate creates a container for the total aggregate.
 func (db *MongoDbBridge) burnAddTotalAggregate() error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurnsAggregate)
-	_, err := col.UpdateByID(context.Background(), burnBaseAggregateDate, bson.D{
+	_, err := col.UpdateByID(ctx, burnBaseAggregateDate, bson.D{
 		{Key: "$setOnInsert", Value: bson.D{
 			{Key: "_id", Value: burnBaseAggregateDate},
 			{Key: "amount", Value: int64(0)},
@@ -151,9 +163,12 @@ func (db *MongoDbBridge) BurnCount() (uint64, error) {
 
 // BurnTotal aggregates the total amount of burned fee across all blocks.
 func (db *MongoDbBridge) BurnTotal() (int64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurnsAggregate)
 
-	sr := col.FindOne(context.Background(), bson.D{{Key: "_id", Value: burnBaseAggregateDate}})
+	sr := col.FindOne(ctx, bson.D{{Key: "_id", Value: burnBaseAggregateDate}})
 	if sr.Err() != nil {
 		db.log.Criticalf("could not get burned total; %s", sr.Err().Error())
 		if sr.Err() == mongo.ErrNoDocuments {
@@ -174,10 +189,13 @@ func (db *MongoDbBridge) BurnTotal() (int64, error) {
 
 // BurnTotalSlow aggregates the total amount of burned fee across all blocks.
 func (db *MongoDbBridge) BurnTotalSlow() (int64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurns)
 
 	// aggregate the total amount of burned native tokens
-	cr, err := col.Aggregate(context.Background(), mongo.Pipeline{
+	cr, err := col.Aggregate(ctx, mongo.Pipeline{
 		{{Key: "$group", Value: bson.D{
 			{Key: "_id", Value: nil},
 			{Key: "amount", Value: bson.D{{Key: "$sum", Value: "$amount"}}},
@@ -189,7 +207,7 @@ func (db *MongoDbBridge) BurnTotalSlow() (int64, error) {
 	}
 
 	defer db.closeCursor(cr)
-	if !cr.Next(context.Background()) {
+	if !cr.Next(ctx) {
 		return 0, fmt.Errorf("burned fee aggregation failed")
 	}
 
@@ -205,16 +223,18 @@ func (db *MongoDbBridge) BurnTotalSlow() (int64, error) {
 
 // BurnList provides list of native FTM burns per blocks stored in the persistent database.
 func (db *MongoDbBridge) BurnList(count int64) ([]types.FtmBurn, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colBurns)
 
-	cr, err := col.Find(context.Background(), bson.D{}, options.Find().SetSort(bson.D{{Key: "block", Value: -1}}).SetLimit(count))
+	cr, err := col.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "block", Value: -1}}).SetLimit(count))
 	if err != nil {
 		db.log.Errorf("failed to load burns; %s", err.Error())
 		return nil, err
 	}
 	defer db.closeCursor(cr)
 
-	ctx := context.Background()
 	list := make([]types.FtmBurn, 0, count)
 
 	for cr.Next(ctx) {
@@ -32,6 +32,9 @@ type ConfigRow struct {
 
 // UpdateLastKnownBlock stores the last known block into the config collection.
 func (db *MongoDbBridge) UpdateLastKnownBlock(blockNo *hexutil.Uint64) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do we have all needed data?
 	if blockNo == nil {
 		return fmt.Errorf("can not add empty block")
@@ -41,7 +44,7 @@ func (db *MongoDbBridge) UpdateLastKnownBlock(blockNo *hexutil.Uint64) error {
 	col := db.client.Database(db.dbName).Collection(coConfiguration)
 
 	// insert/update
-	_, err := col.UpdateByID(context.Background(), keyConfigLastKnownBlock, bson.D{{Key: "$set", Value: bson.D{
+	_, err := col.UpdateByID(ctx, keyConfigLastKnownBlock, bson.D{{Key: "$set", Value: bson.D{
 		{Key: fiConfigPk, Value: keyConfigLastKnownBlock},
 		{Key: fiConfigValue, Value: blockNo.String()},
 	}}}, new(options.UpdateOptions).SetUpsert(true))
@@ -53,11 +56,14 @@ func (db *MongoDbBridge) UpdateLastKnownBlock(blockNo *hexutil.Uint64) error {
 
 // LastKnownBlock returns the last known block from the database.
 func (db *MongoDbBridge) LastKnownBlock() (uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// get the collection for cfg
 	col := db.client.Database(db.dbName).Collection(coConfiguration)
 
 	// get the last known block from the config collection
-	res := col.FindOne(context.Background(), bson.D{{Key: fiConfigPk, Value: keyConfigLastKnownBlock}})
+	res := col.FindOne(ctx, bson.D{{Key: fiConfigPk, Value: keyConfigLastKnownBlock}})
 	if res.Err() == nil {
 		// get the data
 		var row ConfigRow
@@ -81,6 +87,9 @@ func (db *MongoDbBridge) LastKnownBlock() (uint64, error) {
 
 // lastKnownBlock returns number of the last known block stored in the transactions table.
 func (db *MongoDbBridge) lastKnownBlock() (uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// prep search options
 	opt := options.FindOne()
 	opt.SetSort(bson.D{{Key: fiTransactionBlock, Value: -1}})
@@ -88,7 +97,7 @@ func (db *MongoDbBridge) lastKnownBlock() (uint64, error) {
 
 	// get the collection for account transactions
 	col := db.client.Database(db.dbName).Collection(coTransactions)
-	res := col.FindOne(context.Background(), bson.D{}, opt)
+	res := col.FindOne(ctx, bson.D{}, opt)
 	if res.Err() != nil {
 		// may be no block at all
 		if res.Err() == mongo.ErrNoDocuments {
@@ -26,25 +26,58 @@ const (
 	// fiContractSourceValidated is the name of the contract source code
 	// validation timestamp field.
 	fiContractSourceValidated = "val"
+
+	// fiContractName is the name of the contract name field.
+	// db.contract.createIndex({name:"text",sym:"text"},{default_language:"none"})
+	fiContractName = "name"
+
+	// fiContractSymbol is the name of the token contract symbol field.
+	fiContractSymbol = "sym"
+
+	// fiContractKeywords is the name of the contract search keywords field.
+	// db.contract.createIndex({kw:1})
+	fiContractKeywords = "kw"
 )
 
-// initContractsCollection initializes the contracts collection with
-// indexes and additional parameters needed by the app.
-func (db *MongoDbBridge) initContractsCollection(col *mongo.Collection) {
-	// prepare index models
-	ix := make([]mongo.IndexModel, 0)
+// contractCollectionIndexes provides a list of indexes expected to exist on the contracts' collection.
+// The name of the index created before the indexes were named explicitly follows the default naming of the database.
+func contractCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 3)
 
 	// index ordinal key along with the primary key
 	unique := true
-	ix = append(ix, mongo.IndexModel{
-		Keys: bson.D{{Key: fiContractPk, Value: 1}, {Key: fiContractOrdinalIndex, Value: -1}},
+	ixOrdinal := "_id_1_orx_-1"
+	ix[0] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiContractPk, Value: 1}, {Key: fiContractOrdinalIndex, Value: -1}},
+		Options: &options.IndexOptions{Name: &ixOrdinal, Unique: &unique},
+	}
+
+	// full text index of names and symbols, no language stemming is applied to them
+	tix := "search_text"
+	lang := "none"
+	ix[1] = mongo.IndexModel{
+		Keys: bson.D{{Key: fiContractName, Value: "text"}, {Key: fiContractSymbol, Value: "text"}},
 		Options: &options.IndexOptions{
-			Unique: &unique,
+			Name:            &tix,
+			DefaultLanguage: &lang,
 		},
-	})
+	}
+
+	// search keywords for prefix matching
+	kix := "search_kw"
+	ix[2] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiContractKeywords, Value: 1}},
+		Options: &options.IndexOptions{Name: &kix},
+	}
+
+	return ix
+}
 
+// initContractsCollection initializes the contracts collection with
+// indexes and additional parameters needed by the app.
+func (db *MongoDbBridge) initContractsCollection(col *mongo.Collection) {
 	// create indexes
-	if _, err := col.Indexes().CreateMany(context.Background(), ix); err != nil {
+	if _, err := col.Indexes().CreateMany(context.Background(), contractCollectionIndexes()); err != nil {
 		db.log.Panicf("can not create indexes for contracts collection; %s", err.Error())
 	}
 
@@ -52,8 +85,35 @@ func (db *MongoDbBridge) initContractsCollection(col *mongo.Collection) {
 	db.log.Debugf("contracts collection initialized")
 }
 
+// MigrateContractKeywords sets the symbol and the search keywords of contracts stored before they were kept.
+// The given function may fill in the name and the symbol of the contract before the keywords are derived;
+// multi-token contracts stored without the symbol are revisited, too.
+func (db *MongoDbBridge) MigrateContractKeywords(describe func(*types.Contract)) error {
+	col := db.client.Database(db.dbName).Collection(coContract)
+
+	return db.migrateDocuments(col, bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: fiContractKeywords, Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "type", Value: types.AccountTypeERC1155Contract}, {Key: fiContractSymbol, Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}}},
+	}}}, func(cursor *mongo.Cursor) (bson.D, error) {
+		var sc types.Contract
+		if err := cursor.Decode(&sc); err != nil {
+			return nil, err
+		}
+		describe(&sc)
+
+		return bson.D{
+			{Key: fiContractName, Value: sc.Name},
+			{Key: fiContractSymbol, Value: sc.Symbol},
+			{Key: fiContractKeywords, Value: types.ContractKeywords(sc.Name, sc.Symbol)},
+		}, nil
+	})
+}
+
 // AddContract stores a smart contract reference in connected persistent storage.
 func (db *MongoDbBridge) AddContract(sc *types.Contract) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do we have all needed data?
 	if sc == nil {
 		return fmt.Errorf("can not add empty contract")
@@ -76,7 +136,7 @@ func (db *MongoDbBridge) AddContract(sc *types.Contract) error {
 	}
 
 	// try to do the insert
-	if _, err = col.InsertOne(context.Background(), sc); err != nil {
+	if _, err = col.InsertOne(ctx, sc); err != nil {
 		db.log.Critical(err)
 		return err
 	}
@@ -93,6 +153,9 @@ func (db *MongoDbBridge) AddContract(sc *types.Contract) error {
 // UpdateContract updates smart contract information in database to reflect
 // new validation or similar changes passed from repository.
 func (db *MongoDbBridge) UpdateContract(sc *types.Contract) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// complain about missing contract data
 	if sc == nil {
 		db.log.Criticalf("can not update empty contract")
@@ -103,7 +166,7 @@ func (db *MongoDbBridge) UpdateContract(sc *types.Contract) error {
 	col := db.client.Database(db.dbName).Collection(coContract)
 
 	// update the contract details
-	if _, err := col.UpdateOne(context.Background(),
+	if _, err := col.UpdateOne(ctx,
 		bson.D{{Key: fiContractPk, Value: sc.Address.String()}},
 		bson.D{{Key: "$set", Value: sc}}); err != nil {
 		// log the issue
@@ -127,8 +190,11 @@ func (db *MongoDbBridge) IsContractKnown(addr *common.Address) bool {
 
 // isContractKnown checks if a smart contract document already exists in the database.
 func (db *MongoDbBridge) isContractKnown(col *mongo.Collection, addr *common.Address) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// try to find the contract in the database (it may already exist)
-	sr := col.FindOne(context.Background(), bson.D{
+	sr := col.FindOne(ctx, bson.D{
 		{Key: fiContractPk, Value: addr.String()},
 	}, options.FindOne().SetProjection(bson.D{
 		{Key: fiContractPk, Value: true},
@@ -150,9 +216,9 @@ func (db *MongoDbBridge) isContractKnown(col *mongo.Collection, addr *common.Add
 }
 
 // ContractTransaction returns contract creation transaction hash if available.
-func (db *MongoDbBridge) ContractTransaction(addr *common.Address) (*common.Hash, error) {
+func (db *MongoDbBridge) ContractTransaction(ctx context.Context, addr *common.Address) (*common.Hash, error) {
 	// get the contract details from database
-	c, err := db.Contract(addr)
+	c, err := db.Contract(ctx, addr)
 	if err != nil {
 		db.log.Errorf("can not get the contract transaction for [%s]; %s", addr.String(), err.Error())
 		return nil, err
@@ -169,12 +235,15 @@ func (db *MongoDbBridge) ContractTransaction(addr *common.Address) (*common.Hash
 
 // Contract returns details of a smart contract stored in the Mongo database
 // if available, or nil if contract does not exist.
-func (db *MongoDbBridge) Contract(addr *common.Address) (*types.Contract, error) {
+func (db *MongoDbBridge) Contract(ctx context.Context, addr *common.Address) (*types.Contract, error) {
 	// get the collection for transactions
 	col := db.client.Database(db.dbName).Collection(coContract)
 
 	// try to find the contract in the database (it may already exist)
-	sr := col.FindOne(context.Background(), bson.D{{Key: fiContractPk, Value: addr.String()}})
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	sr := col.FindOne(ctx, bson.D{{Key: fiContractPk, Value: addr.String()}})
 
 	// error on lookup?
 	if sr.Err() != nil {
@@ -201,13 +270,45 @@ func (db *MongoDbBridge) Contract(addr *common.Address) (*types.Contract, error)
 	return &con, nil
 }
 
+// ContractsByAddress loads the smart contracts of the given addresses from the database.
+// Contracts not known to the database are not included in the result.
+func (db *MongoDbBridge) ContractsByAddress(ctx context.Context, addr []common.Address) (map[common.Address]*types.Contract, error) {
+	col := db.client.Database(db.dbName).Collection(coContract)
+
+	ids := make(bson.A, len(addr))
+	for i, a := range addr {
+		ids[i] = a.String()
+	}
+
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	cursor, err := col.Find(ctx, bson.D{{Key: fiContractPk, Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		db.log.Errorf("can not load contracts; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	res := make(map[common.Address]*types.Contract, len(addr))
+	for cursor.Next(ctx) {
+		var con types.Contract
+		if err := cursor.Decode(&con); err != nil {
+			db.log.Errorf("can not decode contract; %s", err.Error())
+			return nil, err
+		}
+		res[con.Address] = &con
+	}
+	return res, nil
+}
+
 // ContractCount calculates total number of contracts in the database.
 func (db *MongoDbBridge) ContractCount() (uint64, error) {
 	return db.EstimateCount(db.client.Database(db.dbName).Collection(coContract))
 }
 
 // contractListTotal find the total amount of contracts for the criteria and populates the list
-func (db *MongoDbBridge) contractListTotal(col *mongo.Collection, validatedOnly bool, list *types.ContractList) error {
+func (db *MongoDbBridge) contractListTotal(ctx context.Context, col *mongo.Collection, validatedOnly bool, list *types.ContractList) error {
 	// validation filter
 	filter := bson.D{}
 	if validatedOnly {
@@ -215,7 +316,7 @@ func (db *MongoDbBridge) contractListTotal(col *mongo.Collection, validatedOnly
 	}
 
 	// find how many contracts do we have in the database
-	total, err := col.CountDocuments(context.Background(), filter)
+	total, err := col.CountDocuments(ctx, filter)
 	if err != nil {
 		db.log.Errorf("can not count contracts")
 		return err
@@ -260,7 +361,7 @@ func contractListTopFilter(validatedOnly bool, cursor *string) (*bson.D, error)
 }
 
 // contractListTop find the first contract of the list based on provided criteria and populates the list.
-func (db *MongoDbBridge) contractListTop(col *mongo.Collection, validatedOnly bool, cursor *string, count int32, list *types.ContractList) error {
+func (db *MongoDbBridge) contractListTop(ctx context.Context, col *mongo.Collection, validatedOnly bool, cursor *string, count int32, list *types.ContractList) error {
 	// get the filter
 	filter, err := contractListTopFilter(validatedOnly, cursor)
 	if err != nil {
@@ -271,21 +372,21 @@ func (db *MongoDbBridge) contractListTop(col *mongo.Collection, validatedOnly bo
 	// find out the cursor ordinal index
 	if cursor == nil && count > 0 {
 		// get the highest available ordinal index (top smart contract)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			*filter,
 			options.FindOne().SetSort(bson.D{{Key: fiContractOrdinalIndex, Value: -1}}))
 		list.IsStart = true
 
 	} else if cursor == nil && count < 0 {
 		// get the lowest available ordinal index (bottom smart contract)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			*filter,
 			options.FindOne().SetSort(bson.D{{Key: fiContractOrdinalIndex, Value: 1}}))
 		list.IsEnd = true
 
 	} else if cursor != nil {
 		// get the highest available ordinal index (top smart contract)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			*filter,
 			options.FindOne())
 	}
@@ -299,7 +400,7 @@ func (db *MongoDbBridge) contractListTop(col *mongo.Collection, validatedOnly bo
 }
 
 // contractListInit initializes list of contracts based on provided cursor and count.
-func (db *MongoDbBridge) contractListInit(col *mongo.Collection, validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
+func (db *MongoDbBridge) contractListInit(ctx context.Context, col *mongo.Collection, validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
 	// make the list
 	list := types.ContractList{
 		Collection: make([]*types.Contract, 0),
@@ -311,7 +412,7 @@ func (db *MongoDbBridge) contractListInit(col *mongo.Collection, validatedOnly b
 	}
 
 	// calculate the total number of contracts in the list
-	if err := db.contractListTotal(col, validatedOnly, &list); err != nil {
+	if err := db.contractListTotal(ctx, col, validatedOnly, &list); err != nil {
 		return nil, err
 	}
 
@@ -319,7 +420,7 @@ func (db *MongoDbBridge) contractListInit(col *mongo.Collection, validatedOnly b
 	db.log.Debugf("found %d contracts in off-chain database", list.Total)
 
 	// find the top contract of the list
-	if err := db.contractListTop(col, validatedOnly, cursor, count, &list); err != nil {
+	if err := db.contractListTop(ctx, col, validatedOnly, cursor, count, &list); err != nil {
 		return nil, err
 	}
 
@@ -393,10 +494,7 @@ func (db *MongoDbBridge) contractListOptions(count int32) *options.FindOptions {
 }
 
 // contractListLoad loads the initialized contract list from persistent database.
-func (db *MongoDbBridge) contractListLoad(col *mongo.Collection, validatedOnly bool, cursor *string, count int32, list *types.ContractList) error {
-	// get the context for loader
-	ctx := context.Background()
-
+func (db *MongoDbBridge) contractListLoad(ctx context.Context, col *mongo.Collection, validatedOnly bool, cursor *string, count int32, list *types.ContractList) error {
 	// load the data
 	ld, err := col.Find(ctx, db.contractListFilter(validatedOnly, cursor, count, list), db.contractListOptions(count))
 	if err != nil {
@@ -442,7 +540,7 @@ func (db *MongoDbBridge) contractListLoad(col *mongo.Collection, validatedOnly b
 }
 
 // Contracts provides list of smart contracts stored in the persistent storage.
-func (db *MongoDbBridge) Contracts(validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
+func (db *MongoDbBridge) Contracts(ctx context.Context, validatedOnly bool, cursor *string, count int32) (*types.ContractList, error) {
 	// nothing to load?
 	if count == 0 {
 		return nil, fmt.Errorf("nothing to do, zero contracts requested")
@@ -450,16 +548,18 @@ func (db *MongoDbBridge) Contracts(validatedOnly bool, cursor *string, count int
 
 	// get the collection and context
 	col := db.client.Database(db.dbName).Collection(coContract)
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
 
 	// init the list
-	list, err := db.contractListInit(col, validatedOnly, cursor, count)
+	list, err := db.contractListInit(ctx, col, validatedOnly, cursor, count)
 	if err != nil {
 		db.log.Errorf("can not build contract list; %s", err.Error())
 		return nil, err
 	}
 
 	// load data
-	err = db.contractListLoad(col, validatedOnly, cursor, count, list)
+	err = db.contractListLoad(ctx, col, validatedOnly, cursor, count, list)
 	if err != nil {
 		db.log.Errorf("can not load contracts list from database; %s", err.Error())
 		return nil, err
@@ -0,0 +1,93 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// colContractSync represents the name of the contract validation sync collection
+const colContractSync = "contract_sync"
+
+// contractSyncListLimit represents the max number of contract sync records loaded at once.
+const contractSyncListLimit = 100
+
+// contractSyncCollectionIndexes provides a list of indexes expected to exist on the contract sync collection.
+func contractSyncCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 2)
+
+	ixSyncDue := "ix_sync_status_next"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next", Value: 1}}, Options: &options.IndexOptions{Name: &ixSyncDue}}
+
+	ixSyncContract := "ix_sync_addr"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: "addr", Value: 1}}, Options: &options.IndexOptions{Name: &ixSyncContract}}
+
+	return ix
+}
+
+// StoreContractSync stores, or updates, the given contract sync record in the persistent database.
+func (db *MongoDbBridge) StoreContractSync(cs *types.ContractSync) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colContractSync)
+
+	_, err := col.UpdateByID(ctx, cs.ID, bson.D{
+		{Key: "$set", Value: cs},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store contract sync %s; %s", cs.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ContractSyncsDue loads pending contract sync records scheduled for a retry.
+func (db *MongoDbBridge) ContractSyncsDue() ([]*types.ContractSync, error) {
+	return db.contractSyncs(bson.D{
+		{Key: "status", Value: types.ContractSyncStatusPending},
+		{Key: "next", Value: bson.D{{Key: "$lte", Value: time.Now().UTC()}}},
+	})
+}
+
+// ContractSyncs loads contract sync records of the given contract. If the contract is not specified,
+// the records not synced yet are loaded.
+func (db *MongoDbBridge) ContractSyncs(addr *string) ([]*types.ContractSync, error) {
+	if addr != nil {
+		return db.contractSyncs(bson.D{{Key: "addr", Value: *addr}})
+	}
+	return db.contractSyncs(bson.D{{Key: "status", Value: bson.D{{Key: "$ne", Value: types.ContractSyncStatusSynced}}}})
+}
+
+// contractSyncs loads contract sync records matching the given filter.
+func (db *MongoDbBridge) contractSyncs(filter bson.D) ([]*types.ContractSync, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colContractSync)
+
+	cursor, err := col.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "next", Value: 1}}).
+		SetLimit(contractSyncListLimit))
+	if err != nil {
+		db.log.Errorf("can not load contract syncs; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.ContractSync, 0)
+	for cursor.Next(ctx) {
+		var cs types.ContractSync
+		if err := cursor.Decode(&cs); err != nil {
+			db.log.Errorf("can not decode contract sync; %s", err.Error())
+			continue
+		}
+		list = append(list, &cs)
+	}
+	return list, nil
+}
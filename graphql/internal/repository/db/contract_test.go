@@ -0,0 +1,19 @@
+package db
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestContractCollectionIndexes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the index updater drops indexes missing in the list, all of them must be named
+	names := make([]string, 0)
+	for _, ix := range contractCollectionIndexes() {
+		g.Expect(ix.Options).ToNot(gomega.BeNil())
+		g.Expect(ix.Options.Name).ToNot(gomega.BeNil())
+		names = append(names, *ix.Options.Name)
+	}
+	g.Expect(names).To(gomega.ConsistOf("_id_1_orx_-1", "search_text", "search_kw"))
+}
@@ -0,0 +1,106 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// colContractVerify represents the name of the contract verification jobs collection
+const colContractVerify = "contract_verify"
+
+// contractVerificationStallTimeout represents the time after which a job being processed
+// is considered abandoned and can be claimed again.
+const contractVerificationStallTimeout = 15 * time.Minute
+
+// contractVerificationCollectionIndexes provides a list of indexes expected to exist on the contract verification collection.
+func contractVerificationCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 2)
+
+	ixVerifyQueue := "ix_verify_status_sub"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "sub", Value: 1}}, Options: &options.IndexOptions{Name: &ixVerifyQueue}}
+
+	ixVerifyContract := "ix_verify_addr"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: "addr", Value: 1}}, Options: &options.IndexOptions{Name: &ixVerifyContract}}
+
+	return ix
+}
+
+// StoreContractVerification stores, or updates, the given contract verification job in the persistent database.
+func (db *MongoDbBridge) StoreContractVerification(cv *types.ContractVerification) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colContractVerify)
+
+	_, err := col.UpdateByID(ctx, cv.GUID, bson.D{
+		{Key: "$set", Value: cv},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store contract verification %s; %s", cv.GUID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ContractVerification loads the contract verification job of the given GUID; nil if not found.
+func (db *MongoDbBridge) ContractVerification(guid string) (*types.ContractVerification, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colContractVerify)
+
+	var cv types.ContractVerification
+	err := col.FindOne(ctx, bson.D{{Key: "_id", Value: guid}}).Decode(&cv)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		db.log.Errorf("can not load contract verification %s; %s", guid, err.Error())
+		return nil, err
+	}
+	return &cv, nil
+}
+
+// NextContractVerification claims the oldest contract verification job waiting in the queue
+// and marks it as being processed. Jobs stalled in processing are claimed again.
+// Nil is returned if the queue is empty.
+func (db *MongoDbBridge) NextContractVerification() (*types.ContractVerification, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colContractVerify)
+	now := time.Now().UTC()
+
+	var cv types.ContractVerification
+	err := col.FindOneAndUpdate(ctx, bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "status", Value: types.ContractVerificationStatusPending}},
+			bson.D{
+				{Key: "status", Value: types.ContractVerificationStatusProcessing},
+				{Key: "start", Value: bson.D{{Key: "$lt", Value: now.Add(-contractVerificationStallTimeout)}}},
+			},
+		}},
+	}, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: types.ContractVerificationStatusProcessing},
+			{Key: "start", Value: now},
+		}},
+	}, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "sub", Value: 1}}).
+		SetReturnDocument(options.After)).Decode(&cv)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		db.log.Errorf("can not claim contract verification; %s", err.Error())
+		return nil, err
+	}
+	return &cv, nil
+}
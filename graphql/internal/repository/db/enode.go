@@ -40,10 +40,13 @@ func operaNodeCollectionIndexes() []mongo.IndexModel {
 
 // NetworkNode gets a stored record of Opera network node by its network identifier.
 func (db *MongoDbBridge) NetworkNode(nid enode.ID) (*types.OperaNode, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	// try to find the delegation in the database
-	sr := col.FindOne(context.Background(), bson.D{
+	sr := col.FindOne(ctx, bson.D{
 		{Key: "_id", Value: nid},
 	})
 
@@ -66,17 +69,23 @@ func (db *MongoDbBridge) NetworkNode(nid enode.ID) (*types.OperaNode, error) {
 
 // NetworkNodeEvict removes record of a lost Opera network node from the database by its network identifier.
 func (db *MongoDbBridge) NetworkNodeEvict(nid enode.ID) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
-	_, err := col.DeleteOne(context.Background(), bson.D{{Key: "_id", Value: nid}})
+	_, err := col.DeleteOne(ctx, bson.D{{Key: "_id", Value: nid}})
 	return err
 }
 
 // StoreNetworkNode stores the given Opera node record in the persistent database.
 func (db *MongoDbBridge) StoreNetworkNode(node *types.OperaNode) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	// update or insert the node into the database
-	nu, err := col.UpdateByID(context.Background(), node.Node.ID(), bson.D{
+	nu, err := col.UpdateByID(ctx, node.Node.ID(), bson.D{
 		{Key: "$set", Value: node},
 	}, options.Update().SetUpsert(true))
 
@@ -93,10 +102,13 @@ func (db *MongoDbBridge) StoreNetworkNode(node *types.OperaNode) error {
 
 // IsNetworkNodeKnown checks if the given network node is already registered in the persistent database.
 func (db *MongoDbBridge) IsNetworkNodeKnown(id enode.ID) bool {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	sr := col.FindOne(
-		context.Background(),
+		ctx,
 		bson.D{{Key: "_id", Value: id}},
 		options.FindOne().SetProjection(bson.D{{Key: "_id", Value: true}}),
 	)
@@ -109,6 +121,9 @@ func (db *MongoDbBridge) IsNetworkNodeKnown(id enode.ID) bool {
 
 // NetworkNodeConfirmCheck confirms successful check of the given Opera network node.
 func (db *MongoDbBridge) NetworkNodeConfirmCheck(id enode.ID, inf *types.OperaNodeInformation) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	// prep update set
@@ -124,7 +139,7 @@ func (db *MongoDbBridge) NetworkNodeConfirmCheck(id enode.ID, inf *types.OperaNo
 		set = append(set, bson.E{Key: "info", Value: inf})
 	}
 
-	ur, err := col.UpdateByID(context.Background(), id, bson.D{
+	ur, err := col.UpdateByID(ctx, id, bson.D{
 		{Key: "$set", Value: set},
 		{Key: "$inc", Value: bson.D{
 			{Key: "score", Value: int64(1)},
@@ -144,6 +159,9 @@ func (db *MongoDbBridge) NetworkNodeConfirmCheck(id enode.ID, inf *types.OperaNo
 
 // NetworkNodeFailCheck registers failed check of the given Opera network node.
 func (db *MongoDbBridge) NetworkNodeFailCheck(id enode.ID) (*types.OperaNode, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	// we need a pipeline here to be able to aggregate
@@ -175,7 +193,7 @@ func (db *MongoDbBridge) NetworkNodeFailCheck(id enode.ID) (*types.OperaNode, er
 			{Key: "whenNotMatched", Value: "discard"},
 		}}},
 	}
-	_, err := col.Aggregate(context.Background(), pipeline)
+	_, err := col.Aggregate(ctx, pipeline)
 	if err != nil {
 		db.log.Errorf("could not register node %s check failure; %s", id.String(), err.Error())
 		return nil, err
@@ -189,13 +207,16 @@ func (db *MongoDbBridge) NetworkNodeFailCheck(id enode.ID) (*types.OperaNode, er
 // NetworkNodeUpdateBatch provides a list of Opera network node addresses most suitable for status update
 // based on the registered time of the latest check.
 func (db *MongoDbBridge) NetworkNodeUpdateBatch() ([]*enode.Node, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// calculate the update batch window
 	// we try to avoid rapid update attempts, but the updater must finish a previous batch before pulling the new one
 	window := time.Now().Add(-30 * time.Minute)
 
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 	cu, err := col.Find(
-		context.Background(),
+		ctx,
 		bson.D{{Key: "checked", Value: bson.D{{Key: "$lt", Value: window}}}},
 		options.Find().SetSort(bson.D{{Key: "checked", Value: 1}}).SetLimit(100),
 	)
@@ -206,15 +227,18 @@ func (db *MongoDbBridge) NetworkNodeUpdateBatch() ([]*enode.Node, error) {
 	}
 
 	defer db.closeCursor(cu)
-	return db.NetworkNodeAddressList(cu, 100)
+	return db.NetworkNodeAddressList(ctx, cu, 100)
 }
 
 // NetworkNodeBootstrapSet provides a set of known nodes to be co-used to bootstrap new search.
 func (db *MongoDbBridge) NetworkNodeBootstrapSet() []*enode.Node {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	// sample random set of nodes without failed checks, sorted down from the most recently seen
-	cu, err := col.Aggregate(context.Background(), mongo.Pipeline{
+	cu, err := col.Aggregate(ctx, mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{
 			{Key: "fails", Value: 0},
 		}}},
@@ -236,7 +260,7 @@ func (db *MongoDbBridge) NetworkNodeBootstrapSet() []*enode.Node {
 	defer db.closeCursor(cu)
 
 	// load the list of addresses; the slice is returned even if an error happened
-	l, err := db.NetworkNodeAddressList(cu, 25)
+	l, err := db.NetworkNodeAddressList(ctx, cu, 25)
 	if err != nil {
 		db.log.Errorf("could not load bootstrap batch; %s", err.Error())
 	}
@@ -245,10 +269,10 @@ func (db *MongoDbBridge) NetworkNodeBootstrapSet() []*enode.Node {
 
 // NetworkNodeAddressList provides a list of Opera network node addresses list for the given filter, sorting,
 // and expected batch size.
-func (db *MongoDbBridge) NetworkNodeAddressList(cur *mongo.Cursor, size uint) ([]*enode.Node, error) {
+func (db *MongoDbBridge) NetworkNodeAddressList(ctx context.Context, cur *mongo.Cursor, size uint) ([]*enode.Node, error) {
 	list := make([]*enode.Node, 0, size)
 
-	for cur.Next(context.Background()) {
+	for cur.Next(ctx) {
 		var n struct {
 			Node enode.Node `bson:"node"`
 		}
@@ -264,6 +288,9 @@ func (db *MongoDbBridge) NetworkNodeAddressList(cur *mongo.Cursor, size uint) ([
 
 // NetworkNodesGeoAggregated provides a list of aggregated Opera nodes.
 func (db *MongoDbBridge) NetworkNodesGeoAggregated(level int) ([]*types.OperaNodeLocationAggregate, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colNetworkNodes)
 
 	var mainKey, topKey string
@@ -282,7 +309,7 @@ func (db *MongoDbBridge) NetworkNodesGeoAggregated(level int) ([]*types.OperaNod
 	}
 
 	// sample random set of nodes without failed checks, sorted down from the most recently seen
-	cu, err := col.Aggregate(context.Background(), mongo.Pipeline{
+	cu, err := col.Aggregate(ctx, mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{
 			{Key: "fails", Value: 0},
 		}}},
@@ -305,7 +332,7 @@ func (db *MongoDbBridge) NetworkNodesGeoAggregated(level int) ([]*types.OperaNod
 	defer db.closeCursor(cu)
 
 	list := make([]*types.OperaNodeLocationAggregate, 0)
-	for cu.Next(context.Background()) {
+	for cu.Next(ctx) {
 		var ag types.OperaNodeLocationAggregate
 		if err := cu.Decode(&ag); err != nil {
 			db.log.Errorf("could not decode network node aggregate; %s", err.Error())
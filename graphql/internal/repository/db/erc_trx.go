@@ -3,13 +3,15 @@ package db
 
 import (
 	"context"
+	"encoding/binary"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"time"
+	"math/big"
 )
 
 // colErcTransactions represents the name of the ERC20 transaction collection in database.
@@ -48,6 +50,9 @@ func (db *MongoDbBridge) initErc20TrxCollection(col *mongo.Collection) {
 
 // AddERC20Transaction stores an ERC20 transaction in the database if it doesn't exist.
 func (db *MongoDbBridge) AddERC20Transaction(trx *types.TokenTransaction) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// get the collection for delegations
 	col := db.client.Database(db.dbName).Collection(colErcTransactions)
 
@@ -57,10 +62,11 @@ func (db *MongoDbBridge) AddERC20Transaction(trx *types.TokenTransaction) error
 	}
 
 	// try to do the insert
-	if _, err := col.InsertOne(context.Background(), trx); err != nil {
+	if _, err := col.InsertOne(ctx, trx); err != nil {
 		db.log.Critical(err)
 		return err
 	}
+	db.pushStoreEvent(colErcTransactions, trx)
 
 	// make sure delegation collection is initialized
 	if db.initErc20Trx != nil {
@@ -71,8 +77,11 @@ func (db *MongoDbBridge) AddERC20Transaction(trx *types.TokenTransaction) error
 
 // isErcTransactionKnown checks if the given delegation exists in the database.
 func (db *MongoDbBridge) isErcTransactionKnown(col *mongo.Collection, trx *types.TokenTransaction) bool {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// try to find the delegation in the database
-	sr := col.FindOne(context.Background(), bson.D{
+	sr := col.FindOne(ctx, bson.D{
 		{Key: types.FiTokenTransactionPk, Value: trx.Pk()},
 	}, options.FindOne().SetProjection(bson.D{
 		{Key: types.FiTokenTransactionPk, Value: true},
@@ -103,7 +112,7 @@ func (db *MongoDbBridge) ErcTransactionCount() (uint64, error) {
 }
 
 // ercTrxListInit initializes list of ERC20 transactions based on provided cursor, count, and filter.
-func (db *MongoDbBridge) ercTrxListInit(col *mongo.Collection, cursor *string, count int32, filter *bson.D) (*types.TokenTransactionList, error) {
+func (db *MongoDbBridge) ercTrxListInit(ctx context.Context, col *mongo.Collection, cursor *string, count int32, filter *bson.D) (*types.TokenTransactionList, error) {
 	// make sure some filter is used
 	if nil == filter {
 		filter = &bson.D{}
@@ -130,7 +139,7 @@ func (db *MongoDbBridge) ercTrxListInit(col *mongo.Collection, cursor *string, c
 
 	// is the list non-empty? return the list with properly calculated range marks
 	if 0 < total {
-		return db.ercTrxListCollectRangeMarks(col, &list, cursor, count)
+		return db.ercTrxListCollectRangeMarks(ctx, col, &list, cursor, count)
 	}
 	// this is an empty list
 	db.log.Debug("empty erc trx list created")
@@ -138,27 +147,27 @@ func (db *MongoDbBridge) ercTrxListInit(col *mongo.Collection, cursor *string, c
 }
 
 // ercTrxListCollectRangeMarks returns a list of ERC20 transactions with proper First/Last marks.
-func (db *MongoDbBridge) ercTrxListCollectRangeMarks(col *mongo.Collection, list *types.TokenTransactionList, cursor *string, count int32) (*types.TokenTransactionList, error) {
+func (db *MongoDbBridge) ercTrxListCollectRangeMarks(ctx context.Context, col *mongo.Collection, list *types.TokenTransactionList, cursor *string, count int32) (*types.TokenTransactionList, error) {
 	var err error
 
 	// find out the cursor ordinal index
 	if cursor == nil && count > 0 {
 		// get the highest available pk
-		list.First, err = db.ercTrxListBorderPk(col,
+		list.First, err = db.ercTrxListBorderPk(ctx, col,
 			list.Filter,
 			options.FindOne().SetSort(bson.D{{Key: types.FiTokenTransactionOrdinal, Value: -1}}))
 		list.IsStart = true
 
 	} else if cursor == nil && count < 0 {
 		// get the lowest available pk
-		list.First, err = db.ercTrxListBorderPk(col,
+		list.First, err = db.ercTrxListBorderPk(ctx, col,
 			list.Filter,
 			options.FindOne().SetSort(bson.D{{Key: types.FiTokenTransactionOrdinal, Value: 1}}))
 		list.IsEnd = true
 
 	} else if cursor != nil {
 		// the cursor itself is the starting point
-		list.First, err = db.ercTrxListBorderPk(col,
+		list.First, err = db.ercTrxListBorderPk(ctx, col,
 			bson.D{{Key: types.FiTokenTransactionPk, Value: *cursor}},
 			options.FindOne())
 	}
@@ -175,7 +184,7 @@ func (db *MongoDbBridge) ercTrxListCollectRangeMarks(col *mongo.Collection, list
 }
 
 // ercTrxListBorderPk finds the top PK of the ERC20 transactions collection based on given filter and options.
-func (db *MongoDbBridge) ercTrxListBorderPk(col *mongo.Collection, filter bson.D, opt *options.FindOneOptions) (uint64, error) {
+func (db *MongoDbBridge) ercTrxListBorderPk(ctx context.Context, col *mongo.Collection, filter bson.D, opt *options.FindOneOptions) (uint64, error) {
 	// prep container
 	var row struct {
 		Value uint64 `bson:"orx"`
@@ -185,7 +194,7 @@ func (db *MongoDbBridge) ercTrxListBorderPk(col *mongo.Collection, filter bson.D
 	opt.SetProjection(bson.D{{Key: types.FiTokenTransactionOrdinal, Value: true}})
 
 	// try to decode
-	sr := col.FindOne(context.Background(), filter, opt)
+	sr := col.FindOne(ctx, filter, opt)
 	err := sr.Decode(&row)
 	if err != nil {
 		return 0, err
@@ -240,11 +249,7 @@ func (db *MongoDbBridge) ercTrxListOptions(count int32) *options.FindOptions {
 }
 
 // ercTrxListLoad load the initialized list of ERC20 transactions from database.
-func (db *MongoDbBridge) ercTrxListLoad(col *mongo.Collection, cursor *string, count int32, list *types.TokenTransactionList) (err error) {
-	// get the context for loader
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
+func (db *MongoDbBridge) ercTrxListLoad(ctx context.Context, col *mongo.Collection, cursor *string, count int32, list *types.TokenTransactionList) (err error) {
 	// load the data
 	ld, err := col.Find(ctx, db.ercTrxListFilter(cursor, count, list), db.ercTrxListOptions(count))
 	if err != nil {
@@ -262,7 +267,7 @@ func (db *MongoDbBridge) ercTrxListLoad(col *mongo.Collection, cursor *string, c
 
 	// loop and load the list; we may not store the last value
 	var trx *types.TokenTransaction
-	for ld.Next(context.Background()) {
+	for ld.Next(ctx) {
 		// append a previous value to the list, if we have one
 		if trx != nil {
 			list.Collection = append(list.Collection, trx)
@@ -290,8 +295,72 @@ func (db *MongoDbBridge) ercTrxListLoad(col *mongo.Collection, cursor *string, c
 	return nil
 }
 
+// WithTokenTransactionRange extends the given token transaction list filter with conditions limiting
+// token transactions to the given block and time range. Block limits are applied to the primary key,
+// which starts with the block number; time limits are applied to the ordinal index,
+// which starts with the block time stamp.
+func WithTokenTransactionRange(filter bson.D, rf *types.RangeFilter) bson.D {
+	cond := bson.A{}
+	if rf.IsEmpty() {
+		return filter
+	}
+
+	if rf.FromBlock != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionPk, Value: bson.D{{Key: "$gte", Value: tokenTrxPkBound(*rf.FromBlock, 0)}}}})
+	}
+	if rf.ToBlock != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionPk, Value: bson.D{{Key: "$lte", Value: tokenTrxPkBound(*rf.ToBlock, 0xff)}}}})
+	}
+	if rf.FromTime != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionOrdinal, Value: bson.D{{Key: "$gte", Value: (*rf.FromTime & 0x7FFFFFFFFF) << 24}}}})
+	}
+	if rf.ToTime != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionOrdinal, Value: bson.D{{Key: "$lte", Value: ((*rf.ToTime & 0x7FFFFFFFFF) << 24) | 0xFFFFFF}}}})
+	}
+	return withRangeFilter(filter, cond)
+}
+
+// WithTokenTransactionFilter extends the given token transaction list filter with conditions
+// of the advanced token transaction filter. The token condition uses the token index, amounts
+// are compared on the stored value, which keeps ERC20 amounts with 1e-9 precision of the raw amount.
+func WithTokenTransactionFilter(filter bson.D, tf *types.TokenTransactionFilter) bson.D {
+	cond := bson.A{}
+	if tf.IsEmpty() {
+		return filter
+	}
+
+	// any of the listed tokens
+	if len(tf.Tokens) > 0 {
+		tokens := make(bson.A, len(tf.Tokens))
+		for i, t := range tf.Tokens {
+			tokens[i] = t.String()
+		}
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionToken, Value: bson.D{{Key: "$in", Value: tokens}}}})
+	}
+
+	// amount range
+	if tf.MinAmount != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionValue, Value: bson.D{{Key: "$gte", Value: new(big.Int).Div(tf.MinAmount, types.TransactionDecimalsCorrection).Int64()}}}})
+	}
+	if tf.MaxAmount != nil {
+		cond = append(cond, bson.D{{Key: types.FiTokenTransactionValue, Value: bson.D{{Key: "$lte", Value: new(big.Int).Div(tf.MaxAmount, types.TransactionDecimalsCorrection).Int64()}}}})
+	}
+	return withRangeFilter(filter, cond)
+}
+
+// tokenTrxPkBound provides the lowest, or the highest, token transaction primary key of the given block.
+// The key is hex encoded with fixed length, so the lexicographic order follows the block order.
+func tokenTrxPkBound(blk uint64, fill byte) string {
+	pk := make([]byte, 14)
+	binary.BigEndian.PutUint64(pk[0:8], blk)
+	for i := 8; i < len(pk); i++ {
+		pk[i] = fill
+	}
+	return hexutil.Encode(pk)
+}
+
 // Erc20Transactions pulls list of ERC20 transactions starting at the specified cursor.
-func (db *MongoDbBridge) Erc20Transactions(cursor *string, count int32, filter *bson.D) (*types.TokenTransactionList, error) {
+func (db *MongoDbBridge) Erc20Transactions(ctx context.Context, cursor *string, count int32, filter *bson.D) (*types.TokenTransactionList, error) {
 	// nothing to load?
 	if count == 0 {
 		return nil, fmt.Errorf("nothing to do, zero erc transactions requested")
@@ -299,9 +368,11 @@ func (db *MongoDbBridge) Erc20Transactions(cursor *string, count int32, filter *
 
 	// get the collection and context
 	col := db.client.Database(db.dbName).Collection(colErcTransactions)
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
 
 	// init the list
-	list, err := db.ercTrxListInit(col, cursor, count, filter)
+	list, err := db.ercTrxListInit(ctx, col, cursor, count, filter)
 	if err != nil {
 		db.log.Errorf("can not build erc transaction list; %s", err.Error())
 		return nil, err
@@ -309,7 +380,7 @@ func (db *MongoDbBridge) Erc20Transactions(cursor *string, count int32, filter *
 
 	// load data if there are any
 	if list.Total > 0 {
-		err = db.ercTrxListLoad(col, cursor, count, list)
+		err = db.ercTrxListLoad(ctx, col, cursor, count, list)
 		if err != nil {
 			db.log.Errorf("can not load erc transaction list from database; %s", err.Error())
 			return nil, err
@@ -325,14 +396,17 @@ func (db *MongoDbBridge) Erc20Transactions(cursor *string, count int32, filter *
 
 // Erc20Assets provides list of unique token addresses linked by transactions to the given owner address.
 func (db *MongoDbBridge) Erc20Assets(owner common.Address, count int32) ([]common.Address, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// nothing to load?
 	if count <= 1 {
 		return nil, fmt.Errorf("nothing to do, zero erc assets requested")
 	}
 
-	// get the collection and context
+	// get the collection
 	col := db.client.Database(db.dbName).Collection(colErcTransactions)
-	refs, err := col.Distinct(context.Background(), types.FiTokenTransactionToken,
+	refs, err := col.Distinct(ctx, types.FiTokenTransactionToken,
 		bson.D{{Key: "to", Value: owner.String()}},
 	)
 	if err != nil {
@@ -350,11 +424,14 @@ func (db *MongoDbBridge) Erc20Assets(owner common.Address, count int32) ([]commo
 
 // TokenTransactionsByCall provides list of token transactions for the given blockchain transaction call.
 func (db *MongoDbBridge) TokenTransactionsByCall(trxHash *common.Hash) ([]*types.TokenTransaction, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	col := db.client.Database(db.dbName).Collection(colErcTransactions)
 
 	// search for values
 	ld, err := col.Find(
-		context.Background(),
+		ctx,
 		bson.D{{Key: types.FiTokenTransactionCallHash, Value: trxHash.String()}},
 		options.Find().SetSort(bson.D{{Key: types.FiTokenTransactionOrdinal, Value: -1}}),
 	)
@@ -363,7 +440,7 @@ func (db *MongoDbBridge) TokenTransactionsByCall(trxHash *common.Hash) ([]*types
 
 	// loop and load the list; we may not store the last value
 	list := make([]*types.TokenTransaction, 0)
-	for ld.Next(context.Background()) {
+	for ld.Next(ctx) {
 		var row types.TokenTransaction
 		if err = ld.Decode(&row); err != nil {
 			db.log.Errorf("can not decode the token transaction; %s", err.Error())
@@ -375,3 +452,25 @@ func (db *MongoDbBridge) TokenTransactionsByCall(trxHash *common.Hash) ([]*types
 	}
 	return list, nil
 }
+
+// TokenTransactionsPage loads a page of token transactions matching the given filter.
+// Unlike the cursor based list, the page is addressed by the number of transactions skipped
+// and the order can be chosen; it serves clients of offset paginated interfaces.
+func (db *MongoDbBridge) TokenTransactionsPage(ctx context.Context, filter *bson.D, skip int64, limit int64, asc bool) ([]*types.TokenTransaction, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colErcTransactions)
+
+	list := make([]*types.TokenTransaction, 0)
+	err := db.iterate(ctx, col, *filter, pageFindOptions(types.FiTokenTransactionOrdinal, skip, limit, asc), func(cursor *mongo.Cursor) error {
+		var etx types.TokenTransaction
+		if err := cursor.Decode(&etx); err != nil {
+			db.log.Errorf("can not decode token transaction; %s", err.Error())
+			return err
+		}
+		list = append(list, &etx)
+		return nil
+	})
+	return list, err
+}
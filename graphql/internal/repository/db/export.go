@@ -0,0 +1,77 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportAccountTransactions streams transactions of the given account within the given range
+// ordered from older to newer into the callback; at most limit transactions are streamed.
+// The stream is terminated by the first error of the callback, or by the end of the given context.
+func (db *MongoDbBridge) ExportAccountTransactions(ctx context.Context, addr *common.Address, rf *types.RangeFilter, limit int64, fn func(*types.Transaction) error) error {
+	col := db.client.Database(db.dbName).Collection(coTransactions)
+	filter := withRangeFilter(bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: fiTransactionSender, Value: addr.String()}},
+		bson.D{{Key: fiTransactionRecipient, Value: addr.String()}},
+	}}}, trxRangeConditions(rf))
+
+	return db.iterate(ctx, col, filter, exportFindOptions(fiTransactionOrdinalIndex, limit), func(cursor *mongo.Cursor) error {
+		var trx types.Transaction
+		if err := cursor.Decode(&trx); err != nil {
+			db.log.Errorf("can not decode exported transaction; %s", err.Error())
+			return err
+		}
+		return fn(&trx)
+	})
+}
+
+// ExportTokenTransactions streams token transactions of the given account within the given range
+// ordered from older to newer into the callback; at most limit transactions are streamed.
+// The stream is terminated by the first error of the callback, or by the end of the given context.
+func (db *MongoDbBridge) ExportTokenTransactions(ctx context.Context, addr *common.Address, rf *types.RangeFilter, limit int64, fn func(*types.TokenTransaction) error) error {
+	col := db.client.Database(db.dbName).Collection(colErcTransactions)
+	filter := WithTokenTransactionRange(bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: types.FiTokenTransactionSender, Value: addr.String()}},
+		bson.D{{Key: types.FiTokenTransactionRecipient, Value: addr.String()}},
+	}}}, rf)
+
+	return db.iterate(ctx, col, filter, exportFindOptions(types.FiTokenTransactionOrdinal, limit), func(cursor *mongo.Cursor) error {
+		var etx types.TokenTransaction
+		if err := cursor.Decode(&etx); err != nil {
+			db.log.Errorf("can not decode exported token transaction; %s", err.Error())
+			return err
+		}
+		return fn(&etx)
+	})
+}
+
+// exportFindOptions provides the find options of an export in ascending order of the given field.
+func exportFindOptions(sort string, limit int64) *options.FindOptions {
+	return options.Find().
+		SetSort(bson.D{{Key: sort, Value: 1}}).
+		SetLimit(limit).
+		SetBatchSize(500)
+}
+
+// iterate finds documents of the collection matching the filter and passes the cursor
+// positioned on each of them to the callback. The iteration is terminated by the first error of the callback.
+func (db *MongoDbBridge) iterate(ctx context.Context, col *mongo.Collection, filter bson.D, opt *options.FindOptions, fn func(*mongo.Cursor) error) error {
+	cursor, err := col.Find(ctx, filter, opt)
+	if err != nil {
+		db.log.Errorf("can not open cursor; %s", err.Error())
+		return err
+	}
+	defer db.closeCursor(cursor)
+
+	for cursor.Next(ctx) {
+		if err := fn(cursor); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,326 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	// colFaucet represents the name of the faucet requests collection.
+	colFaucet = "faucet"
+
+	// colFaucetLimits represents the name of the faucet rate limits, budget and nonce collection.
+	colFaucetLimits = "faucet_limits"
+)
+
+// faucetCollectionIndexes provides a list of indexes expected to exist on the faucet requests collection.
+func faucetCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 2)
+
+	ixRecipient := "ix_faucet_to_ts"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "to", Value: 1}, {Key: "ts", Value: -1}}, Options: &options.IndexOptions{Name: &ixRecipient}}
+
+	ixTimeStamp := "ix_faucet_ts"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: "ts", Value: -1}}, Options: &options.IndexOptions{Name: &ixTimeStamp}}
+
+	return ix
+}
+
+// StoreFaucetRequest stores the given faucet request in the persistent database.
+// The request is stored as pending before the transfer is sent, see FinalizeFaucetRequest.
+func (db *MongoDbBridge) StoreFaucetRequest(ctx context.Context, fr *types.FaucetRequest) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucet)
+
+	if _, err := col.InsertOne(ctx, fr); err != nil {
+		db.log.Errorf("can not store faucet request %s; %s", fr.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// FinalizeFaucetRequest sets the hash of the transfer transaction sent for the given pending faucet request.
+func (db *MongoDbBridge) FinalizeFaucetRequest(ctx context.Context, id string, trx *common.Hash) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucet)
+
+	if _, err := col.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "trx", Value: trx.String()}}},
+		{Key: "$unset", Value: bson.D{{Key: "raw", Value: ""}}},
+	}); err != nil {
+		db.log.Errorf("can not finalize faucet request %s; %s", id, err.Error())
+		return err
+	}
+	return nil
+}
+
+// DropFaucetRequest removes the given pending faucet request whose transfer could not be sent.
+func (db *MongoDbBridge) DropFaucetRequest(ctx context.Context, id string) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucet)
+
+	if _, err := col.DeleteOne(ctx, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "trx", Value: bson.D{{Key: "$exists", Value: false}}},
+	}); err != nil {
+		db.log.Errorf("can not drop faucet request %s; %s", id, err.Error())
+		return err
+	}
+	return nil
+}
+
+// FaucetReserveSlot atomically takes one of the given number of rate limit slots of the key.
+// A slot is free if it was last taken before the given since time; the slot taken is marked
+// with the given time stamp. Returns the id of the slot, or an empty string if all the slots are taken.
+func (db *MongoDbBridge) FaucetReserveSlot(ctx context.Context, key string, limit int64, since time.Time, ts time.Time) (string, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucetLimits)
+
+	for i := int64(0); i < limit; i++ {
+		id := fmt.Sprintf("%s/%d", key, i)
+
+		// the upsert collides with the existing slot document if the slot is taken
+		_, err := col.UpdateOne(ctx, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "ts", Value: bson.D{{Key: "$lt", Value: since}}},
+		}, bson.D{
+			{Key: "$set", Value: bson.D{{Key: "ts", Value: ts}}},
+		}, options.Update().SetUpsert(true))
+		if err == nil {
+			return id, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			db.log.Errorf("can not reserve faucet slot %s; %s", id, err.Error())
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// FaucetReleaseSlot frees the rate limit slot taken at the given time stamp.
+func (db *MongoDbBridge) FaucetReleaseSlot(ctx context.Context, id string, ts time.Time) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucetLimits)
+
+	if _, err := col.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}, {Key: "ts", Value: ts}}); err != nil {
+		db.log.Errorf("can not release faucet slot %s; %s", id, err.Error())
+		return err
+	}
+	return nil
+}
+
+// FaucetReserveBudget atomically adds the reduced precision value to the amount spent by the faucet
+// on the given day, if the daily budget allows it. Returns false if the budget would be exceeded.
+func (db *MongoDbBridge) FaucetReserveBudget(ctx context.Context, day time.Time, value int64, budget int64) (bool, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	if value > budget {
+		return false, nil
+	}
+
+	col := db.client.Database(db.dbName).Collection(colFaucetLimits)
+
+	// the upsert collides with the existing budget document if there is not enough budget left
+	_, err := col.UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: faucetBudgetID(day)},
+		{Key: "val", Value: bson.D{{Key: "$lte", Value: budget - value}}},
+	}, bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "val", Value: value}}},
+	}, options.Update().SetUpsert(true))
+	if err == nil {
+		return true, nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	db.log.Errorf("can not reserve faucet budget; %s", err.Error())
+	return false, err
+}
+
+// FaucetReleaseBudget returns the reduced precision value to the faucet budget of the given day.
+func (db *MongoDbBridge) FaucetReleaseBudget(ctx context.Context, day time.Time, value int64) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucetLimits)
+
+	if _, err := col.UpdateOne(ctx, bson.D{{Key: "_id", Value: faucetBudgetID(day)}}, bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "val", Value: -value}}},
+	}); err != nil {
+		db.log.Errorf("can not release faucet budget; %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// faucetBudgetID provides the id of the faucet budget document of the given day.
+func faucetBudgetID(day time.Time) string {
+	return "budget/" + day.UTC().Format("2006-01-02")
+}
+
+// faucetNonceUpdateAttempts represents the number of attempts to update the nonce state
+// of the faucet sender account changed concurrently by another API instance.
+const faucetNonceUpdateAttempts = 20
+
+// FaucetNextNonce atomically reserves the next nonce of the faucet sender account,
+// given the pending nonce of the account known to the node.
+func (db *MongoDbBridge) FaucetNextNonce(ctx context.Context, from *common.Address, pending uint64) (uint64, error) {
+	var nonce uint64
+	err := db.faucetNonceUpdate(ctx, from, func(fn *types.FaucetNonce) {
+		nonce = fn.Reserve(pending, time.Now().UTC())
+	})
+	return nonce, err
+}
+
+// FaucetNonceSent releases the nonce of a transfer accepted by the node.
+func (db *MongoDbBridge) FaucetNonceSent(ctx context.Context, from *common.Address, nonce uint64) error {
+	return db.faucetNonceUpdate(ctx, from, func(fn *types.FaucetNonce) {
+		fn.Sent(nonce)
+	})
+}
+
+// FaucetReturnNonce returns the given nonce of a transfer which could not be sent
+// so the next transfer reuses it.
+func (db *MongoDbBridge) FaucetReturnNonce(ctx context.Context, from *common.Address, nonce uint64) error {
+	return db.faucetNonceUpdate(ctx, from, func(fn *types.FaucetNonce) {
+		fn.Return(nonce)
+	})
+}
+
+// FaucetReturnStaleNonces returns the nonces held since before the given time, except the given ones.
+func (db *MongoDbBridge) FaucetReturnStaleNonces(ctx context.Context, from *common.Address, before time.Time, keep map[uint64]bool) error {
+	return db.faucetNonceUpdate(ctx, from, func(fn *types.FaucetNonce) {
+		fn.ReturnStale(before, keep)
+	})
+}
+
+// faucetNonceUpdate applies the given change to the nonce state of the faucet sender account.
+// The state is replaced only if nobody changed it in the meantime; the change is re-applied otherwise.
+func (db *MongoDbBridge) faucetNonceUpdate(ctx context.Context, from *common.Address, apply func(*types.FaucetNonce)) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucetLimits)
+	id := faucetNonceID(from)
+
+	for i := 0; i < faucetNonceUpdateAttempts; i++ {
+		var fn types.FaucetNonce
+		err := col.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&fn)
+		if err != nil && err != mongo.ErrNoDocuments {
+			db.log.Errorf("can not load faucet nonce; %s", err.Error())
+			return err
+		}
+
+		ver := fn.Version
+		apply(&fn)
+		fn.Version = ver + 1
+
+		// the upsert collides with the existing document if the state has been changed
+		_, err = col.ReplaceOne(ctx, faucetNonceFilter(id, ver), &fn, options.Replace().SetUpsert(true))
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			db.log.Errorf("can not update faucet nonce; %s", err.Error())
+			return err
+		}
+	}
+
+	db.log.Errorf("faucet nonce of %s changed too often", from.String())
+	return fmt.Errorf("faucet nonce update conflict")
+}
+
+// faucetNonceFilter provides the filter of the nonce document of the given version.
+func faucetNonceFilter(id string, ver int64) bson.D {
+	if ver == 0 {
+		return bson.D{{Key: "_id", Value: id}, {Key: "v", Value: bson.D{{Key: "$exists", Value: false}}}}
+	}
+	return bson.D{{Key: "_id", Value: id}, {Key: "v", Value: ver}}
+}
+
+// faucetNonceID provides the id of the nonce document of the given faucet sender account.
+func faucetNonceID(from *common.Address) string {
+	return "nonce/" + from.String()
+}
+
+// FaucetRequests loads the most recent faucet requests, optionally limited to the given recipient.
+func (db *MongoDbBridge) FaucetRequests(ctx context.Context, addr *common.Address, count int64) ([]*types.FaucetRequest, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucet)
+
+	// pending requests are not listed
+	filter := bson.D{{Key: "trx", Value: bson.D{{Key: "$exists", Value: true}}}}
+	if addr != nil {
+		filter = append(filter, bson.E{Key: "to", Value: addr.String()})
+	}
+
+	cursor, err := col.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "ts", Value: -1}}).
+		SetLimit(count))
+	if err != nil {
+		db.log.Errorf("can not load faucet requests; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.FaucetRequest, 0)
+	for cursor.Next(ctx) {
+		var fr types.FaucetRequest
+		if err := cursor.Decode(&fr); err != nil {
+			db.log.Errorf("can not decode faucet request; %s", err.Error())
+			continue
+		}
+		list = append(list, &fr)
+	}
+	return list, nil
+}
+
+// FaucetRequestsPending loads the pending faucet requests stored before the given time.
+func (db *MongoDbBridge) FaucetRequestsPending(ctx context.Context, before time.Time) ([]*types.FaucetRequest, error) {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colFaucet)
+
+	cursor, err := col.Find(ctx, bson.D{
+		{Key: "trx", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "ts", Value: bson.D{{Key: "$lt", Value: before}}},
+	}, options.Find().SetSort(bson.D{{Key: "ts", Value: 1}}))
+	if err != nil {
+		db.log.Errorf("can not load pending faucet requests; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.FaucetRequest, 0)
+	for cursor.Next(ctx) {
+		var fr types.FaucetRequest
+		if err := cursor.Decode(&fr); err != nil {
+			db.log.Errorf("can not decode faucet request; %s", err.Error())
+			continue
+		}
+		list = append(list, &fr)
+	}
+	return list, nil
+}
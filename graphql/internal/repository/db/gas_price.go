@@ -38,6 +38,9 @@ func (db *MongoDbBridge) initGasPriceCollection(col *mongo.Collection) {
 // AddGasPricePeriod stores a new record for the gas price evaluation
 // into the persistent collection.
 func (db *MongoDbBridge) AddGasPricePeriod(gp *types.GasPricePeriod) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do we have anything to store at all?
 	if gp == nil {
 		return fmt.Errorf("no value to store")
@@ -47,7 +50,7 @@ func (db *MongoDbBridge) AddGasPricePeriod(gp *types.GasPricePeriod) error {
 	col := db.client.Database(db.dbName).Collection(colGasPrice)
 
 	// try to do the insert
-	if _, err := col.InsertOne(context.Background(), gp); err != nil {
+	if _, err := col.InsertOne(ctx, gp); err != nil {
 		db.log.Errorf("can not store gas price value; %s", err)
 		return err
 	}
@@ -66,11 +69,14 @@ func (db *MongoDbBridge) GasPricePeriodCount() (uint64, error) {
 
 // GasPriceTicks provides a list of gas price ticks for the given time period.
 func (db *MongoDbBridge) GasPriceTicks(from *time.Time, to *time.Time) ([]types.GasPricePeriod, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// get the collection
 	col := db.client.Database(db.dbName).Collection(colGasPrice)
 
 	// find ticks inside the date/time range
-	cursor, err := col.Find(context.Background(), bson.D{
+	cursor, err := col.Find(ctx, bson.D{
 		{Key: "from", Value: bson.D{{Key: "$gte", Value: from}}},
 		{Key: "to", Value: bson.D{{Key: "$lte", Value: to}}},
 	}, options.Find().SetSort(bson.D{{Key: "from", Value: 1}}))
@@ -84,7 +90,7 @@ func (db *MongoDbBridge) GasPriceTicks(from *time.Time, to *time.Time) ([]types.
 
 	// load all the data from the database
 	list := make([]types.GasPricePeriod, 0)
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var row types.GasPricePeriod
 
 		if err := cursor.Decode(&row); err != nil {
@@ -23,7 +23,19 @@ type IndexList struct {
 func (db *MongoDbBridge) updateDatabaseIndexes() {
 	// define index list loaders
 	var ixLoaders = map[string]indexListProvider{
-		colNetworkNodes: operaNodeCollectionIndexes,
+		colNetworkNodes:      operaNodeCollectionIndexes,
+		colBlocks:            blockCollectionIndexes,
+		coTransactions:       transactionCollectionIndexes,
+		coContract:           contractCollectionIndexes,
+		colSignatures:        signatureCollectionIndexes,
+		colContractSync:      contractSyncCollectionIndexes,
+		colContractVerify:    contractVerificationCollectionIndexes,
+		colFaucet:            faucetCollectionIndexes,
+		colSubmittedTrx:      submittedTrxCollectionIndexes,
+		colWebhookDeliveries: webhookCollectionIndexes,
+		colAlerts:            alertCollectionIndexes,
+		colApiKeys:           apiKeyCollectionIndexes,
+		colApiUsage:          apiUsageCollectionIndexes,
 	}
 
 	// the DB bridge needs a way to terminate this thread
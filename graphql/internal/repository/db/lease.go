@@ -0,0 +1,117 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// colLeases represents the name of the leases collection.
+const colLeases = "leases"
+
+// AcquireLease tries to acquire, or renew, the lease of the given name for the given holder.
+// The lease is granted if it's free, lapsed, or already held by the holder. The current state
+// of the lease is provided regardless of the outcome; check the holder to see who got it.
+// Time stamps are taken from the database server clock, so the clocks of the competing
+// instances do not need to be in sync.
+func (db *MongoDbBridge) AcquireLease(name string, holder string, ttl time.Duration) (*types.Lease, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colLeases)
+
+	sr := col.FindOneAndUpdate(ctx,
+		leaseAcquireFilter(name, holder),
+		leaseAcquireUpdate(holder, ttl),
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+
+	if sr.Err() != nil {
+		// the lease is held by someone else, the upsert collided with it
+		if mongo.IsDuplicateKeyError(sr.Err()) {
+			return db.Lease(name)
+		}
+
+		db.log.Errorf("can not acquire lease %s; %s", name, sr.Err().Error())
+		return nil, sr.Err()
+	}
+
+	var lease types.Lease
+	if err := sr.Decode(&lease); err != nil {
+		db.log.Errorf("can not decode lease %s; %s", name, err.Error())
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// leaseAcquireFilter provides the filter matching the lease of the given name
+// if it's held by the given holder, or if it lapsed by the database server clock.
+func leaseAcquireFilter(name string, holder string) bson.D {
+	return bson.D{
+		{Key: "_id", Value: name},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "holder", Value: holder}},
+			bson.D{{Key: "$expr", Value: bson.D{{Key: "$lte", Value: bson.A{"$exp", "$$NOW"}}}}},
+		}},
+	}
+}
+
+// leaseAcquireUpdate provides the update pipeline granting the lease to the given holder
+// for the given time from now by the database server clock.
+// The acquisition time is kept while the same holder renews the lease.
+func leaseAcquireUpdate(holder string, ttl time.Duration) mongo.Pipeline {
+	return mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "holder", Value: holder},
+		{Key: "acq", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$holder", holder}}}, "$acq", "$$NOW",
+		}}}},
+		{Key: "hb", Value: "$$NOW"},
+		{Key: "exp", Value: bson.D{{Key: "$add", Value: bson.A{"$$NOW", ttl.Milliseconds()}}}},
+	}}}}
+}
+
+// ReleaseLease lets the lease of the given name lapse immediately, if it's held by the given holder.
+func (db *MongoDbBridge) ReleaseLease(name string, holder string) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colLeases)
+
+	_, err := col.UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: name},
+		{Key: "holder", Value: holder},
+	}, mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "exp", Value: "$$NOW"}}}}})
+	if err != nil {
+		db.log.Errorf("can not release lease %s; %s", name, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Lease loads the lease of the given name; nil if the lease has never been acquired.
+func (db *MongoDbBridge) Lease(name string) (*types.Lease, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colLeases)
+
+	sr := col.FindOne(ctx, bson.D{{Key: "_id", Value: name}})
+	if sr.Err() != nil {
+		if sr.Err() == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		db.log.Errorf("can not load lease %s; %s", name, sr.Err().Error())
+		return nil, sr.Err()
+	}
+
+	var lease types.Lease
+	if err := sr.Decode(&lease); err != nil {
+		db.log.Errorf("can not decode lease %s; %s", name, err.Error())
+		return nil, err
+	}
+	return &lease, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+	"time"
+)
+
+func TestLeaseAcquireFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the lapse is checked by the database server clock
+	g.Expect(leaseAcquireFilter("indexer", "me")).To(gomega.Equal(bson.D{
+		{Key: "_id", Value: "indexer"},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "holder", Value: "me"}},
+			bson.D{{Key: "$expr", Value: bson.D{{Key: "$lte", Value: bson.A{"$exp", "$$NOW"}}}}},
+		}},
+	}))
+}
+
+func TestLeaseAcquireUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want int64
+	}{
+		{name: "seconds", ttl: 30 * time.Second, want: 30000},
+		{name: "fraction", ttl: 1500 * time.Millisecond, want: 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			up := leaseAcquireUpdate("me", tt.ttl)
+			g.Expect(up).To(gomega.Equal(mongo.Pipeline{{{Key: "$set", Value: bson.D{
+				{Key: "holder", Value: "me"},
+				{Key: "acq", Value: bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{"$holder", "me"}}}, "$acq", "$$NOW",
+				}}}},
+				{Key: "hb", Value: "$$NOW"},
+				{Key: "exp", Value: bson.D{{Key: "$add", Value: bson.A{"$$NOW", tt.want}}}},
+			}}}}))
+
+			// the pipeline must be encodable for the update command
+			_, err := bson.Marshal(bson.D{{Key: "u", Value: up}})
+			g.Expect(err).To(gomega.BeNil())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+const (
+	// colMigrations represents the name of the collection keeping track of applied data migrations.
+	colMigrations = "migrations"
+
+	// migrationBatchSize represents the number of documents updated by a data migration at once.
+	migrationBatchSize = 500
+)
+
+// Migration represents a one-time update of the stored data identified by a unique id.
+// The update must be idempotent, an interrupted migration is started over on the next run.
+type Migration struct {
+	ID  string
+	Run func() error
+}
+
+// Migrate applies the given data migrations not applied yet in the given order on background.
+// If a migration fails, the migrations following it are postponed to the next run.
+func (db *MongoDbBridge) Migrate(list []Migration) {
+	// the DB bridge needs a way to terminate this thread
+	sig := make(chan bool, 1)
+	db.sig = append(db.sig, sig)
+
+	db.wg.Add(1)
+	go db.migrate(list, sig)
+}
+
+// migrate runs the given list of data migrations skipping those already applied.
+func (db *MongoDbBridge) migrate(list []Migration, sig chan bool) {
+	defer func() {
+		db.wg.Done()
+		db.log.Noticef("db migrations closed")
+	}()
+
+	col := db.client.Database(db.dbName).Collection(colMigrations)
+	for _, m := range list {
+		// respect possible terminate signal
+		select {
+		case <-sig:
+			return
+		default:
+		}
+
+		// skip migrations already done
+		err := col.FindOne(context.Background(), bson.D{{Key: "_id", Value: m.ID}}).Err()
+		if err == nil {
+			continue
+		}
+		if err != mongo.ErrNoDocuments {
+			db.log.Errorf("can not check migration %s; %s", m.ID, err.Error())
+			return
+		}
+
+		db.log.Noticef("running migration %s", m.ID)
+		if err := m.Run(); err != nil {
+			db.log.Errorf("migration %s failed; %s", m.ID, err.Error())
+			return
+		}
+
+		if _, err := col.InsertOne(context.Background(), bson.D{
+			{Key: "_id", Value: m.ID},
+			{Key: "ts", Value: time.Now().UTC()},
+		}); err != nil && !mongo.IsDuplicateKeyError(err) {
+			db.log.Errorf("can not mark migration %s done; %s", m.ID, err.Error())
+			return
+		}
+		db.log.Noticef("migration %s done", m.ID)
+	}
+}
+
+// migrateDocuments applies the update provided by the given function to all the documents
+// of the collection matching the filter. Documents the function returns nil update for are skipped;
+// the filter should not match updated documents so the migration can be resumed.
+func (db *MongoDbBridge) migrateDocuments(col *mongo.Collection, filter bson.D, update func(*mongo.Cursor) (bson.D, error)) error {
+	cursor, err := col.Find(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	defer db.closeCursor(cursor)
+
+	var count int
+	batch := make([]mongo.WriteModel, 0, migrationBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := col.BulkWrite(context.Background(), batch); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for cursor.Next(context.Background()) {
+		set, err := update(cursor)
+		if err != nil {
+			return err
+		}
+		if set == nil {
+			continue
+		}
+
+		batch = append(batch, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: cursor.Current.Lookup("_id")}}).
+			SetUpdate(bson.D{{Key: "$set", Value: set}}))
+		if len(batch) >= migrationBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	db.log.Noticef("%d documents of %s migrated", count, col.Name())
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"strings"
+)
+
+// SearchContracts provides contracts with the name, or the symbol matching the given term.
+// Whole words are matched using the full text index, prefixes of names, symbols
+// and their words are matched using the search keywords index.
+func (db *MongoDbBridge) SearchContracts(term string, limit int64) ([]*types.Contract, error) {
+	col := db.client.Database(db.dbName).Collection(coContract)
+	term = strings.ToLower(strings.TrimSpace(term))
+
+	// prefix matching; an anchored regular expression is resolved by the keywords index
+	list, err := db.searchContracts(col, bson.D{{Key: fiContractKeywords, Value: bson.D{
+		{Key: "$regex", Value: "^" + regexp.QuoteMeta(term)},
+	}}}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// full text matching on the words of names and symbols
+	found, err := db.searchContracts(col, bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: term}}}}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// merge the results skipping the contracts already found
+	known := make(map[string]bool, len(list))
+	for _, sc := range list {
+		known[sc.Address.String()] = true
+	}
+	for _, sc := range found {
+		if !known[sc.Address.String()] {
+			list = append(list, sc)
+		}
+	}
+	return list, nil
+}
+
+// searchContracts loads contracts matching the given filter without their source code and ABI.
+func (db *MongoDbBridge) searchContracts(col *mongo.Collection, filter bson.D, limit int64) ([]*types.Contract, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	opt := options.Find().
+		SetLimit(limit).
+		SetProjection(bson.D{{Key: "src", Value: 0}, {Key: "abi", Value: 0}})
+
+	cursor, err := col.Find(ctx, filter, opt)
+	if err != nil {
+		db.log.Errorf("can not search contracts; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.Contract, 0)
+	for cursor.Next(ctx) {
+		var sc types.Contract
+		if err := cursor.Decode(&sc); err != nil {
+			db.log.Errorf("can not decode contract; %s", err.Error())
+			continue
+		}
+		list = append(list, &sc)
+	}
+	return list, nil
+}
@@ -0,0 +1,75 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// colSignatures represents the name of the registered function and event signatures collection
+const colSignatures = "signatures"
+
+// signatureCollectionIndexes provides a list of indexes expected to exist on the signatures' collection.
+func signatureCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 1)
+
+	unique := true
+	ixSignatureHashText := "ix_sig_hash_text"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "hash", Value: 1}, {Key: "text", Value: 1}}, Options: &options.IndexOptions{
+		Name:   &ixSignatureHashText,
+		Unique: &unique,
+	}}
+
+	return ix
+}
+
+// StoreSignature stores the given function, or event signature in the persistent database.
+// The signature record is not modified if it's already known.
+func (db *MongoDbBridge) StoreSignature(ctx context.Context, sig *types.Signature) error {
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colSignatures)
+
+	_, err := col.UpdateOne(ctx, bson.D{
+		{Key: "hash", Value: sig.Hash},
+		{Key: "text", Value: sig.Text},
+	}, bson.D{
+		{Key: "$setOnInsert", Value: sig},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store signature %s; %s", sig.Text, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Signatures loads all the function and event signatures registered in the persistent database.
+func (db *MongoDbBridge) Signatures() ([]*types.Signature, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colSignatures)
+
+	cursor, err := col.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "ts", Value: 1}}))
+	if err != nil {
+		db.log.Errorf("can not load signatures; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.Signature, 0)
+	for cursor.Next(ctx) {
+		var sig types.Signature
+		if err := cursor.Decode(&sig); err != nil {
+			db.log.Errorf("can not decode signature; %s", err.Error())
+			continue
+		}
+		list = append(list, &sig)
+	}
+	return list, nil
+}
@@ -0,0 +1,264 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	// colStoreEvents represents the name of the capped collection of store events.
+	// The collection is used only if the database does not support change streams.
+	colStoreEvents = "store_events"
+
+	// storeEventsRetryDelay represents the delay before following the store events is retried after a failure.
+	storeEventsRetryDelay = 5 * time.Second
+
+	// storeEventsAwaitTime represents the max time a tailable cursor waits for new store events.
+	storeEventsAwaitTime = time.Second
+)
+
+// storeEventsPosition represents the position of a store events follower,
+// it allows the follower to resume where it left after a failure.
+type storeEventsPosition struct {
+	token bson.Raw
+	last  primitive.ObjectID
+}
+
+// storeEventRow represents a store event of the capped collection.
+type storeEventRow struct {
+	ID  primitive.ObjectID `bson:"_id"`
+	Col string             `bson:"col"`
+	Doc bson.Raw           `bson:"doc"`
+}
+
+// supportsChangeStreams checks if the connected database deployment supports change streams;
+// only replica sets and sharded clusters do.
+func (db *MongoDbBridge) supportsChangeStreams() bool {
+	var row struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	err := db.client.Database("admin").RunCommand(context.Background(), bson.D{{Key: "isMaster", Value: 1}}).Decode(&row)
+	if err != nil {
+		db.log.Errorf("can not check database deployment; %s", err.Error())
+		return false
+	}
+	return row.SetName != "" || row.Msg == "isdbgrid"
+}
+
+// initStoreEvents makes sure the capped collection of store events exists.
+func (db *MongoDbBridge) initStoreEvents(size int64) {
+	names, err := db.client.Database(db.dbName).ListCollectionNames(context.Background(), bson.D{{Key: "name", Value: colStoreEvents}})
+	if err != nil {
+		db.log.Errorf("can not check store events collection; %s", err.Error())
+		return
+	}
+	if len(names) > 0 {
+		return
+	}
+
+	err = db.client.Database(db.dbName).CreateCollection(context.Background(), colStoreEvents,
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(size))
+	if err != nil {
+		db.log.Errorf("can not create store events collection; %s", err.Error())
+		return
+	}
+	db.log.Noticef("store events collection created")
+}
+
+// pushStoreEvent adds the document stored in the given collection to the capped collection of store events.
+// Nothing is needed if the database supports change streams, the followers watch the collections directly.
+func (db *MongoDbBridge) pushStoreEvent(col string, doc interface{}) {
+	if db.changeStreams {
+		return
+	}
+
+	_, err := db.client.Database(db.dbName).Collection(colStoreEvents).InsertOne(context.Background(), bson.D{
+		{Key: "col", Value: col},
+		{Key: "doc", Value: doc},
+	})
+	if err != nil {
+		db.log.Errorf("can not push %s store event; %s", col, err.Error())
+	}
+}
+
+// FollowStore sends new blocks, transactions, token transactions and resolved submitted transactions
+// added to the store by the indexer into the given channel until the context is cancelled.
+// Change streams are used if available, the capped collection of store events is tailed otherwise.
+func (db *MongoDbBridge) FollowStore(ctx context.Context, out chan<- *types.StoreEvent) {
+	follow := db.followChangeStream
+	if !db.changeStreams {
+		follow = db.followStoreEvents
+	}
+
+	pos := storeEventsPosition{last: primitive.NewObjectIDFromTimestamp(time.Now())}
+	for {
+		err := follow(ctx, out, &pos)
+		if ctx.Err() != nil {
+			return
+		}
+
+		db.log.Errorf("store events interrupted; %s", err.Error())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(storeEventsRetryDelay):
+		}
+	}
+}
+
+// followChangeStream watches the store collections for new records using a change stream.
+func (db *MongoDbBridge) followChangeStream(ctx context.Context, out chan<- *types.StoreEvent, pos *storeEventsPosition) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+		bson.D{
+			{Key: "operationType", Value: "insert"},
+			{Key: "ns.coll", Value: bson.D{{Key: "$in", Value: bson.A{colBlocks, coTransactions, colErcTransactions}}}},
+		},
+		bson.D{
+			{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace"}}}},
+			{Key: "ns.coll", Value: colSubmittedTrx},
+		},
+	}}}}}}
+
+	opt := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if pos.token != nil {
+		opt.SetResumeAfter(pos.token)
+	}
+
+	cs, err := db.client.Database(db.dbName).Watch(ctx, pipeline, opt)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cs.Close(context.Background()); err != nil {
+			db.log.Errorf("failed to close change stream; %s", err.Error())
+		}
+	}()
+
+	db.log.Notice("following store change stream")
+	for cs.Next(ctx) {
+		var row struct {
+			Ns struct {
+				Col string `bson:"coll"`
+			} `bson:"ns"`
+			Doc bson.Raw `bson:"fullDocument"`
+		}
+		if err := cs.Decode(&row); err != nil {
+			return err
+		}
+		pos.token = cs.ResumeToken()
+
+		// the document may have been removed before the update lookup
+		if row.Doc == nil {
+			continue
+		}
+		if err := db.sendStoreEvent(ctx, out, row.Ns.Col, row.Doc); err != nil {
+			return err
+		}
+	}
+	return cs.Err()
+}
+
+// followStoreEvents tails the capped collection of store events.
+func (db *MongoDbBridge) followStoreEvents(ctx context.Context, out chan<- *types.StoreEvent, pos *storeEventsPosition) error {
+	col := db.client.Database(db.dbName).Collection(colStoreEvents)
+
+	db.log.Notice("following store events collection")
+	for {
+		cursor, err := col.Find(ctx,
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: pos.last}}}},
+			options.Find().SetCursorType(options.TailableAwait).SetMaxAwaitTime(storeEventsAwaitTime))
+		if err != nil {
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var row storeEventRow
+			if err := cursor.Decode(&row); err != nil {
+				db.closeCursor(cursor)
+				return err
+			}
+			pos.last = row.ID
+
+			if err := db.sendStoreEvent(ctx, out, row.Col, row.Doc); err != nil {
+				db.closeCursor(cursor)
+				return err
+			}
+		}
+
+		// the tailable cursor dies if there is nothing to follow yet; try again later
+		err = cursor.Err()
+		db.closeCursor(cursor)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(storeEventsAwaitTime):
+		}
+	}
+}
+
+// sendStoreEvent decodes the document of the given collection and sends it to the output channel.
+func (db *MongoDbBridge) sendStoreEvent(ctx context.Context, out chan<- *types.StoreEvent, col string, doc bson.Raw) error {
+	ev, err := decodeStoreEvent(col, doc)
+	if err != nil {
+		db.log.Errorf("can not decode %s store event; %s", col, err.Error())
+		return nil
+	}
+	if ev == nil {
+		return nil
+	}
+
+	select {
+	case out <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// decodeStoreEvent decodes the store event from the document of the given collection.
+// Submitted transactions which did not reach a final state yet are skipped.
+func decodeStoreEvent(col string, doc bson.Raw) (*types.StoreEvent, error) {
+	switch col {
+	case colBlocks:
+		blk, err := decodeBlockRow(doc)
+		if err != nil {
+			return nil, err
+		}
+		return &types.StoreEvent{Block: blk}, nil
+	case coTransactions:
+		var trx types.Transaction
+		if err := bson.Unmarshal(doc, &trx); err != nil {
+			return nil, err
+		}
+		return &types.StoreEvent{Transaction: &trx}, nil
+	case colErcTransactions:
+		var trx types.TokenTransaction
+		if err := bson.Unmarshal(doc, &trx); err != nil {
+			return nil, err
+		}
+		return &types.StoreEvent{TokenTransaction: &trx}, nil
+	case colSubmittedTrx:
+		var st types.SubmittedTransaction
+		if err := bson.Unmarshal(doc, &st); err != nil {
+			return nil, err
+		}
+		if !st.IsFinal() {
+			return nil, nil
+		}
+		return &types.StoreEvent{Submitted: &st}, nil
+	}
+	return nil, fmt.Errorf("unknown collection %s", col)
+}
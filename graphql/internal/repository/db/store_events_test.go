@@ -0,0 +1,123 @@
+package db
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+// mustMarshal provides the BSON document of the given value.
+func mustMarshal(t *testing.T, v interface{}) bson.Raw {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("can not marshal document; %s", err.Error())
+	}
+	return data
+}
+
+func TestDecodeStoreEvent(t *testing.T) {
+	hash := common.HexToHash("0x1f")
+	blk := hexutil.Uint64(27)
+	zero := hexutil.Uint64(0)
+	mined := uint64(27)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	tests := []struct {
+		name  string
+		col   string
+		doc   bson.Raw
+		check func(g *gomega.WithT, ev *types.StoreEvent)
+		err   bool
+	}{
+		{
+			name: "block",
+			col:  colBlocks,
+			doc:  mustMarshal(t, blockRow{Number: 27, Hash: hash.String(), Stamp: 1600000000, Stored: now}),
+			check: func(g *gomega.WithT, ev *types.StoreEvent) {
+				g.Expect(ev.Block).ToNot(gomega.BeNil())
+				g.Expect(ev.Block.Number).To(gomega.Equal(hexutil.Uint64(27)))
+				g.Expect(ev.Block.Hash).To(gomega.Equal(hash))
+				g.Expect(ev.Block.TimeStamp).To(gomega.Equal(hexutil.Uint64(1600000000)))
+				g.Expect(ev.Transaction).To(gomega.BeNil())
+			},
+		},
+		{
+			name: "transaction",
+			col:  coTransactions,
+			doc: mustMarshal(t, &types.Transaction{
+				Hash:              hash,
+				BlockHash:         &hash,
+				BlockNumber:       &blk,
+				Index:             &zero,
+				CumulativeGasUsed: &zero,
+				GasUsed:           &zero,
+				Status:            &zero,
+				TimeStamp:         now,
+			}),
+			check: func(g *gomega.WithT, ev *types.StoreEvent) {
+				g.Expect(ev.Transaction).ToNot(gomega.BeNil())
+				g.Expect(ev.Transaction.Hash).To(gomega.Equal(hash))
+				g.Expect(ev.Transaction.BlockNumber).To(gomega.Equal(&blk))
+				g.Expect(ev.Block).To(gomega.BeNil())
+			},
+		},
+		{
+			name: "token transaction",
+			col:  colErcTransactions,
+			doc:  mustMarshal(t, &types.TokenTransaction{Transaction: hash, BlockNumber: 27, TokenType: types.AccountTypeERC20Token}),
+			check: func(g *gomega.WithT, ev *types.StoreEvent) {
+				g.Expect(ev.TokenTransaction).ToNot(gomega.BeNil())
+				g.Expect(ev.TokenTransaction.Transaction).To(gomega.Equal(hash))
+				g.Expect(ev.TokenTransaction.TokenType).To(gomega.Equal(types.AccountTypeERC20Token))
+			},
+		},
+		{
+			name: "mined submitted transaction",
+			col:  colSubmittedTrx,
+			doc:  mustMarshal(t, &types.SubmittedTransaction{Hash: hash, Status: types.SubmittedTrxStatusMined, BlockNumber: &mined, Submitted: now, Updated: now}),
+			check: func(g *gomega.WithT, ev *types.StoreEvent) {
+				g.Expect(ev.Submitted).ToNot(gomega.BeNil())
+				g.Expect(ev.Submitted.Hash).To(gomega.Equal(hash))
+				g.Expect(ev.Submitted.BlockNumber).To(gomega.Equal(&mined))
+			},
+		},
+		{
+			name: "pending submitted transaction",
+			col:  colSubmittedTrx,
+			doc:  mustMarshal(t, &types.SubmittedTransaction{Hash: hash, Status: types.SubmittedTrxStatusPending, Submitted: now, Updated: now}),
+			check: func(g *gomega.WithT, ev *types.StoreEvent) {
+				g.Expect(ev).To(gomega.BeNil())
+			},
+		},
+		{
+			name: "unknown collection",
+			col:  colStoreEvents,
+			doc:  mustMarshal(t, bson.D{{Key: "_id", Value: 1}}),
+			err:  true,
+		},
+		{
+			name: "invalid block",
+			col:  colBlocks,
+			doc:  mustMarshal(t, bson.D{{Key: "_id", Value: "not a number"}}),
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			ev, err := decodeStoreEvent(tt.col, tt.doc)
+			if tt.err {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			tt.check(g, ev)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// colSubmittedTrx represents the name of the submitted transactions collection.
+const colSubmittedTrx = "submitted_trx"
+
+// submittedTrxPendingLimit represents the max number of pending submitted transactions loaded at once.
+const submittedTrxPendingLimit = 1000
+
+// submittedTrxCollectionIndexes provides a list of indexes expected to exist on the submitted transactions collection.
+func submittedTrxCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 1)
+
+	ixStatus := "ix_submitted_status_sub"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "sub", Value: 1}}, Options: &options.IndexOptions{Name: &ixStatus}}
+
+	return ix
+}
+
+// StoreSubmittedTransaction stores, or updates, the given submitted transaction in the persistent database.
+func (db *MongoDbBridge) StoreSubmittedTransaction(st *types.SubmittedTransaction) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colSubmittedTrx)
+
+	_, err := col.UpdateByID(ctx, st.Hash.String(), bson.D{
+		{Key: "$set", Value: st},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store submitted transaction %s; %s", st.Hash.String(), err.Error())
+		return err
+	}
+
+	if st.IsFinal() {
+		db.pushStoreEvent(colSubmittedTrx, st)
+	}
+	return nil
+}
+
+// SubmittedTransaction loads the submitted transaction of the given hash; nil if not found.
+func (db *MongoDbBridge) SubmittedTransaction(hash *common.Hash) (*types.SubmittedTransaction, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colSubmittedTrx)
+
+	sr := col.FindOne(ctx, bson.D{{Key: "_id", Value: hash.String()}})
+	if sr.Err() != nil {
+		if sr.Err() == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		db.log.Errorf("can not load submitted transaction %s; %s", hash.String(), sr.Err().Error())
+		return nil, sr.Err()
+	}
+
+	var st types.SubmittedTransaction
+	if err := sr.Decode(&st); err != nil {
+		db.log.Errorf("can not decode submitted transaction %s; %s", hash.String(), err.Error())
+		return nil, err
+	}
+	return &st, nil
+}
+
+// SubmittedTransactionsPending loads the submitted transactions waiting to be mined, oldest first.
+func (db *MongoDbBridge) SubmittedTransactionsPending() ([]*types.SubmittedTransaction, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colSubmittedTrx)
+
+	cursor, err := col.Find(ctx, bson.D{{Key: "status", Value: types.SubmittedTrxStatusPending}}, options.Find().
+		SetSort(bson.D{{Key: "sub", Value: 1}}).
+		SetLimit(submittedTrxPendingLimit))
+	if err != nil {
+		db.log.Errorf("can not load pending submitted transactions; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.SubmittedTransaction, 0)
+	for cursor.Next(ctx) {
+		var st types.SubmittedTransaction
+		if err := cursor.Decode(&st); err != nil {
+			db.log.Errorf("can not decode submitted transaction; %s", err.Error())
+			continue
+		}
+		list = append(list, &st)
+	}
+	return list, nil
+}
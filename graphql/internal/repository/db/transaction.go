@@ -6,9 +6,11 @@ import (
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 const (
@@ -39,50 +41,71 @@ const (
 
 	// fiTransactionTimeStamp is the name of the field of the transaction time stamp.
 	fiTransactionTimeStamp = "stamp"
+
+	// fiTransactionAmount is the name of the field of the transaction value in gWei.
+	fiTransactionAmount = "amo"
+
+	// fiTransactionStatus is the name of the field of the transaction execution status.
+	fiTransactionStatus = "stat"
+
+	// fiTransactionSelector is the name of the field of the 4-byte method selector of the call.
+	// db.transaction.createIndex({sel:1,orx:-1},{sparse:true})
+	fiTransactionSelector = "sel"
 )
 
-// initTransactionsCollection initializes the transaction collection with
-// indexes and additional parameters needed by the app.
-func (db *MongoDbBridge) initTransactionsCollection(col *mongo.Collection) {
-	// prepare index models
-	ix := make([]mongo.IndexModel, 0)
+// transactionCollectionIndexes provides a list of indexes expected to exist on the transactions' collection.
+// The names of the indexes created before they were named explicitly follow the default naming of the database.
+func transactionCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 7)
+	unique := true
 
 	// index ordinal key sorted from high to low since this is the way we usually list
-	unique := true
-	ix = append(ix, mongo.IndexModel{
-		Keys: bson.D{{Key: fiTransactionOrdinalIndex, Value: -1}},
-		Options: &options.IndexOptions{
-			Unique: &unique,
-		},
-	})
+	ixOrdinal := "orx_-1"
+	ix[0] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiTransactionOrdinalIndex, Value: -1}},
+		Options: &options.IndexOptions{Name: &ixOrdinal, Unique: &unique},
+	}
+
+	// index sender, recipient and time stamp
+	ixSender := "from_1"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: fiTransactionSender, Value: 1}}, Options: &options.IndexOptions{Name: &ixSender}}
+
+	ixRecipient := "to_1"
+	ix[2] = mongo.IndexModel{Keys: bson.D{{Key: fiTransactionRecipient, Value: 1}}, Options: &options.IndexOptions{Name: &ixRecipient}}
 
-	// index sender and recipient
-	ix = append(ix, mongo.IndexModel{Keys: bson.D{{Key: fiTransactionSender, Value: 1}}})
-	ix = append(ix, mongo.IndexModel{Keys: bson.D{{Key: fiTransactionRecipient, Value: 1}}})
-	ix = append(ix, mongo.IndexModel{Keys: bson.D{{Key: fiTransactionTimeStamp, Value: 1}}})
+	ixTimeStamp := "stamp_1"
+	ix[3] = mongo.IndexModel{Keys: bson.D{{Key: fiTransactionTimeStamp, Value: 1}}, Options: &options.IndexOptions{Name: &ixTimeStamp}}
 
 	// sender + ordinal index
 	fox := "from_orx"
-	ix = append(ix, mongo.IndexModel{
-		Keys: bson.D{{Key: fiTransactionSender, Value: 1}, {Key: fiTransactionOrdinalIndex, Value: -1}},
-		Options: &options.IndexOptions{
-			Name:   &fox,
-			Unique: &unique,
-		},
-	})
+	ix[4] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiTransactionSender, Value: 1}, {Key: fiTransactionOrdinalIndex, Value: -1}},
+		Options: &options.IndexOptions{Name: &fox, Unique: &unique},
+	}
 
 	// recipient + ordinal index
 	rox := "to_orx"
-	ix = append(ix, mongo.IndexModel{
-		Keys: bson.D{{Key: fiTransactionRecipient, Value: 1}, {Key: fiTransactionOrdinalIndex, Value: -1}},
-		Options: &options.IndexOptions{
-			Name:   &rox,
-			Unique: &unique,
-		},
-	})
+	ix[5] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiTransactionRecipient, Value: 1}, {Key: fiTransactionOrdinalIndex, Value: -1}},
+		Options: &options.IndexOptions{Name: &rox, Unique: &unique},
+	}
+
+	// method selector + ordinal index; transactions without call data are not indexed
+	sox := "sel_orx"
+	sparse := true
+	ix[6] = mongo.IndexModel{
+		Keys:    bson.D{{Key: fiTransactionSelector, Value: 1}, {Key: fiTransactionOrdinalIndex, Value: -1}},
+		Options: &options.IndexOptions{Name: &sox, Sparse: &sparse},
+	}
 
+	return ix
+}
+
+// initTransactionsCollection initializes the transaction collection with
+// indexes and additional parameters needed by the app.
+func (db *MongoDbBridge) initTransactionsCollection(col *mongo.Collection) {
 	// create indexes
-	if _, err := col.Indexes().CreateMany(context.Background(), ix); err != nil {
+	if _, err := col.Indexes().CreateMany(context.Background(), transactionCollectionIndexes()); err != nil {
 		db.log.Panicf("can not create indexes for transaction collection; %s", err.Error())
 	}
 
@@ -90,6 +113,29 @@ func (db *MongoDbBridge) initTransactionsCollection(col *mongo.Collection) {
 	db.log.Debugf("transactions collection initialized")
 }
 
+// MigrateTransactionSelectors sets the method selector of transactions stored before the selector was kept.
+// Transactions with large input data are skipped, their input is not stored in the database.
+func (db *MongoDbBridge) MigrateTransactionSelectors() error {
+	col := db.client.Database(db.dbName).Collection(coTransactions)
+
+	return db.migrateDocuments(col, bson.D{
+		{Key: fiTransactionSelector, Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "large", Value: false},
+		{Key: "input", Value: bson.D{{Key: "$ne", Value: nil}}},
+	}, func(cursor *mongo.Cursor) (bson.D, error) {
+		var row struct {
+			Input []byte `bson:"input"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		if len(row.Input) < 4 {
+			return nil, nil
+		}
+		return bson.D{{Key: fiTransactionSelector, Value: hexutil.Encode(row.Input[:4])}}, nil
+	})
+}
+
 // shouldAddTransaction validates if the transaction should be added to the persistent storage.
 func (db *MongoDbBridge) shouldAddTransaction(col *mongo.Collection, trx *types.Transaction) bool {
 	// check if the transaction already exists
@@ -105,6 +151,9 @@ func (db *MongoDbBridge) shouldAddTransaction(col *mongo.Collection, trx *types.
 
 // AddTransaction stores a transaction reference in connected persistent storage.
 func (db *MongoDbBridge) AddTransaction(block *types.Block, trx *types.Transaction) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// do we have all needed data?
 	if block == nil || trx == nil {
 		return fmt.Errorf("can not add empty transaction")
@@ -120,13 +169,14 @@ func (db *MongoDbBridge) AddTransaction(block *types.Block, trx *types.Transacti
 	}
 
 	// try to do the insert
-	if _, err := col.InsertOne(context.Background(), trx); err != nil {
+	if _, err := col.InsertOne(ctx, trx); err != nil {
 		db.log.Critical(err)
 		return err
 	}
 
 	// add transaction to the db
 	db.log.Debugf("transaction %s added to database", trx.Hash.String())
+	db.pushStoreEvent(coTransactions, trx)
 
 	// make sure transactions collection is initialized
 	if db.initTransactions != nil {
@@ -138,12 +188,15 @@ func (db *MongoDbBridge) AddTransaction(block *types.Block, trx *types.Transacti
 
 // UpdateTransaction updates transaction data in the database collection.
 func (db *MongoDbBridge) UpdateTransaction(col *mongo.Collection, trx *types.Transaction) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// notify
 	db.log.Debugf("updating transaction %s", trx.Hash.String())
 
 	// try to update a delegation by replacing it in the database
 	// we use address and validator ID to identify unique delegation
-	er, err := col.UpdateOne(context.Background(), bson.D{
+	er, err := col.UpdateOne(ctx, bson.D{
 		{Key: fiTransactionPk, Value: trx.Hash.String()},
 	}, bson.D{{Key: "$set", Value: bson.D{
 		{Key: fiTransactionOrdinalIndex, Value: trx.Uid()},
@@ -165,8 +218,11 @@ func (db *MongoDbBridge) UpdateTransaction(col *mongo.Collection, trx *types.Tra
 
 // IsTransactionKnown checks if a transaction document already exists in the database.
 func (db *MongoDbBridge) IsTransactionKnown(col *mongo.Collection, hash *common.Hash) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// try to find the transaction in the database (it may already exist)
-	sr := col.FindOne(context.Background(), bson.D{
+	sr := col.FindOne(ctx, bson.D{
 		{Key: fiTransactionPk, Value: hash.String()},
 	}, options.FindOne().SetProjection(bson.D{
 		{Key: fiTransactionPk, Value: true},
@@ -191,14 +247,14 @@ func (db *MongoDbBridge) IsTransactionKnown(col *mongo.Collection, hash *common.
 }
 
 // initTrxList initializes list of transactions based on provided cursor and count.
-func (db *MongoDbBridge) initTrxList(col *mongo.Collection, cursor *string, count int32, filter *bson.D) (*types.TransactionList, error) {
+func (db *MongoDbBridge) initTrxList(ctx context.Context, col *mongo.Collection, cursor *string, count int32, filter *bson.D) (*types.TransactionList, error) {
 	// make sure some filter is used
 	if nil == filter {
 		filter = &bson.D{}
 	}
 
 	// find how many transactions do we have in the database
-	total, err := db.listDocumentsCount(col, filter)
+	total, err := db.listDocumentsCount(ctx, col, filter)
 	if err != nil {
 		db.log.Errorf("can not count transactions")
 		return nil, err
@@ -218,7 +274,7 @@ func (db *MongoDbBridge) initTrxList(col *mongo.Collection, cursor *string, coun
 
 	// is the list non-empty? return the list with properly calculated range marks
 	if 0 < total {
-		return db.trxListWithRangeMarks(col, &list, cursor, count, filter)
+		return db.trxListWithRangeMarks(ctx, col, &list, cursor, count, filter)
 	}
 
 	// this is an empty list
@@ -228,6 +284,7 @@ func (db *MongoDbBridge) initTrxList(col *mongo.Collection, cursor *string, coun
 
 // trxListWithRangeMarks returns the transaction list with proper First/Last marks of the transaction range.
 func (db *MongoDbBridge) trxListWithRangeMarks(
+	ctx context.Context,
 	col *mongo.Collection,
 	list *types.TransactionList,
 	cursor *string,
@@ -239,21 +296,21 @@ func (db *MongoDbBridge) trxListWithRangeMarks(
 	// find out the cursor ordinal index
 	if cursor == nil && count > 0 {
 		// get the highest available ordinal index (top transaction)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			*filter,
 			options.FindOne().SetSort(bson.D{{Key: fiTransactionOrdinalIndex, Value: -1}}))
 		list.IsStart = true
 
 	} else if cursor == nil && count < 0 {
 		// get the lowest available ordinal index (top transaction)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			*filter,
 			options.FindOne().SetSort(bson.D{{Key: fiTransactionOrdinalIndex, Value: 1}}))
 		list.IsEnd = true
 
 	} else if cursor != nil {
 		// get the highest available ordinal index (top transaction)
-		list.First, err = db.findBorderOrdinalIndex(col,
+		list.First, err = db.findBorderOrdinalIndex(ctx, col,
 			bson.D{{Key: fiTransactionPk, Value: *cursor}},
 			options.FindOne())
 	}
@@ -271,7 +328,7 @@ func (db *MongoDbBridge) trxListWithRangeMarks(
 
 // findBorderOrdinalIndex finds the highest, or lowest ordinal index in the collection.
 // For negative sort it will return highest and for positive sort it will return lowest available value.
-func (db *MongoDbBridge) findBorderOrdinalIndex(col *mongo.Collection, filter bson.D, opt *options.FindOneOptions) (uint64, error) {
+func (db *MongoDbBridge) findBorderOrdinalIndex(ctx context.Context, col *mongo.Collection, filter bson.D, opt *options.FindOneOptions) (uint64, error) {
 	// prep container
 	var row struct {
 		Value uint64 `bson:"orx"`
@@ -279,7 +336,7 @@ func (db *MongoDbBridge) findBorderOrdinalIndex(col *mongo.Collection, filter bs
 
 	// make sure we pull only what we need
 	opt.SetProjection(bson.D{{Key: "orx", Value: true}})
-	sr := col.FindOne(context.Background(), filter, opt)
+	sr := col.FindOne(ctx, filter, opt)
 
 	// try to decode
 	err := sr.Decode(&row)
@@ -341,10 +398,7 @@ func (db *MongoDbBridge) txListOptions(count int32) *options.FindOptions {
 }
 
 // txListLoad load the initialized list from database
-func (db *MongoDbBridge) txListLoad(col *mongo.Collection, cursor *string, count int32, list *types.TransactionList) error {
-	// get the context for loader
-	ctx := context.Background()
-
+func (db *MongoDbBridge) txListLoad(ctx context.Context, col *mongo.Collection, cursor *string, count int32, list *types.TransactionList) error {
 	// load the data
 	ld, err := col.Find(ctx, db.txListFilter(cursor, count, list), db.txListOptions(count))
 	if err != nil {
@@ -384,13 +438,102 @@ func (db *MongoDbBridge) txListLoad(col *mongo.Collection, cursor *string, count
 	return nil
 }
 
+// TransactionRangeFilter provides the transaction list filter limiting transactions to the given block and time range.
+func TransactionRangeFilter(rf *types.RangeFilter) *bson.D {
+	filter := withRangeFilter(bson.D{}, trxRangeConditions(rf))
+	return &filter
+}
+
+// trxRangeConditions provides the filter conditions limiting transactions to the given block and time range.
+// Block limits are applied to the ordinal index, which is derived from the block number.
+func trxRangeConditions(rf *types.RangeFilter) bson.A {
+	cond := bson.A{}
+	if rf.IsEmpty() {
+		return cond
+	}
+
+	if rf.FromBlock != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionOrdinalIndex, Value: bson.D{{Key: "$gte", Value: *rf.FromBlock << 14}}}})
+	}
+	if rf.ToBlock != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionOrdinalIndex, Value: bson.D{{Key: "$lte", Value: (*rf.ToBlock << 14) | 0x3fff}}}})
+	}
+	if rf.FromTime != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionTimeStamp, Value: bson.D{{Key: "$gte", Value: time.Unix(int64(*rf.FromTime), 0).UTC()}}}})
+	}
+	if rf.ToTime != nil {
+		cond = append(cond, bson.D{{Key: fiTransactionTimeStamp, Value: bson.D{{Key: "$lte", Value: time.Unix(int64(*rf.ToTime), 0).UTC()}}}})
+	}
+	return cond
+}
+
+// withRangeFilter extends the given list filter with the block and time range conditions.
+// The conditions are wrapped in $and, so they don't collide with the ordinal index condition
+// added to the filter by the list loader.
+func withRangeFilter(filter bson.D, cond bson.A) bson.D {
+	if len(cond) == 0 {
+		return filter
+	}
+
+	// extend the existing conjunction, if any, so the filter never holds the same key twice
+	for i, e := range filter {
+		if and, ok := e.Value.(bson.A); ok && e.Key == "$and" {
+			filter[i].Value = append(and, cond...)
+			return filter
+		}
+	}
+	return append(filter, bson.E{Key: "$and", Value: cond})
+}
+
+// TransactionBlocksAround finds the numbers of blocks of the last transaction made before the given time
+// and the first transaction made after the given time. Zero is provided for a block not found.
+func (db *MongoDbBridge) TransactionBlocksAround(ts uint64) (before uint64, after uint64, err error) {
+	col := db.client.Database(db.dbName).Collection(coTransactions)
+	stamp := time.Unix(int64(ts), 0).UTC()
+
+	before, err = db.transactionBlockAt(col, bson.D{{Key: fiTransactionTimeStamp, Value: bson.D{{Key: "$lt", Value: stamp}}}}, -1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	after, err = db.transactionBlockAt(col, bson.D{{Key: fiTransactionTimeStamp, Value: bson.D{{Key: "$gt", Value: stamp}}}}, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+// transactionBlockAt provides the block number of the first transaction matching the filter
+// in the given time stamp sort order; zero if not found.
+func (db *MongoDbBridge) transactionBlockAt(col *mongo.Collection, filter bson.D, sort int) (uint64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	var row struct {
+		Block uint64 `bson:"blk"`
+	}
+
+	err := col.FindOne(ctx, filter, options.FindOne().
+		SetSort(bson.D{{Key: fiTransactionTimeStamp, Value: sort}}).
+		SetProjection(bson.D{{Key: fiTransactionBlock, Value: true}})).Decode(&row)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+
+		db.log.Errorf("can not find transaction block by time; %s", err.Error())
+		return 0, err
+	}
+	return row.Block, nil
+}
+
 // TransactionsCount returns the number of transactions stored in the database.
 func (db *MongoDbBridge) TransactionsCount() (uint64, error) {
 	return db.EstimateCount(db.client.Database(db.dbName).Collection(coTransactions))
 }
 
 // Transactions pulls list of transaction hashes starting on the specified cursor.
-func (db *MongoDbBridge) Transactions(cursor *string, count int32, filter *bson.D) (*types.TransactionList, error) {
+func (db *MongoDbBridge) Transactions(ctx context.Context, cursor *string, count int32, filter *bson.D) (*types.TransactionList, error) {
 	// nothing to load?
 	if count == 0 {
 		return nil, fmt.Errorf("nothing to do, zero transactions requested")
@@ -398,9 +541,11 @@ func (db *MongoDbBridge) Transactions(cursor *string, count int32, filter *bson.
 
 	// get the collection and context
 	col := db.client.Database(db.dbName).Collection(coTransactions)
+	ctx, cancel := db.opContext(ctx)
+	defer cancel()
 
 	// init the list
-	list, err := db.initTrxList(col, cursor, count, filter)
+	list, err := db.initTrxList(ctx, col, cursor, count, filter)
 	if err != nil {
 		db.log.Errorf("can not build transactions list; %s", err.Error())
 		return nil, err
@@ -408,7 +553,7 @@ func (db *MongoDbBridge) Transactions(cursor *string, count int32, filter *bson.
 
 	// load data if there are any
 	if list.Total > 0 {
-		err = db.txListLoad(col, cursor, count, list)
+		err = db.txListLoad(ctx, col, cursor, count, list)
 		if err != nil {
 			db.log.Errorf("can not load transactions list from database; %s", err.Error())
 			return nil, err
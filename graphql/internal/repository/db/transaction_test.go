@@ -0,0 +1,125 @@
+package db
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+// u64 provides a reference to the given value.
+func u64(v uint64) *uint64 {
+	return &v
+}
+
+func TestTrxRangeConditions(t *testing.T) {
+	tests := []struct {
+		name string
+		rf   *types.RangeFilter
+		want bson.A
+	}{
+		{name: "nil range", rf: nil, want: bson.A{}},
+		{name: "empty range", rf: &types.RangeFilter{}, want: bson.A{}},
+		{
+			name: "block range",
+			rf:   &types.RangeFilter{FromBlock: u64(10), ToBlock: u64(12)},
+			want: bson.A{
+				bson.D{{Key: "orx", Value: bson.D{{Key: "$gte", Value: uint64(10 << 14)}}}},
+				bson.D{{Key: "orx", Value: bson.D{{Key: "$lte", Value: uint64(12<<14 | 0x3fff)}}}},
+			},
+		},
+		{
+			name: "time range",
+			rf:   &types.RangeFilter{FromTime: u64(1600000000), ToTime: u64(1600000060)},
+			want: bson.A{
+				bson.D{{Key: "stamp", Value: bson.D{{Key: "$gte", Value: time.Unix(1600000000, 0).UTC()}}}},
+				bson.D{{Key: "stamp", Value: bson.D{{Key: "$lte", Value: time.Unix(1600000060, 0).UTC()}}}},
+			},
+		},
+		{
+			name: "open ended",
+			rf:   &types.RangeFilter{ToBlock: u64(0)},
+			want: bson.A{
+				bson.D{{Key: "orx", Value: bson.D{{Key: "$lte", Value: uint64(0x3fff)}}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(trxRangeConditions(tt.rf)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestWithRangeFilter(t *testing.T) {
+	cond := bson.A{bson.D{{Key: "orx", Value: bson.D{{Key: "$gte", Value: uint64(1)}}}}}
+
+	tests := []struct {
+		name   string
+		filter bson.D
+		cond   bson.A
+		want   bson.D
+	}{
+		{
+			name:   "no conditions",
+			filter: bson.D{{Key: "from", Value: "0x1"}},
+			cond:   bson.A{},
+			want:   bson.D{{Key: "from", Value: "0x1"}},
+		},
+		{
+			name:   "new conjunction",
+			filter: bson.D{{Key: "from", Value: "0x1"}},
+			cond:   cond,
+			want:   bson.D{{Key: "from", Value: "0x1"}, {Key: "$and", Value: cond}},
+		},
+		{
+			name:   "existing conjunction extended",
+			filter: bson.D{{Key: "$and", Value: bson.A{bson.D{{Key: "to", Value: "0x2"}}}}},
+			cond:   cond,
+			want:   bson.D{{Key: "$and", Value: bson.A{bson.D{{Key: "to", Value: "0x2"}}, cond[0]}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(withRangeFilter(tt.filter, tt.cond)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestWithTokenTransactionRange(t *testing.T) {
+	tests := []struct {
+		name string
+		rf   *types.RangeFilter
+		want bson.D
+	}{
+		{name: "empty range", rf: &types.RangeFilter{}, want: bson.D{}},
+		{
+			name: "block range",
+			rf:   &types.RangeFilter{FromBlock: u64(1), ToBlock: u64(2)},
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: types.FiTokenTransactionPk, Value: bson.D{{Key: "$gte", Value: "0x0000000000000001000000000000"}}}},
+				bson.D{{Key: types.FiTokenTransactionPk, Value: bson.D{{Key: "$lte", Value: "0x0000000000000002ffffffffffff"}}}},
+			}}},
+		},
+		{
+			name: "time range",
+			rf:   &types.RangeFilter{FromTime: u64(1), ToTime: u64(2)},
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: types.FiTokenTransactionOrdinal, Value: bson.D{{Key: "$gte", Value: uint64(1 << 24)}}}},
+				bson.D{{Key: types.FiTokenTransactionOrdinal, Value: bson.D{{Key: "$lte", Value: uint64(2<<24 | 0xffffff)}}}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(WithTokenTransactionRange(bson.D{}, tt.rf)).To(gomega.Equal(tt.want))
+		})
+	}
+}
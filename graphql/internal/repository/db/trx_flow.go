@@ -25,11 +25,13 @@ const (
 
 // TrxDailyFlowList loads a range of daily trx volumes from the database.
 func (db *MongoDbBridge) TrxDailyFlowList(from *time.Time, to *time.Time) ([]*types.DailyTrxVolume, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// log what we do
 	db.log.Debugf("loading trx flow between %s and %s", from.String(), to.String())
 
-	// get the collection and context
-	ctx := context.Background()
+	// get the collection
 	col := db.client.Database(db.dbName).Collection(coTransactionVolume)
 
 	// pull the data; make sure there is a limit to the range
@@ -43,19 +45,21 @@ func (db *MongoDbBridge) TrxDailyFlowList(from *time.Time, to *time.Time) ([]*ty
 	defer db.closeCursor(ld)
 
 	// load the list
-	return loadTrxDailyFlowList(ld)
+	return loadTrxDailyFlowList(ctx, ld)
 }
 
 // TrxGasSpeed provides amount of gas consumed by transaction per second
 // in the given time range.
 func (db *MongoDbBridge) TrxGasSpeed(from *time.Time, to *time.Time) (float64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// check the time range
 	if !from.Before(*to) {
 		return 0.0, fmt.Errorf("invalid time range requested")
 	}
 
-	// get the collection and context
-	ctx := context.Background()
+	// get the collection
 	col := db.client.Database(db.dbName).Collection(coTransactions)
 
 	// aggregate the gas used from the given time range
@@ -73,13 +77,13 @@ func (db *MongoDbBridge) TrxGasSpeed(from *time.Time, to *time.Time) (float64, e
 
 	// close the cursor as we leave
 	defer db.closeCursor(cr)
-	return db.trxGasSpeed(cr, from, to)
+	return db.trxGasSpeed(ctx, cr, from, to)
 }
 
 // trxGasSpeed makes the gas speed calculation from the given aggregation cursor.
-func (db *MongoDbBridge) trxGasSpeed(cr *mongo.Cursor, from *time.Time, to *time.Time) (float64, error) {
+func (db *MongoDbBridge) trxGasSpeed(ctx context.Context, cr *mongo.Cursor, from *time.Time, to *time.Time) (float64, error) {
 	// get the row
-	if !cr.Next(context.Background()) {
+	if !cr.Next(ctx) {
 		db.log.Errorf("can not navigate gas speed results")
 		return 0.0, fmt.Errorf("gas speed aggregation failure")
 	}
@@ -99,6 +103,9 @@ func (db *MongoDbBridge) trxGasSpeed(cr *mongo.Cursor, from *time.Time, to *time
 
 // TrxRecentTrxSpeed provides the number of transaction per second on the defined range in seconds.
 func (db *MongoDbBridge) TrxRecentTrxSpeed(sec int32) (float64, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// make sure the request makes sense and calculate the left boundary
 	if sec < 60 {
 		sec = 60
@@ -107,7 +114,7 @@ func (db *MongoDbBridge) TrxRecentTrxSpeed(sec int32) (float64, error) {
 	col := db.client.Database(db.dbName).Collection(coTransactions)
 
 	// find how many transactions do we have in the database
-	total, err := col.CountDocuments(context.Background(), bson.D{
+	total, err := col.CountDocuments(ctx, bson.D{
 		{Key: fiTransactionTimeStamp, Value: bson.D{
 			{Key: "$gte", Value: from},
 		}},
@@ -144,9 +151,8 @@ func trxDailyFlowListFilter(from *time.Time, to *time.Time) *bson.D {
 }
 
 // loadTrxDailyFlowList load the trx flow list from provided DB cursor.
-func loadTrxDailyFlowList(ld *mongo.Cursor) ([]*types.DailyTrxVolume, error) {
+func loadTrxDailyFlowList(ctx context.Context, ld *mongo.Cursor) ([]*types.DailyTrxVolume, error) {
 	// prep the result list
-	ctx := context.Background()
 	list := make([]*types.DailyTrxVolume, 0)
 
 	// loop and load
@@ -166,6 +172,9 @@ func loadTrxDailyFlowList(ld *mongo.Cursor) ([]*types.DailyTrxVolume, error) {
 // TrxDailyFlowUpdate performs an update on the daily trx flow data
 // for the given date range directly.
 func (db *MongoDbBridge) TrxDailyFlowUpdate(from time.Time) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
 	// log what we do
 	db.log.Noticef("updating trx flow after %s", from)
 
@@ -173,7 +182,7 @@ func (db *MongoDbBridge) TrxDailyFlowUpdate(from time.Time) error {
 	col := db.client.Database(db.dbName).Collection(coTransactions)
 
 	// get the collection
-	cr, err := col.Aggregate(context.Background(), mongo.Pipeline{
+	cr, err := col.Aggregate(ctx, mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{
 			{Key: "stamp", Value: bson.D{{Key: "$gte", Value: from}}},
 		}}},
@@ -207,7 +216,7 @@ func (db *MongoDbBridge) TrxDailyFlowUpdate(from time.Time) error {
 	}
 
 	// close the cursor, we don't really need the data
-	if err := cr.Close(context.Background()); err != nil {
+	if err := cr.Close(ctx); err != nil {
 		db.log.Errorf("can not close aggregate cursor; %s", err.Error())
 	}
 	return nil
@@ -0,0 +1,208 @@
+// Package db implements bridge to persistent storage represented by Mongo database.
+package db
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	// colWebhooks represents the name of the webhooks collection.
+	colWebhooks = "webhooks"
+
+	// colWebhookDeliveries represents the name of the webhook deliveries collection.
+	colWebhookDeliveries = "webhook_deliveries"
+
+	// webhookDeliveriesDueLimit represents the max number of due deliveries loaded at once.
+	webhookDeliveriesDueLimit = 250
+)
+
+// webhookCollectionIndexes provides a list of indexes expected to exist on the webhook deliveries collection.
+func webhookCollectionIndexes() []mongo.IndexModel {
+	ix := make([]mongo.IndexModel, 2)
+
+	ixDue := "ix_whd_status_next"
+	ix[0] = mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next", Value: 1}}, Options: &options.IndexOptions{Name: &ixDue}}
+
+	ixHook := "ix_whd_hook_created"
+	ix[1] = mongo.IndexModel{Keys: bson.D{{Key: "hook", Value: 1}, {Key: "created", Value: -1}}, Options: &options.IndexOptions{Name: &ixHook}}
+
+	return ix
+}
+
+// StoreWebhook stores, or updates, the given webhook in the persistent database.
+func (db *MongoDbBridge) StoreWebhook(wh *types.Webhook) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhooks)
+
+	_, err := col.UpdateByID(ctx, wh.ID, bson.D{
+		{Key: "$set", Value: wh},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not store webhook %s; %s", wh.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteWebhook removes the given webhook and its delivery log from the persistent database.
+func (db *MongoDbBridge) DeleteWebhook(id string) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhooks)
+	if _, err := col.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}}); err != nil {
+		db.log.Errorf("can not delete webhook %s; %s", id, err.Error())
+		return err
+	}
+
+	col = db.client.Database(db.dbName).Collection(colWebhookDeliveries)
+	if _, err := col.DeleteMany(ctx, bson.D{{Key: "hook", Value: id}}); err != nil {
+		db.log.Errorf("can not delete deliveries of webhook %s; %s", id, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Webhook loads the webhook of the given ID; nil if not found.
+func (db *MongoDbBridge) Webhook(id string) (*types.Webhook, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhooks)
+
+	sr := col.FindOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if sr.Err() != nil {
+		if sr.Err() == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		db.log.Errorf("can not load webhook %s; %s", id, sr.Err().Error())
+		return nil, sr.Err()
+	}
+
+	var wh types.Webhook
+	if err := sr.Decode(&wh); err != nil {
+		db.log.Errorf("can not decode webhook %s; %s", id, err.Error())
+		return nil, err
+	}
+	return &wh, nil
+}
+
+// Webhooks loads all the registered webhooks, oldest first.
+func (db *MongoDbBridge) Webhooks() ([]*types.Webhook, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhooks)
+
+	cursor, err := col.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created", Value: 1}}))
+	if err != nil {
+		db.log.Errorf("can not load webhooks; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.Webhook, 0)
+	for cursor.Next(ctx) {
+		var wh types.Webhook
+		if err := cursor.Decode(&wh); err != nil {
+			db.log.Errorf("can not decode webhook; %s", err.Error())
+			continue
+		}
+		list = append(list, &wh)
+	}
+	return list, nil
+}
+
+// AddWebhookDelivery inserts the given webhook delivery into the persistent database.
+// A delivery of the same ID already known is left untouched so re-scanned events
+// are not delivered twice. The function reports if the delivery has been added.
+func (db *MongoDbBridge) AddWebhookDelivery(wd *types.WebhookDelivery) (bool, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhookDeliveries)
+
+	res, err := col.UpdateByID(ctx, wd.ID, bson.D{
+		{Key: "$setOnInsert", Value: wd},
+	}, options.Update().SetUpsert(true))
+
+	if err != nil {
+		db.log.Errorf("can not add webhook delivery %s; %s", wd.ID, err.Error())
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+// UpdateWebhookDelivery updates the state of the given webhook delivery in the persistent database.
+func (db *MongoDbBridge) UpdateWebhookDelivery(wd *types.WebhookDelivery) error {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhookDeliveries)
+
+	_, err := col.UpdateByID(ctx, wd.ID, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: wd.Status},
+			{Key: "attempts", Value: wd.Attempts},
+			{Key: "code", Value: wd.ResponseCode},
+			{Key: "error", Value: wd.LastError},
+			{Key: "last", Value: wd.LastAttempt},
+			{Key: "next", Value: wd.NextAttempt},
+		}},
+	})
+
+	if err != nil {
+		db.log.Errorf("can not update webhook delivery %s; %s", wd.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// WebhookDeliveriesDue loads pending webhook deliveries scheduled for an attempt by the given time.
+func (db *MongoDbBridge) WebhookDeliveriesDue(ts time.Time) ([]*types.WebhookDelivery, error) {
+	return db.webhookDeliveries(bson.D{
+		{Key: "status", Value: types.WebhookDeliveryPending},
+		{Key: "next", Value: bson.D{{Key: "$lte", Value: ts}}},
+	}, options.Find().SetSort(bson.D{{Key: "next", Value: 1}}).SetLimit(webhookDeliveriesDueLimit))
+}
+
+// WebhookDeliveries loads the most recent deliveries of the given webhook.
+func (db *MongoDbBridge) WebhookDeliveries(hook string, count int64) ([]*types.WebhookDelivery, error) {
+	return db.webhookDeliveries(bson.D{{Key: "hook", Value: hook}},
+		options.Find().SetSort(bson.D{{Key: "created", Value: -1}}).SetLimit(count))
+}
+
+// webhookDeliveries loads webhook deliveries matching the given filter.
+func (db *MongoDbBridge) webhookDeliveries(filter bson.D, opt *options.FindOptions) ([]*types.WebhookDelivery, error) {
+	ctx, cancel := db.opContext(context.Background())
+	defer cancel()
+
+	col := db.client.Database(db.dbName).Collection(colWebhookDeliveries)
+
+	cursor, err := col.Find(ctx, filter, opt)
+	if err != nil {
+		db.log.Errorf("can not load webhook deliveries; %s", err.Error())
+		return nil, err
+	}
+	defer db.closeCursor(cursor)
+
+	list := make([]*types.WebhookDelivery, 0)
+	for cursor.Next(ctx) {
+		var wd types.WebhookDelivery
+		if err := cursor.Decode(&wd); err != nil {
+			db.log.Errorf("can not decode webhook delivery; %s", err.Error())
+			continue
+		}
+		list = append(list, &wd)
+	}
+	return list, nil
+}
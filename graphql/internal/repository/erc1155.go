@@ -11,6 +11,16 @@ func (p *proxy) Erc1155Contract(addr *common.Address) (*types.Erc1155Contract, e
 	return &types.Erc1155Contract{Address: *addr}, nil
 }
 
+// Erc1155Name provides the name of the multi-token, if the contract provides it.
+func (p *proxy) Erc1155Name(token *common.Address) (string, error) {
+	return p.rpc.Erc1155Name(token)
+}
+
+// Erc1155Symbol provides the symbol of the multi-token, if the contract provides it.
+func (p *proxy) Erc1155Symbol(token *common.Address) (string, error) {
+	return p.rpc.Erc1155Symbol(token)
+}
+
 // Erc1155Uri provides URI of Metadata JSON Schema of the token.
 func (p *proxy) Erc1155Uri(token *common.Address, tokenId *big.Int) (string, error) {
 	return p.rpc.Erc1155Uri(token, tokenId)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fantom-api-graphql/internal/config"
 	"fantom-api-graphql/internal/repository/cache"
 	"fantom-api-graphql/internal/types"
@@ -43,6 +44,37 @@ func (p *proxy) Erc20Token(addr *common.Address) (*types.Erc20Token, error) {
 	return val.(*types.Erc20Token), nil
 }
 
+// Erc20Tokens returns ERC20 tokens of the given addresses mapped by the address.
+// Tokens missing in cache are loaded from the node by a single batch call.
+func (p *proxy) Erc20Tokens(ctx context.Context, addr []common.Address) (map[common.Address]*types.Erc20Token, error) {
+	res := make(map[common.Address]*types.Erc20Token, len(addr))
+	miss := make([]common.Address, 0, len(addr))
+	for i := range addr {
+		if token := p.cache.PullErc20Token(&addr[i]); token != nil {
+			res[addr[i]] = token
+			continue
+		}
+		miss = append(miss, addr[i])
+	}
+
+	if len(miss) == 0 {
+		return res, nil
+	}
+
+	list, err := p.rpc.Erc20Tokens(ctx, miss)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range list {
+		if err = p.cache.PushErc20Token(token); err != nil {
+			p.log.Errorf("can not keep ERC20 token %s in cache; %s", token.Address.String(), err.Error())
+		}
+		res[token.Address] = token
+	}
+	return res, nil
+}
+
 // loadErc20TokenDetails loads details of the given ERC20 token using ERC20
 // contract calls.
 func (p *proxy) loadErc20TokenDetails(token *types.Erc20Token) (*types.Erc20Token, error) {
@@ -9,6 +9,8 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"context"
+	"fantom-api-graphql/internal/repository/db"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -28,7 +30,20 @@ func (p *proxy) TokenTransactionsByCall(trxHash *common.Hash) ([]*types.TokenTra
 }
 
 // TokenTransactions provides list of ERC20/ERC721/ERC1155 transactions based on given filters.
-func (p *proxy) TokenTransactions(tokenType string, token *common.Address, tokenId *big.Int, acc *common.Address, txType []int32, cursor *string, count int32) (*types.TokenTransactionList, error) {
+func (p *proxy) TokenTransactions(ctx context.Context, tokenType string, token *common.Address, tokenId *big.Int, acc *common.Address, txType []int32, rf *types.RangeFilter, tf *types.TokenTransactionFilter, cursor *string, count int32) (*types.TokenTransactionList, error) {
+	fi := tokenTransactionsFilter(tokenType, token, tokenId, acc, txType, rf, tf)
+	return p.db.Erc20Transactions(ctx, cursor, count, &fi)
+}
+
+// TokenTransactionsPage provides an offset addressed page of ERC20/ERC721/ERC1155 transactions
+// of the given token and/or account within the given block and time range.
+func (p *proxy) TokenTransactionsPage(ctx context.Context, tokenType string, token *common.Address, acc *common.Address, rf *types.RangeFilter, skip int64, limit int64, asc bool) ([]*types.TokenTransaction, error) {
+	fi := tokenTransactionsFilter(tokenType, token, nil, acc, nil, rf, nil)
+	return p.db.TokenTransactionsPage(ctx, &fi, skip, limit, asc)
+}
+
+// tokenTransactionsFilter builds the token transactions list filter from the given conditions.
+func tokenTransactionsFilter(tokenType string, token *common.Address, tokenId *big.Int, acc *common.Address, txType []int32, rf *types.RangeFilter, tf *types.TokenTransactionFilter) bson.D {
 	// prep the filter
 	fi := bson.D{}
 
@@ -79,8 +94,13 @@ func (p *proxy) TokenTransactions(tokenType string, token *common.Address, token
 		})
 	}
 
-	// do loading
-	return p.db.Erc20Transactions(cursor, count, &fi)
+	// block and time range
+	fi = db.WithTokenTransactionRange(fi, rf)
+
+	// tokens and amount range
+	fi = db.WithTokenTransactionFilter(fi, tf)
+
+	return fi
 }
 
 // Erc20Assets provides a list of known assets for the given owner.
@@ -0,0 +1,27 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ExportAccountTransactions streams transactions of the given account within the given range
+// ordered from older to newer into the callback; at most limit transactions are streamed.
+func (p *proxy) ExportAccountTransactions(ctx context.Context, addr *common.Address, rf *types.RangeFilter, limit int64, fn func(*types.Transaction) error) error {
+	return p.db.ExportAccountTransactions(ctx, addr, rf, limit, fn)
+}
+
+// ExportTokenTransactions streams token transactions of the given account within the given range
+// ordered from older to newer into the callback; at most limit transactions are streamed.
+func (p *proxy) ExportTokenTransactions(ctx context.Context, addr *common.Address, rf *types.RangeFilter, limit int64, fn func(*types.TokenTransaction) error) error {
+	return p.db.ExportTokenTransactions(ctx, addr, rf, limit, fn)
+}
@@ -0,0 +1,371 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"errors"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	// faucetDefaultGas represents the gas limit used if the faucet transfer can not be estimated.
+	faucetDefaultGas = 21000
+
+	// faucetPendingTimeout represents the time after which a faucet request still pending
+	// is reconciled, the request is expected to be sent within a few seconds.
+	faucetPendingTimeout = 2 * time.Minute
+)
+
+var (
+	// ErrFaucetDisabled signals the faucet is not enabled on this server.
+	ErrFaucetDisabled = errors.New("faucet is not enabled")
+
+	// ErrFaucetAddressLimit signals the recipient address exceeded the faucet rate limit.
+	ErrFaucetAddressLimit = errors.New("too many faucet requests for the address, please try again later")
+
+	// ErrFaucetIpLimit signals the client exceeded the faucet rate limit.
+	ErrFaucetIpLimit = errors.New("too many faucet requests from your address, please try again later")
+
+	// ErrFaucetBudgetExhausted signals the faucet daily budget has been exhausted.
+	ErrFaucetBudgetExhausted = errors.New("faucet daily budget exhausted, please try again tomorrow")
+)
+
+// faucetSender keeps the chain id used to sign the faucet transfers.
+// The rate limits, the budget and the account nonce are reserved in the database
+// so the faucet can be served by several API instances sharing the sender account.
+type faucetSender struct {
+	mu      sync.Mutex
+	chainID *big.Int
+}
+
+// faucetReservation represents the rate limit slots and the budget reserved for a faucet request.
+type faucetReservation struct {
+	ts    time.Time
+	slots []string
+	value int64
+}
+
+// RequestFunds sends the configured amount of native tokens from the server account
+// to the given address, if the rate limits and the daily budget of the faucet allow it.
+func (p *proxy) RequestFunds(ctx context.Context, addr *common.Address, ip string) (*types.FaucetRequest, error) {
+	if !p.cfg.Faucet.Enabled || p.cfg.Signature.PrivateKey == nil {
+		return nil, ErrFaucetDisabled
+	}
+
+	// the limits are reserved atomically before the transfer is sent
+	// so parallel requests can not get over the limits
+	amount := ftmToWei(p.cfg.Faucet.Amount)
+	fr := types.FaucetRequest{
+		ID:        primitive.NewObjectID().Hex(),
+		Recipient: *addr,
+		ClientIP:  ip,
+		Amount:    hexutil.Big(*amount),
+		TimeStamp: time.Now().UTC(),
+	}
+
+	res, err := p.faucetReserve(ctx, &fr)
+	if err != nil {
+		return nil, err
+	}
+
+	from := crypto.PubkeyToAddress(p.cfg.Signature.PrivateKey.PublicKey)
+	if err := p.faucetReserveNonce(ctx, &from, &fr); err != nil {
+		p.faucetRelease(res)
+		return nil, err
+	}
+
+	if err := p.faucetSign(ctx, &fr); err != nil {
+		p.faucetReturnNonce(&from, &fr)
+		p.faucetRelease(res)
+		return nil, err
+	}
+
+	// the pending request keeps the record of the transfer even if it can not be finalized
+	if err := p.db.StoreFaucetRequest(ctx, &fr); err != nil {
+		p.faucetReturnNonce(&from, &fr)
+		p.faucetRelease(res)
+		return nil, err
+	}
+
+	hash, err := p.rpc.SendTransaction(ctx, fr.Raw)
+	if err != nil {
+		p.log.Errorf("faucet transfer to %s failed; %s", addr.String(), err.Error())
+
+		// the transfer may have reached the node before the request was abandoned, leave it to the reconciliation
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if err := p.db.DropFaucetRequest(context.Background(), fr.ID); err == nil {
+			p.faucetReturnNonce(&from, &fr)
+			p.faucetRelease(res)
+		}
+		return nil, err
+	}
+
+	p.faucetSent(&from, &fr, hash)
+	p.log.Noticef("faucet sent %s WEI to %s in %s", amount.String(), addr.String(), hash.String())
+	return &fr, nil
+}
+
+// FaucetRequests provides the list of the most recent faucet requests,
+// optionally limited to the given recipient address.
+func (p *proxy) FaucetRequests(ctx context.Context, addr *common.Address, count int32) ([]*types.FaucetRequest, error) {
+	return p.db.FaucetRequests(ctx, addr, int64(count))
+}
+
+// ReconcileFaucet resolves the faucet requests left pending longer than expected.
+// Transfers known to the node are finalized, transfers whose nonce has been used by another
+// transaction are failed, and the others are sent again. Nonces held by transfers abandoned
+// before their request was stored are returned to be reused.
+func (p *proxy) ReconcileFaucet() error {
+	if !p.cfg.Faucet.Enabled || p.cfg.Signature.PrivateKey == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	from := crypto.PubkeyToAddress(p.cfg.Signature.PrivateKey.PublicKey)
+	pending, err := p.rpc.AccountPendingNonce(ctx, &from)
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().UTC().Add(-faucetPendingTimeout)
+	list, err := p.db.FaucetRequestsPending(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[uint64]bool)
+	for _, fr := range list {
+		if err := p.faucetReconcile(ctx, &from, fr, pending); err != nil {
+			p.log.Errorf("faucet request %s not reconciled; %s", fr.ID, err.Error())
+			keep[fr.Nonce] = true
+		}
+	}
+	return p.db.FaucetReturnStaleNonces(ctx, &from, before, keep)
+}
+
+// faucetReconcile resolves the given pending faucet request against the pending nonce of the sender.
+func (p *proxy) faucetReconcile(ctx context.Context, from *common.Address, fr *types.FaucetRequest, pending uint64) error {
+	// requests stored before the signed transfer was kept are signed again
+	if len(fr.Raw) == 0 {
+		if fr.Nonce < pending {
+			return p.faucetFail(from, fr, false)
+		}
+		if err := p.faucetSign(ctx, fr); err != nil {
+			return err
+		}
+	}
+
+	var tx etc.Transaction
+	if err := tx.UnmarshalBinary(fr.Raw); err != nil {
+		return err
+	}
+
+	hash := tx.Hash()
+	if trx, err := p.rpc.Transaction(ctx, &hash); err == nil && trx != nil {
+		p.faucetSent(from, fr, &hash)
+		return nil
+	}
+
+	// the nonce has been used by another transaction
+	if fr.Nonce < pending {
+		return p.faucetFail(from, fr, false)
+	}
+
+	sent, err := p.rpc.SendTransaction(ctx, fr.Raw)
+	if err != nil {
+		p.log.Errorf("faucet transfer %s re-send failed; %s", fr.ID, err.Error())
+		return p.faucetFail(from, fr, true)
+	}
+
+	p.faucetSent(from, fr, sent)
+	p.log.Noticef("faucet re-sent %s WEI to %s in %s", fr.Amount.ToInt().String(), fr.Recipient.String(), sent.String())
+	return nil
+}
+
+// faucetSent finalizes the faucet request of the given transfer accepted by the node.
+func (p *proxy) faucetSent(from *common.Address, fr *types.FaucetRequest, hash *common.Hash) {
+	// the request is finalized even if it has been abandoned in the meantime
+	ctx := context.Background()
+
+	fr.Transaction = *hash
+	if err := p.db.FinalizeFaucetRequest(ctx, fr.ID, hash); err != nil {
+		p.log.Criticalf("faucet request %s sent in %s stays pending; %s", fr.ID, hash.String(), err.Error())
+		return
+	}
+	if err := p.db.FaucetNonceSent(ctx, from, fr.Nonce); err != nil {
+		p.log.Errorf("faucet nonce %d not released; %s", fr.Nonce, err.Error())
+	}
+}
+
+// faucetFail drops the given pending faucet request and releases its budget.
+// The nonce of the request is reused only if it has not been used by another transaction.
+func (p *proxy) faucetFail(from *common.Address, fr *types.FaucetRequest, reuse bool) error {
+	if err := p.db.DropFaucetRequest(context.Background(), fr.ID); err != nil {
+		return err
+	}
+	p.log.Warningf("faucet request %s to %s failed", fr.ID, fr.Recipient.String())
+
+	if reuse {
+		p.faucetReturnNonce(from, fr)
+	} else if err := p.db.FaucetNonceSent(context.Background(), from, fr.Nonce); err != nil {
+		p.log.Errorf("faucet nonce %d not released; %s", fr.Nonce, err.Error())
+	}
+
+	// the rate limit slots are not known here, they lapse with the faucet period
+	p.faucetRelease(&faucetReservation{ts: fr.TimeStamp, value: fr.Value()})
+	return nil
+}
+
+// faucetReserve reserves the per-address and per-client rate limit slots and the daily budget
+// for the given faucet request. Nothing stays reserved if any of the limits is exceeded.
+func (p *proxy) faucetReserve(ctx context.Context, fr *types.FaucetRequest) (*faucetReservation, error) {
+	res := faucetReservation{ts: fr.TimeStamp, slots: make([]string, 0, 2)}
+	since := fr.TimeStamp.Add(-p.cfg.Faucet.Period)
+
+	limits := []struct {
+		key   string
+		limit int64
+		err   error
+	}{
+		{key: "to/" + fr.Recipient.String(), limit: p.cfg.Faucet.AddressLimit, err: ErrFaucetAddressLimit},
+		{key: "ip/" + fr.ClientIP, limit: p.cfg.Faucet.IpLimit, err: ErrFaucetIpLimit},
+	}
+	for _, l := range limits {
+		slot, err := p.db.FaucetReserveSlot(ctx, l.key, l.limit, since, res.ts)
+		if err == nil && slot == "" {
+			err = l.err
+		}
+		if err != nil {
+			p.faucetRelease(&res)
+			return nil, err
+		}
+		res.slots = append(res.slots, slot)
+	}
+
+	// the budget is spent per calendar day
+	budget := new(big.Int).Div(ftmToWei(p.cfg.Faucet.DailyBudget), types.FaucetDecimalsCorrection).Int64()
+	ok, err := p.db.FaucetReserveBudget(ctx, res.ts, fr.Value(), budget)
+	if err == nil && !ok {
+		err = ErrFaucetBudgetExhausted
+	}
+	if err != nil {
+		p.faucetRelease(&res)
+		return nil, err
+	}
+
+	res.value = fr.Value()
+	return &res, nil
+}
+
+// faucetRelease frees the limits reserved for a faucet request which was not sent.
+// The limits are released even if the request has been abandoned.
+func (p *proxy) faucetRelease(res *faucetReservation) {
+	ctx := context.Background()
+	for _, slot := range res.slots {
+		if err := p.db.FaucetReleaseSlot(ctx, slot, res.ts); err != nil {
+			p.log.Errorf("faucet slot %s not released; %s", slot, err.Error())
+		}
+	}
+	if res.value > 0 {
+		if err := p.db.FaucetReleaseBudget(ctx, res.ts, res.value); err != nil {
+			p.log.Errorf("faucet budget not released; %s", err.Error())
+		}
+	}
+}
+
+// faucetReserveNonce reserves the nonce of the faucet sender account for the given request.
+func (p *proxy) faucetReserveNonce(ctx context.Context, from *common.Address, fr *types.FaucetRequest) error {
+	pending, err := p.rpc.AccountPendingNonce(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	fr.Nonce, err = p.db.FaucetNextNonce(ctx, from, pending)
+	return err
+}
+
+// faucetReturnNonce returns the nonce reserved for a faucet request which was not sent.
+func (p *proxy) faucetReturnNonce(from *common.Address, fr *types.FaucetRequest) {
+	if err := p.db.FaucetReturnNonce(context.Background(), from, fr.Nonce); err != nil {
+		p.log.Errorf("faucet nonce %d not returned; %s", fr.Nonce, err.Error())
+	}
+}
+
+// faucetSign signs the native tokens transfer of the given faucet request
+// from the server account using the nonce reserved for the request.
+func (p *proxy) faucetSign(ctx context.Context, fr *types.FaucetRequest) error {
+	from := crypto.PubkeyToAddress(p.cfg.Signature.PrivateKey.PublicKey)
+
+	chainID, err := p.faucetChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	price, err := p.rpc.GasPrice(ctx)
+	if err != nil {
+		return err
+	}
+
+	var gas uint64 = faucetDefaultGas
+	est, err := p.rpc.GasEstimate(ctx, &struct {
+		From  *common.Address
+		To    *common.Address
+		Value *hexutil.Big
+		Data  *string
+	}{From: &from, To: &fr.Recipient, Value: &fr.Amount})
+	if err == nil && est != nil {
+		gas = uint64(*est)
+	}
+
+	tx, err := etc.SignTx(etc.NewTx(&etc.LegacyTx{
+		Nonce:    fr.Nonce,
+		GasPrice: price.ToInt(),
+		Gas:      gas,
+		To:       &fr.Recipient,
+		Value:    fr.Amount.ToInt(),
+	}), etc.LatestSignerForChainID(chainID), p.cfg.Signature.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	fr.Raw, err = tx.MarshalBinary()
+	return err
+}
+
+// faucetChainID provides the chain id used to sign the faucet transfers.
+func (p *proxy) faucetChainID(ctx context.Context) (*big.Int, error) {
+	p.faucet.mu.Lock()
+	defer p.faucet.mu.Unlock()
+
+	if p.faucet.chainID == nil {
+		id, err := p.rpc.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.faucet.chainID = id
+	}
+	return p.faucet.chainID, nil
+}
+
+// ftmToWei converts the given amount of FTM to WEI units.
+func ftmToWei(ftm float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(ftm), big.NewFloat(1e18)).Int(nil)
+	return wei
+}
@@ -9,6 +9,8 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"context"
+	"encoding/json"
 	"fantom-api-graphql/internal/repository/p2p"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -24,13 +26,16 @@ import (
 // Repository interface defines functions the underlying implementation provides to API resolvers.
 type Repository interface {
 	// Account returns account at Opera blockchain for an address, nil if not found.
-	Account(*common.Address) (*types.Account, error)
+	Account(context.Context, *common.Address) (*types.Account, error)
+
+	// AccountsByAddress returns accounts at Opera blockchain for the given addresses mapped by the address.
+	AccountsByAddress(context.Context, []common.Address) (map[common.Address]*types.Account, error)
 
 	// AccountBalance returns the current balance of an account at Opera blockchain.
-	AccountBalance(*common.Address) (*hexutil.Big, error)
+	AccountBalance(context.Context, *common.Address) (*hexutil.Big, error)
 
 	// AccountNonce returns the current number of sent transactions of an account at Opera blockchain.
-	AccountNonce(*common.Address) (*hexutil.Uint64, error)
+	AccountNonce(context.Context, *common.Address) (*hexutil.Uint64, error)
 
 	// AccountTransactions returns list of transaction hashes for account at Opera blockchain.
 	//
@@ -45,8 +50,16 @@ type Repository interface {
 	// (or at the bottom without one) and loads at most defined number
 	// of transactions newer than that.
 	//
+	// The list is limited to the block and time range of the range filter, if any,
+	// and to transactions passing the advanced transaction filter, if any.
+	//
 	// Transactions are always sorted from newer to older.
-	AccountTransactions(*common.Address, *common.Address, *string, int32) (*types.TransactionList, error)
+	AccountTransactions(context.Context, *common.Address, *common.Address, *types.RangeFilter, *types.TransactionFilter, *string, int32) (*types.TransactionList, error)
+
+	// AccountTransactionsPage provides an offset addressed page of transactions of the given account
+	// within the given block and time range. The page is defined by the number of transactions skipped
+	// and the max number of transactions loaded; transactions are sorted by the given direction.
+	AccountTransactionsPage(context.Context, *common.Address, *types.RangeFilter, int64, int64, bool) ([]*types.Transaction, error)
 
 	// AccountsActive total number of accounts known to repository.
 	AccountsActive() (hexutil.Uint64, error)
@@ -61,7 +74,7 @@ type Repository interface {
 	AccountMarkActivity(*common.Address, uint64) error
 
 	// BlockHeight returns the current height of the Opera blockchain in blocks.
-	BlockHeight() (*hexutil.Big, error)
+	BlockHeight(context.Context) (*hexutil.Big, error)
 
 	// LastKnownBlock returns number of the last block known to the repository.
 	LastKnownBlock() (uint64, error)
@@ -73,49 +86,105 @@ type Repository interface {
 	// by the connected blockchain node.
 	ObservedHeaders() chan *etc.Header
 
+	// BlockByTime returns the last block collated at, or before, the given UNIX time stamp;
+	// or the first block collated at, or after, the time stamp if the after flag is set.
+	// If the block is not found, ErrBlockNotFound error is returned.
+	BlockByTime(context.Context, uint64, bool) (*types.Block, error)
+
 	// BlockByNumber returns a block at Opera blockchain represented by a number.
 	// Top block is returned if the number is not provided.
 	// If the block is not found, ErrBlockNotFound error is returned.
-	BlockByNumber(*hexutil.Uint64) (*types.Block, error)
+	BlockByNumber(context.Context, *hexutil.Uint64) (*types.Block, error)
+
+	// BlocksByNumber returns blocks at Opera blockchain of the given numbers mapped by the number.
+	// Blocks not found are not included in the result.
+	BlocksByNumber(context.Context, []hexutil.Uint64) (map[hexutil.Uint64]*types.Block, error)
 
 	// BlockByHash returns a block at Opera blockchain represented by a hash.
 	// Top block is returned if the hash is not provided.
 	// If the block is not found, ErrBlockNotFound error is returned.
-	BlockByHash(*common.Hash) (*types.Block, error)
+	BlockByHash(context.Context, *common.Hash) (*types.Block, error)
 
 	// Blocks pulls list of blocks starting on the specified block number
 	// and going up, or down based on count number.
-	Blocks(*uint64, int32) (*types.BlockList, error)
+	Blocks(context.Context, *uint64, int32) (*types.BlockList, error)
 
 	// CacheBlock puts a block to the internal block ring cache.
 	CacheBlock(blk *types.Block)
 
+	// StoreBlock records the block as processed in the shared store,
+	// so API replicas without the indexer learn about it.
+	StoreBlock(blk *types.Block) error
+
 	// Contract extract a smart contract information by address if available.
-	Contract(*common.Address) (*types.Contract, error)
+	Contract(context.Context, *common.Address) (*types.Contract, error)
+
+	// ContractsByAddress extracts smart contracts of the given addresses mapped by the address.
+	// Addresses of unknown contracts are not included in the result.
+	ContractsByAddress(context.Context, []common.Address) (map[common.Address]*types.Contract, error)
 
 	// Contracts returns list of smart contracts at Opera blockchain.
-	Contracts(bool, *string, int32) (*types.ContractList, error)
+	Contracts(context.Context, bool, *string, int32) (*types.ContractList, error)
+
+	// SearchContracts provides contracts with the name, or the symbol matching the given term,
+	// ranked by the match quality, the verification status and the contract activity.
+	SearchContracts(string, int32) ([]*types.Contract, error)
 
 	// ValidateContract tries to validate contract byte code using
 	// provided source code. If successful, the contract information
 	// is updated the the repository.
-	ValidateContract(*types.Contract) error
+	ValidateContract(context.Context, *types.Contract, *types.ContractCompilation) error
 
 	// StoreContract updates the contract in repository.
 	StoreContract(*types.Contract) error
 
+	// StoreContractSync stores the state of a contract validation sync to an API peer.
+	StoreContractSync(*types.ContractSync) error
+
+	// ContractSyncsDue provides the list of pending contract validation syncs scheduled for a retry.
+	ContractSyncsDue() ([]*types.ContractSync, error)
+
+	// ContractSyncs provides the list of contract validation syncs of the given contract.
+	// If the contract is not specified, the syncs not finished yet are provided.
+	ContractSyncs(*common.Address) ([]*types.ContractSync, error)
+
+	// SubmitContractVerification queues the given contract verification job for processing.
+	SubmitContractVerification(*types.ContractVerification) error
+
+	// StoreContractVerification updates the state of a contract verification job.
+	StoreContractVerification(*types.ContractVerification) error
+
+	// ContractVerification provides the contract verification job of the given GUID; nil if not found.
+	ContractVerification(string) (*types.ContractVerification, error)
+
+	// NextContractVerification claims the next contract verification job waiting for processing.
+	NextContractVerification() (*types.ContractVerification, error)
+
+	// CallContract executes a read-only call of the given contract function with the given arguments
+	// on the state of the given block, or the latest block, using the stored contract ABI.
+	CallContract(context.Context, *common.Address, string, []string, *hexutil.Uint64) (*types.ContractCallResult, error)
+
+	// ContractViewFunctions provides the list of read-only functions of the given contract
+	// declared by the stored contract ABI.
+	ContractViewFunctions(context.Context, *common.Address) []*types.ContractFunction
+
 	// StoreTransaction adds a new incoming transaction from blockchain to the repository.
 	StoreTransaction(*types.Block, *types.Transaction) error
 
 	// LoadTransaction returns a transaction at Opera blockchain
 	// by a hash loaded directly from the node.
-	LoadTransaction(hash *common.Hash) (*types.Transaction, error)
+	LoadTransaction(ctx context.Context, hash *common.Hash) (*types.Transaction, error)
 
 	// Transaction returns a transaction at Opera blockchain by a hash, nil if not found.
-	Transaction(*common.Hash) (*types.Transaction, error)
+	Transaction(context.Context, *common.Hash) (*types.Transaction, error)
+
+	// TransactionsByHash returns transactions at Opera blockchain of the given hashes mapped by the hash.
+	// Transactions not found are not included in the result.
+	TransactionsByHash(context.Context, []common.Hash) (map[common.Hash]*types.Transaction, error)
 
-	// Transactions returns list of transaction hashes at Opera blockchain.
-	Transactions(*string, int32) (*types.TransactionList, error)
+	// Transactions returns list of transaction hashes at Opera blockchain
+	// limited to the block and time range of the range filter, if any.
+	Transactions(context.Context, *types.RangeFilter, *string, int32) (*types.TransactionList, error)
 
 	// TransactionsCount returns total number of transactions in the block chain.
 	TransactionsCount() (uint64, error)
@@ -133,23 +202,168 @@ type Repository interface {
 	CacheTransaction(trx *types.Transaction)
 
 	// SendTransaction sends raw signed and RLP encoded transaction to the block chain.
-	SendTransaction(hexutil.Bytes) (*types.Transaction, error)
+	SendTransaction(context.Context, hexutil.Bytes) (*types.Transaction, error)
+
+	// NodeCall performs a raw JSON-RPC call of the given method on the connected Opera node
+	// and provides the undecoded result.
+	NodeCall(context.Context, string, ...interface{}) (json.RawMessage, error)
+
+	// GatewayCall performs a JSON-RPC call of the given method and parameters on behalf of the RPC gateway.
+	// Immutable results are served from the in-memory cache.
+	GatewayCall(context.Context, string, json.RawMessage) (json.RawMessage, error)
+
+	// SimulateTransaction executes the given transaction on the state of the given block
+	// with the given state overrides applied, without sending it to the block chain.
+	SimulateTransaction(ctx context.Context, from *common.Address, to *common.Address, value *hexutil.Big, data hexutil.Bytes, block *hexutil.Uint64, overrides []types.StateOverride) (*types.SimulationResult, error)
+
+	// RequestFunds sends the configured amount of native tokens from the server account
+	// to the given address, if the faucet rate limits and the daily budget allow it.
+	RequestFunds(context.Context, *common.Address, string) (*types.FaucetRequest, error)
+
+	// FaucetRequests provides the list of the most recent faucet requests,
+	// optionally limited to the given recipient address.
+	FaucetRequests(context.Context, *common.Address, int32) ([]*types.FaucetRequest, error)
+
+	// ReconcileFaucet resolves the faucet requests left pending longer than expected
+	// and returns the nonces abandoned by the faucet transfers to be reused.
+	ReconcileFaucet() error
+
+	// StoreSubmittedTransaction updates the lifecycle state of a transaction submitted through the API.
+	StoreSubmittedTransaction(*types.SubmittedTransaction) error
+
+	// SubmittedTransaction provides the lifecycle state of a transaction submitted through the API;
+	// nil if the transaction is not known.
+	SubmittedTransaction(*common.Hash) (*types.SubmittedTransaction, error)
+
+	// SubmittedTransactionsPending provides the list of transactions submitted through the API
+	// and waiting to be mined.
+	SubmittedTransactionsPending() ([]*types.SubmittedTransaction, error)
+
+	// FollowStore sends records added to the shared store by the indexer into the given channel
+	// until the context is cancelled. It allows API replicas without the indexer to feed subscriptions.
+	FollowStore(ctx context.Context, out chan<- *types.StoreEvent)
+
+	// AcquireLease tries to acquire, or renew, the lease of the given name for the given holder
+	// and provides the current state of the lease.
+	AcquireLease(name string, holder string, ttl time.Duration) (*types.Lease, error)
+
+	// ReleaseLease lets the lease of the given name lapse, if it's held by the given holder.
+	ReleaseLease(name string, holder string) error
+
+	// Lease provides the current state of the lease of the given name; nil if never acquired.
+	Lease(name string) (*types.Lease, error)
+
+	// CreateApiKey generates and stores a new API key of the given ID, holder name and access tier.
+	// The generated key is provided along with the stored record.
+	CreateApiKey(id string, name string, tier string) (*types.ApiKey, string, error)
+
+	// StoreApiKey updates the given API key.
+	StoreApiKey(*types.ApiKey) error
+
+	// DeleteApiKey removes the API key of the given ID together with its usage records.
+	DeleteApiKey(string) error
+
+	// ApiKey provides the API key of the given ID; nil if not found.
+	ApiKey(string) (*types.ApiKey, error)
+
+	// ApiKeyByKey provides the API key record of the given key presented by a client; nil if not found.
+	ApiKeyByKey(string) (*types.ApiKey, error)
+
+	// ApiKeys provides the list of all the API keys.
+	ApiKeys() ([]*types.ApiKey, error)
+
+	// AddApiKeyUsage adds the given numbers of requests to the daily usage of an API key.
+	AddApiKeyUsage(*types.ApiKeyUsage) error
+
+	// ConsumeApiQuota counts a request of the client against its daily quota;
+	// false is returned if the quota of the day is exhausted.
+	ConsumeApiQuota(string, string, int64) (bool, error)
+
+	// ApiKeyUsage provides the daily usage of the API key over the given number of the most recent days.
+	ApiKeyUsage(string, int32) ([]*types.ApiKeyUsage, error)
+
+	// StoreWebhook validates and stores, or updates, the given webhook.
+	StoreWebhook(*types.Webhook) error
+
+	// DeleteWebhook removes the webhook of the given ID together with its delivery log.
+	DeleteWebhook(string) error
+
+	// Webhook provides the webhook of the given ID; nil if not found.
+	Webhook(string) (*types.Webhook, error)
+
+	// Webhooks provides the list of all registered webhooks.
+	Webhooks() ([]*types.Webhook, error)
+
+	// AddWebhookDelivery stores a new webhook delivery, unless a delivery of the same ID is already known.
+	// It reports if the delivery has been added.
+	AddWebhookDelivery(*types.WebhookDelivery) (bool, error)
+
+	// UpdateWebhookDelivery updates the state of the given webhook delivery.
+	UpdateWebhookDelivery(*types.WebhookDelivery) error
+
+	// WebhookDeliveriesDue provides the list of pending webhook deliveries scheduled for an attempt by the given time.
+	WebhookDeliveriesDue(time.Time) ([]*types.WebhookDelivery, error)
+
+	// WebhookDeliveries provides the list of the most recent deliveries of the given webhook.
+	WebhookDeliveries(string, int32) ([]*types.WebhookDelivery, error)
+
+	// AddAlert stores a new alert, unless an alert of the same ID is already known.
+	// It reports if the alert has been added.
+	AddAlert(*types.Alert) (bool, error)
+
+	// UpdateAlertPush updates the sinks push state of the given alert.
+	UpdateAlertPush(*types.Alert) error
+
+	// AlertsPushDue provides alerts waiting to be pushed to their sinks scheduled for an attempt by the given time.
+	AlertsPushDue(time.Time) ([]*types.Alert, error)
+
+	// AlertRules provides the list of alert rules stored in the persistent storage.
+	AlertRules() ([]*types.AlertRule, error)
+
+	// Alerts provides list of alerts starting at the specified cursor.
+	Alerts(*string, int32) (*types.AlertList, error)
+
+	// DecodeTransactionInput decodes the input data of the given transaction using the ABI
+	// of the recipient contract, or ABIs of well known token standards.
+	DecodeTransactionInput(context.Context, *types.Transaction) (*types.DecodedCall, error)
+
+	// TransactionMethodName provides the name of the contract function called by the given transaction,
+	// if it can be identified by the recipient contract ABI, or by the signature database.
+	TransactionMethodName(context.Context, *types.Transaction) (*string, error)
+
+	// LogEventSignature provides the text signature of the event emitted by the given log record,
+	// if it can be identified by the emitting contract ABI, or by the signature database.
+	LogEventSignature(context.Context, *etc.Log) *string
+
+	// DecodeLog decodes the given log record using the ABI of the emitting contract,
+	// ABIs of well known token standards, or the signature database.
+	DecodeLog(context.Context, *etc.Log) *types.DecodedEvent
+
+	// FunctionSignatures provides a list of candidate text signatures of the given 4 bytes function selector.
+	FunctionSignatures([]byte) []*types.Signature
+
+	// EventSignatures provides a list of candidate text signatures of the given event topic.
+	EventSignatures(common.Hash) []*types.Signature
+
+	// RegisterSignature registers a new function, or event signature in the signature database.
+	// The signature text must hash to the given 4 bytes function selector, or 32 bytes event topic.
+	RegisterSignature(context.Context, hexutil.Bytes, string) (*types.Signature, error)
 
 	// Price returns a price information for the given target symbol.
 	Price(sym string) (types.Price, error)
 
 	// GasPrice provides the raw suggested value for the gas price.
-	GasPrice() (hexutil.Big, error)
+	GasPrice(context.Context) (hexutil.Big, error)
 
 	// GasPriceExtended provides extended gas price information.
-	GasPriceExtended() (*types.GasPrice, error)
+	GasPriceExtended(context.Context) (*types.GasPrice, error)
 
 	// StoreGasPricePeriod stores gas price period data into the persistent storage.
 	StoreGasPricePeriod(*types.GasPricePeriod) error
 
 	// GasEstimate calculates the estimated amount of Gas required to perform
 	// transaction described by the input params.
-	GasEstimate(*struct {
+	GasEstimate(context.Context, *struct {
 		From  *common.Address
 		To    *common.Address
 		Value *hexutil.Big
@@ -159,8 +373,22 @@ type Repository interface {
 	//NativeTokenAddress returns address of the native token wrapper, if available.
 	//NativeTokenAddress() (*common.Address, error)
 
+	// ExportAccountTransactions streams transactions of the given account within the given range
+	// ordered from older to newer into the callback; at most limit transactions are streamed.
+	// The stream is terminated by the first error of the callback.
+	ExportAccountTransactions(context.Context, *common.Address, *types.RangeFilter, int64, func(*types.Transaction) error) error
+
+	// ExportTokenTransactions streams token transactions of the given account within the given range
+	// ordered from older to newer into the callback; at most limit transactions are streamed.
+	// The stream is terminated by the first error of the callback.
+	ExportTokenTransactions(context.Context, *common.Address, *types.RangeFilter, int64, func(*types.TokenTransaction) error) error
+
 	// TokenTransactions provides list of ERC20/ERC721/ERC1155 transactions based on given filters.
-	TokenTransactions(tokenType string, token *common.Address, tokenId *big.Int, acc *common.Address, txType []int32, cursor *string, count int32) (*types.TokenTransactionList, error)
+	TokenTransactions(ctx context.Context, tokenType string, token *common.Address, tokenId *big.Int, acc *common.Address, txType []int32, rf *types.RangeFilter, tf *types.TokenTransactionFilter, cursor *string, count int32) (*types.TokenTransactionList, error)
+
+	// TokenTransactionsPage provides an offset addressed page of ERC20/ERC721/ERC1155 transactions
+	// of the given token and/or account within the given block and time range.
+	TokenTransactionsPage(ctx context.Context, tokenType string, token *common.Address, acc *common.Address, rf *types.RangeFilter, skip int64, limit int64, asc bool) ([]*types.TokenTransaction, error)
 
 	// TokenTransactionsByCall provides a list of token transaction made inside a specific
 	// transaction call (blockchain transaction).
@@ -169,6 +397,9 @@ type Repository interface {
 	// Erc20Token returns an ERC20 token for the given address, if available.
 	Erc20Token(*common.Address) (*types.Erc20Token, error)
 
+	// Erc20Tokens returns ERC20 tokens of the given addresses mapped by the address.
+	Erc20Tokens(context.Context, []common.Address) (map[common.Address]*types.Erc20Token, error)
+
 	// Erc20TokensList returns a list of known ERC20 tokens ordered by their activity.
 	Erc20TokensList(int32) ([]common.Address, error)
 
@@ -237,6 +468,12 @@ type Repository interface {
 	// Erc1155ContractsList returns a list of known ERC1155 contracts ordered by their activity.
 	Erc1155ContractsList(int32) ([]common.Address, error)
 
+	// Erc1155Name provides the name of the multi-token, if the contract provides it.
+	Erc1155Name(token *common.Address) (string, error)
+
+	// Erc1155Symbol provides the symbol of the multi-token, if the contract provides it.
+	Erc1155Symbol(token *common.Address) (string, error)
+
 	// Erc1155Uri provides URI of Metadata JSON Schema of the token.
 	Erc1155Uri(token *common.Address, tokenId *big.Int) (string, error)
 
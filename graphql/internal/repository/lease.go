@@ -0,0 +1,30 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"time"
+)
+
+// AcquireLease tries to acquire, or renew, the lease of the given name for the given holder
+// and provides the current state of the lease.
+func (p *proxy) AcquireLease(name string, holder string, ttl time.Duration) (*types.Lease, error) {
+	return p.db.AcquireLease(name, holder, ttl)
+}
+
+// ReleaseLease lets the lease of the given name lapse, if it's held by the given holder.
+func (p *proxy) ReleaseLease(name string, holder string) error {
+	return p.db.ReleaseLease(name, holder)
+}
+
+// Lease provides the current state of the lease of the given name; nil if never acquired.
+func (p *proxy) Lease(name string) (*types.Lease, error) {
+	return p.db.Lease(name)
+}
@@ -0,0 +1,51 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/repository/db"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// dataMigrations provides the ordered list of one-time updates of the data stored by older versions.
+// Ids of the migrations must never change, they identify the migrations already applied.
+func (p *proxy) dataMigrations() []db.Migration {
+	return []db.Migration{
+		{ID: "trx_method_selector", Run: p.db.MigrateTransactionSelectors},
+		{ID: "contract_search_keywords", Run: func() error { return p.db.MigrateContractKeywords(p.describeTokenContract) }},
+	}
+}
+
+// describeTokenContract fills in the missing name and symbol of the given token contract from the blockchain.
+// The values are optional for NFT and multi-token contracts, they are left empty if not available.
+func (p *proxy) describeTokenContract(sc *types.Contract) {
+	var name, symbol func(*common.Address) (string, error)
+	switch sc.Type {
+	case types.AccountTypeERC20Token:
+		name, symbol = p.rpc.Erc20Name, p.rpc.Erc20Symbol
+	case types.AccountTypeERC721Contract:
+		name, symbol = p.rpc.Erc20Name, p.rpc.Erc721Symbol
+	case types.AccountTypeERC1155Contract:
+		name, symbol = p.rpc.Erc1155Name, p.rpc.Erc1155Symbol
+	default:
+		return
+	}
+
+	if sc.Name == "" {
+		if v, err := name(&sc.Address); err == nil {
+			sc.Name = v
+		}
+	}
+	if sc.Symbol == "" {
+		if v, err := symbol(&sc.Address); err == nil {
+			sc.Symbol = v
+		}
+	}
+}
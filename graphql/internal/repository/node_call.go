@@ -0,0 +1,20 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// NodeCall performs a raw JSON-RPC call of the given method on the connected Opera node
+// and provides the undecoded result.
+func (p *proxy) NodeCall(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	return p.rpc.NodeCall(ctx, method, params...)
+}
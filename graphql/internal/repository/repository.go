@@ -56,6 +56,13 @@ func R() Repository {
 	return repo
 }
 
+// Set replaces the singleton instance of the Repository provided by R,
+// i.e. by a substitute repository in tests.
+func Set(r Repository) {
+	onceRepo.Do(func() {})
+	repo = r
+}
+
 // Proxy represents Repository interface implementation and controls access to data
 // trough several low level bridges.
 type proxy struct {
@@ -74,6 +81,13 @@ type proxy struct {
 
 	// smart contract compilers
 	solCompiler string
+	solReleases string
+
+	// known function and event signatures
+	sigDb signatureDb
+
+	// faucet transfers sender state
+	faucet faucetSender
 }
 
 // newRepository creates new instance of Repository implementation, namely proxy structure.
@@ -105,6 +119,12 @@ func newRepository() Repository {
 		cfg:   cfg,
 		// keep reference to the SOL compiler
 		solCompiler: cfg.Compiler.DefaultSolCompilerPath,
+		solReleases: cfg.Compiler.SolReleasesPath,
+	}
+
+	// the indexer brings the data stored by older versions up to date
+	if cfg.Server.Mode != config.ModeApi {
+		p.db.Migrate(p.dataMigrations())
 	}
 
 	// return the proxy
@@ -14,15 +14,19 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // AccountBalance reads balance of account from Opera node.
-func (ftm *FtmBridge) AccountBalance(addr *common.Address) (*hexutil.Big, error) {
+func (ftm *FtmBridge) AccountBalance(ctx context.Context, addr *common.Address) (*hexutil.Big, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	// use RPC to make the call
 	var balance string
-	err := ftm.rpc.Call(&balance, "eth_getBalance", addr.Hex(), "latest")
+	err := ftm.rpc.CallContext(ctx, &balance, "eth_getBalance", addr.Hex(), "latest")
 	if err != nil {
 		ftm.log.Errorf("can not get balance of account [%s]", addr.Hex())
 		return nil, err
@@ -39,12 +43,43 @@ func (ftm *FtmBridge) AccountBalance(addr *common.Address) (*hexutil.Big, error)
 }
 
 // AccountNonce returns the total number of transaction of account from Opera node.
-func (ftm *FtmBridge) AccountNonce(addr *common.Address) (*hexutil.Uint64, error) {
+func (ftm *FtmBridge) AccountNonce(ctx context.Context, addr *common.Address) (*hexutil.Uint64, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	var nonce hexutil.Uint64
-	err := ftm.rpc.Call(&nonce, "eth_getTransactionCount", addr.Hex(), "latest")
+	err := ftm.rpc.CallContext(ctx, &nonce, "eth_getTransactionCount", addr.Hex(), "latest")
 	if err != nil {
 		ftm.log.Errorf("can not get number of transaction of account [%s]", addr.Hex())
 		return nil, err
 	}
 	return &nonce, nil
 }
+
+// AccountPendingNonce returns the next nonce of the account including transactions waiting in the pool.
+func (ftm *FtmBridge) AccountPendingNonce(ctx context.Context, addr *common.Address) (uint64, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var nonce hexutil.Uint64
+	err := ftm.rpc.CallContext(ctx, &nonce, "eth_getTransactionCount", addr.Hex(), "pending")
+	if err != nil {
+		ftm.log.Errorf("can not get pending nonce of account [%s]", addr.Hex())
+		return 0, err
+	}
+	return uint64(nonce), nil
+}
+
+// AccountCode returns the byte code deployed at the given account address.
+func (ftm *FtmBridge) AccountCode(ctx context.Context, addr *common.Address) (hexutil.Bytes, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var code hexutil.Bytes
+	err := ftm.rpc.CallContext(ctx, &code, "eth_getCode", addr.Hex(), "latest")
+	if err != nil {
+		ftm.log.Errorf("can not get byte code of account [%s]", addr.Hex())
+		return nil, err
+	}
+	return code, nil
+}
@@ -14,9 +14,11 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"math/big"
 	"time"
 )
@@ -30,8 +32,11 @@ const (
 // MustBlockHeight returns the current block height
 // of the blockchain. It returns nil if the block height can not be pulled.
 func (ftm *FtmBridge) MustBlockHeight() *big.Int {
+	ctx, cancel := ftm.opContext(context.Background())
+	defer cancel()
+
 	var val hexutil.Big
-	if err := ftm.rpc.Call(&val, "eth_blockNumber"); err != nil {
+	if err := ftm.rpc.CallContext(ctx, &val, "eth_blockNumber"); err != nil {
 		ftm.log.Errorf("failed block height check; %s", err.Error())
 		return nil
 	}
@@ -39,13 +44,16 @@ func (ftm *FtmBridge) MustBlockHeight() *big.Int {
 }
 
 // BlockHeight returns the current block height of the Opera blockchain.
-func (ftm *FtmBridge) BlockHeight() (*hexutil.Big, error) {
+func (ftm *FtmBridge) BlockHeight(ctx context.Context) (*hexutil.Big, error) {
 	// keep track of the operation
 	ftm.log.Debugf("checking current block height")
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	// call for data
 	var height hexutil.Big
-	err := ftm.rpc.Call(&height, "eth_blockNumber")
+	err := ftm.rpc.CallContext(ctx, &height, "eth_blockNumber")
 	if err != nil {
 		ftm.log.Error("block height could not be obtained")
 		return nil, err
@@ -58,13 +66,16 @@ func (ftm *FtmBridge) BlockHeight() (*hexutil.Big, error) {
 
 // Block returns information about a blockchain block by encoded hex number, or by a type tag.
 // For tag based loading use predefined BlockType contacts.
-func (ftm *FtmBridge) Block(numTag *string) (*types.Block, error) {
+func (ftm *FtmBridge) Block(ctx context.Context, numTag *string) (*types.Block, error) {
 	// keep track of the operation
 	ftm.log.Debugf("loading details of block num/tag %s", *numTag)
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	// call for data
 	var block types.Block
-	err := ftm.rpc.Call(&block, "eth_getBlockByNumber", numTag, false)
+	err := ftm.rpc.CallContext(ctx, &block, "eth_getBlockByNumber", numTag, false)
 	if err != nil {
 		ftm.log.Error("block could not be extracted")
 		return nil, err
@@ -83,13 +94,16 @@ func (ftm *FtmBridge) Block(numTag *string) (*types.Block, error) {
 }
 
 // BlockByHash returns information about a blockchain block by hash.
-func (ftm *FtmBridge) BlockByHash(hash *string) (*types.Block, error) {
+func (ftm *FtmBridge) BlockByHash(ctx context.Context, hash *string) (*types.Block, error) {
 	// keep track of the operation
 	ftm.log.Debugf("loading details of block %s", *hash)
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	// call for data
 	var block types.Block
-	err := ftm.rpc.Call(&block, "eth_getBlockByHash", hash, false)
+	err := ftm.rpc.CallContext(ctx, &block, "eth_getBlockByHash", hash, false)
 	if err != nil {
 		ftm.log.Error("block could not be extracted")
 		return nil, err
@@ -106,3 +120,29 @@ func (ftm *FtmBridge) BlockByHash(hash *string) (*types.Block, error) {
 		uint64(block.Number), time.Unix(int64(block.TimeStamp), 0).String(), *hash)
 	return &block, nil
 }
+
+// Blocks returns information about the blockchain blocks of the given numbers loaded by a single batch call.
+// Blocks not found are not included in the result.
+func (ftm *FtmBridge) Blocks(ctx context.Context, nums []hexutil.Uint64) (map[hexutil.Uint64]*types.Block, error) {
+	// keep track of the operation
+	ftm.log.Debugf("loading batch of %d blocks", len(nums))
+
+	list := make([]*types.Block, len(nums))
+	batch := make([]ethrpc.BatchElem, len(nums))
+	for i := range nums {
+		batch[i] = ethrpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{nums[i], false}, Result: &list[i]}
+	}
+
+	if err := ftm.batch(ctx, batch); err != nil {
+		ftm.log.Errorf("blocks could not be extracted; %s", err.Error())
+		return nil, err
+	}
+
+	res := make(map[hexutil.Uint64]*types.Block, len(nums))
+	for i := range list {
+		if list[i] != nil {
+			res[nums[i]] = list[i]
+		}
+	}
+	return res, nil
+}
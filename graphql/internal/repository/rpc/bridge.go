@@ -24,6 +24,7 @@ import (
 	ftm "github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/sync/singleflight"
 	"sync"
+	"time"
 )
 
 // rpcHeadProxyChannelCapacity represents the capacity of the new received blocks proxy channel.
@@ -36,6 +37,9 @@ type FtmBridge struct {
 	log logger.Logger
 	cg  *singleflight.Group
 
+	// timeout represents the deadline of a single node call
+	timeout time.Duration
+
 	// fMintCfg represents the configuration of the fMint protocol
 	sigConfig *config.ServerSignature
 
@@ -63,6 +67,9 @@ func New(cfg *config.Config, log logger.Logger) (*FtmBridge, error) {
 		log: log,
 		cg:  new(singleflight.Group),
 
+		// calls not finished in time are abandoned
+		timeout: cfg.Opera.Timeout,
+
 		// special configuration options below this line
 		sigConfig: &cfg.Signature,
 
@@ -75,8 +82,10 @@ func New(cfg *config.Config, log logger.Logger) (*FtmBridge, error) {
 	// inform about the local address of the API node
 	log.Noticef("using signature address %s", br.sigConfig.Address.String())
 
-	// add the bridge ref to the fMintCfg and return the instance
-	br.run()
+	// new blocks are observed only for the indexer; the API alone does not consume them
+	if cfg.Server.Mode != config.ModeApi {
+		br.run()
+	}
 	return br, nil
 }
 
@@ -104,6 +113,11 @@ func connect(cfg *config.Config, log logger.Logger) (*ftm.Client, *eth.Client, e
 	return client, con, nil
 }
 
+// opContext derives the context of a single node call limited by the configured deadline.
+func (ftm *FtmBridge) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, ftm.timeout)
+}
+
 // run starts the bridge threads required to collect blockchain data.
 func (ftm *FtmBridge) run() {
 	ftm.wg.Add(1)
@@ -135,6 +149,12 @@ func (ftm *FtmBridge) Connection() *ftm.Client {
 	return ftm.rpc
 }
 
+// callOpts creates the options of a single contract call limited by the configured deadline.
+func (ftm *FtmBridge) callOpts(ctx context.Context) (*bind.CallOpts, context.CancelFunc) {
+	ctx, cancel := ftm.opContext(ctx)
+	return &bind.CallOpts{Context: ctx}, cancel
+}
+
 // DefaultCallOpts creates a default record for call options.
 func (ftm *FtmBridge) DefaultCallOpts() *bind.CallOpts {
 	// get the default call opts only once if called in parallel
@@ -0,0 +1,57 @@
+/*
+Package rpc implements bridge to Opera full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Opera node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Opera RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Opera RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Opera RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallArgs represents arguments of a message call executed against the blockchain state
+// without creating a transaction.
+type CallArgs struct {
+	From  *common.Address `json:"from,omitempty"`
+	To    *common.Address `json:"to,omitempty"`
+	Gas   *hexutil.Uint64 `json:"gas,omitempty"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+// blockNumberTag provides the block tag of the given block number;
+// the latest block tag is used if the number is not provided.
+func blockNumberTag(block *hexutil.Uint64) string {
+	if block == nil {
+		return BlockTypeLatest
+	}
+	return block.String()
+}
+
+// Call executes the given message call on the state of the given block, or the latest block,
+// and provides the raw output of the call.
+func (ftm *FtmBridge) Call(ctx context.Context, args *CallArgs, block *hexutil.Uint64) (hexutil.Bytes, error) {
+	// keep track of the operation
+	ftm.log.Debugf("executing message call on block %s", blockNumberTag(block))
+
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var out hexutil.Bytes
+	err := ftm.rpc.CallContext(ctx, &out, "eth_call", args, blockNumberTag(block))
+	if err != nil {
+		ftm.log.Debugf("message call failed; %s", err.Error())
+		return nil, err
+	}
+	return out, nil
+}
@@ -14,6 +14,7 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository/rpc/contracts"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -25,6 +26,9 @@ import (
 
 // Erc1155Uri provides URI of Metadata JSON Schema of the ERC1155 token.
 func (ftm *FtmBridge) Erc1155Uri(token *common.Address, tokenId *big.Int) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC1155(*token, ftm.eth)
 	if err != nil {
@@ -33,7 +37,7 @@ func (ftm *FtmBridge) Erc1155Uri(token *common.Address, tokenId *big.Int) (strin
 	}
 
 	// get the token name
-	uri, err := contract.Uri(nil, tokenId)
+	uri, err := contract.Uri(opts, tokenId)
 	if err != nil {
 		ftm.log.Errorf("ERC1155 token %s/%s URI not available; %s", token.String(), tokenId.String(), err.Error())
 		return "", err
@@ -42,8 +46,55 @@ func (ftm *FtmBridge) Erc1155Uri(token *common.Address, tokenId *big.Int) (strin
 	return uri, nil
 }
 
+// Erc1155Name provides the name of the ERC1155 multi-token. The name is not part of the standard,
+// but many multi-token contracts implement the ERC721 metadata methods; empty if not available.
+func (ftm *FtmBridge) Erc1155Name(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
+	// the name method is shared with the ERC721 metadata extension
+	contract, err := contracts.NewERC721(*token, ftm.eth)
+	if err != nil {
+		ftm.log.Errorf("can not contact ERC1155 contract; %s", err.Error())
+		return "", err
+	}
+
+	name, err := contract.Name(opts)
+	if err != nil {
+		ftm.log.Debugf("ERC1155 token %s name not available; %s", token.String(), err.Error())
+		return "", nil
+	}
+
+	return name, nil
+}
+
+// Erc1155Symbol provides the symbol of the ERC1155 multi-token. The symbol is not part of the standard,
+// but many multi-token contracts implement the ERC721 metadata methods; empty if not available.
+func (ftm *FtmBridge) Erc1155Symbol(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
+	// the symbol method is shared with the ERC721 metadata extension
+	contract, err := contracts.NewERC721(*token, ftm.eth)
+	if err != nil {
+		ftm.log.Errorf("can not contact ERC1155 contract; %s", err.Error())
+		return "", err
+	}
+
+	symbol, err := contract.Symbol(opts)
+	if err != nil {
+		ftm.log.Debugf("ERC1155 token %s symbol not available; %s", token.String(), err.Error())
+		return "", nil
+	}
+
+	return symbol, nil
+}
+
 // Erc1155BalanceOf provides amount of tokens owned by given owner in given ERC1155 contract.
 func (ftm *FtmBridge) Erc1155BalanceOf(token *common.Address, owner *common.Address, tokenId *big.Int) (*big.Int, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC1155(*token, ftm.eth)
 	if err != nil {
@@ -51,7 +102,7 @@ func (ftm *FtmBridge) Erc1155BalanceOf(token *common.Address, owner *common.Addr
 		return nil, err
 	}
 
-	balance, err := contract.BalanceOf(nil, *owner, tokenId)
+	balance, err := contract.BalanceOf(opts, *owner, tokenId)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC1155 %s/%s balance for %s; %s", token.String(), tokenId.String(), owner.String(), err.Error())
 		return nil, err
@@ -62,6 +113,9 @@ func (ftm *FtmBridge) Erc1155BalanceOf(token *common.Address, owner *common.Addr
 
 // Erc1155BalanceOfBatch provides amounts of tokens owned by given owners in given ERC1155 contract.
 func (ftm *FtmBridge) Erc1155BalanceOfBatch(token *common.Address, owners *[]common.Address, tokenIds []*big.Int) ([]*big.Int, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC1155(*token, ftm.eth)
 	if err != nil {
@@ -69,7 +123,7 @@ func (ftm *FtmBridge) Erc1155BalanceOfBatch(token *common.Address, owners *[]com
 		return nil, err
 	}
 
-	balances, err := contract.BalanceOfBatch(nil, *owners, tokenIds)
+	balances, err := contract.BalanceOfBatch(opts, *owners, tokenIds)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC1155 batch balance for %s; %s", token.String(), err.Error())
 		return nil, err
@@ -80,6 +134,9 @@ func (ftm *FtmBridge) Erc1155BalanceOfBatch(token *common.Address, owners *[]com
 
 // Erc1155IsApprovedForAll provides information about operator approved to manipulate with tokens of given owner.
 func (ftm *FtmBridge) Erc1155IsApprovedForAll(token *common.Address, owner *common.Address, operator *common.Address) (bool, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC1155(*token, ftm.eth)
 	if err != nil {
@@ -87,7 +144,7 @@ func (ftm *FtmBridge) Erc1155IsApprovedForAll(token *common.Address, owner *comm
 		return false, err
 	}
 
-	isApproved, err := contract.IsApprovedForAll(nil, *owner, *operator)
+	isApproved, err := contract.IsApprovedForAll(opts, *owner, *operator)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC1155 %s approved-for-all status for owner %s and operator %s; %s", token.String(), owner.String(), operator.String(), err.Error())
 		return false, err
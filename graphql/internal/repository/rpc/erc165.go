@@ -14,6 +14,7 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository/rpc/contracts"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -21,6 +22,9 @@ import (
 //go:generate tools/abigen.sh --abi ./contracts/abi/erc165.abi --pkg contracts --type ERC165 --out ./contracts/erc165.go
 
 func (ftm *FtmBridge) Erc165SupportsInterface(address *common.Address, interfaceID [4]byte) (bool, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC165(*address, ftm.eth)
 	if err != nil {
@@ -28,7 +32,7 @@ func (ftm *FtmBridge) Erc165SupportsInterface(address *common.Address, interface
 		return false, err
 	}
 
-	supports, err := contract.SupportsInterface(nil, interfaceID)
+	supports, err := contract.SupportsInterface(opts, interfaceID)
 	if err != nil {
 		ftm.log.Noticef("interface support by ERC165 for contract %s cannot be detected; %s", address.String(), err.Error())
 		return false, err
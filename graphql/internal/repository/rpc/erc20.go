@@ -14,9 +14,12 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository/rpc/contracts"
+	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"math/big"
 )
 
@@ -25,6 +28,9 @@ import (
 
 // Erc20Name provides information about the name of the ERC20 token.
 func (ftm *FtmBridge) Erc20Name(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -33,7 +39,7 @@ func (ftm *FtmBridge) Erc20Name(token *common.Address) (string, error) {
 	}
 
 	// get the token name
-	name, err := contract.Name(nil)
+	name, err := contract.Name(opts)
 	if err != nil {
 		ftm.log.Errorf("ERC20 token %s name not available; %s", token.String(), err.Error())
 		return "", err
@@ -44,6 +50,9 @@ func (ftm *FtmBridge) Erc20Name(token *common.Address) (string, error) {
 
 // Erc20Symbol provides information about the symbol of the ERC20 token.
 func (ftm *FtmBridge) Erc20Symbol(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -52,7 +61,7 @@ func (ftm *FtmBridge) Erc20Symbol(token *common.Address) (string, error) {
 	}
 
 	// get the token name
-	symbol, err := contract.Symbol(nil)
+	symbol, err := contract.Symbol(opts)
 	if err != nil {
 		ftm.log.Errorf("ERC20 token %s symbol not available; %s", token.String(), err.Error())
 		return "", err
@@ -63,6 +72,9 @@ func (ftm *FtmBridge) Erc20Symbol(token *common.Address) (string, error) {
 
 // Erc20Decimals provides information about the decimals of the ERC20 token.
 func (ftm *FtmBridge) Erc20Decimals(token *common.Address) (int32, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -71,7 +83,7 @@ func (ftm *FtmBridge) Erc20Decimals(token *common.Address) (int32, error) {
 	}
 
 	// get the token name
-	deci, err := contract.Decimals(nil)
+	deci, err := contract.Decimals(opts)
 	if err != nil {
 		ftm.log.Errorf("ERC20 token %s decimals not available; %s", token.String(), err.Error())
 		return 0, nil
@@ -83,6 +95,9 @@ func (ftm *FtmBridge) Erc20Decimals(token *common.Address) (int32, error) {
 // Erc20BalanceOf loads the current available balance of and ERC20 token identified by the token
 // contract address for an identified owner address.
 func (ftm *FtmBridge) Erc20BalanceOf(token *common.Address, owner *common.Address) (hexutil.Big, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -91,7 +106,7 @@ func (ftm *FtmBridge) Erc20BalanceOf(token *common.Address, owner *common.Addres
 	}
 
 	// get the balance
-	val, err := contract.BalanceOf(nil, *owner)
+	val, err := contract.BalanceOf(opts, *owner)
 	if err != nil {
 		ftm.log.Errorf("can not ERC20 %s balance for %s; %s", token.String(), owner.String(), err.Error())
 		return hexutil.Big{}, err
@@ -112,6 +127,9 @@ func (ftm *FtmBridge) Erc20BalanceOf(token *common.Address, owner *common.Addres
 // Erc20Allowance loads the current amount of ERC20 tokens unlocked for DeFi
 // contract by the token owner.
 func (ftm *FtmBridge) Erc20Allowance(token *common.Address, owner *common.Address, spender *common.Address) (hexutil.Big, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -126,7 +144,7 @@ func (ftm *FtmBridge) Erc20Allowance(token *common.Address, owner *common.Addres
 	}
 
 	// get the amount of tokens allowed for DeFi
-	val, err := contract.Allowance(nil, *owner, *spender)
+	val, err := contract.Allowance(opts, *owner, *spender)
 	if err != nil {
 		ftm.log.Errorf("can not get defi ERC20 %s allowance for %s; %s", token.String(), owner.String(), err.Error())
 		return hexutil.Big{}, err
@@ -146,6 +164,9 @@ func (ftm *FtmBridge) Erc20Allowance(token *common.Address, owner *common.Addres
 
 // Erc20TotalSupply provides information about all available tokens
 func (ftm *FtmBridge) Erc20TotalSupply(token *common.Address) (hexutil.Big, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERCTwenty(*token, ftm.eth)
 	if err != nil {
@@ -154,7 +175,7 @@ func (ftm *FtmBridge) Erc20TotalSupply(token *common.Address) (hexutil.Big, erro
 	}
 
 	// get the amount of tokens allowed for DeFi
-	val, err := contract.TotalSupply(nil)
+	val, err := contract.TotalSupply(opts)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC20 %s total supply; %s", token.String(), err.Error())
 		return hexutil.Big{}, err
@@ -169,3 +190,77 @@ func (ftm *FtmBridge) Erc20TotalSupply(token *common.Address) (hexutil.Big, erro
 	// return the account balance
 	return hexutil.Big(*val), nil
 }
+
+// Erc20Tokens provides the name, the symbol and the decimals of the given ERC20 tokens
+// loaded by a single batch call. Details not available on a token contract are substituted
+// by the token address, an empty symbol mark, and zero decimals respectively.
+func (ftm *FtmBridge) Erc20Tokens(ctx context.Context, tokens []common.Address) ([]*types.Erc20Token, error) {
+	// keep track of the operation
+	ftm.log.Debugf("loading batch of %d ERC20 tokens", len(tokens))
+
+	ab, err := contracts.ERCTwentyMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	// the same calls are made to each token contract
+	methods := []string{"name", "symbol", "decimals"}
+	input := make([][]byte, len(methods))
+	for i, m := range methods {
+		if input[i], err = ab.Pack(m); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]hexutil.Bytes, len(tokens)*len(methods))
+	batch := make([]ethrpc.BatchElem, len(out))
+	for i := range batch {
+		batch[i] = ethrpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{map[string]interface{}{
+				"to":   tokens[i/len(methods)],
+				"data": hexutil.Bytes(input[i%len(methods)]),
+			}, BlockTypeLatest},
+			Result: &out[i],
+		}
+	}
+
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	if err := ftm.rpc.BatchCallContext(ctx, batch); err != nil {
+		ftm.log.Errorf("ERC20 tokens could not be loaded; %s", err.Error())
+		return nil, err
+	}
+
+	list := make([]*types.Erc20Token, len(tokens))
+	for i := range tokens {
+		tk := types.Erc20Token{Address: tokens[i], Name: tokens[i].String(), Symbol: "-"}
+		for j, m := range methods {
+			el := batch[i*len(methods)+j]
+			if el.Error != nil {
+				ftm.log.Errorf("ERC20 token %s %s not available; %s", tokens[i].String(), m, el.Error.Error())
+				continue
+			}
+
+			val, err := ab.Unpack(m, out[i*len(methods)+j])
+			if err != nil || len(val) == 0 {
+				ftm.log.Errorf("ERC20 token %s %s not recognized", tokens[i].String(), m)
+				continue
+			}
+
+			switch v := val[0].(type) {
+			case string:
+				if m == "name" {
+					tk.Name = v
+				} else {
+					tk.Symbol = v
+				}
+			case uint8:
+				tk.Decimals = int32(v)
+			}
+		}
+		list[i] = &tk
+	}
+	return list, nil
+}
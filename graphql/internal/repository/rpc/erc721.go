@@ -14,6 +14,7 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository/rpc/contracts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -24,6 +25,9 @@ import (
 
 // Erc721Name provides information about the name of the ERC721 token.
 func (ftm *FtmBridge) Erc721Name(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -32,7 +36,7 @@ func (ftm *FtmBridge) Erc721Name(token *common.Address) (string, error) {
 	}
 
 	// get the token name
-	name, err := contract.Name(nil)
+	name, err := contract.Name(opts)
 	if err != nil {
 		ftm.log.Errorf("ERC721 token %s name not available; %s", token.String(), err.Error())
 		return "", err
@@ -43,6 +47,9 @@ func (ftm *FtmBridge) Erc721Name(token *common.Address) (string, error) {
 
 // Erc721Symbol provides information about the symbol of the ERC721 token.
 func (ftm *FtmBridge) Erc721Symbol(token *common.Address) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -51,7 +58,7 @@ func (ftm *FtmBridge) Erc721Symbol(token *common.Address) (string, error) {
 	}
 
 	// get the token name
-	symbol, err := contract.Symbol(nil)
+	symbol, err := contract.Symbol(opts)
 	if err != nil {
 		ftm.log.Errorf("ERC721 token %s symbol not available; %s", token.String(), err.Error())
 		return "", err
@@ -62,6 +69,9 @@ func (ftm *FtmBridge) Erc721Symbol(token *common.Address) (string, error) {
 
 // Erc721BalanceOf provides amount of NFT tokens owned by given owner in given ERC721 contract.
 func (ftm *FtmBridge) Erc721BalanceOf(token *common.Address, owner *common.Address) (hexutil.Big, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -70,7 +80,7 @@ func (ftm *FtmBridge) Erc721BalanceOf(token *common.Address, owner *common.Addre
 	}
 
 	// get the balance
-	val, err := contract.BalanceOf(nil, *owner)
+	val, err := contract.BalanceOf(opts, *owner)
 	if err != nil {
 		ftm.log.Errorf("can not ERC721 %s balance for %s; %s", token.String(), owner.String(), err.Error())
 		return hexutil.Big{}, err
@@ -90,6 +100,9 @@ func (ftm *FtmBridge) Erc721BalanceOf(token *common.Address, owner *common.Addre
 
 // Erc721TotalSupply provides information about all available tokens
 func (ftm *FtmBridge) Erc721TotalSupply(token *common.Address) (hexutil.Big, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -98,7 +111,7 @@ func (ftm *FtmBridge) Erc721TotalSupply(token *common.Address) (hexutil.Big, err
 	}
 
 	// get the amount of tokens allowed for DeFi
-	val, err := contract.TotalSupply(nil)
+	val, err := contract.TotalSupply(opts)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC721 %s total supply; %s", token.String(), err.Error())
 		return hexutil.Big{}, err
@@ -116,6 +129,9 @@ func (ftm *FtmBridge) Erc721TotalSupply(token *common.Address) (hexutil.Big, err
 
 // Erc721TokenURI provides URI of Metadata JSON Schema of the ERC721 token.
 func (ftm *FtmBridge) Erc721TokenURI(token *common.Address, tokenId *big.Int) (string, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -124,7 +140,7 @@ func (ftm *FtmBridge) Erc721TokenURI(token *common.Address, tokenId *big.Int) (s
 	}
 
 	// get the token name
-	uri, err := contract.TokenURI(nil, tokenId)
+	uri, err := contract.TokenURI(opts, tokenId)
 	if err != nil {
 		ftm.log.Errorf("ERC721 token %s/%s URI not available; %s", token.String(), tokenId.String(), err.Error())
 		return "", err
@@ -135,6 +151,9 @@ func (ftm *FtmBridge) Erc721TokenURI(token *common.Address, tokenId *big.Int) (s
 
 // Erc721OwnerOf provides information about NFT token ownership
 func (ftm *FtmBridge) Erc721OwnerOf(token *common.Address, tokenId *big.Int) (common.Address, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -142,7 +161,7 @@ func (ftm *FtmBridge) Erc721OwnerOf(token *common.Address, tokenId *big.Int) (co
 		return common.Address{}, err
 	}
 
-	owner, err := contract.OwnerOf(nil, tokenId)
+	owner, err := contract.OwnerOf(opts, tokenId)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC721 %s owner of %s; %s", token.String(), tokenId.String(), err.Error())
 		return common.Address{}, err
@@ -153,6 +172,9 @@ func (ftm *FtmBridge) Erc721OwnerOf(token *common.Address, tokenId *big.Int) (co
 
 // Erc721GetApproved provides information about operator approved to manipulate with the NFT token.
 func (ftm *FtmBridge) Erc721GetApproved(token *common.Address, tokenId *big.Int) (common.Address, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -160,7 +182,7 @@ func (ftm *FtmBridge) Erc721GetApproved(token *common.Address, tokenId *big.Int)
 		return common.Address{}, err
 	}
 
-	owner, err := contract.GetApproved(nil, tokenId)
+	owner, err := contract.GetApproved(opts, tokenId)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC721 %s approved operator of %s; %s", token.String(), tokenId.String(), err.Error())
 		return common.Address{}, err
@@ -171,6 +193,9 @@ func (ftm *FtmBridge) Erc721GetApproved(token *common.Address, tokenId *big.Int)
 
 // Erc721IsApprovedForAll provides information about operator approved to manipulate with NFT tokens of given owner.
 func (ftm *FtmBridge) Erc721IsApprovedForAll(token *common.Address, owner *common.Address, operator *common.Address) (bool, error) {
+	opts, cancel := ftm.callOpts(context.Background())
+	defer cancel()
+
 	// connect the contract
 	contract, err := contracts.NewERC721(*token, ftm.eth)
 	if err != nil {
@@ -178,7 +203,7 @@ func (ftm *FtmBridge) Erc721IsApprovedForAll(token *common.Address, owner *commo
 		return false, err
 	}
 
-	isApproved, err := contract.IsApprovedForAll(nil, *owner, *operator)
+	isApproved, err := contract.IsApprovedForAll(opts, *owner, *operator)
 	if err != nil {
 		ftm.log.Errorf("can not get ERC721 %s approved-for-all status for owner %s and operator %s; %s", token.String(), owner.String(), operator.String(), err.Error())
 		return false, err
@@ -0,0 +1,169 @@
+/*
+Package rpc implements bridge to Opera full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Opera node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Opera RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Opera RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Opera RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	retypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+var (
+	// ErcApprovalTopic represents the topic of ERC20/ERC721 Approval event.
+	// event Approval(address indexed owner, address indexed spender, uint256 value)
+	ErcApprovalTopic = common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+
+	// ErcTransferTopic represents the topic of ERC20/ERC721 Transfer event.
+	// event Transfer(address indexed from, address indexed to, uint256 value)
+	ErcTransferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	// Erc1155TransferSingleTopic represents the topic of ERC1155 TransferSingle event.
+	// event TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)
+	Erc1155TransferSingleTopic = common.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+
+	// Erc1155TransferBatchTopic represents the topic of ERC1155 TransferBatch event.
+	// event TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)
+	Erc1155TransferBatchTopic = common.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+)
+
+// ErrUnrecognizedTokenLog signals a log record with a known token event topic,
+// but with a layout not matching any of the supported token standards.
+var ErrUnrecognizedTokenLog = errors.New("unrecognized token event")
+
+// ErcTokenTransactions decodes generic token (ERC20/ERC721/ERC1155) transactions from the given log record.
+// Empty list is returned if the log record is not a token event. Time stamp of the transactions is not set,
+// the log record does not carry it.
+func ErcTokenTransactions(lg *retypes.Log) ([]*types.TokenTransaction, error) {
+	if len(lg.Topics) == 0 {
+		return nil, nil
+	}
+
+	switch lg.Topics[0] {
+	case ErcApprovalTopic:
+		return ercTransaction(lg, types.TokenTrxTypeApproval)
+	case ErcTransferTopic:
+		return ercTransaction(lg, types.TokenTrxTypeTransfer)
+	case Erc1155TransferSingleTopic:
+		return erc1155TransferSingle(lg)
+	case Erc1155TransferBatchTopic:
+		return erc1155TransferBatch(lg)
+	}
+	return nil, nil
+}
+
+// ercTransaction decodes Approval and/or Transfer event on an ERC20/ERC721 token.
+// event Transfer(address indexed from,  address indexed to, uint256 value); // ERC20
+// event Transfer(address indexed from,  address indexed to, uint256 indexed _tokenId); // ERC721
+// event Approval(address indexed owner, address indexed operator, uint256 value); // ERC20
+// event Approval(address indexed owner, address indexed operator, uint256 indexed _tokenId); // ERC721
+func ercTransaction(lg *retypes.Log, trxType int32) ([]*types.TokenTransaction, error) {
+	// ERC20 has 2 indexed params (=> 3 topics) and 1 non-indexed uint256 param (=> 32 bytes)
+	if len(lg.Topics) == 3 && len(lg.Data) == 32 {
+		from := common.BytesToAddress(lg.Topics[1].Bytes())
+		to := common.BytesToAddress(lg.Topics[2].Bytes())
+		amount := new(big.Int).SetBytes(lg.Data[:])
+		tokenId := big.NewInt(0)
+		return []*types.TokenTransaction{
+			tokenTransaction(lg, types.AccountTypeERC20Token, tokenTrxType(trxType, from, to), from, to, *amount, *tokenId, 0),
+		}, nil
+	}
+
+	// ERC721 has 3 indexed params (=> 4 topics) and no non-indexed param (=> 0 bytes)
+	if len(lg.Topics) == 4 && len(lg.Data) == 0 {
+		from := common.BytesToAddress(lg.Topics[1].Bytes())
+		to := common.BytesToAddress(lg.Topics[2].Bytes())
+		amount := big.NewInt(1)
+		tokenId := new(big.Int).SetBytes(lg.Topics[3].Bytes())
+		return []*types.TokenTransaction{
+			tokenTransaction(lg, types.AccountTypeERC721Contract, tokenTrxType(trxType, from, to), from, to, *amount, *tokenId, 0),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w; ERC-20/ERC-721 Transfer/Approval with %d data bytes, %d topics", ErrUnrecognizedTokenLog, len(lg.Data), len(lg.Topics))
+}
+
+// erc1155TransferSingle decodes ERC1155 TransferSingle event.
+// event TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 tokenId, uint256 value)
+func erc1155TransferSingle(lg *retypes.Log) ([]*types.TokenTransaction, error) {
+	// 3 indexed params, 2 uint256 params
+	if len(lg.Topics) == 4 && len(lg.Data) == 64 {
+		from := common.BytesToAddress(lg.Topics[2].Bytes())
+		to := common.BytesToAddress(lg.Topics[3].Bytes())
+		tokenId := new(big.Int).SetBytes(lg.Data[0:32])
+		amount := new(big.Int).SetBytes(lg.Data[32:64])
+		return []*types.TokenTransaction{
+			tokenTransaction(lg, types.AccountTypeERC1155Contract, tokenTrxType(types.TokenTrxTypeTransfer, from, to), from, to, *amount, *tokenId, 0),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w; ERC-1155 TransferSingle with %d data bytes, %d topics", ErrUnrecognizedTokenLog, len(lg.Data), len(lg.Topics))
+}
+
+// erc1155TransferBatch decodes ERC1155 TransferBatch event.
+// event TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)
+func erc1155TransferBatch(lg *retypes.Log) ([]*types.TokenTransaction, error) {
+	// 3 indexed params
+	if len(lg.Topics) != 4 {
+		return nil, fmt.Errorf("%w; ERC-1155 TransferBatch with %d data bytes, %d topics", ErrUnrecognizedTokenLog, len(lg.Data), len(lg.Topics))
+	}
+
+	from := common.BytesToAddress(lg.Topics[2].Bytes())
+	to := common.BytesToAddress(lg.Topics[3].Bytes())
+	ids, values, err := Erc1155ParseTransferBatchData(lg.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC1155 TransferBatch data; %s", err.Error())
+	}
+	if len(ids) != len(values) {
+		return nil, fmt.Errorf("ERC1155 TransferBatch ids and values length differs")
+	}
+
+	list := make([]*types.TokenTransaction, len(ids))
+	for i := range ids {
+		list[i] = tokenTransaction(lg, types.AccountTypeERC1155Contract, types.TokenTrxTypeTransfer, from, to, *values[i], *ids[i], uint16(i))
+	}
+	return list, nil
+}
+
+// tokenTrxType detects minting and burning transfers by the zero sender or recipient address.
+func tokenTrxType(trxType int32, from common.Address, to common.Address) int32 {
+	if trxType == types.TokenTrxTypeTransfer && config.EmptyAddress == from.String() {
+		return types.TokenTrxTypeMint
+	}
+	if trxType == types.TokenTrxTypeTransfer && config.EmptyAddress == to.String() {
+		return types.TokenTrxTypeBurn
+	}
+	return trxType
+}
+
+// tokenTransaction builds general token (ERC20/ERC721/ERC1155) transaction from the given log record.
+func tokenTransaction(lg *retypes.Log, tokenType string, eventType int32, from common.Address, to common.Address, amount big.Int, tokenId big.Int, seq uint16) *types.TokenTransaction {
+	return &types.TokenTransaction{
+		Transaction:  lg.TxHash,
+		TrxIndex:     hexutil.Uint64(uint64(lg.TxIndex)),
+		TokenAddress: lg.Address,
+		Type:         eventType,
+		TokenType:    tokenType,
+		Sender:       from,
+		Recipient:    to,
+		Amount:       hexutil.Big(amount),
+		TokenId:      hexutil.Big(tokenId),
+		LogIndex:     lg.Index,
+		BlockNumber:  lg.BlockNumber,
+		Seq:          seq, // sequence of erc transactions emitted by one log event - non-zero only for batch transfer events
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Package rpc implements bridge to Opera full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Opera node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Opera RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Opera RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Opera RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// NodeCall performs a raw JSON-RPC call of the given method on the connected Opera node
+// and provides the undecoded result.
+func (ftm *FtmBridge) NodeCall(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var res json.RawMessage
+	if err := ftm.rpc.CallContext(ctx, &res, method, params...); err != nil {
+		ftm.log.Debugf("node call %s failed; %s", method, err.Error())
+		return nil, err
+	}
+	return res, nil
+}
+
+// batch sends the given calls to the connected Opera node in a single batch request.
+// The error of the first failed call is provided, if any.
+func (ftm *FtmBridge) batch(ctx context.Context, calls []ethrpc.BatchElem) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	if err := ftm.rpc.BatchCallContext(ctx, calls); err != nil {
+		return err
+	}
+
+	for _, c := range calls {
+		if c.Error != nil {
+			return c.Error
+		}
+	}
+	return nil
+}
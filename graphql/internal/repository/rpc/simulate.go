@@ -0,0 +1,173 @@
+/*
+Package rpc implements bridge to Opera full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Opera node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Opera RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Opera RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Opera RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	retypes "github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// StateOverride represents temporary changes of an account state applied
+// before a simulated call is executed.
+type StateOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverrides represents a set of account state overrides keyed by the account address.
+type StateOverrides map[common.Address]StateOverride
+
+// SimulatedCall represents the outcome of a simulated message call.
+type SimulatedCall struct {
+	Traced  bool
+	Error   string
+	GasUsed hexutil.Uint64
+	Output  hexutil.Bytes
+	Logs    []retypes.Log
+}
+
+// traceConfig represents the configuration of the call tracer used for simulation.
+type traceConfig struct {
+	Tracer         string         `json:"tracer"`
+	TracerConfig   interface{}    `json:"tracerConfig,omitempty"`
+	StateOverrides StateOverrides `json:"stateOverrides,omitempty"`
+}
+
+// callTracerFrame represents a single call frame provided by the call tracer.
+type callTracerFrame struct {
+	GasUsed      hexutil.Uint64    `json:"gasUsed"`
+	Output       hexutil.Bytes     `json:"output"`
+	Error        string            `json:"error"`
+	RevertReason string            `json:"revertReason"`
+	Calls        []callTracerFrame `json:"calls"`
+	Logs         []callTracerLog   `json:"logs"`
+}
+
+// callTracerLog represents a log record collected by the call tracer;
+// the position is the index of the inner call the log record was emitted before.
+type callTracerLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"`
+}
+
+// SimulateCall executes the given message call on the state of the given block, or the latest block,
+// with the given state overrides applied. The call tracer is used if the node supports it so the emitted
+// logs can be collected; plain message call with gas estimation is used otherwise.
+func (ftm *FtmBridge) SimulateCall(ctx context.Context, args *CallArgs, block *hexutil.Uint64, overrides StateOverrides) (*SimulatedCall, error) {
+	sc, err := ftm.traceCall(ctx, args, block, overrides)
+	if err == nil {
+		return sc, nil
+	}
+	ftm.log.Debugf("call tracer not available, simulating by message call; %s", err.Error())
+	return ftm.simulateByCall(ctx, args, block, overrides)
+}
+
+// traceCall simulates the message call using the call tracer.
+func (ftm *FtmBridge) traceCall(ctx context.Context, args *CallArgs, block *hexutil.Uint64, overrides StateOverrides) (*SimulatedCall, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var frame callTracerFrame
+	err := ftm.rpc.CallContext(ctx, &frame, "debug_traceCall", args, blockNumberTag(block), traceConfig{
+		Tracer:         "callTracer",
+		TracerConfig:   map[string]bool{"withLog": true},
+		StateOverrides: overrides,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sc := SimulatedCall{
+		Traced:  true,
+		Error:   frame.Error,
+		GasUsed: frame.GasUsed,
+		Output:  frame.Output,
+	}
+	if sc.Error == "" {
+		sc.Logs = frame.collectLogs(make([]retypes.Log, 0))
+	}
+	return &sc, nil
+}
+
+// collectLogs collects log records of the call frame and all its successful inner calls
+// in the order they were emitted.
+func (cf *callTracerFrame) collectLogs(list []retypes.Log) []retypes.Log {
+	// logs of failed frames are reverted
+	if cf.Error != "" {
+		return list
+	}
+
+	var next int
+	for _, lg := range cf.Logs {
+		for ; next < len(cf.Calls) && next < int(lg.Position); next++ {
+			list = cf.Calls[next].collectLogs(list)
+		}
+		list = append(list, retypes.Log{
+			Address: lg.Address,
+			Topics:  lg.Topics,
+			Data:    lg.Data,
+			Index:   uint(len(list)),
+		})
+	}
+	for ; next < len(cf.Calls); next++ {
+		list = cf.Calls[next].collectLogs(list)
+	}
+	return list
+}
+
+// simulateByCall simulates the message call using plain eth_call; logs are not available
+// and the amount of gas used is estimated.
+func (ftm *FtmBridge) simulateByCall(ctx context.Context, args *CallArgs, block *hexutil.Uint64, overrides StateOverrides) (*SimulatedCall, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var out hexutil.Bytes
+	var err error
+
+	if len(overrides) > 0 {
+		err = ftm.rpc.CallContext(ctx, &out, "eth_call", args, blockNumberTag(block), overrides)
+	} else {
+		err = ftm.rpc.CallContext(ctx, &out, "eth_call", args, blockNumberTag(block))
+	}
+
+	if err != nil {
+		// reverted calls carry the revert data along with the error
+		var de ethrpc.DataError
+		if !errors.As(err, &de) {
+			ftm.log.Debugf("simulated call failed; %s", err.Error())
+			return nil, err
+		}
+
+		sc := SimulatedCall{Error: de.Error()}
+		if data, ok := de.ErrorData().(string); ok {
+			sc.Output, _ = hexutil.Decode(data)
+		}
+		return &sc, nil
+	}
+
+	sc := SimulatedCall{Output: out}
+	if err := ftm.rpc.CallContext(ctx, &sc.GasUsed, "eth_estimateGas", args, blockNumberTag(block)); err != nil {
+		ftm.log.Debugf("can not estimate gas of simulated call; %s", err.Error())
+	}
+	return &sc, nil
+}
@@ -14,20 +14,25 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	retypes "github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 // Transaction returns information about a blockchain transaction by hash.
-func (ftm *FtmBridge) Transaction(hash *common.Hash) (*types.Transaction, error) {
+func (ftm *FtmBridge) Transaction(ctx context.Context, hash *common.Hash) (*types.Transaction, error) {
 	// keep track of the operation
 	ftm.log.Debugf("loading transaction %s", hash.String())
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	// call for data
 	var trx types.Transaction
-	err := ftm.rpc.Call(&trx, "eth_getTransactionByHash", hash)
+	err := ftm.rpc.CallContext(ctx, &trx, "eth_getTransactionByHash", hash)
 	if err != nil {
 		ftm.log.Error("transaction could not be extracted")
 		return nil, err
@@ -46,7 +51,7 @@ func (ftm *FtmBridge) Transaction(hash *common.Hash) (*types.Transaction, error)
 		}
 
 		// call for the transaction receipt data
-		err := ftm.rpc.Call(&rec, "eth_getTransactionReceipt", hash)
+		err := ftm.rpc.CallContext(ctx, &rec, "eth_getTransactionReceipt", hash)
 		if err != nil {
 			ftm.log.Errorf("can not get receipt for transaction %s", hash)
 			return nil, err
@@ -67,12 +72,15 @@ func (ftm *FtmBridge) Transaction(hash *common.Hash) (*types.Transaction, error)
 }
 
 // SendTransaction sends raw signed and RLP encoded transaction to the block chain.
-func (ftm *FtmBridge) SendTransaction(tx hexutil.Bytes) (*common.Hash, error) {
+func (ftm *FtmBridge) SendTransaction(ctx context.Context, tx hexutil.Bytes) (*common.Hash, error) {
 	// keep track of the operation
 	ftm.log.Debug("sending new transaction to block chain")
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	var hash common.Hash
-	err := ftm.rpc.Call(&hash, "eth_sendRawTransaction", tx)
+	err := ftm.rpc.CallContext(ctx, &hash, "eth_sendRawTransaction", tx)
 	if err != nil {
 		ftm.log.Error("transaction could not be sent")
 		return nil, err
@@ -82,3 +90,62 @@ func (ftm *FtmBridge) SendTransaction(tx hexutil.Bytes) (*common.Hash, error) {
 	ftm.log.Debugf("transaction has been accepted with hash %s", hash.String())
 	return &hash, nil
 }
+
+// Transactions returns information about the blockchain transactions of the given hashes
+// loaded by batch calls. Transactions not found are not included in the result.
+func (ftm *FtmBridge) Transactions(ctx context.Context, hashes []common.Hash) (map[common.Hash]*types.Transaction, error) {
+	// keep track of the operation
+	ftm.log.Debugf("loading batch of %d transactions", len(hashes))
+
+	list := make([]*types.Transaction, len(hashes))
+	batch := make([]ethrpc.BatchElem, len(hashes))
+	for i := range hashes {
+		batch[i] = ethrpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{hashes[i]}, Result: &list[i]}
+	}
+
+	if err := ftm.batch(ctx, batch); err != nil {
+		ftm.log.Errorf("transactions could not be extracted; %s", err.Error())
+		return nil, err
+	}
+
+	// receipts are loaded for the transactions already bundled in a block
+	type receipt struct {
+		Index             hexutil.Uint64  `json:"transactionIndex"`
+		CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+		GasUsed           hexutil.Uint64  `json:"gasUsed"`
+		ContractAddress   *common.Address `json:"contractAddress,omitempty"`
+		Status            hexutil.Uint64  `json:"status"`
+		Logs              []retypes.Log   `json:"logs"`
+	}
+
+	res := make(map[common.Hash]*types.Transaction, len(hashes))
+	rec := make([]receipt, 0, len(hashes))
+	batch = batch[:0]
+	for _, trx := range list {
+		if trx == nil {
+			continue
+		}
+
+		res[trx.Hash] = trx
+		if trx.BlockNumber != nil {
+			rec = append(rec, receipt{})
+			batch = append(batch, ethrpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{trx.Hash}, Result: &rec[len(rec)-1]})
+		}
+	}
+
+	if err := ftm.batch(ctx, batch); err != nil {
+		ftm.log.Errorf("transaction receipts could not be extracted; %s", err.Error())
+		return nil, err
+	}
+
+	for i := range batch {
+		trx := res[batch[i].Args[0].(common.Hash)]
+		trx.Index = &rec[i].Index
+		trx.CumulativeGasUsed = &rec[i].CumulativeGasUsed
+		trx.GasUsed = &rec[i].GasUsed
+		trx.ContractAddress = rec[i].ContractAddress
+		trx.Status = &rec[i].Status
+		trx.Logs = rec[i].Logs
+	}
+	return res, nil
+}
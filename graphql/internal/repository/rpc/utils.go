@@ -14,6 +14,7 @@ We strongly discourage opening Opera RPC interface for unrestricted Internet acc
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -26,7 +27,7 @@ import (
 var maxAcceptedGasPrice = big.NewInt(1_000_000_000_000_000_000)
 
 // GasPrice pulls the current amount of WEI for single Gas.
-func (ftm *FtmBridge) GasPrice() (hexutil.Big, error) {
+func (ftm *FtmBridge) GasPrice(ctx context.Context) (hexutil.Big, error) {
 	// keep track of the operation
 	ftm.log.Debugf("checking current gas price")
 
@@ -34,7 +35,7 @@ func (ftm *FtmBridge) GasPrice() (hexutil.Big, error) {
 	var price hexutil.Big
 	var try uint8
 	for {
-		err := ftm.rpc.Call(&price, "eth_gasPrice")
+		err := ftm.gasPrice(ctx, &price)
 		if err != nil {
 			ftm.log.Error("current gas price could not be obtained")
 			return price, err
@@ -58,9 +59,16 @@ func (ftm *FtmBridge) GasPrice() (hexutil.Big, error) {
 	return price, nil
 }
 
+// gasPrice pulls a single gas price suggestion from the node.
+func (ftm *FtmBridge) gasPrice(ctx context.Context, price *hexutil.Big) error {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+	return ftm.rpc.CallContext(ctx, price, "eth_gasPrice")
+}
+
 // GasEstimate calculates the estimated amount of Gas required to perform
 // transaction described by the input params.
-func (ftm *FtmBridge) GasEstimate(trx *struct {
+func (ftm *FtmBridge) GasEstimate(ctx context.Context, trx *struct {
 	From  *common.Address
 	To    *common.Address
 	Value *hexutil.Big
@@ -69,12 +77,15 @@ func (ftm *FtmBridge) GasEstimate(trx *struct {
 	// keep track of the operation
 	ftm.log.Debugf("calling for gas amount estimation")
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	var val hexutil.Uint64
-	err := ftm.rpc.Call(&val, "eth_estimateGas", trx)
+	err := ftm.rpc.CallContext(ctx, &val, "eth_estimateGas", trx)
 	if err != nil {
 		// missing required argument? incompatibility between old and new RPC API
 		if strings.Contains(err.Error(), "missing value") {
-			return ftm.GasEstimateWithBlock(trx)
+			return ftm.GasEstimateWithBlock(ctx, trx)
 		}
 
 		// return error
@@ -89,7 +100,7 @@ func (ftm *FtmBridge) GasEstimate(trx *struct {
 // transaction described by the input params with specifying the block on which the calculation
 // should happen (new RPC API compatibility).
 // @TODO Replace the old gas estimate call once the API gets upgraded on all nodes.
-func (ftm *FtmBridge) GasEstimateWithBlock(trx *struct {
+func (ftm *FtmBridge) GasEstimateWithBlock(ctx context.Context, trx *struct {
 	From  *common.Address
 	To    *common.Address
 	Value *hexutil.Big
@@ -98,8 +109,11 @@ func (ftm *FtmBridge) GasEstimateWithBlock(trx *struct {
 	// keep track of the operation
 	ftm.log.Debugf("calling for gas amount estimation with block details")
 
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
 	var val hexutil.Uint64
-	err := ftm.rpc.Call(&val, "eth_estimateGas", trx, BlockTypeLatest)
+	err := ftm.rpc.CallContext(ctx, &val, "eth_estimateGas", trx, BlockTypeLatest)
 	if err != nil {
 		// return error
 		ftm.log.Errorf("can not estimate gas; %s", err.Error())
@@ -108,3 +122,16 @@ func (ftm *FtmBridge) GasEstimateWithBlock(trx *struct {
 
 	return &val, nil
 }
+
+// ChainID pulls the chain identifier used for signing transactions.
+func (ftm *FtmBridge) ChainID(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := ftm.opContext(ctx)
+	defer cancel()
+
+	var id hexutil.Big
+	if err := ftm.rpc.CallContext(ctx, &id, "eth_chainId"); err != nil {
+		ftm.log.Errorf("can not get chain ID; %s", err.Error())
+		return nil, err
+	}
+	return id.ToInt(), nil
+}
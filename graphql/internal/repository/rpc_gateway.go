@@ -0,0 +1,111 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// GatewayCall performs a JSON-RPC call of the given method and parameters on behalf of the RPC gateway.
+// Results known to be immutable are served from, and kept in, the in-memory cache.
+func (p *proxy) GatewayCall(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	var args []json.RawMessage
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+	}
+
+	// the cache key is built from the compact form of the parameters
+	key, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	if res := p.cache.PullRpcResult(method, key); res != nil {
+		return res, nil
+	}
+
+	in := make([]interface{}, len(args))
+	for i := range args {
+		in[i] = args[i]
+	}
+
+	res, err := p.rpc.NodeCall(ctx, method, in...)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isImmutableRpcResult(ctx, method, args, res) {
+		if err := p.cache.PushRpcResult(method, key, res); err != nil {
+			p.log.Debugf("can not cache %s result; %s", method, err.Error())
+		}
+	}
+	return res, nil
+}
+
+// isImmutableRpcResult checks if the result of the given JSON-RPC call can not change anymore
+// and so it can be served from the cache.
+func (p *proxy) isImmutableRpcResult(ctx context.Context, method string, args []json.RawMessage, res json.RawMessage) bool {
+	// empty results may be filled later
+	if res == nil || bytes.Equal(res, []byte("null")) {
+		return false
+	}
+
+	switch method {
+	case "eth_chainId", "net_version":
+		return true
+	case "eth_getBlockByHash", "eth_getBlockTransactionCountByHash", "eth_getTransactionByBlockHashAndIndex":
+		return true
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt":
+		var trx struct {
+			BlockNumber *hexutil.Big `json:"blockNumber"`
+		}
+		return json.Unmarshal(res, &trx) == nil && trx.BlockNumber != nil
+	case "eth_getBlockByNumber", "eth_getBlockTransactionCountByNumber", "eth_getTransactionByBlockNumberAndIndex":
+		return len(args) > 0 && p.isBelowHead(ctx, args[0])
+	case "eth_getLogs":
+		return len(args) > 0 && p.isLogFilterBelowHead(ctx, args[0])
+	}
+	return false
+}
+
+// isLogFilterBelowHead checks if the given log filter covers only blocks below the current head.
+func (p *proxy) isLogFilterBelowHead(ctx context.Context, filter json.RawMessage) bool {
+	var lf struct {
+		BlockHash *hexutil.Bytes  `json:"blockHash"`
+		FromBlock json.RawMessage `json:"fromBlock"`
+		ToBlock   json.RawMessage `json:"toBlock"`
+	}
+	if err := json.Unmarshal(filter, &lf); err != nil {
+		return false
+	}
+
+	if lf.BlockHash != nil {
+		return true
+	}
+	return p.isBelowHead(ctx, lf.FromBlock) && p.isBelowHead(ctx, lf.ToBlock)
+}
+
+// isBelowHead checks if the given block number parameter is an explicit number below the current head.
+// Block tags, i.e. latest, or pending, are never below the head.
+func (p *proxy) isBelowHead(ctx context.Context, num json.RawMessage) bool {
+	var blk hexutil.Uint64
+	if err := json.Unmarshal(num, &blk); err != nil {
+		return false
+	}
+
+	head, err := p.BlockHeight(ctx)
+	if err != nil {
+		return false
+	}
+	return uint64(blk) < head.ToInt().Uint64()
+}
@@ -0,0 +1,60 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"sort"
+	"strings"
+)
+
+// searchCandidatesFactor is the number of contract candidates loaded per one requested search result,
+// so the ranking is not limited to the order of the index scan.
+const searchCandidatesFactor = 4
+
+// SearchContracts provides contracts with the name, or the symbol matching the given term.
+// Exact matches go first, verified contracts are preferred to unverified
+// and the more active contract is preferred to the less active one.
+func (p *proxy) SearchContracts(term string, count int32) ([]*types.Contract, error) {
+	list, err := p.db.SearchContracts(term, int64(count)*searchCandidatesFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the activity of the contracts found
+	addr := make([]common.Address, len(list))
+	for i, sc := range list {
+		addr[i] = sc.Address
+	}
+	act, err := p.db.AccountsActivity(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// rank the candidates
+	term = strings.ToLower(strings.TrimSpace(term))
+	exact := func(sc *types.Contract) bool {
+		return strings.ToLower(sc.Name) == term || strings.ToLower(sc.Symbol) == term
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if ei, ej := exact(list[i]), exact(list[j]); ei != ej {
+			return ei
+		}
+		if vi, vj := list[i].Validated != nil, list[j].Validated != nil; vi != vj {
+			return vi
+		}
+		return act[list[i].Address] > act[list[j].Address]
+	})
+
+	if len(list) > int(count) {
+		list = list[:count]
+	}
+	return list, nil
+}
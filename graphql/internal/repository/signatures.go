@@ -0,0 +1,225 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// embeddedSignatures represents the function and event signature database
+// bundled with the API server.
+//
+//go:embed signatures.txt
+var embeddedSignatures []byte
+
+// signatureDb represents an in-memory database of known function selectors
+// and event topics mapped to candidate text signatures.
+type signatureDb struct {
+	mu     sync.RWMutex
+	once   sync.Once
+	known  map[string]bool
+	fn     map[[4]byte][]*types.Signature
+	events map[common.Hash][]*types.Signature
+}
+
+// signatures provides the signature database, the database is loaded on the first use.
+func (p *proxy) signatures() *signatureDb {
+	p.sigDb.once.Do(func() {
+		p.sigDb.known = make(map[string]bool)
+		p.sigDb.fn = make(map[[4]byte][]*types.Signature)
+		p.sigDb.events = make(map[common.Hash][]*types.Signature)
+
+		p.loadEmbeddedSignatures()
+		p.loadSignaturesFile()
+		p.loadStoredSignatures()
+	})
+	return &p.sigDb
+}
+
+// loadEmbeddedSignatures loads signatures bundled with the API server.
+func (p *proxy) loadEmbeddedSignatures() {
+	count, err := p.sigDb.importText(bytes.NewReader(embeddedSignatures))
+	if err != nil {
+		p.log.Errorf("can not load embedded signatures; %s", err.Error())
+		return
+	}
+	p.log.Debugf("%d embedded signatures loaded", count)
+}
+
+// loadSignaturesFile imports signatures from the local file configured, if any.
+func (p *proxy) loadSignaturesFile() {
+	if p.cfg.SignaturesFilePath == "" {
+		return
+	}
+
+	f, err := os.Open(p.cfg.SignaturesFilePath)
+	if err != nil {
+		p.log.Errorf("can not open signatures file; %s", err.Error())
+		return
+	}
+
+	// make sure to close the file
+	defer func() {
+		if err := f.Close(); err != nil {
+			p.log.Errorf("signatures file can not be closed; %s", err.Error())
+		}
+	}()
+
+	count, err := p.sigDb.importText(f)
+	if err != nil {
+		p.log.Errorf("can not import signatures from %s; %s", p.cfg.SignaturesFilePath, err.Error())
+		return
+	}
+	p.log.Noticef("%d signatures imported from %s", count, p.cfg.SignaturesFilePath)
+}
+
+// loadStoredSignatures loads signatures registered in the persistent database.
+func (p *proxy) loadStoredSignatures() {
+	list, err := p.db.Signatures()
+	if err != nil {
+		p.log.Errorf("can not load registered signatures; %s", err.Error())
+		return
+	}
+
+	for _, sig := range list {
+		p.sigDb.add(sig)
+	}
+	p.log.Debugf("%d registered signatures loaded", len(list))
+}
+
+// importText imports signatures from the given text source.
+// Each line contains the signature type (function, or event) followed by the signature text.
+// Empty lines and lines starting with # are ignored.
+func (sdb *signatureDb) importText(r io.Reader) (int, error) {
+	var count int
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// split the type and the text
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 {
+			log.Debugf("invalid signature line %s", line)
+			continue
+		}
+
+		sig, err := types.NewSignature(strings.ToUpper(parts[0]), parts[1])
+		if err != nil {
+			log.Debugf("invalid signature line %s; %s", line, err.Error())
+			continue
+		}
+
+		if sdb.add(sig) {
+			count++
+		}
+	}
+	return count, sc.Err()
+}
+
+// add adds the given signature into the database. It returns FALSE if the signature is already known.
+func (sdb *signatureDb) add(sig *types.Signature) bool {
+	sdb.mu.Lock()
+	defer sdb.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", sig.Hash.String(), sig.Text)
+	if sdb.known[key] {
+		return false
+	}
+	sdb.known[key] = true
+
+	switch sig.Type {
+	case types.SignatureTypeFunction:
+		var sel [4]byte
+		copy(sel[:], sig.Hash)
+		sdb.fn[sel] = append(sdb.fn[sel], sig)
+	case types.SignatureTypeEvent:
+		topic := common.BytesToHash(sig.Hash)
+		sdb.events[topic] = append(sdb.events[topic], sig)
+	}
+	return true
+}
+
+// function provides the list of candidate signatures of the given function selector.
+func (sdb *signatureDb) function(input []byte) []*types.Signature {
+	if len(input) < 4 {
+		return nil
+	}
+
+	var sel [4]byte
+	copy(sel[:], input[:4])
+
+	sdb.mu.RLock()
+	defer sdb.mu.RUnlock()
+	return sdb.fn[sel]
+}
+
+// event provides the list of candidate signatures of the given event topic.
+func (sdb *signatureDb) event(topic common.Hash) []*types.Signature {
+	sdb.mu.RLock()
+	defer sdb.mu.RUnlock()
+	return sdb.events[topic]
+}
+
+// FunctionSignatures provides a list of candidate text signatures of the given 4 bytes function selector.
+func (p *proxy) FunctionSignatures(selector []byte) []*types.Signature {
+	return p.signatures().function(selector)
+}
+
+// EventSignatures provides a list of candidate text signatures of the given event topic.
+func (p *proxy) EventSignatures(topic common.Hash) []*types.Signature {
+	return p.signatures().event(topic)
+}
+
+// RegisterSignature registers a new function, or event signature. The signature text
+// must hash to the given 4 bytes function selector, or 32 bytes event topic.
+func (p *proxy) RegisterSignature(ctx context.Context, hash hexutil.Bytes, text string) (*types.Signature, error) {
+	var typ string
+	switch len(hash) {
+	case 4:
+		typ = types.SignatureTypeFunction
+	case common.HashLength:
+		typ = types.SignatureTypeEvent
+	default:
+		return nil, fmt.Errorf("invalid signature hash length %d", len(hash))
+	}
+
+	sig, err := types.NewSignature(typ, text)
+	if err != nil {
+		return nil, err
+	}
+
+	// the text must match the hash
+	if !bytes.Equal(sig.Hash, hash) {
+		return nil, fmt.Errorf("signature %s does not match %s", sig.Text, hash.String())
+	}
+
+	if err := p.db.StoreSignature(ctx, sig); err != nil {
+		return nil, err
+	}
+
+	// already known signatures are not registered again
+	if p.signatures().add(sig) {
+		p.log.Noticef("signature %s registered for %s", sig.Text, hash.String())
+	}
+	return sig, nil
+}
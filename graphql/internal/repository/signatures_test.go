@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/onsi/gomega"
+	"strings"
+	"testing"
+)
+
+// newTestSignatureDb creates an empty signature database.
+func newTestSignatureDb() *signatureDb {
+	if log == nil {
+		log = logger.New(&config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}})
+	}
+	return &signatureDb{
+		known:  make(map[string]bool),
+		fn:     make(map[[4]byte][]*types.Signature),
+		events: make(map[common.Hash][]*types.Signature),
+	}
+}
+
+func TestSignatureDbImportText(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sdb := newTestSignatureDb()
+	count, err := sdb.importText(strings.NewReader(`
+# comment
+function transfer(address,uint256)
+FUNCTION transfer(address, uint256)
+event Transfer(address,address,uint256)
+function not a signature
+unknown foo(uint256)
+function
+`))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(count).To(gomega.Equal(2))
+
+	fn := sdb.function(common.FromHex("0xa9059cbb000000000000000000000000"))
+	g.Expect(fn).To(gomega.HaveLen(1))
+	g.Expect(fn[0].Text).To(gomega.Equal("transfer(address,uint256)"))
+
+	ev := sdb.event(common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"))
+	g.Expect(ev).To(gomega.HaveLen(1))
+	g.Expect(ev[0].Name()).To(gomega.Equal("Transfer"))
+}
+
+func TestSignatureDbLookup(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "short input", input: "0xa905", want: nil},
+		{name: "unknown selector", input: "0x12345678", want: nil},
+		{name: "selector only", input: "0x095ea7b3", want: []string{"approve(address,uint256)"}},
+		{name: "collided selector keeps order", input: "0x42966c68", want: []string{"burn(uint256)", "collate_propagate_storage(bytes16)"}},
+	}
+
+	sdb := newTestSignatureDb()
+	for _, text := range []string{"approve(address,uint256)", "burn(uint256)", "collate_propagate_storage(bytes16)"} {
+		sig, err := types.NewSignature(types.SignatureTypeFunction, text)
+		if err != nil {
+			t.Fatalf("invalid signature %s; %s", text, err.Error())
+		}
+		sdb.add(sig)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			list := sdb.function(common.FromHex(tt.input))
+			g.Expect(list).To(gomega.HaveLen(len(tt.want)))
+			for i, sig := range list {
+				g.Expect(sig.Text).To(gomega.Equal(tt.want[i]))
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fantom-api-graphql/internal/repository/rpc"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// panicSelector is the selector of the Panic(uint256) error raised by failed Solidity assertions.
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// panicCodes maps well known Solidity panic codes to their description.
+var panicCodes = map[uint64]string{
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to invalid internal function",
+}
+
+// SimulateTransaction executes the given transaction on the state of the given block, or the latest block,
+// with the given state overrides applied, without sending it to the blockchain. Logs emitted by the call
+// and token transactions decoded from them are available only if the node supports the call tracer.
+func (p *proxy) SimulateTransaction(ctx context.Context, from *common.Address, to *common.Address, value *hexutil.Big, data hexutil.Bytes, block *hexutil.Uint64, overrides []types.StateOverride) (*types.SimulationResult, error) {
+	sc, err := p.rpc.SimulateCall(ctx, &rpc.CallArgs{From: from, To: to, Value: value, Data: data}, block, stateOverrides(overrides))
+	if err != nil {
+		p.log.Errorf("can not simulate transaction; %s", err.Error())
+		return nil, err
+	}
+
+	res := types.SimulationResult{
+		Success:           sc.Error == "",
+		GasUsed:           sc.GasUsed,
+		ReturnData:        sc.Output,
+		LogsAvailable:     sc.Traced,
+		Logs:              sc.Logs,
+		TokenTransactions: make([]*types.TokenTransaction, 0),
+	}
+	if !res.Success {
+		res.Error = &sc.Error
+		res.RevertReason = revertReason(sc.Output)
+	}
+
+	// decode token transactions the same way the log dispatcher does
+	for i := range res.Logs {
+		if block != nil {
+			res.Logs[i].BlockNumber = uint64(*block)
+		}
+
+		tl, err := rpc.ErcTokenTransactions(&res.Logs[i])
+		if err != nil {
+			p.log.Debugf("can not decode simulated token event; %s", err.Error())
+			continue
+		}
+		res.TokenTransactions = append(res.TokenTransactions, tl...)
+	}
+	return &res, nil
+}
+
+// stateOverrides converts the list of state overrides into the set expected by the RPC bridge.
+func stateOverrides(list []types.StateOverride) rpc.StateOverrides {
+	if len(list) == 0 {
+		return nil
+	}
+
+	so := make(rpc.StateOverrides, len(list))
+	for _, o := range list {
+		so[o.Address] = rpc.StateOverride{
+			Balance:   o.Balance,
+			Nonce:     o.Nonce,
+			Code:      o.Code,
+			State:     o.State,
+			StateDiff: o.StateDiff,
+		}
+	}
+	return so
+}
+
+// revertReason decodes the reason of a reverted call from the revert data;
+// both Error(string) and Panic(uint256) errors are recognized.
+func revertReason(data []byte) *string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return &reason
+	}
+
+	if len(data) == 36 && bytes.Equal(data[:4], panicSelector) {
+		code := new(big.Int).SetBytes(data[4:])
+		reason := fmt.Sprintf("panic code 0x%x", code)
+		if desc, ok := panicCodes[code.Uint64()]; ok && code.IsUint64() {
+			reason = fmt.Sprintf("%s (%s)", reason, desc)
+		}
+		return &reason
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+)
+
+// FollowStore sends records added to the shared store by the indexer into the given channel
+// until the context is cancelled. It allows API replicas without the indexer to feed subscriptions.
+func (p *proxy) FollowStore(ctx context.Context, out chan<- *types.StoreEvent) {
+	p.db.FollowStore(ctx, out)
+}
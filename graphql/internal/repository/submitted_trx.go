@@ -0,0 +1,57 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
+
+// trackSubmittedTransaction registers the given transaction sent through the API
+// to be tracked until it's mined, replaced, or dropped.
+func (p *proxy) trackSubmittedTransaction(trx *types.Transaction) {
+	now := time.Now().UTC()
+	st := types.SubmittedTransaction{
+		Hash:      trx.Hash,
+		From:      trx.From,
+		Nonce:     uint64(trx.Nonce),
+		Status:    types.SubmittedTrxStatusPending,
+		Submitted: now,
+		Updated:   now,
+	}
+
+	// the transaction may have been mined already
+	if trx.BlockNumber != nil {
+		blk := uint64(*trx.BlockNumber)
+		st.Status = types.SubmittedTrxStatusMined
+		st.BlockNumber = &blk
+	}
+
+	if err := p.db.StoreSubmittedTransaction(&st); err != nil {
+		p.log.Errorf("can not track submitted trx %s; %s", trx.Hash.String(), err.Error())
+	}
+}
+
+// StoreSubmittedTransaction updates the lifecycle state of a transaction submitted through the API.
+func (p *proxy) StoreSubmittedTransaction(st *types.SubmittedTransaction) error {
+	return p.db.StoreSubmittedTransaction(st)
+}
+
+// SubmittedTransaction provides the lifecycle state of a transaction submitted through the API;
+// nil if the transaction is not known.
+func (p *proxy) SubmittedTransaction(hash *common.Hash) (*types.SubmittedTransaction, error) {
+	return p.db.SubmittedTransaction(hash)
+}
+
+// SubmittedTransactionsPending provides the list of transactions submitted through the API
+// and waiting to be mined.
+func (p *proxy) SubmittedTransactionsPending() ([]*types.SubmittedTransaction, error) {
+	return p.db.SubmittedTransactionsPending()
+}
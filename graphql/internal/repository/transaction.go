@@ -10,8 +10,10 @@ package repository
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fantom-api-graphql/internal/repository/cache"
+	"fantom-api-graphql/internal/repository/db"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -34,7 +36,7 @@ func (p *proxy) CacheTransaction(trx *types.Transaction) {
 
 // Transaction returns a transaction at Opera blockchain by a hash, nil if not found.
 // If the transaction is not found, ErrTransactionNotFound error is returned.
-func (p *proxy) Transaction(hash *common.Hash) (*types.Transaction, error) {
+func (p *proxy) Transaction(ctx context.Context, hash *common.Hash) (*types.Transaction, error) {
 	p.log.Debugf("requested transaction %s", hash.String())
 
 	// try to use the in-memory cache
@@ -44,7 +46,7 @@ func (p *proxy) Transaction(hash *common.Hash) (*types.Transaction, error) {
 	}
 
 	// return the value
-	trx, err := p.LoadTransaction(hash)
+	trx, err := p.LoadTransaction(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -62,18 +64,50 @@ func (p *proxy) Transaction(hash *common.Hash) (*types.Transaction, error) {
 	return trx, nil
 }
 
+// TransactionsByHash returns transactions at Opera blockchain of the given hashes mapped by the hash.
+// Transactions missing in cache are pulled from the node by batch calls.
+func (p *proxy) TransactionsByHash(ctx context.Context, hashes []common.Hash) (map[common.Hash]*types.Transaction, error) {
+	res := make(map[common.Hash]*types.Transaction, len(hashes))
+	miss := make([]common.Hash, 0, len(hashes))
+	for i := range hashes {
+		if trx := p.cache.PullTransaction(&hashes[i]); trx != nil {
+			res[hashes[i]] = trx
+			continue
+		}
+		miss = append(miss, hashes[i])
+	}
+
+	if len(miss) == 0 {
+		return res, nil
+	}
+
+	list, err := p.rpc.Transactions(ctx, miss)
+	if err != nil {
+		return nil, err
+	}
+
+	for hash, trx := range list {
+		// pending transactions are not cached, see Transaction() above
+		if trx.BlockHash != nil {
+			p.cache.PushTransaction(trx)
+		}
+		res[hash] = trx
+	}
+	return res, nil
+}
+
 // LoadTransaction returns a transaction at Opera blockchain
 // by a hash loaded directly from the node.
-func (p *proxy) LoadTransaction(hash *common.Hash) (*types.Transaction, error) {
-	return p.rpc.Transaction(hash)
+func (p *proxy) LoadTransaction(ctx context.Context, hash *common.Hash) (*types.Transaction, error) {
+	return p.rpc.Transaction(ctx, hash)
 }
 
 // SendTransaction sends raw signed and RLP encoded transaction to the block chain.
-func (p *proxy) SendTransaction(tx hexutil.Bytes) (*types.Transaction, error) {
+func (p *proxy) SendTransaction(ctx context.Context, tx hexutil.Bytes) (*types.Transaction, error) {
 	p.log.Debugf("announcing trx %s", tx.String())
 
 	// try to send it and get the tx hash
-	hash, err := p.rpc.SendTransaction(tx)
+	hash, err := p.rpc.SendTransaction(ctx, tx)
 	if err != nil {
 		p.log.Errorf("can not send transaction to block chain; %s", err.Error())
 		return nil, err
@@ -87,7 +121,7 @@ func (p *proxy) SendTransaction(tx hexutil.Bytes) (*types.Transaction, error) {
 
 	// we do have the hash, so we can use it to get the transaction details
 	// we always need to go to RPC, and we will not try to store the transaction in cache yet
-	trx, err := p.rpc.Transaction(hash)
+	trx, err := p.rpc.Transaction(context.Background(), hash)
 	if err != nil {
 		// transaction simply not found?
 		if err == eth.ErrNoResult {
@@ -107,6 +141,9 @@ func (p *proxy) SendTransaction(tx hexutil.Bytes) (*types.Transaction, error) {
 
 	// log transaction hash
 	p.log.Noticef("trx %s from %s submitted", hash.String(), trx.From.String())
+
+	// keep track of the transaction until it's mined, replaced, or dropped
+	p.trackSubmittedTransaction(trx)
 	return trx, nil
 }
 
@@ -116,9 +153,9 @@ func (p *proxy) SendTransaction(tx hexutil.Bytes) (*types.Transaction, error) {
 // No-number boundaries are handled as follows:
 // 	- For positive count we start from the most recent transaction and scan to older transactions.
 // 	- For negative count we start from the first transaction and scan to newer transactions.
-func (p *proxy) Transactions(cursor *string, count int32) (*types.TransactionList, error) {
+func (p *proxy) Transactions(ctx context.Context, rf *types.RangeFilter, cursor *string, count int32) (*types.TransactionList, error) {
 	// we may be able to pull the list faster than from the db
-	if cursor == nil && count > 0 && count < cache.TransactionRingCacheSize {
+	if cursor == nil && rf.IsEmpty() && count > 0 && count < cache.TransactionRingCacheSize {
 		// pull the quick list
 		tl := p.cache.ListTransactions(int(count))
 
@@ -137,7 +174,7 @@ func (p *proxy) Transactions(cursor *string, count int32) (*types.TransactionLis
 	}
 
 	// use slow trx list pulling
-	return p.db.Transactions(cursor, count, nil)
+	return p.db.Transactions(ctx, cursor, count, db.TransactionRangeFilter(rf))
 }
 
 // StoreGasPricePeriod stores the given gas price period data in the persistent storage
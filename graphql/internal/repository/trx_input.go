@@ -0,0 +1,170 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// maxConstructorArgsScanWords represents the max number of 32 bytes words we scan
+// at the end of a contract deployment input to find encoded constructor arguments.
+const maxConstructorArgsScanWords = 512
+
+// DecodeTransactionInput decodes the input data of the given transaction using the ABI
+// of the recipient contract, or ABIs of well known token standards. Constructor arguments
+// are decoded for contract deployments. Nil is returned if the input can not be decoded.
+func (p *proxy) DecodeTransactionInput(ctx context.Context, trx *types.Transaction) (dc *types.DecodedCall, err error) {
+	// ABI unpacking of unexpected data should not take the resolver down
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Errorf("can not decode input of transaction %s; %v", trx.Hash.String(), r)
+			dc, err = nil, nil
+		}
+	}()
+
+	input, err := p.transactionInput(trx)
+	if err != nil {
+		return nil, err
+	}
+
+	// contract deployment; try to find the constructor arguments
+	if trx.To == nil {
+		if trx.ContractAddress == nil {
+			return nil, nil
+		}
+		return decodeConstructorCall(p.contractAbi(ctx, trx.ContractAddress), input), nil
+	}
+
+	// we need at least the method selector to do anything
+	if len(input) < 4 {
+		return nil, nil
+	}
+
+	for _, ab := range p.contractAbis(ctx, trx.To) {
+		if dc := decodeMethodCall(ab, input); dc != nil {
+			return dc, nil
+		}
+	}
+	return nil, nil
+}
+
+// transactionInput provides the full input data of the given transaction.
+// Large inputs are not stored off-chain, so we may need to load them from the node.
+func (p *proxy) transactionInput(trx *types.Transaction) (hexutil.Bytes, error) {
+	if !trx.LargeInput || len(trx.InputData) > 0 {
+		return trx.InputData, nil
+	}
+
+	full, err := p.rpc.Transaction(context.Background(), &trx.Hash)
+	if err != nil {
+		p.log.Errorf("can not load input of transaction %s; %s", trx.Hash.String(), err.Error())
+		return nil, err
+	}
+	return full.InputData, nil
+}
+
+// decodeMethodCall decodes the given call input data using the given ABI.
+// It returns nil if the method selector is not known to the ABI, or if the arguments
+// can not be unpacked.
+func decodeMethodCall(ab *abi.ABI, input []byte) *types.DecodedCall {
+	m, err := ab.MethodById(input[:4])
+	if err != nil {
+		return nil
+	}
+
+	values, err := m.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil
+	}
+
+	return &types.DecodedCall{
+		Method:    m.RawName,
+		Signature: m.Sig,
+		Args:      decodedArguments(m.Inputs, values),
+	}
+}
+
+// decodeConstructorCall decodes constructor arguments from the given contract deployment input.
+// The arguments are ABI encoded and appended to the deployment byte code, but we don't know the length
+// of the byte code. We scan the tail of the input for the shortest word aligned block
+// which unpacks into the constructor arguments and packs back into the very same block.
+func decodeConstructorCall(ab *abi.ABI, input []byte) *types.DecodedCall {
+	if ab == nil {
+		return nil
+	}
+
+	args := ab.Constructor.Inputs
+	dc := types.DecodedCall{
+		Method:    types.DecodedCallConstructor,
+		Signature: abiSignature(types.DecodedCallConstructor, args),
+		Args:      make([]*types.DecodedArgument, 0),
+	}
+
+	// no arguments expected
+	if len(args) == 0 {
+		return &dc
+	}
+
+	for w := 1; w <= maxConstructorArgsScanWords && w*32 <= len(input); w++ {
+		tail := input[len(input)-w*32:]
+
+		values, err := args.Unpack(tail)
+		if err != nil {
+			continue
+		}
+
+		packed, err := args.Pack(values...)
+		if err != nil || !bytes.Equal(packed, tail) {
+			continue
+		}
+
+		dc.Args = decodedArguments(args, values)
+		return &dc
+	}
+	return nil
+}
+
+// TransactionMethodName provides the name of the contract function called by the given transaction.
+// The ABI of the recipient contract is used if available, the signature database is consulted otherwise.
+// Nil is returned for contract deployments and calls of unknown functions.
+func (p *proxy) TransactionMethodName(ctx context.Context, trx *types.Transaction) (*string, error) {
+	if trx.To == nil {
+		return nil, nil
+	}
+
+	input, err := p.transactionInput(trx)
+	if err != nil {
+		return nil, err
+	}
+
+	// we need at least the method selector to do anything
+	if len(input) < 4 {
+		return nil, nil
+	}
+
+	// try the verified contract ABI first
+	if ab := p.contractAbi(ctx, trx.To); ab != nil {
+		if m, err := ab.MethodById(input[:4]); err == nil {
+			return &m.RawName, nil
+		}
+	}
+
+	// use the first candidate of the signature database
+	list := p.FunctionSignatures(input[:4])
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	name := list[0].Name()
+	return &name, nil
+}
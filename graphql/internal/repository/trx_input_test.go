@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/onsi/gomega"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testTokenAbi is the ABI of a token with constructor arguments and a few methods.
+const testTokenAbi = `[
+  {"type": "constructor", "inputs": [{"name": "name", "type": "string"}, {"name": "supply", "type": "uint256"}]},
+  {"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}]},
+  {"type": "function", "name": "setOwners", "inputs": [{"name": "owners", "type": "address[]"}]},
+  {"type": "function", "name": "pause", "inputs": []}
+]`
+
+// mustAbi parses the given ABI definition, or fails the test.
+func mustAbi(t *testing.T, def string) *abi.ABI {
+	ab, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatalf("invalid ABI; %s", err.Error())
+	}
+	return &ab
+}
+
+// mustPack packs the call of the given method, or fails the test.
+func mustPack(t *testing.T, ab *abi.ABI, method string, args ...interface{}) []byte {
+	data, err := ab.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("can not pack %s; %s", method, err.Error())
+	}
+	return data
+}
+
+// argValues provides the names and values of the decoded arguments.
+func argValues(list []*types.DecodedArgument) [][3]string {
+	out := make([][3]string, len(list))
+	for i, a := range list {
+		out[i] = [3]string{a.Name, a.Type, a.Value}
+	}
+	return out
+}
+
+func TestDecodeMethodCall(t *testing.T) {
+	ab := mustAbi(t, testTokenAbi)
+	to := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+	transfer := mustPack(t, ab, "transfer", to, big.NewInt(1000))
+
+	tests := []struct {
+		name  string
+		input []byte
+		sig   string
+		args  [][3]string
+	}{
+		{
+			name:  "static arguments",
+			input: transfer,
+			sig:   "transfer(address,uint256)",
+			args:  [][3]string{{"to", "address", to.String()}, {"amount", "uint256", "1000"}},
+		},
+		{
+			name:  "dynamic arguments",
+			input: mustPack(t, ab, "setOwners", []common.Address{to, to}),
+			sig:   "setOwners(address[])",
+			args:  [][3]string{{"owners", "address[]", `["` + to.String() + `","` + to.String() + `"]`}},
+		},
+		{
+			name:  "no arguments",
+			input: mustPack(t, ab, "pause"),
+			sig:   "pause()",
+			args:  [][3]string{},
+		},
+		{name: "unknown method", input: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "truncated arguments", input: transfer[:4+32]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			dc := decodeMethodCall(ab, tt.input)
+			if tt.sig == "" {
+				g.Expect(dc).To(gomega.BeNil())
+				return
+			}
+
+			g.Expect(dc).ToNot(gomega.BeNil())
+			g.Expect(dc.Signature).To(gomega.Equal(tt.sig))
+			g.Expect(dc.Method).To(gomega.Equal(tt.sig[:strings.Index(tt.sig, "(")]))
+			g.Expect(argValues(dc.Args)).To(gomega.Equal(tt.args))
+		})
+	}
+}
+
+func TestDecodeConstructorCall(t *testing.T) {
+	ab := mustAbi(t, testTokenAbi)
+	noArgs := mustAbi(t, `[{"type": "function", "name": "pause", "inputs": []}]`)
+
+	// the deployment byte code ends with a word aligned block of zeroes on purpose,
+	// the arguments must still be recognized by the shortest valid tail
+	code := append(common.FromHex("0x6080604052348015600f57600080fd5b50"), make([]byte, 32)...)
+	args, err := ab.Constructor.Inputs.Pack("Test Token", big.NewInt(1e6))
+	if err != nil {
+		t.Fatalf("can not pack constructor; %s", err.Error())
+	}
+
+	tests := []struct {
+		name  string
+		ab    *abi.ABI
+		input []byte
+		sig   string
+		args  [][3]string
+	}{
+		{
+			name:  "constructor arguments",
+			ab:    ab,
+			input: append(append([]byte{}, code...), args...),
+			sig:   "constructor(string,uint256)",
+			args:  [][3]string{{"name", "string", "Test Token"}, {"supply", "uint256", "1000000"}},
+		},
+		{
+			name:  "no constructor arguments",
+			ab:    noArgs,
+			input: code,
+			sig:   "constructor()",
+			args:  [][3]string{},
+		},
+		{name: "no ABI", ab: nil, input: append(append([]byte{}, code...), args...)},
+		{name: "arguments missing", ab: ab, input: code[:17]},
+		{name: "arguments damaged", ab: ab, input: append(append([]byte{}, code...), args[:len(args)-32]...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			dc := decodeConstructorCall(tt.ab, tt.input)
+			if tt.sig == "" {
+				g.Expect(dc).To(gomega.BeNil())
+				return
+			}
+
+			g.Expect(dc).ToNot(gomega.BeNil())
+			g.Expect(dc.Method).To(gomega.Equal(types.DecodedCallConstructor))
+			g.Expect(dc.Signature).To(gomega.Equal(tt.sig))
+			g.Expect(argValues(dc.Args)).To(gomega.Equal(tt.args))
+		})
+	}
+}
+
+func TestBundledAbis(t *testing.T) {
+	from := common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9")
+	to := common.HexToAddress("0x21be370d5312f44cb42ce377bc9b8a0cef1a4c83")
+
+	// ERC20 and ERC721 share the selector of the transferFrom method
+	input := mustPack(t, bundledAbis("")[0], "transferFrom", from, to, big.NewInt(7))
+
+	tests := []struct {
+		name      string
+		tokenType string
+		arg       string
+	}{
+		{name: "unknown type", tokenType: "", arg: "amount"},
+		{name: "ERC20 token", tokenType: types.AccountTypeERC20Token, arg: "amount"},
+		{name: "ERC721 token", tokenType: types.AccountTypeERC721Contract, arg: "_tokenId"},
+		{name: "generic contract", tokenType: types.AccountTypeContract, arg: "amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			list := bundledAbis(tt.tokenType)
+			g.Expect(list).To(gomega.HaveLen(len(bundledAbiTypes)))
+
+			dc := decodeMethodCall(list[0], input)
+			g.Expect(dc).ToNot(gomega.BeNil())
+			g.Expect(dc.Signature).To(gomega.Equal("transferFrom(address,address,uint256)"))
+			g.Expect(dc.Args[2].Name).To(gomega.Equal(tt.arg))
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"strings"
+)
+
+// LogEventSignature provides the text signature of the event emitted by the given log record.
+// The ABI of the emitting contract is used if available, the signature database is consulted otherwise.
+// Nil is returned for anonymous and unknown events.
+func (p *proxy) LogEventSignature(ctx context.Context, lg *etc.Log) *string {
+	if len(lg.Topics) == 0 {
+		return nil
+	}
+
+	// try the verified contract ABI first
+	if ab := p.contractAbi(ctx, &lg.Address); ab != nil {
+		if ev, err := ab.EventByID(lg.Topics[0]); err == nil {
+			return &ev.Sig
+		}
+	}
+
+	// use the first candidate of the signature database
+	list := p.EventSignatures(lg.Topics[0])
+	if len(list) == 0 {
+		return nil
+	}
+	return &list[0].Text
+}
+
+// DecodeLog decodes the given log record using the ABI of the emitting contract, ABIs of well known
+// token standards, or the signature database. Nil is returned for anonymous and unknown events.
+func (p *proxy) DecodeLog(ctx context.Context, lg *etc.Log) (de *types.DecodedEvent) {
+	// ABI unpacking of unexpected data should not take the resolver down
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Errorf("can not decode log #%d of transaction %s; %v", lg.Index, lg.TxHash.String(), r)
+			de = nil
+		}
+	}()
+
+	if len(lg.Topics) == 0 {
+		return nil
+	}
+
+	// try the verified contract ABI and the bundled ABIs
+	for _, ab := range p.contractAbis(ctx, &lg.Address) {
+		ev, err := ab.EventByID(lg.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		if de := decodeEvent(ev.RawName, ev.Sig, ev.Inputs, lg); de != nil {
+			return de
+		}
+	}
+
+	// try candidates of the signature database; we don't know which arguments
+	// are indexed, so we expect the leading arguments to be the indexed ones
+	for _, sig := range p.EventSignatures(lg.Topics[0]) {
+		args, err := signatureArguments(sig.Text, len(lg.Topics)-1)
+		if err != nil {
+			p.log.Debugf("can not parse signature %s; %s", sig.Text, err.Error())
+			continue
+		}
+
+		if de := decodeEvent(sig.Name(), sig.Text, args, lg); de != nil {
+			return de
+		}
+	}
+	return nil
+}
+
+// decodeEvent decodes the given log record using the given event arguments.
+// Indexed arguments are decoded from the log topics, the other arguments from the log data.
+// It returns nil if the log record does not match the arguments.
+func decodeEvent(name string, sig string, args abi.Arguments, lg *etc.Log) *types.DecodedEvent {
+	// the number of indexed arguments must match the number of topics
+	var indexed int
+	for _, arg := range args {
+		if arg.Indexed {
+			indexed++
+		}
+	}
+	if indexed != len(lg.Topics)-1 {
+		return nil
+	}
+
+	// unpack non-indexed values from the data
+	data, err := args.Unpack(lg.Data)
+	if err != nil {
+		return nil
+	}
+
+	de := types.DecodedEvent{
+		Name:      name,
+		Signature: sig,
+		Args:      make([]*types.DecodedArgument, len(args)),
+	}
+
+	var ti, di int
+	for i, arg := range args {
+		var value interface{}
+		if arg.Indexed {
+			ti++
+			value = topicValue(arg, lg.Topics[ti])
+		} else {
+			value = data[di]
+			di++
+		}
+
+		de.Args[i] = &types.DecodedArgument{
+			Name:  arg.Name,
+			Type:  arg.Type.String(),
+			Value: abiValueString(value),
+		}
+	}
+	return &de
+}
+
+// topicValue decodes the value of an indexed event argument from the given topic.
+// Dynamic types are stored in topics as a hash of the value, so the hash itself is provided.
+func topicValue(arg abi.Argument, topic common.Hash) interface{} {
+	switch arg.Type.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic
+	}
+
+	arg.Indexed = false
+	val, err := abi.Arguments{arg}.Unpack(topic.Bytes())
+	if err != nil || len(val) == 0 {
+		return topic
+	}
+	return val[0]
+}
+
+// signatureArguments builds a list of unnamed event arguments from the given text signature.
+// The given number of leading arguments is marked as indexed.
+func signatureArguments(text string, indexed int) (abi.Arguments, error) {
+	from, to := strings.Index(text, "("), strings.LastIndex(text, ")")
+	if from < 0 || to < from {
+		return nil, fmt.Errorf("invalid signature %s", text)
+	}
+
+	list := splitSignatureTypes(text[from+1 : to])
+	if indexed > len(list) {
+		return nil, fmt.Errorf("too many indexed arguments for %s", text)
+	}
+
+	args := make(abi.Arguments, len(list))
+	for i, t := range list {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = abi.Argument{Type: typ, Indexed: i < indexed}
+	}
+	return args, nil
+}
+
+// splitSignatureTypes splits the list of types of a text signature
+// respecting the nested tuple types.
+func splitSignatureTypes(list string) []string {
+	out := make([]string, 0)
+	if list == "" {
+		return out
+	}
+
+	var depth, start int
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, list[start:])
+}
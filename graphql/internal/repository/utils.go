@@ -9,6 +9,7 @@ results. BigCache for in-memory object storage to speed up loading of frequently
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"fantom-api-graphql/internal/types"
 	"fmt"
@@ -32,14 +33,14 @@ const (
 )
 
 // GasPrice pulls the current amount of WEI for single Gas.
-func (p *proxy) GasPrice() (hexutil.Big, error) {
-	return p.rpc.GasPrice()
+func (p *proxy) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	return p.rpc.GasPrice(ctx)
 }
 
 // GasPriceExtended provides extended gas price information.
-func (p *proxy) GasPriceExtended() (*types.GasPrice, error) {
+func (p *proxy) GasPriceExtended(ctx context.Context) (*types.GasPrice, error) {
 	// get the current gas price
-	gp, err := p.rpc.GasPrice()
+	gp, err := p.rpc.GasPrice(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -61,13 +62,13 @@ func (p *proxy) GasPriceTicks(from *time.Time, to *time.Time) ([]types.GasPriceP
 
 // GasEstimate calculates the estimated amount of Gas required to perform
 // transaction described by the input params.
-func (p *proxy) GasEstimate(trx *struct {
+func (p *proxy) GasEstimate(ctx context.Context, trx *struct {
 	From  *common.Address
 	To    *common.Address
 	Value *hexutil.Big
 	Data  *string
 }) (*hexutil.Uint64, error) {
-	return p.rpc.GasEstimate(trx)
+	return p.rpc.GasEstimate(ctx, trx)
 }
 
 // isValidPriceSymbol checks if the requested symbol is a valid price symbol we support
@@ -0,0 +1,72 @@
+/*
+Package repository implements repository for handling fast and efficient access to data required
+by the resolvers of the API server.
+
+Internally it utilizes RPC to access Opera/Lachesis full node for blockchain interaction. Mongo database
+for fast, robust and scalable off-chain data storage, especially for aggregated and pre-calculated data mining
+results. BigCache for in-memory object storage to speed up loading of frequently accessed entities.
+*/
+package repository
+
+import (
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"time"
+)
+
+// StoreWebhook validates and stores, or updates, the given webhook.
+func (p *proxy) StoreWebhook(wh *types.Webhook) error {
+	if !types.IsValidWebhookURL(wh.URL) {
+		return fmt.Errorf("invalid webhook URL %s", wh.URL)
+	}
+
+	if len(wh.Events) == 0 {
+		return fmt.Errorf("webhook must subscribe to at least one event")
+	}
+	for _, e := range wh.Events {
+		if e != types.WebhookEventTransaction && e != types.WebhookEventTokenTransfer {
+			return fmt.Errorf("unknown webhook event %s", e)
+		}
+	}
+
+	if len(wh.Addresses) == 0 && len(wh.Tokens) == 0 {
+		return fmt.Errorf("webhook must watch at least one address, or token")
+	}
+	return p.db.StoreWebhook(wh)
+}
+
+// DeleteWebhook removes the webhook of the given ID together with its delivery log.
+func (p *proxy) DeleteWebhook(id string) error {
+	return p.db.DeleteWebhook(id)
+}
+
+// Webhook provides the webhook of the given ID; nil if not found.
+func (p *proxy) Webhook(id string) (*types.Webhook, error) {
+	return p.db.Webhook(id)
+}
+
+// Webhooks provides the list of all registered webhooks.
+func (p *proxy) Webhooks() ([]*types.Webhook, error) {
+	return p.db.Webhooks()
+}
+
+// AddWebhookDelivery stores a new webhook delivery, unless a delivery of the same ID is already known.
+// It reports if the delivery has been added.
+func (p *proxy) AddWebhookDelivery(wd *types.WebhookDelivery) (bool, error) {
+	return p.db.AddWebhookDelivery(wd)
+}
+
+// UpdateWebhookDelivery updates the state of the given webhook delivery.
+func (p *proxy) UpdateWebhookDelivery(wd *types.WebhookDelivery) error {
+	return p.db.UpdateWebhookDelivery(wd)
+}
+
+// WebhookDeliveriesDue provides the list of pending webhook deliveries scheduled for an attempt by the given time.
+func (p *proxy) WebhookDeliveriesDue(ts time.Time) ([]*types.WebhookDelivery, error) {
+	return p.db.WebhookDeliveriesDue(ts)
+}
+
+// WebhookDeliveries provides the list of the most recent deliveries of the given webhook.
+func (p *proxy) WebhookDeliveries(hook string, count int32) ([]*types.WebhookDelivery, error) {
+	return p.db.WebhookDeliveries(hook, int64(count))
+}
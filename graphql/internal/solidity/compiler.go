@@ -3,3 +3,259 @@
 package solidity
 
 //go:generate sh ./tools/compile_releases.sh "../../../solidity"
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// CodeFormatSingleFile represents a source code provided as a single Solidity file.
+	CodeFormatSingleFile = "solidity-single-file"
+
+	// CodeFormatStandardJson represents a source code provided as the compiler standard JSON input.
+	CodeFormatStandardJson = "solidity-standard-json-input"
+
+	// singleFileSourceName represents the name of the source unit of a single file source code.
+	singleFileSourceName = "contract.sol"
+)
+
+// versionRegexp represents the syntax of a compiler release version, i.e. v0.8.17+commit.8df45f5f
+var versionRegexp = regexp.MustCompile(`^v?(\d+\.\d+\.\d+)(\+commit\.[0-9a-f]{8})?$`)
+
+// outputSelection represents the compiler output needed to verify a contract.
+var outputSelection = map[string]map[string][]string{
+	"*": {"*": {"abi", "metadata", "evm.deployedBytecode.object", "evm.deployedBytecode.immutableReferences"}},
+}
+
+// Settings represents the compiler settings of a single file source code.
+type Settings struct {
+	Optimized    bool
+	OptimizeRuns int32
+	EvmVersion   string
+}
+
+// Contract represents a contract built by the compiler.
+type Contract struct {
+	// Name represents the name of the contract, qualified by the source unit, i.e. contract.sol:Token
+	Name string
+
+	// Abi represents the JSON ABI definition of the contract.
+	Abi string
+
+	// Compiler represents the version of the compiler used, i.e. v0.8.17+commit.8df45f5f
+	Compiler string
+
+	// RuntimeCode represents the deployed byte code of the contract.
+	RuntimeCode []byte
+
+	// Immutables represents the [offset, length] ranges of immutable values in the runtime code.
+	Immutables [][2]int
+}
+
+// CompilerPath resolves the path of the compiler binary of the given release version.
+// The default compiler is used if no version is requested; other releases are expected
+// in the releases directory named by the version, i.e. solc-v0.8.17
+func CompilerPath(def string, releases string, version string) (string, error) {
+	if version == "" || version == "latest" {
+		return def, nil
+	}
+
+	match := versionRegexp.FindStringSubmatch(version)
+	if match == nil {
+		return "", fmt.Errorf("invalid compiler version %s", version)
+	}
+
+	if releases == "" {
+		return "", fmt.Errorf("compiler version %s not available", version)
+	}
+
+	path := filepath.Join(releases, "solc-v"+match[1])
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("compiler version %s not available", version)
+	}
+	return path, nil
+}
+
+// SingleFileInput builds the compiler standard JSON input of the given single file source code.
+func SingleFileInput(source string, set *Settings) ([]byte, error) {
+	settings := map[string]interface{}{
+		"optimizer":       map[string]interface{}{"enabled": set.Optimized, "runs": set.OptimizeRuns},
+		"outputSelection": outputSelection,
+	}
+	if set.EvmVersion != "" && set.EvmVersion != "default" {
+		settings["evmVersion"] = set.EvmVersion
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"language": "Solidity",
+		"sources":  map[string]interface{}{singleFileSourceName: map[string]string{"content": source}},
+		"settings": settings,
+	})
+}
+
+// StandardJsonInput prepares the given compiler standard JSON input for the verification.
+// The compiler settings of the input are kept, only the output selection is replaced.
+// Sources must be provided by content, the compiler is not allowed to access local files.
+func StandardJsonInput(input string) ([]byte, error) {
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(input), &in); err != nil {
+		return nil, fmt.Errorf("invalid standard JSON input; %s", err.Error())
+	}
+
+	var sources map[string]struct {
+		Content *string `json:"content"`
+	}
+	if err := json.Unmarshal(in["sources"], &sources); err != nil || len(sources) == 0 {
+		return nil, fmt.Errorf("standard JSON input sources not found")
+	}
+	for name, src := range sources {
+		if src.Content == nil {
+			return nil, fmt.Errorf("content of source %s not provided", name)
+		}
+	}
+
+	settings := make(map[string]interface{})
+	if raw, ok := in["settings"]; ok {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return nil, fmt.Errorf("invalid standard JSON input settings; %s", err.Error())
+		}
+	}
+	settings["outputSelection"] = outputSelection
+
+	var err error
+	if in["settings"], err = json.Marshal(settings); err != nil {
+		return nil, err
+	}
+	return json.Marshal(in)
+}
+
+// Compile builds the given standard JSON input with the compiler on the given path.
+func Compile(ctx context.Context, path string, input []byte) ([]Contract, error) {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "--standard-json")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("compiler failed; %s", err.Error())
+	}
+	return decodeOutput(out.Bytes())
+}
+
+// decodeOutput decodes the compiler standard JSON output into the list of contracts.
+func decodeOutput(data []byte) ([]Contract, error) {
+	var out struct {
+		Errors []struct {
+			Severity         string `json:"severity"`
+			FormattedMessage string `json:"formattedMessage"`
+			Message          string `json:"message"`
+		} `json:"errors"`
+		Contracts map[string]map[string]struct {
+			Abi      json.RawMessage `json:"abi"`
+			Metadata string          `json:"metadata"`
+			Evm      struct {
+				DeployedBytecode struct {
+					Object     string `json:"object"`
+					Immutables map[string][]struct {
+						Start  int `json:"start"`
+						Length int `json:"length"`
+					} `json:"immutableReferences"`
+				} `json:"deployedBytecode"`
+			} `json:"evm"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid compiler output; %s", err.Error())
+	}
+
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			msg := e.FormattedMessage
+			if msg == "" {
+				msg = e.Message
+			}
+			return nil, fmt.Errorf("compilation failed; %s", strings.TrimSpace(msg))
+		}
+	}
+
+	list := make([]Contract, 0)
+	for unit, contracts := range out.Contracts {
+		for name, c := range contracts {
+			// unlinked libraries leave placeholders in the code; we can not match those
+			code, err := hex.DecodeString(strings.TrimPrefix(c.Evm.DeployedBytecode.Object, "0x"))
+			if err != nil || len(code) == 0 {
+				continue
+			}
+
+			sc := Contract{
+				Name:        unit + ":" + name,
+				Abi:         string(c.Abi),
+				Compiler:    metadataCompiler(c.Metadata),
+				RuntimeCode: code,
+			}
+			for _, refs := range c.Evm.DeployedBytecode.Immutables {
+				for _, ref := range refs {
+					sc.Immutables = append(sc.Immutables, [2]int{ref.Start, ref.Length})
+				}
+			}
+			list = append(list, sc)
+		}
+	}
+	return list, nil
+}
+
+// metadataCompiler extracts the compiler version from the contract metadata.
+func metadataCompiler(metadata string) string {
+	var meta struct {
+		Compiler struct {
+			Version string `json:"version"`
+		} `json:"compiler"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil || meta.Compiler.Version == "" {
+		return ""
+	}
+	return "v" + meta.Compiler.Version
+}
+
+// Matches checks if the contract runtime code matches the given deployed byte code.
+// The trailing metadata, which depends on the source code layout, is not compared;
+// the immutable values, set on the deployment, are taken from the deployed code.
+func (sc *Contract) Matches(deployed []byte) bool {
+	if len(deployed) != len(sc.RuntimeCode) {
+		return false
+	}
+
+	code := make([]byte, len(deployed))
+	copy(code, deployed)
+	for _, ref := range sc.Immutables {
+		if ref[0] < 0 || ref[0]+ref[1] > len(code) {
+			return false
+		}
+		copy(code[ref[0]:ref[0]+ref[1]], make([]byte, ref[1]))
+	}
+
+	return bytes.Equal(stripMetadata(code), stripMetadata(sc.RuntimeCode))
+}
+
+// stripMetadata removes the CBOR encoded metadata appended by the compiler to the byte code.
+// The metadata length is stored in the last two bytes of the code.
+func stripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+
+	size := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - size
+	if size == 0 || start < 0 || code[start]&0xf0 != 0xa0 {
+		return code
+	}
+	return code[:start]
+}
@@ -0,0 +1,234 @@
+package solidity
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/onsi/gomega"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testOutput is a compiler output of a contract with an immutable value and the metadata.
+const testOutput = `{
+  "errors": [{"severity": "warning", "message": "SPDX license identifier not provided"}],
+  "contracts": {
+    "contract.sol": {
+      "Token": {
+        "abi": [{"type": "function", "name": "owner", "inputs": [], "outputs": [{"type": "address"}]}],
+        "metadata": "{\"compiler\":{\"version\":\"0.8.17+commit.8df45f5f\"}}",
+        "evm": {"deployedBytecode": {
+          "object": "6080000000005ba16500000000000007",
+          "immutableReferences": {"3": [{"start": 2, "length": 4}]}
+        }}
+      },
+      "Library": {
+        "abi": [],
+        "metadata": "",
+        "evm": {"deployedBytecode": {"object": "73__$d1c3e8b1a2f2e5d3f1e8b1a2f2e5d3f1e8$__"}}
+      }
+    }
+  }
+}`
+
+func TestCompilerPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "solc-v0.8.17"), []byte{}, 0755); err != nil {
+		t.Fatalf("can not create compiler; %s", err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		releases string
+		version  string
+		want     string
+		valid    bool
+	}{
+		{name: "default", releases: dir, version: "", want: "/usr/bin/solc", valid: true},
+		{name: "latest", releases: dir, version: "latest", want: "/usr/bin/solc", valid: true},
+		{name: "release", releases: dir, version: "v0.8.17+commit.8df45f5f", want: filepath.Join(dir, "solc-v0.8.17"), valid: true},
+		{name: "release without prefix", releases: dir, version: "0.8.17", want: filepath.Join(dir, "solc-v0.8.17"), valid: true},
+		{name: "missing release", releases: dir, version: "v0.8.16+commit.07a7930e"},
+		{name: "no releases", releases: "", version: "v0.8.17+commit.8df45f5f"},
+		{name: "path traversal", releases: dir, version: "../../bin/sh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			path, err := CompilerPath("/usr/bin/solc", tt.releases, tt.version)
+			if !tt.valid {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(path).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestSingleFileInput(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Settings
+		want string
+	}{
+		{
+			name: "optimized",
+			set:  Settings{Optimized: true, OptimizeRuns: 1000, EvmVersion: "london"},
+			want: `{"enabled":true,"runs":1000}`,
+		},
+		{
+			name: "default evm",
+			set:  Settings{EvmVersion: "default"},
+			want: `{"enabled":false,"runs":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			data, err := SingleFileInput("contract A {}", &tt.set)
+			g.Expect(err).To(gomega.BeNil())
+
+			var in struct {
+				Sources  map[string]struct{ Content string }
+				Settings map[string]json.RawMessage
+			}
+			g.Expect(json.Unmarshal(data, &in)).To(gomega.Succeed())
+			g.Expect(in.Sources[singleFileSourceName].Content).To(gomega.Equal("contract A {}"))
+			g.Expect(string(in.Settings["optimizer"])).To(gomega.MatchJSON(tt.want))
+			g.Expect(in.Settings).To(gomega.HaveKey("outputSelection"))
+
+			if tt.set.EvmVersion == "default" {
+				g.Expect(in.Settings).ToNot(gomega.HaveKey("evmVersion"))
+			} else {
+				g.Expect(string(in.Settings["evmVersion"])).To(gomega.Equal(`"` + tt.set.EvmVersion + `"`))
+			}
+		})
+	}
+}
+
+func TestStandardJsonInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{
+			name:  "settings kept",
+			input: `{"language":"Solidity","sources":{"a.sol":{"content":"contract A {}"}},"settings":{"optimizer":{"enabled":true,"runs":200},"evmVersion":"london","outputSelection":{"*":{"*":["*"]}}}}`,
+			valid: true,
+		},
+		{
+			name:  "no settings",
+			input: `{"language":"Solidity","sources":{"a.sol":{"content":"contract A {}"}}}`,
+			valid: true,
+		},
+		{name: "not JSON", input: `contract A {}`},
+		{name: "no sources", input: `{"language":"Solidity","sources":{}}`},
+		{name: "source by url", input: `{"language":"Solidity","sources":{"a.sol":{"urls":["/etc/passwd"]}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			data, err := StandardJsonInput(tt.input)
+			if !tt.valid {
+				g.Expect(err).ToNot(gomega.BeNil())
+				return
+			}
+			g.Expect(err).To(gomega.BeNil())
+
+			var in, orig struct {
+				Sources  json.RawMessage
+				Settings map[string]json.RawMessage
+			}
+			g.Expect(json.Unmarshal(data, &in)).To(gomega.Succeed())
+			g.Expect(json.Unmarshal([]byte(tt.input), &orig)).To(gomega.Succeed())
+			g.Expect(string(in.Sources)).To(gomega.MatchJSON(string(orig.Sources)))
+
+			sel, _ := json.Marshal(outputSelection)
+			g.Expect(string(in.Settings["outputSelection"])).To(gomega.MatchJSON(string(sel)))
+			for key, val := range orig.Settings {
+				if key != "outputSelection" {
+					g.Expect(string(in.Settings[key])).To(gomega.MatchJSON(string(val)))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	list, err := decodeOutput([]byte(testOutput))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list).To(gomega.HaveLen(1))
+	g.Expect(list[0].Name).To(gomega.Equal("contract.sol:Token"))
+	g.Expect(list[0].Compiler).To(gomega.Equal("v0.8.17+commit.8df45f5f"))
+	g.Expect(list[0].Abi).To(gomega.ContainSubstring(`"name": "owner"`))
+	g.Expect(list[0].Immutables).To(gomega.Equal([][2]int{{2, 4}}))
+
+	_, err = decodeOutput([]byte(`{"errors": [{"severity": "error", "formattedMessage": "ParserError: Expected ';'\n"}]}`))
+	g.Expect(err).To(gomega.MatchError("compilation failed; ParserError: Expected ';'"))
+}
+
+func TestContractMatches(t *testing.T) {
+	list, err := decodeOutput([]byte(testOutput))
+	if err != nil || len(list) != 1 {
+		t.Fatalf("can not decode output; %v", err)
+	}
+	sc := list[0]
+
+	tests := []struct {
+		name     string
+		deployed string
+		want     bool
+	}{
+		{name: "same code", deployed: "6080000000005ba16500000000000007", want: true},
+		{name: "immutable value set", deployed: "60801234abcd5ba16500000000000007", want: true},
+		{name: "other metadata", deployed: "6080000000005ba165ffffffffff0007", want: true},
+		{name: "other code", deployed: "6081000000005ba16500000000000007", want: false},
+		{name: "other length", deployed: "6080000000005ba16500000000000000000008", want: false},
+		{name: "no code", deployed: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			code, err := hex.DecodeString(tt.deployed)
+			g.Expect(err).To(gomega.BeNil())
+			g.Expect(sc.Matches(code)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the compiler substitute checks the standard JSON mode and prints the prepared output
+	dir := t.TempDir()
+	out := filepath.Join(dir, "output.json")
+	g.Expect(os.WriteFile(out, []byte(testOutput), 0644)).To(gomega.Succeed())
+
+	sol := filepath.Join(dir, "solc")
+	script := "#!/bin/sh\n[ \"$1\" = \"--standard-json\" ] || exit 1\ncat > /dev/null\ncat " + out + "\n"
+	g.Expect(os.WriteFile(sol, []byte(script), 0755)).To(gomega.Succeed())
+
+	in, err := SingleFileInput("contract Token {}", &Settings{})
+	g.Expect(err).To(gomega.BeNil())
+
+	list, err := Compile(context.Background(), sol, in)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list).To(gomega.HaveLen(1))
+	g.Expect(list[0].Name).To(gomega.Equal("contract.sol:Token"))
+
+	_, err = Compile(context.Background(), filepath.Join(dir, "missing"), in)
+	g.Expect(err).ToNot(gomega.BeNil())
+}
@@ -0,0 +1,218 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// alertSinkWebhookAttempts represents the number of attempts to push an alert to a webhook sink.
+	alertSinkWebhookAttempts = 3
+
+	// alertSinkWebhookTimeout represents the time a webhook sink has to accept an alert.
+	alertSinkWebhookTimeout = 10 * time.Second
+)
+
+// alertSink defines the interface of a target alerts are pushed to.
+type alertSink interface {
+	// send pushes the given alert to the sink.
+	send(*types.Alert) error
+
+	// close releases resources held by the sink.
+	close()
+}
+
+// alertSinkFactory maps the configured sink type to the sink constructor.
+// New sink types are plugged in here.
+var alertSinkFactory = map[string]func(config.AlertSink) (alertSink, error){
+	"log":     newLogAlertSink,
+	"file":    newFileAlertSink,
+	"webhook": newWebhookAlertSink,
+}
+
+// alertPayload represents the JSON encoded alert pushed to sinks.
+type alertPayload struct {
+	ID          string          `json:"id"`
+	Rule        string          `json:"rule"`
+	Name        string          `json:"name"`
+	Event       string          `json:"event"`
+	Transaction common.Hash     `json:"transaction"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Token       *common.Address `json:"token,omitempty"`
+	Value       hexutil.Big     `json:"value"`
+	Message     string          `json:"message"`
+	Timestamp   int64           `json:"timestamp"`
+}
+
+// newAlertSink creates a new alert sink from the given configuration.
+func newAlertSink(sc config.AlertSink) (alertSink, error) {
+	factory, ok := alertSinkFactory[sc.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown alert sink type %s", sc.Type)
+	}
+	return factory(sc)
+}
+
+// encodeAlert provides the JSON encoded alert pushed to sinks.
+func encodeAlert(alert *types.Alert) ([]byte, error) {
+	return json.Marshal(alertPayload{
+		ID:          alert.ID,
+		Rule:        alert.Rule,
+		Name:        alert.RuleName,
+		Event:       alert.Event,
+		Transaction: alert.Transaction,
+		BlockNumber: hexutil.Uint64(alert.BlockNumber),
+		From:        alert.From,
+		To:          alert.To,
+		Token:       alert.Token,
+		Value:       alert.Value,
+		Message:     alert.Message,
+		Timestamp:   alert.TimeStamp.Unix(),
+	})
+}
+
+// logAlertSink implements alert sink writing alerts into the application log.
+type logAlertSink struct{}
+
+// newLogAlertSink creates a new application log alert sink.
+func newLogAlertSink(_ config.AlertSink) (alertSink, error) {
+	return &logAlertSink{}, nil
+}
+
+// send writes the alert into the application log.
+func (ls *logAlertSink) send(alert *types.Alert) error {
+	log.Warningf("alert %s; %s", alert.RuleName, alert.Message)
+	return nil
+}
+
+// close releases resources held by the sink.
+func (ls *logAlertSink) close() {}
+
+// fileAlertSink implements alert sink appending JSON encoded alerts to a file, one alert per line.
+type fileAlertSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newFileAlertSink creates a new file alert sink.
+func newFileAlertSink(sc config.AlertSink) (alertSink, error) {
+	if sc.Path == "" {
+		return nil, fmt.Errorf("alert sink %s file path not set", sc.Name)
+	}
+
+	f, err := os.OpenFile(sc.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileAlertSink{file: f}, nil
+}
+
+// send appends the alert to the file.
+func (fs *fileAlertSink) send(alert *types.Alert) error {
+	data, err := encodeAlert(alert)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	_, err = fs.file.Write(append(data, '\n'))
+	return err
+}
+
+// close closes the file.
+func (fs *fileAlertSink) close() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.file.Close(); err != nil {
+		log.Errorf("can not close alert sink file; %s", err.Error())
+	}
+}
+
+// webhookAlertSink implements alert sink posting JSON encoded alerts to a webhook.
+// Alerts are signed the same way as address activity webhook deliveries.
+type webhookAlertSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// newWebhookAlertSink creates a new webhook alert sink.
+func newWebhookAlertSink(sc config.AlertSink) (alertSink, error) {
+	if sc.URL == "" {
+		return nil, fmt.Errorf("alert sink %s URL not set", sc.Name)
+	}
+	return &webhookAlertSink{
+		url:    sc.URL,
+		secret: sc.Secret,
+		client: &http.Client{Timeout: alertSinkWebhookTimeout},
+	}, nil
+}
+
+// send posts the alert to the webhook; failed attempts are retried with a growing delay.
+func (ws *webhookAlertSink) send(alert *types.Alert) error {
+	data, err := encodeAlert(alert)
+	if err != nil {
+		return err
+	}
+
+	delay := time.Second
+	for i := 1; ; i++ {
+		err = ws.post(data)
+		if err == nil || i >= alertSinkWebhookAttempts {
+			return err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// post makes a single attempt to post the given alert data to the webhook.
+func (ws *webhookAlertSink) post(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, ws.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fantom-api-graphql")
+	if ws.secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(ws.secret, data))
+	}
+
+	res, err := ws.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response %s", res.Status)
+	}
+	return nil
+}
+
+// close releases resources held by the sink.
+func (ws *webhookAlertSink) close() {
+	ws.client.CloseIdleConnections()
+}
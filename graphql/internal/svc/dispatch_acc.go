@@ -147,21 +147,25 @@ func (acd *accDispatcher) detectContract(addr *common.Address, block *types.Bloc
 	isErc1155, err := repo.Erc165SupportsInterface(addr, erc1155InterfaceId)
 	if err == nil && isErc1155 {
 		log.Noticef("ERC1155 multi-token detected at %s", addr.String())
-		contract := types.NewErcTokenContract(addr, "", block, trx, types.AccountTypeERC1155Contract, contracts.ERC1155MetaData.ABI)
+		name, symbol := acd.detectErc1155Names(addr)
+		contract := types.NewErcTokenContract(addr, name, block, trx, types.AccountTypeERC1155Contract, contracts.ERC1155MetaData.ABI)
+		contract.Symbol = symbol
 		return contract, types.AccountTypeERC1155Contract, nil
 	}
 
-	isErc721, name := acd.detectErc721Token(addr)
+	isErc721, name, symbol := acd.detectErc721Token(addr)
 	if err == nil && isErc721 {
 		log.Noticef("ERC721 NFT token detected at %s", addr.String())
 		contract := types.NewErcTokenContract(addr, name, block, trx, types.AccountTypeERC721Contract, contracts.ERC721MetaData.ABI)
+		contract.Symbol = symbol
 		return contract, types.AccountTypeERC721Contract, nil
 	}
 
-	isErc20, name := acd.detectErc20Token(addr)
+	isErc20, name, symbol := acd.detectErc20Token(addr)
 	if isErc20 {
 		log.Noticef("ERC20 token %s detected at %s", name, addr.String())
 		contract := types.NewErcTokenContract(addr, name, block, trx, types.AccountTypeERC20Token, contracts.ERCTwentyMetaData.ABI)
+		contract.Symbol = symbol
 		return contract, types.AccountTypeERC20Token, nil
 	}
 
@@ -172,43 +176,65 @@ func (acd *accDispatcher) detectContract(addr *common.Address, block *types.Bloc
 	return types.NewGenericContract(addr, block, trx), types.AccountTypeContract, nil
 }
 
+// detectErc1155Names tries to get the name and the symbol of an ERC1155 multi-token;
+// both are optional, they are not part of the standard.
+func (acd *accDispatcher) detectErc1155Names(addr *common.Address) (name string, symbol string) {
+	name, err := repo.Erc1155Name(addr)
+	if err != nil {
+		return "", ""
+	}
+
+	symbol, err = repo.Erc1155Symbol(addr)
+	if err != nil {
+		return name, ""
+	}
+	return name, symbol
+}
+
 // detectErc20Token identifies ERC20 token contracts by trying to call specific contract methods.
-func (acd *accDispatcher) detectErc20Token(addr *common.Address) (isErc20 bool, name string) {
+func (acd *accDispatcher) detectErc20Token(addr *common.Address) (isErc20 bool, name string, symbol string) {
 	// try to get the token name
 	name, err := repo.Erc20Name(addr)
 	if err != nil {
-		return false, ""
+		return false, "", ""
 	}
 
 	// try to detect symbol
-	if _, err := repo.Erc20Symbol(addr); err != nil {
-		return false, ""
+	symbol, err = repo.Erc20Symbol(addr)
+	if err != nil {
+		return false, "", ""
 	}
 
 	// try to detect balance of
 	if _, err := repo.Erc20BalanceOf(addr, &testAddress); err != nil {
-		return false, ""
+		return false, "", ""
 	}
 
 	// try to detect total supply
 	if _, err := repo.Erc20TotalSupply(addr); err != nil {
-		return false, ""
+		return false, "", ""
 	}
 
-	return true, name
+	return true, name, symbol
 }
 
-func (acd *accDispatcher) detectErc721Token(addr *common.Address) (isErc721 bool, name string) {
+func (acd *accDispatcher) detectErc721Token(addr *common.Address) (isErc721 bool, name string, symbol string) {
 	isErc721, err := repo.Erc165SupportsInterface(addr, erc721InterfaceId)
 	if err != nil || !isErc721 {
-		return false, ""
+		return false, "", ""
 	}
 
 	// try to detect name - but it is optional for ERC721
 	name, err = repo.Erc20Name(addr)
 	if err != nil {
-		return true, ""
+		return true, "", ""
+	}
+
+	// the symbol is optional, too
+	symbol, err = repo.Erc721Symbol(addr)
+	if err != nil {
+		return true, name, ""
 	}
 
-	return true, name
+	return true, name, symbol
 }
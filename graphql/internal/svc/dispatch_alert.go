@@ -0,0 +1,440 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"bytes"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// alertRulesReloadInterval represents the interval in which the alert rules are re-loaded.
+	alertRulesReloadInterval = 30 * time.Second
+
+	// alertQueueCapacity represents the capacity of the queue of alerts waiting to be pushed to sinks.
+	alertQueueCapacity = 500
+
+	// alertPushRetryInterval represents the interval in which alerts due for another push attempt are re-loaded.
+	alertPushRetryInterval = 10 * time.Second
+
+	// alertPushMaxAttempts represents the number of attempts after which pushing an alert is abandoned.
+	alertPushMaxAttempts = 10
+
+	// alertConfigRulePrefix represents the prefix of IDs of alert rules loaded from the configuration.
+	alertConfigRulePrefix = "config:"
+)
+
+// alertEngine implements evaluation of declarative alert rules against processed transactions
+// and token transactions. Matching alerts are stored and pushed to the configured sinks.
+type alertEngine struct {
+	service
+
+	// inTransaction represents the source of processed transactions
+	inTransaction chan *types.Transaction
+
+	// inTokenTrx represents the source of processed token transactions
+	inTokenTrx chan *types.TokenTransaction
+
+	// rules represents the list of enabled alert rules
+	rules []*types.AlertRule
+
+	// sinks represents the configured alert sinks by their name
+	sinks map[string]alertSink
+
+	// queue represents the alerts waiting to be pushed to sinks
+	queue chan *types.Alert
+
+	// inFlight represents the alerts queued, or being pushed, by their ID
+	inFlight map[string]bool
+	mu       sync.Mutex
+
+	// lastOrdinal represents the ordinal index of the last alert raised
+	lastOrdinal uint64
+
+	reloadTicker *time.Ticker
+	retryTicker  *time.Ticker
+	sinksDone    chan struct{}
+}
+
+// name returns the name of the service used by orchestrator.
+func (ale *alertEngine) name() string {
+	return "alert engine"
+}
+
+// init prepares the alert engine to perform its function.
+func (ale *alertEngine) init() {
+	ale.sigStop = make(chan struct{})
+	ale.sinksDone = make(chan struct{})
+	ale.queue = make(chan *types.Alert, alertQueueCapacity)
+	ale.inFlight = make(map[string]bool)
+	ale.sinks = make(map[string]alertSink, len(cfg.Alerting.Sinks))
+
+	for _, sc := range cfg.Alerting.Sinks {
+		sink, err := newAlertSink(sc)
+		if err != nil {
+			log.Errorf("can not create alert sink %s; %s", sc.Name, err.Error())
+			continue
+		}
+		ale.sinks[sc.Name] = sink
+	}
+}
+
+// run starts the alert engine job
+func (ale *alertEngine) run() {
+	// make sure we are orchestrated
+	if ale.mgr == nil {
+		panic(fmt.Errorf("no svc manager set on %s", ale.name()))
+	}
+
+	// start the sinks pusher
+	go ale.push()
+
+	// signal orchestrator we started and go
+	ale.mgr.started(ale)
+	go ale.execute()
+}
+
+// close terminates the alert engine.
+func (ale *alertEngine) close() {
+	if ale.reloadTicker != nil {
+		ale.reloadTicker.Stop()
+	}
+	if ale.retryTicker != nil {
+		ale.retryTicker.Stop()
+	}
+	if ale.sigStop != nil {
+		close(ale.sigStop)
+	}
+}
+
+// execute evaluates the alert rules against incoming transactions and token transactions.
+func (ale *alertEngine) execute() {
+	defer func() {
+		<-ale.sinksDone
+		for _, sink := range ale.sinks {
+			sink.close()
+		}
+		ale.mgr.finished(ale)
+	}()
+
+	ale.reloadTicker = time.NewTicker(alertRulesReloadInterval)
+	ale.retryTicker = time.NewTicker(alertPushRetryInterval)
+	ale.reload()
+
+	for {
+		select {
+		case <-ale.sigStop:
+			return
+		case <-ale.reloadTicker.C:
+			ale.reload()
+		case <-ale.retryTicker.C:
+			ale.retry()
+		case trx, ok := <-ale.inTransaction:
+			if !ok {
+				return
+			}
+			ale.onTransaction(trx)
+		case tt, ok := <-ale.inTokenTrx:
+			if !ok {
+				return
+			}
+			ale.onTokenTransaction(tt)
+		}
+	}
+}
+
+// reload updates the list of enabled alert rules from the configuration and the persistent storage.
+func (ale *alertEngine) reload() {
+	rules := make([]*types.AlertRule, 0, len(cfg.Alerting.Rules))
+	for i := range cfg.Alerting.Rules {
+		rules = append(rules, configAlertRule(i))
+	}
+
+	stored, err := repo.AlertRules()
+	if err != nil {
+		log.Errorf("can not load alert rules; %s", err.Error())
+	}
+	rules = append(rules, stored...)
+
+	ale.rules = make([]*types.AlertRule, 0, len(rules))
+	for _, r := range rules {
+		if !r.Enabled {
+			continue
+		}
+		if r.Event != types.AlertEventTransaction && r.Event != types.AlertEventTokenTransfer {
+			log.Errorf("alert rule %s has unknown event %s", r.Name, r.Event)
+			continue
+		}
+		ale.rules = append(ale.rules, r)
+	}
+}
+
+// configAlertRule converts the alert rule configuration of the given index into an alert rule.
+func configAlertRule(ix int) *types.AlertRule {
+	rc := cfg.Alerting.Rules[ix]
+	return &types.AlertRule{
+		ID:           alertConfigRulePrefix + rc.Name,
+		Name:         rc.Name,
+		Event:        rc.Event,
+		Enabled:      true,
+		From:         nonEmptyAddress(rc.From),
+		To:           nonEmptyAddress(rc.To),
+		Address:      nonEmptyAddress(rc.Address),
+		Token:        nonEmptyAddress(rc.Token),
+		TokenTrxType: rc.TokenTrxType,
+		Method:       types.AlertMethodSelector(rc.Method),
+		MinValue:     types.AlertMinValue(rc.MinValue),
+		Status:       rc.Status,
+		Sinks:        rc.Sinks,
+	}
+}
+
+// nonEmptyAddress provides a pointer to the given address; nil if the address is empty.
+func nonEmptyAddress(adr common.Address) *common.Address {
+	if adr == (common.Address{}) {
+		return nil
+	}
+	return &adr
+}
+
+// matchParties checks the sender and the recipient conditions of the given rule.
+func matchParties(r *types.AlertRule, from *common.Address, to *common.Address) bool {
+	if r.From != nil && *r.From != *from {
+		return false
+	}
+	if r.To != nil && (to == nil || *r.To != *to) {
+		return false
+	}
+	if r.Address != nil && *r.Address != *from && (to == nil || *r.Address != *to) {
+		return false
+	}
+	return true
+}
+
+// matchMinValue checks the min value condition of the given rule.
+func matchMinValue(r *types.AlertRule, val *big.Int) bool {
+	return r.MinValue == nil || val.Cmp(r.MinValue) >= 0
+}
+
+// matchTransaction checks if the given rule matches the transaction.
+func matchTransaction(r *types.AlertRule, trx *types.Transaction) bool {
+	if r.Event != types.AlertEventTransaction || !matchParties(r, &trx.From, trx.To) || !matchMinValue(r, trx.Value.ToInt()) {
+		return false
+	}
+
+	if r.Method != nil && (len(trx.InputData) < 4 || !bytes.Equal(trx.InputData[:4], r.Method)) {
+		return false
+	}
+
+	switch r.Status {
+	case types.AlertStatusSuccess:
+		return trx.Status != nil && *trx.Status == 1
+	case types.AlertStatusFailed:
+		return trx.Status != nil && *trx.Status == 0
+	}
+	return true
+}
+
+// matchTokenTransaction checks if the given rule matches the token transaction.
+// Approvals are matched only if the rule asks for them explicitly.
+func matchTokenTransaction(r *types.AlertRule, tt *types.TokenTransaction) bool {
+	if r.Event != types.AlertEventTokenTransfer || !matchParties(r, &tt.Sender, &tt.Recipient) || !matchMinValue(r, tt.Amount.ToInt()) {
+		return false
+	}
+
+	if r.Token != nil && *r.Token != tt.TokenAddress {
+		return false
+	}
+
+	if r.TokenTrxType == "" {
+		return tt.Type != types.TokenTrxTypeApproval && tt.Type != types.TokenTrxTypeApprovalForAll
+	}
+	return r.TokenTrxType == types.TokenTrxTypeName(tt.Type)
+}
+
+// onTransaction evaluates the alert rules against the given transaction.
+func (ale *alertEngine) onTransaction(trx *types.Transaction) {
+	for _, r := range ale.rules {
+		if !matchTransaction(r, trx) {
+			continue
+		}
+
+		var blk uint64
+		if trx.BlockNumber != nil {
+			blk = uint64(*trx.BlockNumber)
+		}
+
+		ale.raise(r, &types.Alert{
+			ID:          fmt.Sprintf("%s/%s", r.ID, trx.Hash.String()),
+			Event:       types.AlertEventTransaction,
+			Transaction: trx.Hash,
+			BlockNumber: blk,
+			From:        trx.From,
+			To:          trx.To,
+			Value:       trx.Value,
+			Message:     fmt.Sprintf("transaction %s from %s to %s, value %s", trx.Hash.String(), trx.From.String(), addressOrNone(trx.To), trx.Value.ToInt().String()),
+			TimeStamp:   trx.TimeStamp,
+		})
+	}
+}
+
+// onTokenTransaction evaluates the alert rules against the given token transaction.
+func (ale *alertEngine) onTokenTransaction(tt *types.TokenTransaction) {
+	for _, r := range ale.rules {
+		if !matchTokenTransaction(r, tt) {
+			continue
+		}
+
+		tp := types.TokenTrxTypeName(tt.Type)
+		ale.raise(r, &types.Alert{
+			ID:          fmt.Sprintf("%s/%s/%d/%d", r.ID, tt.Transaction.String(), tt.LogIndex, tt.Seq),
+			Event:       types.AlertEventTokenTransfer,
+			Transaction: tt.Transaction,
+			BlockNumber: tt.BlockNumber,
+			From:        tt.Sender,
+			To:          &tt.Recipient,
+			Token:       &tt.TokenAddress,
+			Value:       tt.Amount,
+			Message:     fmt.Sprintf("token %s %s of %s from %s to %s, amount %s", tt.TokenType, tp, tt.TokenAddress.String(), tt.Sender.String(), tt.Recipient.String(), tt.Amount.ToInt().String()),
+			TimeStamp:   time.Unix(int64(tt.TimeStamp), 0).UTC(),
+		})
+	}
+}
+
+// addressOrNone provides the string representation of the given address; "none" if not available.
+func addressOrNone(adr *common.Address) string {
+	if adr == nil {
+		return "none"
+	}
+	return adr.String()
+}
+
+// raise stores the alert matched by the given rule and queues it for the rule sinks.
+// Alerts already known are not raised again. The alert keeps the list of sinks it waits for,
+// so it's pushed even if the queue is full now, or a sink fails.
+func (ale *alertEngine) raise(r *types.AlertRule, alert *types.Alert) {
+	alert.Rule = r.ID
+	alert.RuleName = r.Name
+	alert.Created = time.Now().UTC()
+
+	// the ordinal index keeps the alerts in the order they were raised
+	alert.Ordinal = uint64(alert.Created.UnixNano())
+	if alert.Ordinal <= ale.lastOrdinal {
+		alert.Ordinal = ale.lastOrdinal + 1
+	}
+	ale.lastOrdinal = alert.Ordinal
+
+	alert.Pending = ale.sinkNames(r)
+	if len(alert.Pending) > 0 {
+		alert.NextPush = alert.Created
+	}
+
+	added, err := repo.AddAlert(alert)
+	if err != nil || !added || len(alert.Pending) == 0 {
+		return
+	}
+	ale.enqueue(alert)
+}
+
+// sinkNames provides the names of the sinks alerts of the given rule are pushed to;
+// all the sinks if the rule does not specify any.
+func (ale *alertEngine) sinkNames(r *types.AlertRule) []string {
+	if len(r.Sinks) > 0 {
+		return append([]string{}, r.Sinks...)
+	}
+
+	names := make([]string, 0, len(ale.sinks))
+	for name := range ale.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// retry queues alerts due for another push attempt.
+func (ale *alertEngine) retry() {
+	list, err := repo.AlertsPushDue(time.Now().UTC())
+	if err != nil {
+		log.Errorf("can not load alerts due for push; %s", err.Error())
+		return
+	}
+
+	for _, alert := range list {
+		ale.enqueue(alert)
+	}
+}
+
+// enqueue passes the given alert to the sinks pusher, unless it's already in flight.
+// If the queue is full, the alert is left for the next retry round.
+func (ale *alertEngine) enqueue(alert *types.Alert) {
+	ale.mu.Lock()
+	defer ale.mu.Unlock()
+
+	if ale.inFlight[alert.ID] {
+		return
+	}
+
+	select {
+	case ale.queue <- alert:
+		ale.inFlight[alert.ID] = true
+	default:
+		log.Debugf("alert sinks queue full, alert %s left for retry", alert.ID)
+	}
+}
+
+// push sends queued alerts to their sinks.
+func (ale *alertEngine) push() {
+	defer close(ale.sinksDone)
+
+	for {
+		select {
+		case <-ale.sigStop:
+			return
+		case alert := <-ale.queue:
+			ale.pushToSinks(alert)
+
+			ale.mu.Lock()
+			delete(ale.inFlight, alert.ID)
+			ale.mu.Unlock()
+		}
+	}
+}
+
+// pushToSinks sends the alert to the sinks it waits for and updates its push state.
+// Sinks which failed are attempted again later; sinks not configured are skipped for good.
+func (ale *alertEngine) pushToSinks(alert *types.Alert) {
+	failed := make([]string, 0)
+	for _, name := range alert.Pending {
+		sink, ok := ale.sinks[name]
+		if !ok {
+			log.Errorf("alert sink %s not available for alert %s", name, alert.ID)
+			continue
+		}
+		if err := sink.send(alert); err != nil {
+			log.Errorf("can not push alert %s to sink %s; %s", alert.ID, name, err.Error())
+			failed = append(failed, name)
+		}
+	}
+
+	alert.PushAttempts++
+	alert.Pending = failed
+	alert.NextPush = time.Time{}
+
+	switch {
+	case len(failed) == 0:
+		alert.Pending = nil
+	case alert.PushAttempts >= alertPushMaxAttempts:
+		log.Warningf("alert %s not pushed to %v, giving up", alert.ID, failed)
+	default:
+		alert.NextPush = time.Now().UTC().Add(webhookRetryDelay(alert.PushAttempts))
+	}
+
+	if err := repo.UpdateAlertPush(alert); err != nil {
+		log.Errorf("can not update alert %s push; %s", alert.ID, err.Error())
+	}
+}
@@ -0,0 +1,170 @@
+package svc
+
+import (
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/onsi/gomega"
+	"sync"
+	"testing"
+	"time"
+)
+
+// alertRepository is a repository substitute keeping the alerts in memory.
+type alertRepository struct {
+	repository.Repository
+	mu     sync.Mutex
+	alerts map[string]types.Alert
+}
+
+// AddAlert keeps the alert unless it's already known.
+func (ar *alertRepository) AddAlert(alert *types.Alert) (bool, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.alerts[alert.ID]; ok {
+		return false, nil
+	}
+	ar.alerts[alert.ID] = *alert
+	return true, nil
+}
+
+// UpdateAlertPush updates the push state of the kept alert.
+func (ar *alertRepository) UpdateAlertPush(alert *types.Alert) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	ar.alerts[alert.ID] = *alert
+	return nil
+}
+
+// AlertsPushDue provides the kept alerts due for a push.
+func (ar *alertRepository) AlertsPushDue(ts time.Time) ([]*types.Alert, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	list := make([]*types.Alert, 0)
+	for _, a := range ar.alerts {
+		if !a.NextPush.IsZero() && !a.NextPush.After(ts) {
+			alert := a
+			list = append(list, &alert)
+		}
+	}
+	return list, nil
+}
+
+// stored provides the kept alert of the given ID.
+func (ar *alertRepository) stored(id string) types.Alert {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	return ar.alerts[id]
+}
+
+// testAlertSink is an alert sink substitute failing the given number of pushes.
+type testAlertSink struct {
+	fails int
+	sent  []string
+}
+
+// send records the alert, or fails.
+func (ts *testAlertSink) send(alert *types.Alert) error {
+	if ts.fails > 0 {
+		ts.fails--
+		return errors.New("sink not available")
+	}
+	ts.sent = append(ts.sent, alert.ID)
+	return nil
+}
+
+// close releases resources held by the sink.
+func (ts *testAlertSink) close() {}
+
+// testAlertEngine prepares an alert engine of the given sinks and queue capacity.
+func testAlertEngine(sinks map[string]alertSink, capacity int) (*alertEngine, *alertRepository) {
+	cfg = &config.Config{AppName: "test", Log: config.Log{Level: "CRITICAL", Format: "%{message}"}}
+	log = logger.New(cfg)
+
+	ar := &alertRepository{alerts: make(map[string]types.Alert)}
+	repo = ar
+
+	return &alertEngine{
+		sinks:    sinks,
+		queue:    make(chan *types.Alert, capacity),
+		inFlight: make(map[string]bool),
+	}, ar
+}
+
+func TestAlertPushRetried(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	good, bad := &testAlertSink{}, &testAlertSink{fails: 1}
+	ale, ar := testAlertEngine(map[string]alertSink{"good": good, "bad": bad}, 1)
+
+	// the rule without sinks goes to all of them
+	ale.raise(&types.AlertRule{ID: "r1", Name: "rule"}, &types.Alert{ID: "a1"})
+	g.Expect(ar.stored("a1").Pending).To(gomega.Equal([]string{"bad", "good"}))
+	g.Expect(ale.queue).To(gomega.HaveLen(1))
+
+	// the failed sink is kept for the next attempt
+	ale.pushToSinks(<-ale.queue)
+	a1 := ar.stored("a1")
+	g.Expect(good.sent).To(gomega.Equal([]string{"a1"}))
+	g.Expect(a1.Pending).To(gomega.Equal([]string{"bad"}))
+	g.Expect(a1.PushAttempts).To(gomega.Equal(int32(1)))
+	g.Expect(a1.NextPush).To(gomega.BeTemporally("~", time.Now().Add(webhookRetryBase), time.Second))
+
+	// once due, only the failed sink is attempted again
+	a1.NextPush = time.Now().UTC()
+	g.Expect(ar.UpdateAlertPush(&a1)).To(gomega.Succeed())
+	delete(ale.inFlight, "a1")
+	ale.retry()
+	ale.pushToSinks(<-ale.queue)
+
+	a1 = ar.stored("a1")
+	g.Expect(good.sent).To(gomega.Equal([]string{"a1"}))
+	g.Expect(bad.sent).To(gomega.Equal([]string{"a1"}))
+	g.Expect(a1.Pending).To(gomega.BeEmpty())
+	g.Expect(a1.NextPush.IsZero()).To(gomega.BeTrue())
+}
+
+func TestAlertPushQueueFull(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sink := &testAlertSink{}
+	ale, ar := testAlertEngine(map[string]alertSink{"log": sink}, 1)
+
+	rule := &types.AlertRule{ID: "r1", Name: "rule", Sinks: []string{"log"}}
+	ale.raise(rule, &types.Alert{ID: "a1"})
+	ale.raise(rule, &types.Alert{ID: "a2"})
+	g.Expect(ale.queue).To(gomega.HaveLen(1))
+
+	// the alert not queued is stored as pending and picked up by the retry
+	g.Expect(ar.stored("a2").Pending).To(gomega.Equal([]string{"log"}))
+	ale.pushToSinks(<-ale.queue)
+	delete(ale.inFlight, "a1")
+
+	ale.retry()
+	ale.pushToSinks(<-ale.queue)
+	g.Expect(sink.sent).To(gomega.Equal([]string{"a1", "a2"}))
+
+	// re-raised alerts are not pushed again
+	ale.raise(rule, &types.Alert{ID: "a2"})
+	g.Expect(ale.queue).To(gomega.BeEmpty())
+}
+
+func TestAlertPushAbandoned(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sink := &testAlertSink{fails: alertPushMaxAttempts}
+	ale, ar := testAlertEngine(map[string]alertSink{"hook": sink}, 1)
+
+	alert := &types.Alert{ID: "a1", Pending: []string{"hook", "missing"}, PushAttempts: alertPushMaxAttempts - 1}
+	ar.alerts["a1"] = *alert
+	ale.pushToSinks(alert)
+
+	// the missing sink is dropped, the failing one is kept as not pushed, but not attempted anymore
+	a1 := ar.stored("a1")
+	g.Expect(a1.Pending).To(gomega.Equal([]string{"hook"}))
+	g.Expect(a1.NextPush.IsZero()).To(gomega.BeTrue())
+}
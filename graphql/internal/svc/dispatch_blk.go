@@ -2,6 +2,7 @@
 package svc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -89,8 +90,11 @@ func (bld *blockDispatcher) execute() {
 			case <-time.After(200 * time.Millisecond):
 			}
 
-			// add the block to the ring
+			// add the block to the ring and announce it to the replicas
 			repo.CacheBlock(blk)
+			if err := repo.StoreBlock(blk); err != nil {
+				log.Errorf("block #%d not stored; %s", uint64(blk.Number), err.Error())
+			}
 		}
 	}
 }
@@ -144,7 +148,7 @@ func (bld *blockDispatcher) processTxs(blk *types.Block) bool {
 // load a transaction detail from repository, if possible.
 func (bld *blockDispatcher) load(blk *types.Block, th *common.Hash) *types.Transaction {
 	// get transaction
-	trx, err := repo.Transaction(th)
+	trx, err := repo.Transaction(context.Background(), th)
 	if err != nil {
 		log.Errorf("transaction %s detail not available; %s", th.String(), err.Error())
 		return nil
@@ -2,6 +2,7 @@
 package svc
 
 import (
+	"fantom-api-graphql/internal/repository/rpc"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -11,6 +12,9 @@ import (
 type logDispatcher struct {
 	service
 	inLog       chan *types.LogRecord
+	onTokenTrx  chan *types.TokenTransaction
+	outTokenTrx chan *types.TokenTransaction
+	outAlert    chan *types.TokenTransaction
 	knownTopics map[common.Hash]func(*types.LogRecord)
 }
 
@@ -22,20 +26,22 @@ func (lgd *logDispatcher) name() string {
 // init prepares the log dispatcher to perform its function.
 func (lgd *logDispatcher) init() {
 	lgd.sigStop = make(chan struct{})
+	lgd.outTokenTrx = make(chan *types.TokenTransaction, trxLogQueueCapacity)
+	lgd.outAlert = make(chan *types.TokenTransaction, trxLogQueueCapacity)
 	lgd.knownTopics = map[common.Hash]func(*types.LogRecord){
 		/* ---------------- ERC20 and ERC721 contracts related event hooks below this line ---------------- */
 
 		/* ERC20::Approval(address indexed owner, address indexed spender, uint256 value) */
-		common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"): handleErcTokenApproval,
+		rpc.ErcApprovalTopic: lgd.handleErcTokenLog,
 
 		/* ERC20::Transfer(address indexed from, address indexed to, uint256 value) */
-		common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"): handleErcTokenTransfer,
+		rpc.ErcTransferTopic: lgd.handleErcTokenLog,
 
 		/* ERC1155::TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value) */
-		common.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"): handleErc1155TransferSingle,
+		rpc.Erc1155TransferSingleTopic: lgd.handleErcTokenLog,
 
 		/* ERC1155::TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values) */
-		common.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"): handleErc1155TransferBatch,
+		rpc.Erc1155TransferBatchTopic: lgd.handleErcTokenLog,
 	}
 }
 
@@ -55,6 +61,8 @@ func (lgd *logDispatcher) run() {
 func (lgd *logDispatcher) execute() {
 	// don't forget to sign off after we are done
 	defer func() {
+		close(lgd.outTokenTrx)
+		close(lgd.outAlert)
 		lgd.mgr.finished(lgd)
 	}()
 
@@ -41,6 +41,9 @@ type trxDispatcher struct {
 	outTransaction chan *eventTrx
 	outAccount     chan *eventAcc
 	outLog         chan *types.LogRecord
+	outMined       chan *types.Transaction
+	outWebhook     chan *types.Transaction
+	outAlert       chan *types.Transaction
 }
 
 // name returns the name of the service used by orchestrator.
@@ -55,6 +58,9 @@ func (trd *trxDispatcher) init() {
 	trd.outAccount = make(chan *eventAcc, trxAddressQueueCapacity)
 	trd.outLog = make(chan *types.LogRecord, trxLogQueueCapacity)
 	trd.outTransaction = make(chan *eventTrx, trxLogQueueCapacity)
+	trd.outMined = make(chan *types.Transaction, trxLogQueueCapacity)
+	trd.outWebhook = make(chan *types.Transaction, trxLogQueueCapacity)
+	trd.outAlert = make(chan *types.Transaction, trxLogQueueCapacity)
 }
 
 // run starts the transaction dispatcher job
@@ -89,6 +95,9 @@ func (trd *trxDispatcher) execute() {
 		close(trd.outAccount)
 		close(trd.outLog)
 		close(trd.outTransaction)
+		close(trd.outMined)
+		close(trd.outWebhook)
+		close(trd.outAlert)
 
 		trd.mgr.finished(trd)
 	}()
@@ -157,6 +166,29 @@ func (trd *trxDispatcher) process(evt *eventTrx) {
 	// we spawn a lot of go-routines here, so we should test the optimal queue length above
 	go trd.waitAndStore(evt, &wg)
 
+	// pass the transaction to submitted transactions tracking; the monitor
+	// checks pending transactions against the node, so it's fine to skip if it's busy
+	select {
+	case trd.outMined <- evt.trx:
+	default:
+	}
+
+	// pass the transaction to webhooks matching; the matching only stores the deliveries,
+	// slow webhook receivers are handled by the delivery workers, so we can wait here
+	select {
+	case trd.outWebhook <- evt.trx:
+	case <-trd.sigStop:
+		return
+	}
+
+	// pass the transaction to alert rules evaluation; the engine only stores the alerts,
+	// they are pushed to the sinks separately, so we can wait here
+	select {
+	case trd.outAlert <- evt.trx:
+	case <-trd.sigStop:
+		return
+	}
+
 	// broadcast new transaction; if it can not be broadcast quickly, skip
 	select {
 	case trd.onTransaction <- evt.trx:
@@ -0,0 +1,427 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookReloadInterval represents the interval in which the registered webhooks are re-loaded.
+	webhookReloadInterval = 15 * time.Second
+
+	// webhookRetryInterval represents the interval in which due deliveries are re-loaded for another attempt.
+	webhookRetryInterval = 10 * time.Second
+
+	// webhookRetryBase represents the delay of the first retry; it doubles with each failed attempt.
+	webhookRetryBase = 30 * time.Second
+
+	// webhookRetryMaxDelay represents the max delay between two delivery attempts.
+	webhookRetryMaxDelay = time.Hour
+
+	// webhookMaxAttempts represents the number of attempts after which a delivery is abandoned.
+	webhookMaxAttempts = 10
+
+	// webhookRequestTimeout represents the time a receiver has to accept a delivery.
+	webhookRequestTimeout = 10 * time.Second
+
+	// webhookWorkers represents the number of parallel delivery workers.
+	webhookWorkers = 4
+
+	// webhookQueueCapacity represents the capacity of the delivery queue.
+	webhookQueueCapacity = 500
+
+	// webhookSignatureHeader represents the HTTP header carrying the HMAC signature of the payload.
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// webhookDispatcher implements dispatcher of address activity notifications to registered webhooks.
+type webhookDispatcher struct {
+	service
+
+	// inTransaction represents the source of processed transactions
+	inTransaction chan *types.Transaction
+
+	// inTokenTrx represents the source of processed token transactions
+	inTokenTrx chan *types.TokenTransaction
+
+	// hooks represents the list of enabled webhooks
+	hooks []*types.Webhook
+
+	// queue represents the deliveries waiting for a worker
+	queue chan *types.WebhookDelivery
+
+	// inFlight represents the deliveries queued, or being delivered, by their ID
+	inFlight map[string]bool
+	mu       sync.Mutex
+
+	client       *http.Client
+	workers      sync.WaitGroup
+	reloadTicker *time.Ticker
+	retryTicker  *time.Ticker
+}
+
+// webhookPayload represents the JSON body posted to a webhook.
+type webhookPayload struct {
+	ID        string      `json:"id"`
+	Hook      string      `json:"hook"`
+	Event     string      `json:"event"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// webhookTransaction represents the transaction details of a webhook payload.
+type webhookTransaction struct {
+	Hash        common.Hash     `json:"hash"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Contract    *common.Address `json:"contract,omitempty"`
+	Nonce       hexutil.Uint64  `json:"nonce"`
+	Value       hexutil.Big     `json:"value"`
+	GasUsed     *hexutil.Uint64 `json:"gasUsed"`
+	Status      *hexutil.Uint64 `json:"status"`
+}
+
+// webhookTokenTransfer represents the token transfer details of a webhook payload.
+type webhookTokenTransfer struct {
+	Transaction common.Hash    `json:"transaction"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    uint           `json:"logIndex"`
+	Token       common.Address `json:"token"`
+	TokenType   string         `json:"tokenType"`
+	Type        string         `json:"type"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Amount      hexutil.Big    `json:"amount"`
+	TokenId     hexutil.Big    `json:"tokenId"`
+}
+
+// name returns the name of the service used by orchestrator.
+func (whd *webhookDispatcher) name() string {
+	return "webhook dispatcher"
+}
+
+// init prepares the webhook dispatcher to perform its function.
+func (whd *webhookDispatcher) init() {
+	whd.sigStop = make(chan struct{})
+	whd.queue = make(chan *types.WebhookDelivery, webhookQueueCapacity)
+	whd.inFlight = make(map[string]bool)
+	whd.client = &http.Client{Timeout: webhookRequestTimeout}
+}
+
+// run starts the webhook dispatcher job
+func (whd *webhookDispatcher) run() {
+	// make sure we are orchestrated
+	if whd.mgr == nil {
+		panic(fmt.Errorf("no svc manager set on %s", whd.name()))
+	}
+
+	// start delivery workers
+	for i := 0; i < webhookWorkers; i++ {
+		whd.workers.Add(1)
+		go whd.worker()
+	}
+
+	// signal orchestrator we started and go
+	whd.mgr.started(whd)
+	go whd.execute()
+}
+
+// close terminates the webhook dispatcher.
+func (whd *webhookDispatcher) close() {
+	if whd.reloadTicker != nil {
+		whd.reloadTicker.Stop()
+	}
+	if whd.retryTicker != nil {
+		whd.retryTicker.Stop()
+	}
+	if whd.sigStop != nil {
+		close(whd.sigStop)
+	}
+}
+
+// execute matches incoming transactions and token transfers against registered webhooks
+// and schedules deliveries for them.
+func (whd *webhookDispatcher) execute() {
+	defer func() {
+		whd.workers.Wait()
+		whd.mgr.finished(whd)
+	}()
+
+	whd.reloadTicker = time.NewTicker(webhookReloadInterval)
+	whd.retryTicker = time.NewTicker(webhookRetryInterval)
+	whd.reload()
+
+	for {
+		select {
+		case <-whd.sigStop:
+			return
+		case <-whd.reloadTicker.C:
+			whd.reload()
+		case <-whd.retryTicker.C:
+			whd.retry()
+		case trx, ok := <-whd.inTransaction:
+			if !ok {
+				return
+			}
+			whd.onTransaction(trx)
+		case tt, ok := <-whd.inTokenTrx:
+			if !ok {
+				return
+			}
+			whd.onTokenTransaction(tt)
+		}
+	}
+}
+
+// reload updates the list of enabled webhooks from the persistent storage.
+func (whd *webhookDispatcher) reload() {
+	list, err := repo.Webhooks()
+	if err != nil {
+		log.Errorf("can not load webhooks; %s", err.Error())
+		return
+	}
+
+	whd.hooks = make([]*types.Webhook, 0, len(list))
+	for _, wh := range list {
+		if wh.Enabled {
+			whd.hooks = append(whd.hooks, wh)
+		}
+	}
+}
+
+// onTransaction schedules deliveries of the given transaction to webhooks watching its sender, or recipient.
+func (whd *webhookDispatcher) onTransaction(trx *types.Transaction) {
+	for _, wh := range whd.hooks {
+		if !wh.HasEvent(types.WebhookEventTransaction) {
+			continue
+		}
+		if !wh.WatchesAddress(&trx.From) && !wh.WatchesAddress(trx.To) && !wh.WatchesAddress(trx.ContractAddress) {
+			continue
+		}
+
+		whd.schedule(wh, types.WebhookEventTransaction, trx.Hash.String(), webhookTransaction{
+			Hash:        trx.Hash,
+			BlockNumber: trx.BlockNumber,
+			From:        trx.From,
+			To:          trx.To,
+			Contract:    trx.ContractAddress,
+			Nonce:       trx.Nonce,
+			Value:       trx.Value,
+			GasUsed:     trx.GasUsed,
+			Status:      trx.Status,
+		})
+	}
+}
+
+// onTokenTransaction schedules deliveries of the given token transfer to webhooks watching the token,
+// or any of the involved addresses. Approvals are not delivered.
+func (whd *webhookDispatcher) onTokenTransaction(tt *types.TokenTransaction) {
+	if tt.Type == types.TokenTrxTypeApproval || tt.Type == types.TokenTrxTypeApprovalForAll {
+		return
+	}
+
+	for _, wh := range whd.hooks {
+		if !wh.HasEvent(types.WebhookEventTokenTransfer) {
+			continue
+		}
+		if !wh.WatchesToken(&tt.TokenAddress) && !wh.WatchesAddress(&tt.Sender) && !wh.WatchesAddress(&tt.Recipient) {
+			continue
+		}
+
+		whd.schedule(wh, types.WebhookEventTokenTransfer, fmt.Sprintf("%s/%d/%d", tt.Transaction.String(), tt.LogIndex, tt.Seq), webhookTokenTransfer{
+			Transaction: tt.Transaction,
+			BlockNumber: hexutil.Uint64(tt.BlockNumber),
+			LogIndex:    tt.LogIndex,
+			Token:       tt.TokenAddress,
+			TokenType:   tt.TokenType,
+			Type:        types.TokenTrxTypeName(tt.Type),
+			From:        tt.Sender,
+			To:          tt.Recipient,
+			Amount:      tt.Amount,
+			TokenId:     tt.TokenId,
+		})
+	}
+}
+
+// schedule stores a new delivery of the given event data to the given webhook and queues it.
+// The delivery ID is derived from the event so re-scanned events are not delivered twice.
+func (whd *webhookDispatcher) schedule(wh *types.Webhook, event string, key string, data interface{}) {
+	id := fmt.Sprintf("%s/%s/%s", wh.ID, event, key)
+	now := time.Now().UTC()
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:        id,
+		Hook:      wh.ID,
+		Event:     event,
+		Timestamp: now.Unix(),
+		Data:      data,
+	})
+	if err != nil {
+		log.Errorf("can not encode webhook %s payload; %s", wh.ID, err.Error())
+		return
+	}
+
+	wd := types.WebhookDelivery{
+		ID:          id,
+		Hook:        wh.ID,
+		Event:       event,
+		Payload:     payload,
+		Status:      types.WebhookDeliveryPending,
+		Created:     now,
+		NextAttempt: now,
+	}
+
+	added, err := repo.AddWebhookDelivery(&wd)
+	if err != nil || !added {
+		return
+	}
+	whd.enqueue(&wd)
+}
+
+// retry queues pending deliveries due for another attempt.
+func (whd *webhookDispatcher) retry() {
+	list, err := repo.WebhookDeliveriesDue(time.Now().UTC())
+	if err != nil {
+		log.Errorf("can not load due webhook deliveries; %s", err.Error())
+		return
+	}
+
+	for _, wd := range list {
+		whd.enqueue(wd)
+	}
+}
+
+// enqueue passes the given delivery to the workers, unless it's already in flight.
+// If the queue is full, the delivery is left for the next retry round.
+func (whd *webhookDispatcher) enqueue(wd *types.WebhookDelivery) {
+	whd.mu.Lock()
+	defer whd.mu.Unlock()
+
+	if whd.inFlight[wd.ID] {
+		return
+	}
+
+	select {
+	case whd.queue <- wd:
+		whd.inFlight[wd.ID] = true
+	default:
+	}
+}
+
+// worker delivers queued webhook deliveries.
+func (whd *webhookDispatcher) worker() {
+	defer whd.workers.Done()
+
+	for {
+		select {
+		case <-whd.sigStop:
+			return
+		case wd := <-whd.queue:
+			whd.deliver(wd)
+
+			whd.mu.Lock()
+			delete(whd.inFlight, wd.ID)
+			whd.mu.Unlock()
+		}
+	}
+}
+
+// deliver makes a single delivery attempt and updates the delivery state.
+func (whd *webhookDispatcher) deliver(wd *types.WebhookDelivery) {
+	wh, err := repo.Webhook(wd.Hook)
+	if err != nil {
+		return
+	}
+
+	// the hook has been removed in the meantime
+	if wh == nil {
+		wd.Status = types.WebhookDeliveryFailed
+		wd.LastError = "webhook not found"
+		_ = repo.UpdateWebhookDelivery(wd)
+		return
+	}
+
+	wd.Attempts++
+	wd.LastAttempt = time.Now().UTC()
+	wd.ResponseCode, err = whd.post(wh, wd)
+
+	switch {
+	case err == nil:
+		wd.Status = types.WebhookDeliveryDelivered
+		wd.LastError = ""
+	case wd.Attempts >= webhookMaxAttempts:
+		wd.Status = types.WebhookDeliveryFailed
+		wd.LastError = err.Error()
+		log.Warningf("webhook %s delivery %s failed; %s", wh.ID, wd.ID, err.Error())
+	default:
+		wd.LastError = err.Error()
+		wd.NextAttempt = wd.LastAttempt.Add(webhookRetryDelay(wd.Attempts))
+	}
+
+	if err := repo.UpdateWebhookDelivery(wd); err != nil {
+		log.Errorf("can not update webhook delivery %s; %s", wd.ID, err.Error())
+	}
+}
+
+// webhookRetryDelay provides the delay before the next attempt after the given number of failed attempts.
+func webhookRetryDelay(attempts int32) time.Duration {
+	delay := webhookRetryBase
+	for i := int32(1); i < attempts && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookRetryMaxDelay {
+		return webhookRetryMaxDelay
+	}
+	return delay
+}
+
+// post sends the delivery payload to the webhook URL signed by the webhook secret.
+// Any 2xx response is considered a success.
+func (whd *webhookDispatcher) post(wh *types.Webhook, wd *types.WebhookDelivery) (int32, error) {
+	req, err := http.NewRequest(http.MethodPost, wh.URL, bytes.NewReader(wd.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fantom-api-graphql")
+	req.Header.Set("X-Webhook-Id", wh.ID)
+	req.Header.Set("X-Webhook-Event", wd.Event)
+	req.Header.Set("X-Webhook-Delivery", wd.ID)
+	req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(wh.Secret, wd.Payload))
+
+	res, err := whd.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return int32(res.StatusCode), fmt.Errorf("unexpected response %s", res.Status)
+	}
+	return int32(res.StatusCode), nil
+}
+
+// webhookSignature calculates the hex encoded HMAC-SHA256 of the payload keyed by the webhook secret.
+func webhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,136 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"fantom-api-graphql/internal/types"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"time"
+)
+
+// leaderElection implements the lease based election of the instance running the indexing services.
+// The leader renews the lease on each heartbeat; a standby takes over once the lease lapses
+// and the block scanner continues from the last known block.
+type leaderElection struct {
+	mgr      *ServiceManager
+	sigStop  chan struct{}
+	done     chan struct{}
+	leader   bool
+	holder   string
+	renewed  time.Time
+	stopping time.Duration
+}
+
+// newLeaderElection creates a new leader election of the given service manager.
+func newLeaderElection(mgr *ServiceManager) *leaderElection {
+	return &leaderElection{
+		mgr:     mgr,
+		sigStop: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// run competes for the indexer lease until closed.
+func (le *leaderElection) run() {
+	defer close(le.done)
+
+	tick := time.NewTicker(cfg.Lease.Heartbeat)
+	defer tick.Stop()
+
+	log.Noticef("indexer lease election started as %s", cfg.Lease.Holder)
+	le.beat()
+
+	heads := repo.ObservedHeaders()
+	for {
+		// the standby drops new heads, the leader dispatches them
+		var standby chan *etc.Header
+		if !le.leader {
+			standby = heads
+		}
+
+		select {
+		case <-le.sigStop:
+			if le.leader {
+				le.stepDown("closing")
+				if err := repo.ReleaseLease(types.LeaseIndexer, cfg.Lease.Holder); err == nil {
+					log.Notice("indexer lease released")
+				}
+			}
+			return
+		case <-tick.C:
+			le.beat()
+		case <-standby:
+		}
+	}
+}
+
+// close terminates the election; the leader stops the services and releases the lease.
+func (le *leaderElection) close() {
+	close(le.sigStop)
+	<-le.done
+}
+
+// beat acquires, or renews, the indexer lease and switches the role of the instance if needed.
+func (le *leaderElection) beat() {
+	// the lease may be renewed as soon as the request is sent, it's the safe start of the renewed term
+	sent := time.Now()
+	lease, err := repo.AcquireLease(types.LeaseIndexer, cfg.Lease.Holder, cfg.Lease.Ttl)
+	if err != nil {
+		// the lease can not be confirmed; the leader must stop the services before the lease may lapse
+		if le.leader && time.Since(le.renewed) >= cfg.Lease.Ttl-le.stepDownMargin() {
+			le.stepDown("lease not renewed")
+		}
+		return
+	}
+
+	if lease.Holder != le.holder {
+		log.Noticef("indexer lease held by %s since %s", lease.Holder, lease.Acquired.Format(time.RFC3339))
+		le.holder = lease.Holder
+	}
+
+	// the lease is provided with our holder only if it has just been granted, or renewed
+	if lease.Holder == cfg.Lease.Holder {
+		le.renewed = sent
+		if !le.leader {
+			log.Noticef("indexer lease acquired until %s, taking over", lease.Expires.Format(time.RFC3339))
+			le.leader = true
+			le.mgr.start()
+		}
+		return
+	}
+
+	if le.leader {
+		le.stepDown("lease lost")
+		return
+	}
+	log.Debugf("standing by, indexer lease expires %s", lease.Expires.Format(time.RFC3339))
+}
+
+// stepDownMargin provides the time before the lease lapses the leader must start stepping down at
+// if the lease can not be renewed. It covers the next heartbeat and stopping the services,
+// the longest stop observed is used if it took more than expected.
+func (le *leaderElection) stepDownMargin() time.Duration {
+	stop := cfg.Lease.StopTimeout
+	if le.stopping > stop {
+		stop = le.stopping
+	}
+	return cfg.Lease.Heartbeat + stop
+}
+
+// stepDown stops the services of the leader and switches the instance to standby.
+func (le *leaderElection) stepDown(reason string) {
+	log.Warningf("indexer leader stepping down; %s", reason)
+
+	start := time.Now()
+	le.mgr.stop()
+
+	took := time.Since(start)
+	if took > le.stopping {
+		le.stopping = took
+	}
+	if took > cfg.Lease.StopTimeout {
+		log.Warningf("indexer services stopped in %s, more than the lease stop timeout %s", took, cfg.Lease.StopTimeout)
+	}
+
+	le.leader = false
+	log.Notice("indexer standing by")
+}
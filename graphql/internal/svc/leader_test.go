@@ -0,0 +1,109 @@
+package svc
+
+import (
+	"errors"
+	"fantom-api-graphql/internal/config"
+	"fantom-api-graphql/internal/logger"
+	"fantom-api-graphql/internal/repository"
+	"fantom-api-graphql/internal/types"
+	"github.com/onsi/gomega"
+	"sync"
+	"testing"
+	"time"
+)
+
+// leaseRepository is a repository substitute granting the configured lease.
+type leaseRepository struct {
+	repository.Repository
+	holder string
+	err    error
+}
+
+// AcquireLease provides the lease held by the configured holder.
+func (lr *leaseRepository) AcquireLease(name string, _ string, ttl time.Duration) (*types.Lease, error) {
+	if lr.err != nil {
+		return nil, lr.err
+	}
+	now := time.Now().UTC()
+	return &types.Lease{Name: name, Holder: lr.holder, Acquired: now, Heartbeat: now, Expires: now.Add(ttl)}, nil
+}
+
+// testService is a managed service substitute counting its runs; it takes the given time to close.
+type testService struct {
+	runs    int
+	closing time.Duration
+}
+
+func (ts *testService) name() string { return "test service" }
+func (ts *testService) init()        {}
+func (ts *testService) run()         { ts.runs++ }
+func (ts *testService) close()       { time.Sleep(ts.closing) }
+
+// testElection prepares the configuration and the election of a manager running the given service.
+func testElection(ts *testService) *leaderElection {
+	cfg = &config.Config{
+		AppName: "test",
+		Log:     config.Log{Level: "CRITICAL", Format: "%{message}"},
+		Lease:   config.Lease{Enabled: true, Holder: "me", Ttl: 30 * time.Second, Heartbeat: 10 * time.Second, StopTimeout: 5 * time.Second},
+	}
+	log = logger.New(cfg)
+	return newLeaderElection(&ServiceManager{wg: new(sync.WaitGroup), svc: []Svc{ts}})
+}
+
+func TestLeaderBeat(t *testing.T) {
+	tests := []struct {
+		name       string
+		leader     bool
+		renewedAgo time.Duration
+		stopping   time.Duration
+		holder     string
+		err        error
+		wantLeader bool
+		wantRuns   int
+	}{
+		{name: "lease acquired", holder: "me", wantLeader: true, wantRuns: 1},
+		{name: "lease renewed", leader: true, renewedAgo: 10 * time.Second, holder: "me", wantLeader: true},
+		{name: "lease held by other", holder: "other"},
+		{name: "lease lost", leader: true, holder: "other"},
+		{name: "renewal failed within margin", leader: true, renewedAgo: 14 * time.Second, err: errors.New("timeout"), wantLeader: true},
+		{name: "renewal failed past margin", leader: true, renewedAgo: 16 * time.Second, err: errors.New("timeout")},
+		{name: "renewal failed with slow stop", leader: true, renewedAgo: 14 * time.Second, stopping: 8 * time.Second, err: errors.New("timeout")},
+		{name: "standby renewal failed", err: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			ts := &testService{}
+			le := testElection(ts)
+			le.leader = tt.leader
+			le.renewed = time.Now().Add(-tt.renewedAgo)
+			le.stopping = tt.stopping
+			repo = &leaseRepository{holder: tt.holder, err: tt.err}
+
+			le.beat()
+			g.Expect(le.leader).To(gomega.Equal(tt.wantLeader))
+			g.Expect(ts.runs).To(gomega.Equal(tt.wantRuns))
+			if tt.wantLeader && tt.err == nil {
+				g.Expect(le.renewed).To(gomega.BeTemporally("~", time.Now(), time.Second))
+			}
+		})
+	}
+}
+
+func TestLeaderStepDownMargin(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ts := &testService{closing: 20 * time.Millisecond}
+	le := testElection(ts)
+	cfg.Lease.StopTimeout = 10 * time.Millisecond
+	g.Expect(le.stepDownMargin()).To(gomega.Equal(cfg.Lease.Heartbeat + cfg.Lease.StopTimeout))
+
+	// the stop took longer than expected, the margin follows it
+	le.leader = true
+	le.stepDown("test")
+	g.Expect(le.leader).To(gomega.BeFalse())
+	g.Expect(le.stopping).To(gomega.BeNumerically(">=", ts.closing))
+	g.Expect(le.stepDownMargin()).To(gomega.Equal(cfg.Lease.Heartbeat + le.stopping))
+}
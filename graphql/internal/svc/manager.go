@@ -20,9 +20,15 @@ type ServiceManager struct {
 	lgd *logDispatcher
 	bls *blkScanner
 	bud *burnDispatcher
+	stm *submittedTrxMonitor
+	whd *webhookDispatcher
+	ale *alertEngine
 
 	// collection of all the managed services
 	svc []Svc
+
+	// indexer leader election, if enabled
+	ldr *leaderElection
 }
 
 // newServiceManager creates a new instance of service manager.
@@ -47,10 +53,21 @@ func newServiceManager() *ServiceManager {
 }
 
 // Run starts all the services prepared to be run.
+// With the leader election enabled, the services are started once the indexer lease is acquired.
 func (mgr *ServiceManager) Run() {
 	// get local copy of the repository
 	repo = repository.R()
 
+	if cfg.Lease.Enabled {
+		mgr.ldr = newLeaderElection(mgr)
+		go mgr.ldr.run()
+		return
+	}
+	mgr.start()
+}
+
+// start initializes and starts all the managed services.
+func (mgr *ServiceManager) start() {
 	// init all the services to the starting state
 	for _, s := range mgr.svc {
 		s.init()
@@ -66,6 +83,19 @@ func (mgr *ServiceManager) Run() {
 func (mgr *ServiceManager) Close() {
 	log.Noticef("svc manager received a close signal")
 
+	// the election stops the services if the instance is the leader
+	if mgr.ldr != nil {
+		mgr.ldr.close()
+	} else {
+		mgr.stop()
+	}
+
+	// we are done
+	log.Notice("svc manager closed")
+}
+
+// stop signals all the managed services to terminate and waits for them to finish.
+func (mgr *ServiceManager) stop() {
 	// pass the signal to all the services
 	for _, s := range mgr.svc {
 		log.Noticef("closing %s", s.name())
@@ -75,9 +105,6 @@ func (mgr *ServiceManager) Close() {
 	// wait scanners to terminate
 	log.Notice("waiting for services to finish")
 	mgr.wg.Wait()
-
-	// we are done
-	log.Notice("svc manager closed")
 }
 
 // SetBlockChannel registers a channel for notifying new block events.
@@ -90,6 +117,16 @@ func (mgr *ServiceManager) SetTrxChannel(ch chan *types.Transaction) {
 	mgr.trd.onTransaction = ch
 }
 
+// SetTokenTrxChannel registers a channel for notifying new token transaction events.
+func (mgr *ServiceManager) SetTokenTrxChannel(ch chan *types.TokenTransaction) {
+	mgr.lgd.onTokenTrx = ch
+}
+
+// SetTrxStatusChannel registers a channel for notifying submitted transaction status changes.
+func (mgr *ServiceManager) SetTrxStatusChannel(ch chan *types.SubmittedTransaction) {
+	mgr.stm.onStatus = ch
+}
+
 // Init the svc manager.
 func (mgr *ServiceManager) init() {
 	// make the block dispatcher
@@ -122,6 +159,21 @@ func (mgr *ServiceManager) init() {
 	// make transaction flow monitor
 	mgr.svc = append(mgr.svc, &trxFlowMonitor{service: service{mgr: mgr}})
 
+	// make submitted transactions monitor
+	mgr.stm = &submittedTrxMonitor{service: service{mgr: mgr}}
+	mgr.svc = append(mgr.svc, mgr.stm)
+
+	// make faucet requests monitor
+	mgr.svc = append(mgr.svc, &faucetMonitor{service: service{mgr: mgr}})
+
+	// make webhook dispatcher
+	mgr.whd = &webhookDispatcher{service: service{mgr: mgr}}
+	mgr.svc = append(mgr.svc, mgr.whd)
+
+	// make alert engine
+	mgr.ale = &alertEngine{service: service{mgr: mgr}}
+	mgr.svc = append(mgr.svc, mgr.ale)
+
 	// make the network discovery
 	mgr.svc = append(mgr.svc, &netCrawler{service: service{mgr: mgr}})
 
@@ -30,6 +30,11 @@ func (nc *netCrawler) name() string {
 func (nc *netCrawler) init() {
 	nc.sigStop = make(chan struct{})
 
+	// the discovery is kept open if the crawler is restarted, e.g. by the leader election
+	if nc.client != nil {
+		return
+	}
+
 	// initialise discovery protocol
 	var err error
 	nc.client, err = newDiscovery()
@@ -2,6 +2,7 @@
 package svc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/repository/cache/ring"
 	"fantom-api-graphql/internal/types"
 	"fmt"
@@ -38,6 +39,11 @@ func (or *orchestrator) init() {
 	or.mgr.bld.inBlock = or.mgr.bls.outBlock
 	or.mgr.bls.inDispatched = or.mgr.bld.outDispatched
 	or.mgr.bud.inTransaction = or.mgr.trd.outTransaction
+	or.mgr.stm.inTransaction = or.mgr.trd.outMined
+	or.mgr.whd.inTransaction = or.mgr.trd.outWebhook
+	or.mgr.whd.inTokenTrx = or.mgr.lgd.outTokenTrx
+	or.mgr.ale.inTransaction = or.mgr.trd.outAlert
+	or.mgr.ale.inTokenTrx = or.mgr.lgd.outAlert
 	or.inScanStateSwitch = or.mgr.bls.outStateSwitch
 
 	// read initial block scanner state
@@ -94,7 +100,7 @@ func (or *orchestrator) execute() {
 func (or *orchestrator) handleNewHead(h *etc.Header) {
 	// get the block
 	bn := h.Number.Uint64()
-	blk, err := repo.BlockByNumber((*hexutil.Uint64)(&bn))
+	blk, err := repo.BlockByNumber(context.Background(), (*hexutil.Uint64)(&bn))
 	if err != nil {
 		log.Errorf("block #%d not available; %s", bn, err.Error())
 		return
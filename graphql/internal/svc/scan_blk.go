@@ -2,6 +2,7 @@
 package svc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/config"
 	"fantom-api-graphql/internal/types"
 	"fmt"
@@ -149,7 +150,7 @@ func (bls *blkScanner) execute() {
 // It returns expected idle state to be used to transition if needed.
 func (bls *blkScanner) observe() bool {
 	// try to get the block height
-	bh, err := repo.BlockHeight()
+	bh, err := repo.BlockHeight(context.Background())
 	if err != nil {
 		log.Errorf("can not get current block height; %s", err.Error())
 		return false
@@ -218,7 +219,7 @@ func (bls *blkScanner) shift() {
 	}
 
 	// pull the current block
-	block, err := repo.BlockByNumber((*hexutil.Uint64)(&bls.next))
+	block, err := repo.BlockByNumber(context.Background(), (*hexutil.Uint64)(&bls.next))
 	if err != nil {
 		log.Errorf("block #%d not available; %s", bls.next, err.Error())
 		return
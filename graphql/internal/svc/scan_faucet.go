@@ -0,0 +1,70 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"fmt"
+	"time"
+)
+
+// faucetCheckInterval represents the interval in which the faucet requests left pending are reconciled.
+const faucetCheckInterval = time.Minute
+
+// faucetMonitor represents a monitor resolving the faucet requests left pending
+// so the nonce sequence of the faucet sender account does not get stuck on a gap.
+type faucetMonitor struct {
+	service
+
+	// checkTicker controls the pending faucet requests checks
+	checkTicker *time.Ticker
+}
+
+// name returns a human-readable name of the service used by the manager.
+func (fm *faucetMonitor) name() string {
+	return "faucet monitor"
+}
+
+// init prepares the faucet monitor to perform its function.
+func (fm *faucetMonitor) init() {
+	fm.sigStop = make(chan struct{})
+}
+
+// run starts the faucet requests reconciliation
+func (fm *faucetMonitor) run() {
+	// make sure we are orchestrated
+	if fm.mgr == nil {
+		panic(fmt.Errorf("no svc manager set on %s", fm.name()))
+	}
+
+	// start go routine for processing
+	fm.mgr.started(fm)
+	go fm.execute()
+}
+
+// close terminates the faucet monitor.
+func (fm *faucetMonitor) close() {
+	if fm.checkTicker != nil {
+		fm.checkTicker.Stop()
+	}
+	if fm.sigStop != nil {
+		close(fm.sigStop)
+	}
+}
+
+// execute reconciles the pending faucet requests periodically.
+func (fm *faucetMonitor) execute() {
+	defer func() {
+		fm.mgr.finished(fm)
+	}()
+
+	fm.checkTicker = time.NewTicker(faucetCheckInterval)
+	for {
+		select {
+		case <-fm.sigStop:
+			return
+		case <-fm.checkTicker.C:
+			if err := repo.ReconcileFaucet(); err != nil {
+				log.Errorf("can not reconcile faucet requests; %s", err.Error())
+			}
+		}
+	}
+}
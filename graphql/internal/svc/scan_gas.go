@@ -2,6 +2,7 @@
 package svc
 
 import (
+	"context"
 	"fantom-api-graphql/internal/types"
 	"fmt"
 	"math"
@@ -125,7 +126,7 @@ func (gps *gpsMonitor) execute() {
 // read makes the reading on the current gas price suggestion from connected the Opera node.
 func (gps *gpsMonitor) read() {
 	// get the current suggested gas price value
-	gs, err := repo.GasPrice()
+	gs, err := repo.GasPrice(context.Background())
 	if err != nil {
 		log.Errorf("can not read the suggested gas price; %s", err.Error())
 		return
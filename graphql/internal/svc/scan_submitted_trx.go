@@ -0,0 +1,202 @@
+// Package svc implements blockchain data processing services.
+package svc
+
+import (
+	"context"
+	"fantom-api-graphql/internal/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
+
+const (
+	// submittedTrxCheckInterval represents the interval in which pending submitted transactions
+	// are re-loaded and checked against the connected node.
+	submittedTrxCheckInterval = 5 * time.Second
+
+	// submittedTrxDropTimeout represents the time after which a submitted transaction
+	// not mined yet is considered dropped.
+	submittedTrxDropTimeout = 30 * time.Minute
+)
+
+// submittedTrxMonitor represents a monitor tracking the lifecycle of transactions
+// submitted through the API until they are mined, replaced, or dropped.
+type submittedTrxMonitor struct {
+	service
+
+	// inTransaction represents the source of mined transactions
+	inTransaction chan *types.Transaction
+
+	// onStatus represents the target of submitted transaction status changes
+	onStatus chan *types.SubmittedTransaction
+
+	// pending represents the submitted transactions waiting to be mined by their hash
+	pending map[common.Hash]*types.SubmittedTransaction
+
+	// bySender represents the pending submitted transactions by their sender and nonce
+	bySender map[string]*types.SubmittedTransaction
+
+	// checkTicker controls the pending transactions checks
+	checkTicker *time.Ticker
+}
+
+// name returns a human-readable name of the service used by the manager.
+func (stm *submittedTrxMonitor) name() string {
+	return "submitted transactions monitor"
+}
+
+// init prepares the submitted transactions monitor to perform its function.
+func (stm *submittedTrxMonitor) init() {
+	stm.sigStop = make(chan struct{})
+	stm.pending = make(map[common.Hash]*types.SubmittedTransaction)
+	stm.bySender = make(map[string]*types.SubmittedTransaction)
+}
+
+// run starts the submitted transactions tracking
+func (stm *submittedTrxMonitor) run() {
+	// make sure we are orchestrated
+	if stm.mgr == nil {
+		panic(fmt.Errorf("no svc manager set on %s", stm.name()))
+	}
+
+	// start go routine for processing
+	stm.mgr.started(stm)
+	go stm.execute()
+}
+
+// close terminates the submitted transactions monitor.
+func (stm *submittedTrxMonitor) close() {
+	if stm.checkTicker != nil {
+		stm.checkTicker.Stop()
+	}
+	if stm.sigStop != nil {
+		close(stm.sigStop)
+	}
+}
+
+// execute matches mined transactions against the pending submitted transactions
+// and periodically checks the pending transactions against the connected node.
+func (stm *submittedTrxMonitor) execute() {
+	defer func() {
+		stm.mgr.finished(stm)
+	}()
+
+	stm.checkTicker = time.NewTicker(submittedTrxCheckInterval)
+	stm.check()
+
+	for {
+		select {
+		case <-stm.sigStop:
+			return
+		case <-stm.checkTicker.C:
+			stm.check()
+		case trx, ok := <-stm.inTransaction:
+			if !ok {
+				return
+			}
+			stm.mined(trx)
+		}
+	}
+}
+
+// senderKey provides the key of a transaction by its sender and nonce.
+func senderKey(from *common.Address, nonce uint64) string {
+	return fmt.Sprintf("%s#%d", from.String(), nonce)
+}
+
+// mined updates the state of the pending submitted transaction matching the given mined transaction.
+func (stm *submittedTrxMonitor) mined(trx *types.Transaction) {
+	st, ok := stm.bySender[senderKey(&trx.From, uint64(trx.Nonce))]
+	if !ok {
+		return
+	}
+
+	if st.Hash == trx.Hash {
+		stm.update(st, types.SubmittedTrxStatusMined, trx)
+		return
+	}
+	stm.update(st, types.SubmittedTrxStatusReplaced, trx)
+}
+
+// check re-loads the pending submitted transactions and resolves those already mined, replaced,
+// or timed out, against the connected node. It covers transactions the dispatcher missed.
+func (stm *submittedTrxMonitor) check() {
+	list, err := repo.SubmittedTransactionsPending()
+	if err != nil {
+		log.Errorf("can not load pending submitted transactions; %s", err.Error())
+		return
+	}
+
+	stm.pending = make(map[common.Hash]*types.SubmittedTransaction, len(list))
+	stm.bySender = make(map[string]*types.SubmittedTransaction, len(list))
+	nonces := make(map[common.Address]uint64)
+
+	for _, st := range list {
+		// the sender nonce moved past the transaction; it's either mined, or replaced
+		nonce, ok := nonces[st.From]
+		if !ok {
+			n, err := repo.AccountNonce(context.Background(), &st.From)
+			if err != nil {
+				log.Errorf("can not check nonce of %s; %s", st.From.String(), err.Error())
+				continue
+			}
+			nonce, nonces[st.From] = uint64(*n), uint64(*n)
+		}
+
+		if nonce > st.Nonce || time.Since(st.Submitted) > submittedTrxDropTimeout {
+			stm.resolve(st, nonce > st.Nonce)
+			continue
+		}
+
+		stm.pending[st.Hash] = st
+		stm.bySender[senderKey(&st.From, st.Nonce)] = st
+	}
+}
+
+// resolve decides the final state of a submitted transaction no longer expected to be pending.
+func (stm *submittedTrxMonitor) resolve(st *types.SubmittedTransaction, nonceUsed bool) {
+	trx, err := repo.LoadTransaction(context.Background(), &st.Hash)
+	if err == nil && trx != nil && trx.BlockNumber != nil {
+		stm.update(st, types.SubmittedTrxStatusMined, trx)
+		return
+	}
+
+	if nonceUsed {
+		stm.update(st, types.SubmittedTrxStatusReplaced, nil)
+		return
+	}
+	stm.update(st, types.SubmittedTrxStatusDropped, nil)
+}
+
+// update stores the new state of the given submitted transaction and broadcasts the change.
+func (stm *submittedTrxMonitor) update(st *types.SubmittedTransaction, status string, trx *types.Transaction) {
+	st.Status = status
+	st.Updated = time.Now().UTC()
+
+	if trx != nil && trx.BlockNumber != nil {
+		blk := uint64(*trx.BlockNumber)
+		st.BlockNumber = &blk
+	}
+	if status == types.SubmittedTrxStatusReplaced && trx != nil {
+		st.ReplacedBy = &trx.Hash
+	}
+
+	delete(stm.pending, st.Hash)
+	delete(stm.bySender, senderKey(&st.From, st.Nonce))
+
+	if err := repo.StoreSubmittedTransaction(st); err != nil {
+		log.Errorf("can not update submitted trx %s; %s", st.Hash.String(), err.Error())
+		return
+	}
+	log.Noticef("submitted trx %s is %s", st.Hash.String(), st.Status)
+
+	// broadcast the change; if it can not be broadcast quickly, skip
+	if stm.onStatus == nil {
+		return
+	}
+	select {
+	case stm.onStatus <- st:
+	case <-time.After(200 * time.Millisecond):
+	case <-stm.sigStop:
+	}
+}
@@ -0,0 +1,283 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.mongodb.org/mongo-driver/bson"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertEventTransaction represents an alert rule evaluated against processed transactions.
+	AlertEventTransaction = "TRANSACTION"
+
+	// AlertEventTokenTransfer represents an alert rule evaluated against processed token transactions.
+	AlertEventTokenTransfer = "TOKEN_TRANSFER"
+
+	// AlertStatusSuccess represents a rule condition matching successful transactions only.
+	AlertStatusSuccess = "SUCCESS"
+
+	// AlertStatusFailed represents a rule condition matching failed transactions only.
+	AlertStatusFailed = "FAILED"
+)
+
+// AlertRule represents a declarative rule evaluated against the indexed activity.
+// Empty conditions match anything; all the set conditions must match.
+type AlertRule struct {
+	ID      string
+	Name    string
+	Event   string
+	Enabled bool
+
+	// From and To match the sender and the recipient, Address matches either of them.
+	From    *common.Address
+	To      *common.Address
+	Address *common.Address
+
+	// Token matches the token contract of a token transaction.
+	Token *common.Address
+
+	// TokenTrxType matches the type name of a token transaction (TRANSFER, MINT, BURN, APPROVAL, ...).
+	TokenTrxType string
+
+	// Method matches the 4 bytes selector of the function called by a transaction.
+	Method []byte
+
+	// MinValue matches the native value of a transaction, or the amount of a token transaction.
+	MinValue *big.Int
+
+	// Status matches the execution status of a transaction (AlertStatusSuccess, AlertStatusFailed).
+	Status string
+
+	// Sinks is the list of sinks the matching alerts are pushed to; all sinks if empty.
+	Sinks []string
+}
+
+// alertRuleRow represents the BSON i/o struct for an alert rule.
+type alertRuleRow struct {
+	ID           string   `bson:"_id"`
+	Name         string   `bson:"name"`
+	Event        string   `bson:"event"`
+	Enabled      bool     `bson:"enabled"`
+	From         string   `bson:"from,omitempty"`
+	To           string   `bson:"to,omitempty"`
+	Address      string   `bson:"address,omitempty"`
+	Token        string   `bson:"token,omitempty"`
+	TokenTrxType string   `bson:"type,omitempty"`
+	Method       string   `bson:"method,omitempty"`
+	MinValue     string   `bson:"min_value,omitempty"`
+	Status       string   `bson:"status,omitempty"`
+	Sinks        []string `bson:"sinks,omitempty"`
+}
+
+// UnmarshalBSON updates the value from BSON source.
+func (ar *AlertRule) UnmarshalBSON(data []byte) error {
+	var row alertRuleRow
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	ar.ID = row.ID
+	ar.Name = row.Name
+	ar.Event = row.Event
+	ar.Enabled = row.Enabled
+	ar.From = optionalAddress(row.From)
+	ar.To = optionalAddress(row.To)
+	ar.Address = optionalAddress(row.Address)
+	ar.Token = optionalAddress(row.Token)
+	ar.TokenTrxType = row.TokenTrxType
+	ar.Status = row.Status
+	ar.Sinks = row.Sinks
+
+	ar.Method = AlertMethodSelector(row.Method)
+	ar.MinValue = AlertMinValue(row.MinValue)
+	return nil
+}
+
+// AlertMethodSelector decodes the method condition of an alert rule into the 4 bytes selector.
+// The method is accepted as a hex encoded selector (i.e. 0xa9059cbb), or as a function
+// signature (i.e. transfer(address,uint256)). Nil is returned for an empty method.
+func AlertMethodSelector(method string) []byte {
+	method = strings.TrimSpace(method)
+	if method == "" {
+		return nil
+	}
+	if strings.Contains(method, "(") {
+		return crypto.Keccak256([]byte(strings.ReplaceAll(method, " ", "")))[:4]
+	}
+	return common.FromHex(method)
+}
+
+// AlertMinValue decodes the min value condition of an alert rule given as a decimal,
+// or a hex number. Nil is returned for an empty, or invalid value.
+func AlertMinValue(val string) *big.Int {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return nil
+	}
+	v, ok := new(big.Int).SetString(val, 0)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// optionalAddress decodes the given hex address; nil if empty.
+func optionalAddress(s string) *common.Address {
+	if s == "" {
+		return nil
+	}
+	adr := common.HexToAddress(s)
+	return &adr
+}
+
+// Alert represents a match of an alert rule on the indexed activity.
+type Alert struct {
+	ID          string
+	Ordinal     uint64
+	Rule        string
+	RuleName    string
+	Event       string
+	Transaction common.Hash
+	BlockNumber uint64
+	From        common.Address
+	To          *common.Address
+	Token       *common.Address
+	Value       hexutil.Big
+	Message     string
+	TimeStamp   time.Time
+	Created     time.Time
+
+	// Pending is the list of sinks the alert has not been pushed to yet.
+	Pending []string
+
+	// PushAttempts is the number of attempts made to push the alert to its sinks.
+	PushAttempts int32
+
+	// NextPush is the time of the next push attempt; zero if the alert is not waiting for one.
+	NextPush time.Time
+}
+
+// alertRow represents the BSON i/o struct for an alert.
+type alertRow struct {
+	ID          string     `bson:"_id"`
+	Ordinal     uint64     `bson:"orx"`
+	Rule        string     `bson:"rule"`
+	RuleName    string     `bson:"name"`
+	Event       string     `bson:"event"`
+	Transaction string     `bson:"trx"`
+	BlockNumber uint64     `bson:"blk"`
+	From        string     `bson:"from"`
+	To          *string    `bson:"to"`
+	Token       *string    `bson:"tok"`
+	Value       string     `bson:"val"`
+	Message     string     `bson:"msg"`
+	TimeStamp   time.Time  `bson:"ts"`
+	Created     time.Time  `bson:"created"`
+	Pending     []string   `bson:"pending,omitempty"`
+	Attempts    int32      `bson:"attempts"`
+	NextPush    *time.Time `bson:"next,omitempty"`
+}
+
+// MarshalBSON returns a BSON document for the alert.
+func (a *Alert) MarshalBSON() ([]byte, error) {
+	row := alertRow{
+		ID:          a.ID,
+		Ordinal:     a.Ordinal,
+		Rule:        a.Rule,
+		RuleName:    a.RuleName,
+		Event:       a.Event,
+		Transaction: a.Transaction.String(),
+		BlockNumber: a.BlockNumber,
+		From:        a.From.String(),
+		Value:       a.Value.String(),
+		Message:     a.Message,
+		TimeStamp:   a.TimeStamp,
+		Created:     a.Created,
+		Pending:     a.Pending,
+		Attempts:    a.PushAttempts,
+	}
+	if !a.NextPush.IsZero() {
+		row.NextPush = &a.NextPush
+	}
+	if a.To != nil {
+		to := a.To.String()
+		row.To = &to
+	}
+	if a.Token != nil {
+		tok := a.Token.String()
+		row.Token = &tok
+	}
+	return bson.Marshal(row)
+}
+
+// UnmarshalBSON updates the value from BSON source.
+func (a *Alert) UnmarshalBSON(data []byte) error {
+	var row alertRow
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	a.ID = row.ID
+	a.Ordinal = row.Ordinal
+	a.Rule = row.Rule
+	a.RuleName = row.RuleName
+	a.Event = row.Event
+	a.Transaction = common.HexToHash(row.Transaction)
+	a.BlockNumber = row.BlockNumber
+	a.From = common.HexToAddress(row.From)
+	a.Value = (hexutil.Big)(*hexutil.MustDecodeBig(row.Value))
+	a.Message = row.Message
+	a.TimeStamp = row.TimeStamp
+	a.Created = row.Created
+	a.Pending = row.Pending
+	a.PushAttempts = row.Attempts
+
+	a.NextPush = time.Time{}
+	if row.NextPush != nil {
+		a.NextPush = *row.NextPush
+	}
+
+	if row.To != nil {
+		a.To = optionalAddress(*row.To)
+	}
+	if row.Token != nil {
+		a.Token = optionalAddress(*row.Token)
+	}
+	return nil
+}
+
+// AlertList represents a list of alerts.
+type AlertList struct {
+	// List keeps the actual Collection.
+	Collection []*Alert
+
+	// Total indicates total number of alerts in the whole collection.
+	Total uint64
+
+	// First is the ordinal index of the first item on the list
+	First uint64
+
+	// IsStart indicates there are no alerts available above the list currently.
+	IsStart bool
+
+	// IsEnd indicates there are no alerts available below the list currently.
+	IsEnd bool
+}
+
+// Reverse reverses the order of alerts in the list.
+func (al *AlertList) Reverse() {
+	// anything to swap at all?
+	if al.Collection == nil || len(al.Collection) < 2 {
+		return
+	}
+
+	// swap elements
+	for i, j := 0, len(al.Collection)-1; i < j; i, j = i+1, j-1 {
+		al.Collection[i], al.Collection[j] = al.Collection[j], al.Collection[i]
+	}
+}
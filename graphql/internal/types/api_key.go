@@ -0,0 +1,47 @@
+// Package types implements different core types of the API.
+package types
+
+import "time"
+
+// ApiKey represents an API key granting its holder the access tier of the key.
+// Only the hash of the key is stored; the key itself is provided on the key creation only.
+type ApiKey struct {
+	// ID represents the unique identifier of the key used to manage it.
+	ID string `bson:"_id"`
+
+	// Hash represents the SHA-256 hash of the key.
+	Hash string `bson:"hash"`
+
+	// Prefix represents the first characters of the key helping to recognize it.
+	Prefix string `bson:"prefix"`
+
+	// Name represents the name of the key holder.
+	Name string `bson:"name"`
+
+	// Tier represents the name of the access tier of the key.
+	Tier string `bson:"tier"`
+
+	// Enabled signals the key can be used to access the API.
+	Enabled bool `bson:"enabled"`
+
+	// Created represents the time stamp of the key creation.
+	Created time.Time `bson:"created"`
+}
+
+// ApiKeyUsage represents the usage of an API key on a single day.
+type ApiKeyUsage struct {
+	// Key represents the ID of the API key; anonymous usage is aggregated under an empty ID.
+	Key string `bson:"key"`
+
+	// Day represents the UTC day of the usage in format YYYY-MM-DD.
+	Day string `bson:"day"`
+
+	// Requests represents the number of requests served.
+	Requests int64 `bson:"req"`
+
+	// Rejected represents the number of requests rejected by the rate limits.
+	Rejected int64 `bson:"rej"`
+}
+
+// ApiKeyUsageDayFormat represents the format of the API key usage day.
+const ApiKeyUsageDayFormat = "2006-01-02"
@@ -8,6 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"go.mongodb.org/mongo-driver/bson"
+	"strings"
+	"unicode"
 )
 
 // Contract represents an Opera smart contract at the blockchain.
@@ -27,6 +29,9 @@ type Contract struct {
 	// Name of the smart contract, if available.
 	Name string `json:"name"`
 
+	// Symbol of the token contract, if available.
+	Symbol string `json:"symbol,omitempty"`
+
 	// Smart contract version identifier, if available.
 	Version string `json:"ver,omitempty"`
 
@@ -63,24 +68,45 @@ type Contract struct {
 	Validated *hexutil.Uint64 `json:"ok,omitempty" bson:"is_ok,omitempty"`
 }
 
+// ContractCompilation represents the compiler settings of a contract source code validation,
+// which are not kept with the contract itself.
+type ContractCompilation struct {
+	// CodeFormat represents the format of the source code,
+	// i.e. solidity-single-file, or solidity-standard-json-input.
+	CodeFormat string
+
+	// Compiler represents the requested compiler release, i.e. v0.8.17+commit.8df45f5f;
+	// the default compiler is used if empty.
+	Compiler string
+
+	// EvmVersion represents the target EVM version of a single file source code.
+	EvmVersion string
+
+	// ConstructorArguments represents the hex encoded ABI constructor arguments
+	// expected at the end of the deployment transaction input.
+	ConstructorArguments string
+}
+
 // BsonContract represents the contract data structure for BSON formatting.
 type BsonContract struct {
-	Address   string  `bson:"_id"`
-	Type      string  `bson:"type"`
-	Name      string  `bson:"name"`
-	Ordinal   uint64  `bson:"orx"`
-	Trx       string  `bson:"trx"`
-	Created   uint64  `bson:"ts"`
-	Version   string  `bson:"ver"`
-	Support   string  `bson:"sup"`
-	License   string  `bson:"lic"`
-	Compiler  string  `bson:"sol"`
-	IsOpt     bool    `bson:"is_opt"`
-	OptRuns   int32   `bson:"opt"`
-	Src       string  `bson:"src"`
-	Abi       string  `bson:"abi"`
-	SrcHash   *string `bson:"src_h"`
-	Validated *uint64 `bson:"val"`
+	Address   string   `bson:"_id"`
+	Type      string   `bson:"type"`
+	Name      string   `bson:"name"`
+	Symbol    string   `bson:"sym"`
+	Keywords  []string `bson:"kw"`
+	Ordinal   uint64   `bson:"orx"`
+	Trx       string   `bson:"trx"`
+	Created   uint64   `bson:"ts"`
+	Version   string   `bson:"ver"`
+	Support   string   `bson:"sup"`
+	License   string   `bson:"lic"`
+	Compiler  string   `bson:"sol"`
+	IsOpt     bool     `bson:"is_opt"`
+	OptRuns   int32    `bson:"opt"`
+	Src       string   `bson:"src"`
+	Abi       string   `bson:"abi"`
+	SrcHash   *string  `bson:"src_h"`
+	Validated *uint64  `bson:"val"`
 }
 
 // UnmarshalContract parses the JSON-encoded smart contract data.
@@ -100,6 +126,32 @@ func (sc *Contract) Uid() uint64 {
 	return (uint64(sc.TimeStamp)&0xFFFFFFFFFF)<<24 | (binary.BigEndian.Uint64(sc.TransactionHash[:8]) & 0xFFFFFF)
 }
 
+// ContractKeywords provides the list of lower case search keywords of a contract
+// with the given name and symbol. The list contains both the full name and symbol
+// and their individual words, so a prefix of any of them can be matched.
+func ContractKeywords(name string, symbol string) []string {
+	kw := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(k string) {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			kw = append(kw, k)
+		}
+	}
+
+	for _, s := range []string{name, symbol} {
+		s = strings.ToLower(strings.TrimSpace(s))
+		add(s)
+		for _, w := range strings.FieldsFunc(s, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(w)
+		}
+	}
+	return kw
+}
+
 // NewGenericContract creates new generic contract record
 func NewGenericContract(addr *common.Address, block *Block, trx *Transaction) *Contract {
 	// make the contract
@@ -141,6 +193,8 @@ func (sc *Contract) MarshalBSON() ([]byte, error) {
 		Address:  sc.Address.String(),
 		Type:     sc.Type,
 		Name:     sc.Name,
+		Symbol:   sc.Symbol,
+		Keywords: ContractKeywords(sc.Name, sc.Symbol),
 		Ordinal:  sc.Uid(),
 		Trx:      sc.TransactionHash.String(),
 		Created:  uint64(sc.TimeStamp),
@@ -183,6 +237,7 @@ func (sc *Contract) UnmarshalBSON(data []byte) (err error) {
 	sc.Address = common.HexToAddress(row.Address)
 	sc.Type = row.Type
 	sc.Name = row.Name
+	sc.Symbol = row.Symbol
 	sc.TransactionHash = common.HexToHash(row.Trx)
 	sc.TimeStamp = hexutil.Uint64(row.Created)
 	sc.Version = row.Version
@@ -0,0 +1,66 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AbiParameter represents an input, or output parameter of a contract function
+// as declared by the contract ABI.
+type AbiParameter struct {
+	// Name represents the name of the parameter; empty if not named.
+	Name string
+
+	// Type represents the canonical Solidity type of the parameter.
+	Type string
+
+	// Components represents the list of tuple components, if the parameter is a tuple,
+	// or an array of tuples.
+	Components []*AbiParameter
+}
+
+// ContractFunction represents a contract function as declared by the contract ABI.
+type ContractFunction struct {
+	// Name represents the name of the function.
+	Name string
+
+	// Signature represents the canonical signature of the function.
+	Signature string
+
+	// Selector represents the 4 bytes selector of the function.
+	Selector hexutil.Bytes
+
+	// StateMutability represents the state mutability of the function, i.e. view, or pure.
+	StateMutability string
+
+	// Inputs represents the list of input parameters of the function.
+	Inputs []*AbiParameter
+
+	// Outputs represents the list of output parameters of the function.
+	Outputs []*AbiParameter
+}
+
+// ContractCallResult represents the decoded result of a read-only contract call.
+type ContractCallResult struct {
+	// Method represents the name of the called contract function.
+	Method string
+
+	// Signature represents the canonical signature of the called function.
+	Signature string
+
+	// Outputs represents the list of decoded output values.
+	Outputs []*ContractCallOutput
+}
+
+// ContractCallOutput represents a single typed output value of a read-only contract call.
+type ContractCallOutput struct {
+	// Name represents the name of the output parameter; empty if not named.
+	Name string
+
+	// Type represents the canonical Solidity type of the output parameter.
+	Type string
+
+	// Value represents the JSON friendly output value of the Solidity type. Numbers are decimal strings,
+	// bytes, addresses and hashes are hex strings, arrays are lists and tuples are objects keyed by component names.
+	Value interface{}
+}
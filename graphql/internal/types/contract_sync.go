@@ -0,0 +1,53 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
+
+const (
+	// ContractSyncStatusPending represents a contract sync waiting for a (re)try.
+	ContractSyncStatusPending = "PENDING"
+
+	// ContractSyncStatusSynced represents a contract sync accepted by the peer.
+	ContractSyncStatusSynced = "SYNCED"
+
+	// ContractSyncStatusFailed represents a contract sync abandoned after too many failed attempts.
+	ContractSyncStatusFailed = "FAILED"
+)
+
+// ContractSync represents the state of a contract validation sync to an API peer.
+type ContractSync struct {
+	// ID represents the unique identifier of the sync; a pair of the peer and the contract.
+	ID string `bson:"_id"`
+
+	// Peer represents the URL of the API peer the contract is synced to.
+	Peer string `bson:"peer"`
+
+	// Contract represents the address of the synced contract.
+	Contract string `bson:"addr"`
+
+	// Payload represents the sync input sent to the peer; it's signed for the peer on each attempt.
+	Payload []byte `bson:"payload"`
+
+	// Status represents the status of the sync; see ContractSyncStatus* constants.
+	Status string `bson:"status"`
+
+	// Attempts represents the number of sync attempts made.
+	Attempts int32 `bson:"attempts"`
+
+	// LastError represents the error of the last failed attempt; empty if none.
+	LastError string `bson:"error"`
+
+	// LastAttempt represents the time stamp of the last sync attempt.
+	LastAttempt time.Time `bson:"last"`
+
+	// NextAttempt represents the time stamp of the next scheduled sync attempt.
+	NextAttempt time.Time `bson:"next"`
+}
+
+// ContractSyncID provides the unique identifier of a sync of the given contract to the given peer.
+func ContractSyncID(peer string, addr *common.Address) string {
+	return peer + "#" + addr.String()
+}
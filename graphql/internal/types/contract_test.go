@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestContractKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol string
+		want   []string
+	}{
+		{name: "", symbol: "", want: []string{}},
+		{name: "Wrapped Fantom", symbol: "WFTM", want: []string{"wrapped fantom", "wrapped", "fantom", "wftm"}},
+		{name: "  USD Coin ", symbol: "USDC", want: []string{"usd coin", "usd", "coin", "usdc"}},
+		{name: "SpookySwap LP", symbol: "spLP", want: []string{"spookyswap lp", "spookyswap", "lp", "splp"}},
+		{name: "Fantom-Token", symbol: "fantom", want: []string{"fantom-token", "fantom", "token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.symbol, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(ContractKeywords(tt.name, tt.symbol)).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package types implements different core types of the API.
+package types
+
+import "time"
+
+const (
+	// ContractVerificationStatusPending represents a verification job waiting in the queue.
+	ContractVerificationStatusPending = "PENDING"
+
+	// ContractVerificationStatusProcessing represents a verification job being processed.
+	ContractVerificationStatusProcessing = "PROCESSING"
+
+	// ContractVerificationStatusPass represents a verification job which verified the contract.
+	ContractVerificationStatusPass = "PASS"
+
+	// ContractVerificationStatusFail represents a verification job which failed to verify the contract.
+	ContractVerificationStatusFail = "FAIL"
+)
+
+// ContractVerification represents an asynchronous contract source code verification job
+// submitted through the Etherscan compatible API.
+type ContractVerification struct {
+	// GUID represents the unique identifier of the job returned to the submitter.
+	GUID string `bson:"_id"`
+
+	// Contract represents the address of the verified contract.
+	Contract string `bson:"addr"`
+
+	// Name represents the name of the contract.
+	Name string `bson:"name"`
+
+	// CodeFormat represents the format of the source code,
+	// i.e. solidity-single-file, or solidity-standard-json-input.
+	CodeFormat string `bson:"format"`
+
+	// SourceCode represents the submitted source code.
+	SourceCode string `bson:"code"`
+
+	// Compiler represents the version of the compiler used to build the contract.
+	Compiler string `bson:"sol"`
+
+	// Optimized signals the contract byte code was optimized during compilation.
+	Optimized bool `bson:"opt"`
+
+	// OptimizeRuns represents the number of optimization runs used during compilation.
+	OptimizeRuns int32 `bson:"runs"`
+
+	// ConstructorArguments represents the hex encoded ABI constructor arguments.
+	ConstructorArguments string `bson:"args"`
+
+	// EvmVersion represents the target EVM version of the compilation.
+	EvmVersion string `bson:"evm"`
+
+	// License represents the license of the source code.
+	License string `bson:"lic"`
+
+	// Status represents the status of the job; see ContractVerificationStatus* constants.
+	Status string `bson:"status"`
+
+	// Error represents the reason of a failed verification; empty if none.
+	Error string `bson:"error"`
+
+	// Submitted represents the time stamp of the job submission.
+	Submitted time.Time `bson:"sub"`
+
+	// Started represents the time stamp of the last processing start.
+	Started time.Time `bson:"start"`
+
+	// Finished represents the time stamp of the job finish.
+	Finished time.Time `bson:"fin"`
+}
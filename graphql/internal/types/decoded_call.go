@@ -0,0 +1,47 @@
+// Package types implements different core types of the API.
+package types
+
+// DecodedCallConstructor represents the method name used for decoded contract deployment calls.
+const DecodedCallConstructor = "constructor"
+
+// DecodedArgument represents a single argument of a contract call, or an event,
+// decoded using a known ABI.
+type DecodedArgument struct {
+	// Name represents the name of the argument as declared by the ABI.
+	Name string
+
+	// Type represents the canonical Solidity type of the argument.
+	Type string
+
+	// Value represents the human-readable value of the argument.
+	// Scalar values are rendered as plain strings, complex values
+	// (arrays, tuples) are rendered as JSON.
+	Value string
+}
+
+// DecodedCall represents a contract call input data decoded using a known ABI.
+type DecodedCall struct {
+	// Method represents the name of the called contract function.
+	Method string
+
+	// Signature represents the canonical signature of the called function,
+	// i.e. transfer(address,uint256)
+	Signature string
+
+	// Args represents the list of decoded arguments of the call.
+	Args []*DecodedArgument
+}
+
+// DecodedEvent represents a contract event log record decoded using a known ABI,
+// or a known event signature.
+type DecodedEvent struct {
+	// Name represents the name of the emitted event.
+	Name string
+
+	// Signature represents the canonical signature of the emitted event,
+	// i.e. Transfer(address,address,uint256)
+	Signature string
+
+	// Args represents the list of decoded arguments of the event.
+	Args []*DecodedArgument
+}
@@ -21,6 +21,7 @@ const (
 	FiTokenTransactionType      = "type"
 	FiTokenTransactionSender    = "from"
 	FiTokenTransactionRecipient = "to"
+	FiTokenTransactionValue     = "val"
 
 	// TokenTrxTypeTransfer represents token transfer transaction.
 	TokenTrxTypeTransfer = 1
@@ -75,6 +76,24 @@ type BsonErc20Transaction struct {
 	Stamp     time.Time `bson:"stamp"`
 }
 
+// TokenTrxTypeName provides the name of the given token transaction type.
+func TokenTrxTypeName(tp int32) string {
+	switch tp {
+	case TokenTrxTypeTransfer:
+		return "TRANSFER"
+	case TokenTrxTypeMint:
+		return "MINT"
+	case TokenTrxTypeBurn:
+		return "BURN"
+	case TokenTrxTypeApproval:
+		return "APPROVAL"
+	case TokenTrxTypeApprovalForAll:
+		return "APPROVAL_FOR_ALL"
+	default:
+		return "OTHER"
+	}
+}
+
 // Pk generates unique identifier of the ERC20 transaction from the transaction data.
 func (etx *TokenTransaction) Pk() string {
 	bytes := make([]byte, 14)
@@ -164,5 +183,12 @@ func (etx *TokenTransaction) UnmarshalBSON(data []byte) (err error) {
 	etx.Recipient = common.HexToAddress(row.To)
 	etx.Amount = (hexutil.Big)(*hexutil.MustDecodeBig(row.Amo))
 	etx.TokenId = (hexutil.Big)(*hexutil.MustDecodeBig(row.TokenId))
+
+	// the block number and the log index are encoded in the primary key
+	if pk, err := hexutil.Decode(row.ID); err == nil && len(pk) == 14 {
+		etx.BlockNumber = binary.BigEndian.Uint64(pk[0:8])
+		etx.LogIndex = uint(binary.BigEndian.Uint32(pk[8:12]))
+		etx.Seq = binary.BigEndian.Uint16(pk[12:14])
+	}
 	return nil
 }
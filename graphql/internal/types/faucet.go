@@ -0,0 +1,86 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.mongodb.org/mongo-driver/bson"
+	"math/big"
+	"time"
+)
+
+// FaucetDecimalsCorrection is used to reduce precision of an amount of native tokens sent by the faucet.
+var FaucetDecimalsCorrection = new(big.Int).SetUint64(10_000_000_000)
+
+// FaucetRequest represents a single transfer of native tokens sent by the faucet.
+// The request is pending, with an empty transaction hash, until the transfer is sent;
+// the signed transfer is kept with the pending request so it can be re-sent.
+type FaucetRequest struct {
+	ID          string
+	Nonce       uint64
+	Transaction common.Hash
+	Raw         hexutil.Bytes
+	Recipient   common.Address
+	ClientIP    string
+	Amount      hexutil.Big
+	TimeStamp   time.Time
+}
+
+// faucetRequestRow represents the BSON i/o struct for a faucet request.
+type faucetRequestRow struct {
+	ID        string    `bson:"_id"`
+	Nonce     int64     `bson:"nonce"`
+	Trx       string    `bson:"trx,omitempty"`
+	Raw       []byte    `bson:"raw,omitempty"`
+	To        string    `bson:"to"`
+	IP        string    `bson:"ip"`
+	Amount    string    `bson:"amo"`
+	Value     int64     `bson:"val"`
+	TimeStamp time.Time `bson:"ts"`
+}
+
+// MarshalBSON returns a BSON document for the faucet request.
+func (fr *FaucetRequest) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(faucetRequestRow{
+		ID:        fr.ID,
+		Nonce:     int64(fr.Nonce),
+		Trx:       fr.trx(),
+		Raw:       fr.Raw,
+		To:        fr.Recipient.String(),
+		IP:        fr.ClientIP,
+		Amount:    fr.Amount.String(),
+		Value:     fr.Value(),
+		TimeStamp: fr.TimeStamp,
+	})
+}
+
+// UnmarshalBSON updates the value from BSON source.
+func (fr *FaucetRequest) UnmarshalBSON(data []byte) error {
+	var row faucetRequestRow
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	fr.ID = row.ID
+	fr.Nonce = uint64(row.Nonce)
+	fr.Transaction = common.HexToHash(row.Trx)
+	fr.Raw = row.Raw
+	fr.Recipient = common.HexToAddress(row.To)
+	fr.ClientIP = row.IP
+	fr.Amount = (hexutil.Big)(*hexutil.MustDecodeBig(row.Amount))
+	fr.TimeStamp = row.TimeStamp
+	return nil
+}
+
+// trx returns the hash of the transfer transaction; empty if the request is pending.
+func (fr *FaucetRequest) trx() string {
+	if fr.Transaction == (common.Hash{}) {
+		return ""
+	}
+	return fr.Transaction.String()
+}
+
+// Value returns the reduced precision amount of native tokens sent.
+func (fr *FaucetRequest) Value() int64 {
+	return new(big.Int).Div(fr.Amount.ToInt(), FaucetDecimalsCorrection).Int64()
+}
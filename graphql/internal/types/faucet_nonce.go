@@ -0,0 +1,109 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// FaucetNonce represents the nonce state of the faucet sender account shared by the API instances.
+// Nonces reserved for transfers not sent yet are held; nonces of transfers which could not be sent
+// are free and reused by the next transfers so the account nonce sequence does not get any gaps.
+type FaucetNonce struct {
+	// Next represents the next nonce not reserved yet.
+	Next uint64 `bson:"n"`
+
+	// Held represents the nonces reserved for transfers not sent yet.
+	Held []FaucetNonceHold `bson:"held"`
+
+	// Free represents the nonces below Next returned by transfers which could not be sent.
+	Free []uint64 `bson:"free"`
+
+	// Version represents the version of the state used to update it atomically.
+	Version int64 `bson:"v"`
+}
+
+// FaucetNonceHold represents a nonce reserved for a faucet transfer not sent yet.
+type FaucetNonceHold struct {
+	Nonce uint64    `bson:"nonce"`
+	Since time.Time `bson:"ts"`
+}
+
+// Reserve takes the nonce for a new transfer at the given time.
+// The pending nonce of the account known to the node is the base of the sequence.
+func (fn *FaucetNonce) Reserve(pending uint64, ts time.Time) uint64 {
+	// nonces below the pending one have already been used by the node
+	free := fn.Free[:0]
+	for _, n := range fn.Free {
+		if n >= pending {
+			free = append(free, n)
+		}
+	}
+	fn.Free = free
+
+	// if nothing is in flight and there is no gap to fill, the node knows the exact state of the account;
+	// the transfers queued behind a gap are not included in the pending nonce of the node
+	if len(fn.Held) == 0 && len(fn.Free) == 0 {
+		fn.Next = pending
+	} else if pending > fn.Next {
+		fn.Next = pending
+	}
+
+	var nonce uint64
+	if len(fn.Free) > 0 {
+		nonce, fn.Free = fn.Free[0], fn.Free[1:]
+	} else {
+		nonce = fn.Next
+		fn.Next++
+	}
+
+	fn.Held = append(fn.Held, FaucetNonceHold{Nonce: nonce, Since: ts})
+	return nonce
+}
+
+// Sent releases the nonce of a transfer accepted by the node, or otherwise used.
+func (fn *FaucetNonce) Sent(nonce uint64) {
+	fn.release(nonce)
+}
+
+// Return releases the nonce of a transfer which could not be sent, so the next transfer reuses it.
+func (fn *FaucetNonce) Return(nonce uint64) {
+	if !fn.release(nonce) || nonce >= fn.Next {
+		return
+	}
+
+	fn.Free = append(fn.Free, nonce)
+	sort.Slice(fn.Free, func(i, j int) bool { return fn.Free[i] < fn.Free[j] })
+
+	// free nonces on top of the sequence are not gaps
+	for len(fn.Free) > 0 && fn.Free[len(fn.Free)-1] == fn.Next-1 {
+		fn.Free = fn.Free[:len(fn.Free)-1]
+		fn.Next--
+	}
+}
+
+// ReturnStale returns the nonces held since before the given time, except the given ones.
+// It frees the nonces of transfers abandoned before their request was stored.
+func (fn *FaucetNonce) ReturnStale(before time.Time, keep map[uint64]bool) {
+	stale := make([]uint64, 0)
+	for _, h := range fn.Held {
+		if h.Since.Before(before) && !keep[h.Nonce] {
+			stale = append(stale, h.Nonce)
+		}
+	}
+
+	for _, n := range stale {
+		fn.Return(n)
+	}
+}
+
+// release removes the given nonce from the held ones; returns false if the nonce is not held.
+func (fn *FaucetNonce) release(nonce uint64) bool {
+	for i, h := range fn.Held {
+		if h.Nonce == nonce {
+			fn.Held = append(fn.Held[:i], fn.Held[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
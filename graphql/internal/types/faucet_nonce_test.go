@@ -0,0 +1,123 @@
+package types
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+func TestFaucetNonceFailedSend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ts := time.Unix(1666000000, 0).UTC()
+
+	var fn FaucetNonce
+	g.Expect(fn.Reserve(10, ts)).To(gomega.Equal(uint64(10)))
+	g.Expect(fn.Reserve(10, ts)).To(gomega.Equal(uint64(11)))
+	g.Expect(fn.Reserve(10, ts)).To(gomega.Equal(uint64(12)))
+
+	// the send of a nonce in the middle fails while the others are sent
+	fn.Sent(10)
+	fn.Return(11)
+	fn.Sent(12)
+	g.Expect(fn.Held).To(gomega.BeEmpty())
+
+	// the next request fills the gap; the node does not count 12 queued behind it
+	g.Expect(fn.Reserve(11, ts)).To(gomega.Equal(uint64(11)))
+	g.Expect(fn.Reserve(11, ts)).To(gomega.Equal(uint64(13)))
+
+	// once idle, the node pending nonce is used again
+	fn.Sent(11)
+	fn.Sent(13)
+	g.Expect(fn.Reserve(14, ts)).To(gomega.Equal(uint64(14)))
+}
+
+func TestFaucetNonceReserve(t *testing.T) {
+	ts := time.Unix(1666000000, 0).UTC()
+
+	tests := []struct {
+		name    string
+		state   FaucetNonce
+		pending uint64
+		want    uint64
+		next    uint64
+		free    []uint64
+	}{
+		{name: "new account", pending: 0, want: 0, next: 1},
+		{name: "idle resets down", state: FaucetNonce{Next: 20, Free: []uint64{10}}, pending: 12, want: 12, next: 13},
+		{name: "idle fills gap", state: FaucetNonce{Next: 20, Free: []uint64{15, 17}}, pending: 15, want: 15, next: 20, free: []uint64{17}},
+		{name: "idle follows node", state: FaucetNonce{Next: 5}, pending: 9, want: 9, next: 10},
+		{
+			name:    "in flight reuses lowest free",
+			state:   FaucetNonce{Next: 20, Held: []FaucetNonceHold{{Nonce: 19}}, Free: []uint64{16, 17}},
+			pending: 15,
+			want:    16,
+			next:    20,
+			free:    []uint64{17},
+		},
+		{
+			name:    "in flight drops used free",
+			state:   FaucetNonce{Next: 20, Held: []FaucetNonceHold{{Nonce: 19}}, Free: []uint64{16, 17}},
+			pending: 18,
+			want:    20,
+			next:    21,
+			free:    []uint64{},
+		},
+		{
+			name:    "in flight follows node ahead",
+			state:   FaucetNonce{Next: 20, Held: []FaucetNonceHold{{Nonce: 19}}},
+			pending: 25,
+			want:    25,
+			next:    26,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			fn := tt.state
+			held := len(fn.Held)
+			g.Expect(fn.Reserve(tt.pending, ts)).To(gomega.Equal(tt.want))
+			g.Expect(fn.Next).To(gomega.Equal(tt.next))
+			if tt.free == nil {
+				g.Expect(fn.Free).To(gomega.BeEmpty())
+			} else {
+				g.Expect(fn.Free).To(gomega.Equal(tt.free))
+			}
+			g.Expect(fn.Held).To(gomega.HaveLen(held + 1))
+			g.Expect(fn.Held[held]).To(gomega.Equal(FaucetNonceHold{Nonce: tt.want, Since: ts}))
+		})
+	}
+}
+
+func TestFaucetNonceReturn(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ts := time.Unix(1666000000, 0).UTC()
+
+	var fn FaucetNonce
+	for i := 0; i < 4; i++ {
+		fn.Reserve(0, ts.Add(time.Duration(i)*time.Minute))
+	}
+
+	// nonces not held are ignored
+	fn.Return(7)
+	g.Expect(fn.Free).To(gomega.BeEmpty())
+
+	// a gap is kept free, returns on top of the sequence roll it back
+	fn.Return(1)
+	g.Expect(fn.Free).To(gomega.Equal([]uint64{1}))
+	fn.Return(3)
+	g.Expect(fn.Next).To(gomega.Equal(uint64(3)))
+	fn.Return(2)
+	g.Expect(fn.Next).To(gomega.Equal(uint64(1)))
+	g.Expect(fn.Free).To(gomega.BeEmpty())
+
+	// stale holds are returned unless kept
+	fn = FaucetNonce{}
+	for i := 0; i < 3; i++ {
+		fn.Reserve(0, ts.Add(time.Duration(i)*time.Minute))
+	}
+	fn.ReturnStale(ts.Add(90*time.Second), map[uint64]bool{1: true})
+	g.Expect(fn.Free).To(gomega.Equal([]uint64{0}))
+	g.Expect(fn.Held).To(gomega.Equal([]FaucetNonceHold{{Nonce: 1, Since: ts.Add(time.Minute)}, {Nonce: 2, Since: ts.Add(2 * time.Minute)}}))
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestFaucetRequestBSON(t *testing.T) {
+	tests := []struct {
+		name string
+		trx  common.Hash
+		raw  hexutil.Bytes
+	}{
+		{name: "pending", raw: hexutil.Bytes{0xf8, 0x6b, 0x2a}},
+		{name: "sent", trx: common.HexToHash("0x6e2d1bfb5b3d6a0a0cbc0e1e5d5a9b3f7b0e6fd2ff7a9f0a3ad4c6fd3b8e1a55")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			fr := FaucetRequest{
+				ID:          "6351a1b2c3d4e5f601234567",
+				Nonce:       42,
+				Transaction: tt.trx,
+				Raw:         tt.raw,
+				Recipient:   common.HexToAddress("0x5aa1e8a1b4ea1e2b5fa4cdb7a7aed9bd84b4a3d9"),
+				ClientIP:    "203.0.113.7",
+				Amount:      hexutil.Big(*new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18))),
+				TimeStamp:   time.Unix(1666000000, 0).UTC(),
+			}
+
+			data, err := bson.Marshal(&fr)
+			g.Expect(err).To(gomega.BeNil())
+
+			var row bson.M
+			g.Expect(bson.Unmarshal(data, &row)).To(gomega.Succeed())
+			g.Expect(row["val"]).To(gomega.Equal(int64(300_000_000)))
+			if tt.trx == (common.Hash{}) {
+				g.Expect(row).ToNot(gomega.HaveKey("trx"))
+			} else {
+				g.Expect(row["trx"]).To(gomega.Equal(tt.trx.String()))
+			}
+
+			var out FaucetRequest
+			g.Expect(bson.Unmarshal(data, &out)).To(gomega.Succeed())
+			g.Expect(out).To(gomega.Equal(fr))
+		})
+	}
+}
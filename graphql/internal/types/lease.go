@@ -0,0 +1,30 @@
+// Package types implements different core types of the API.
+package types
+
+import "time"
+
+// LeaseIndexer represents the name of the lease granting its holder the role of the blockchain indexer.
+const LeaseIndexer = "indexer"
+
+// Lease represents a time limited exclusive role shared by several API server instances.
+type Lease struct {
+	// Name represents the name of the lease.
+	Name string `bson:"_id"`
+
+	// Holder represents the identifier of the instance holding the lease.
+	Holder string `bson:"holder"`
+
+	// Acquired represents the time stamp the holder acquired the lease.
+	Acquired time.Time `bson:"acq"`
+
+	// Heartbeat represents the time stamp of the latest lease renewal.
+	Heartbeat time.Time `bson:"hb"`
+
+	// Expires represents the time stamp the lease lapses if not renewed.
+	Expires time.Time `bson:"exp"`
+}
+
+// IsHeldBy checks if the lease is held by the given holder at the given time.
+func (l *Lease) IsHeldBy(holder string, now time.Time) bool {
+	return l.Holder == holder && l.Expires.After(now)
+}
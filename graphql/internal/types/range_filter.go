@@ -0,0 +1,17 @@
+// Package types implements different core types of the API.
+package types
+
+// RangeFilter represents block number and time range limits applied to a list.
+// Times are UNIX time stamps in seconds; all the limits are inclusive
+// and nil limits are not applied.
+type RangeFilter struct {
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *uint64
+	ToTime    *uint64
+}
+
+// IsEmpty checks if the range filter does not apply any limit.
+func (rf *RangeFilter) IsEmpty() bool {
+	return rf == nil || (rf.FromBlock == nil && rf.ToBlock == nil && rf.FromTime == nil && rf.ToTime == nil)
+}
@@ -0,0 +1,69 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureTypeFunction represents a 4 bytes contract function selector signature.
+	SignatureTypeFunction = "FUNCTION"
+
+	// SignatureTypeEvent represents a 32 bytes event topic signature.
+	SignatureTypeEvent = "EVENT"
+)
+
+// signatureTextPattern represents the expected format of a canonical signature text,
+// i.e. transfer(address,uint256)
+var signatureTextPattern = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*\([a-zA-Z0-9_,()\[\]]*\)$`)
+
+// Signature represents a text signature of a contract function, or an event,
+// mapped to its hash; e.g. the 4 bytes function selector, or the event topic.
+type Signature struct {
+	// Type represents the type of the signature; see SignatureType* constants.
+	Type string `bson:"type"`
+
+	// Hash represents the function selector, or the event topic.
+	Hash hexutil.Bytes `bson:"hash"`
+
+	// Text represents the canonical text of the signature.
+	Text string `bson:"text"`
+
+	// Registered represents the time stamp of the signature registration.
+	Registered time.Time `bson:"ts"`
+}
+
+// NewSignature creates a new signature record of the given type for the given text.
+// The hash of the signature is calculated from the text.
+func NewSignature(typ string, text string) (*Signature, error) {
+	text = strings.Join(strings.Fields(text), "")
+	if !signatureTextPattern.MatchString(text) {
+		return nil, fmt.Errorf("invalid signature text %s", text)
+	}
+
+	hash := crypto.Keccak256([]byte(text))
+	switch typ {
+	case SignatureTypeFunction:
+		hash = hash[:4]
+	case SignatureTypeEvent:
+	default:
+		return nil, fmt.Errorf("unknown signature type %s", typ)
+	}
+
+	return &Signature{
+		Type:       typ,
+		Hash:       hash,
+		Text:       text,
+		Registered: time.Now().UTC(),
+	}, nil
+}
+
+// Name provides the function, or the event name of the signature.
+func (sig *Signature) Name() string {
+	return sig.Text[:strings.Index(sig.Text, "(")]
+}
@@ -0,0 +1,57 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	retypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// StateOverride represents temporary changes of an account state
+// applied before a transaction is simulated.
+type StateOverride struct {
+	// Address is the address of the account overridden.
+	Address common.Address
+
+	// Balance is the fake balance of the account.
+	Balance *hexutil.Big
+
+	// Nonce is the fake nonce of the account.
+	Nonce *hexutil.Uint64
+
+	// Code is the fake byte code of the account.
+	Code *hexutil.Bytes
+
+	// State replaces the whole storage of the account with the given slots.
+	State map[common.Hash]common.Hash
+
+	// StateDiff overrides individual slots of the account storage.
+	StateDiff map[common.Hash]common.Hash
+}
+
+// SimulationResult represents the outcome of a simulated transaction.
+type SimulationResult struct {
+	// Success signals the call finished without being reverted.
+	Success bool
+
+	// Error is the execution error of a failed call.
+	Error *string
+
+	// RevertReason is the decoded reason of a reverted call, if provided by the contract.
+	RevertReason *string
+
+	// GasUsed is the amount of gas used by the call; estimated if the call tracer is not available.
+	GasUsed hexutil.Uint64
+
+	// ReturnData is the raw output of the call, or the revert data of a reverted call.
+	ReturnData hexutil.Bytes
+
+	// LogsAvailable signals the emitted logs were collected by the call tracer.
+	LogsAvailable bool
+
+	// Logs is the list of log records emitted by the call.
+	Logs []retypes.Log
+
+	// TokenTransactions is the list of token transactions decoded from the emitted logs.
+	TokenTransactions []*TokenTransaction
+}
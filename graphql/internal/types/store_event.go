@@ -0,0 +1,11 @@
+// Package types implements different core types of the API.
+package types
+
+// StoreEvent represents a record added to the shared persistent store by the indexer.
+// Exactly one of the records is set.
+type StoreEvent struct {
+	Block            *Block
+	Transaction      *Transaction
+	TokenTransaction *TokenTransaction
+	Submitted        *SubmittedTransaction
+}
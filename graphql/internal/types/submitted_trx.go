@@ -0,0 +1,101 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+const (
+	// SubmittedTrxStatusPending represents a submitted transaction waiting to be mined.
+	SubmittedTrxStatusPending = "PENDING"
+
+	// SubmittedTrxStatusMined represents a submitted transaction included in a block.
+	SubmittedTrxStatusMined = "MINED"
+
+	// SubmittedTrxStatusReplaced represents a submitted transaction replaced by another transaction
+	// of the same sender and nonce.
+	SubmittedTrxStatusReplaced = "REPLACED"
+
+	// SubmittedTrxStatusDropped represents a submitted transaction not mined in time.
+	SubmittedTrxStatusDropped = "DROPPED"
+)
+
+// SubmittedTransaction represents the lifecycle state of a transaction submitted through the API.
+type SubmittedTransaction struct {
+	Hash        common.Hash
+	From        common.Address
+	Nonce       uint64
+	Status      string
+	BlockNumber *uint64
+	ReplacedBy  *common.Hash
+	Submitted   time.Time
+	Updated     time.Time
+}
+
+// submittedTrxRow represents the BSON i/o struct for a submitted transaction.
+type submittedTrxRow struct {
+	Hash        string    `bson:"_id"`
+	From        string    `bson:"from"`
+	Nonce       int64     `bson:"nonce"`
+	Status      string    `bson:"status"`
+	BlockNumber *int64    `bson:"blk"`
+	ReplacedBy  *string   `bson:"rep"`
+	Submitted   time.Time `bson:"sub"`
+	Updated     time.Time `bson:"upd"`
+}
+
+// IsFinal checks if the submitted transaction reached a final state.
+func (st *SubmittedTransaction) IsFinal() bool {
+	return st.Status != SubmittedTrxStatusPending
+}
+
+// MarshalBSON returns a BSON document for the submitted transaction.
+func (st *SubmittedTransaction) MarshalBSON() ([]byte, error) {
+	row := submittedTrxRow{
+		Hash:      st.Hash.String(),
+		From:      st.From.String(),
+		Nonce:     int64(st.Nonce),
+		Status:    st.Status,
+		Submitted: st.Submitted,
+		Updated:   st.Updated,
+	}
+	if st.BlockNumber != nil {
+		blk := int64(*st.BlockNumber)
+		row.BlockNumber = &blk
+	}
+	if st.ReplacedBy != nil {
+		rep := st.ReplacedBy.String()
+		row.ReplacedBy = &rep
+	}
+	return bson.Marshal(row)
+}
+
+// UnmarshalBSON updates the value from BSON source.
+func (st *SubmittedTransaction) UnmarshalBSON(data []byte) error {
+	var row submittedTrxRow
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	st.Hash = common.HexToHash(row.Hash)
+	st.From = common.HexToAddress(row.From)
+	st.Nonce = uint64(row.Nonce)
+	st.Status = row.Status
+	st.Submitted = row.Submitted
+	st.Updated = row.Updated
+
+	st.BlockNumber = nil
+	if row.BlockNumber != nil {
+		blk := uint64(*row.BlockNumber)
+		st.BlockNumber = &blk
+	}
+
+	st.ReplacedBy = nil
+	if row.ReplacedBy != nil {
+		rep := common.HexToHash(*row.ReplacedBy)
+		st.ReplacedBy = &rep
+	}
+	return nil
+}
@@ -106,6 +106,7 @@ type BsonTransaction struct {
 	Amount     int64     `bson:"amo"`
 	LargeInput bool      `bson:"large"`
 	Input      []byte    `bson:"input"`
+	Selector   *string   `bson:"sel,omitempty"`
 	Gas        int64     `bson:"gas_lim"`
 	UsedGas    *uint64   `bson:"gas_use"`
 	CumGas     *uint64   `bson:"gas_cum"`
@@ -162,6 +163,12 @@ func (trx *Transaction) MarshalBSON() ([]byte, error) {
 		pom.Input = trx.InputData
 	}
 
+	// keep the 4-byte method selector of contract calls for filtering
+	if len(trx.InputData) >= 4 {
+		sel := hexutil.Encode(trx.InputData[:4])
+		pom.Selector = &sel
+	}
+
 	// transaction has been mined, we have all the extra info, too
 	if trx.BlockHash != nil {
 		// block hash
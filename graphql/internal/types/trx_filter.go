@@ -0,0 +1,51 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+const (
+	// TrxDirectionAny represents transactions both sent and received by the filtered account.
+	TrxDirectionAny = iota
+
+	// TrxDirectionIncoming represents transactions received by the filtered account.
+	TrxDirectionIncoming
+
+	// TrxDirectionOutgoing represents transactions sent by the filtered account.
+	TrxDirectionOutgoing
+
+	// TrxDirectionSelf represents transactions sent by the filtered account to itself.
+	TrxDirectionSelf
+)
+
+// TransactionFilter represents an advanced filter of account transaction lists.
+// Values are compared with the precision of the stored transaction amount (1 gWei).
+type TransactionFilter struct {
+	Direction     int
+	Status        *uint64
+	CreationsOnly bool
+	Method        *string
+	MinValue      *big.Int
+	MaxValue      *big.Int
+}
+
+// TokenTransactionFilter represents an advanced filter of token transaction lists.
+// Amounts of ERC20 tokens are compared with the precision of the stored value (1e-9 of the raw amount).
+type TokenTransactionFilter struct {
+	Tokens    []common.Address
+	MinAmount *big.Int
+	MaxAmount *big.Int
+}
+
+// IsEmpty checks if the transaction filter does not limit the list in any way.
+func (tf *TransactionFilter) IsEmpty() bool {
+	return tf == nil || (tf.Direction == TrxDirectionAny && tf.Status == nil && !tf.CreationsOnly &&
+		tf.Method == nil && tf.MinValue == nil && tf.MaxValue == nil)
+}
+
+// IsEmpty checks if the token transaction filter does not limit the list in any way.
+func (tf *TokenTransactionFilter) IsEmpty() bool {
+	return tf == nil || (len(tf.Tokens) == 0 && tf.MinAmount == nil && tf.MaxAmount == nil)
+}
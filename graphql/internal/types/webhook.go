@@ -0,0 +1,150 @@
+// Package types implements different core types of the API.
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/url"
+	"time"
+)
+
+const (
+	// WebhookEventTransaction represents a transaction sent, or received by a watched address.
+	WebhookEventTransaction = "TRANSACTION"
+
+	// WebhookEventTokenTransfer represents a token transfer of a watched token, or a watched address.
+	WebhookEventTokenTransfer = "TOKEN_TRANSFER"
+
+	// WebhookDeliveryPending represents a webhook delivery waiting for a (re)try.
+	WebhookDeliveryPending = "PENDING"
+
+	// WebhookDeliveryDelivered represents a webhook delivery accepted by the receiver.
+	WebhookDeliveryDelivered = "DELIVERED"
+
+	// WebhookDeliveryFailed represents a webhook delivery abandoned after too many failed attempts.
+	WebhookDeliveryFailed = "FAILED"
+)
+
+// Webhook represents a registered receiver of address activity notifications.
+type Webhook struct {
+	ID        string
+	URL       string
+	Secret    string
+	Addresses []common.Address
+	Tokens    []common.Address
+	Events    []string
+	Enabled   bool
+	Created   time.Time
+}
+
+// IsValidWebhookURL checks the given webhook URL is an absolute http(s) URL.
+func IsValidWebhookURL(addr string) bool {
+	u, err := url.Parse(addr)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// webhookRow represents the BSON i/o struct for a webhook.
+type webhookRow struct {
+	ID        string    `bson:"_id"`
+	URL       string    `bson:"url"`
+	Secret    string    `bson:"secret"`
+	Addresses []string  `bson:"addr"`
+	Tokens    []string  `bson:"tokens"`
+	Events    []string  `bson:"events"`
+	Enabled   bool      `bson:"enabled"`
+	Created   time.Time `bson:"created"`
+}
+
+// MarshalBSON returns a BSON document for the webhook.
+func (wh *Webhook) MarshalBSON() ([]byte, error) {
+	row := webhookRow{
+		ID:        wh.ID,
+		URL:       wh.URL,
+		Secret:    wh.Secret,
+		Addresses: make([]string, len(wh.Addresses)),
+		Tokens:    make([]string, len(wh.Tokens)),
+		Events:    wh.Events,
+		Enabled:   wh.Enabled,
+		Created:   wh.Created,
+	}
+	for i, a := range wh.Addresses {
+		row.Addresses[i] = a.String()
+	}
+	for i, t := range wh.Tokens {
+		row.Tokens[i] = t.String()
+	}
+	return bson.Marshal(row)
+}
+
+// UnmarshalBSON updates the value from BSON source.
+func (wh *Webhook) UnmarshalBSON(data []byte) error {
+	var row webhookRow
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	wh.ID = row.ID
+	wh.URL = row.URL
+	wh.Secret = row.Secret
+	wh.Events = row.Events
+	wh.Enabled = row.Enabled
+	wh.Created = row.Created
+
+	wh.Addresses = make([]common.Address, len(row.Addresses))
+	for i, a := range row.Addresses {
+		wh.Addresses[i] = common.HexToAddress(a)
+	}
+	wh.Tokens = make([]common.Address, len(row.Tokens))
+	for i, t := range row.Tokens {
+		wh.Tokens[i] = common.HexToAddress(t)
+	}
+	return nil
+}
+
+// HasEvent checks if the webhook is subscribed to the given event kind.
+func (wh *Webhook) HasEvent(event string) bool {
+	for _, e := range wh.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchesAddress checks if the webhook watches the given address.
+func (wh *Webhook) WatchesAddress(addr *common.Address) bool {
+	if addr == nil {
+		return false
+	}
+	for _, a := range wh.Addresses {
+		if a == *addr {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchesToken checks if the webhook watches the given token contract.
+func (wh *Webhook) WatchesToken(token *common.Address) bool {
+	for _, t := range wh.Tokens {
+		if t == *token {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery represents a single notification delivery to a webhook.
+type WebhookDelivery struct {
+	ID           string    `bson:"_id"`
+	Hook         string    `bson:"hook"`
+	Event        string    `bson:"event"`
+	Payload      []byte    `bson:"payload"`
+	Status       string    `bson:"status"`
+	Attempts     int32     `bson:"attempts"`
+	ResponseCode int32     `bson:"code"`
+	LastError    string    `bson:"error"`
+	Created      time.Time `bson:"created"`
+	LastAttempt  time.Time `bson:"last"`
+	NextAttempt  time.Time `bson:"next"`
+}
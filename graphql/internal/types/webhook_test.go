@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestIsValidWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://hooks.example.com/fantom", valid: true},
+		{url: "http://10.0.0.5:8080/hook?x=1", valid: true},
+		{url: "ftp://hooks.example.com/fantom", valid: false},
+		{url: "javascript:alert(1)", valid: false},
+		{url: "/relative/path", valid: false},
+		{url: "https://", valid: false},
+		{url: "http://[::1", valid: false},
+		{url: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(IsValidWebhookURL(tt.url)).To(gomega.Equal(tt.valid))
+		})
+	}
+}